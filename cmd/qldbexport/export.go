@@ -0,0 +1,105 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver"
+)
+
+// ledger is the part of the driver used by the export.
+type ledger interface {
+	GetTableNames(ctx context.Context) ([]string, error)
+	ExportSnapshot(ctx context.Context, tableNames []string, open func(tableName string) (io.WriteCloser, error), fns ...func(*qldbdriver.SnapshotOptions)) ([]qldbdriver.TableSnapshotExport, error)
+}
+
+// exporter writes the tables of a ledger and their schemas to files in dir.
+type exporter struct {
+	ledger    ledger
+	format    qldbdriver.ExportFormat
+	extension string
+	dir       string
+	stdout    io.Writer
+	stderr    io.Writer
+}
+
+// run exports the comma-separated tables, or all the active tables if tables is empty, and returns the exit code of
+// the command.
+func (exp *exporter) run(ctx context.Context, tables string) int {
+	var tableNames []string
+	if tables != "" {
+		tableNames = strings.Split(tables, ",")
+	} else {
+		var err error
+		tableNames, err = exp.ledger.GetTableNames(ctx)
+		if err != nil {
+			fmt.Fprintf(exp.stderr, "Failed to list the tables: %v\n", err)
+			return 1
+		}
+	}
+
+	exports, err := exp.ledger.ExportSnapshot(ctx, tableNames, func(tableName string) (io.WriteCloser, error) {
+		return os.Create(filepath.Join(exp.dir, tableName+exp.extension))
+	}, func(options *qldbdriver.SnapshotOptions) {
+		options.Format = exp.format
+	})
+	for _, export := range exports {
+		if schemaErr := writeSchema(filepath.Join(exp.dir, export.TableName+".schema.json"), export.Schema); schemaErr != nil {
+			fmt.Fprintf(exp.stderr, "Failed to write the schema of %s: %v\n", export.TableName, schemaErr)
+			return 1
+		}
+		fmt.Fprintf(exp.stdout, "%s: %d documents\n", export.TableName, export.Rows)
+	}
+	if err != nil {
+		fmt.Fprintf(exp.stderr, "Failed to export the tables: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// parseFormat returns the export format and the file extension for a format name.
+func parseFormat(name string) (qldbdriver.ExportFormat, string, bool) {
+	switch strings.ToLower(name) {
+	case "ion":
+		return qldbdriver.ExportIonText, ".ion", true
+	case "json":
+		return qldbdriver.ExportJSONLines, ".jsonl", true
+	case "csv":
+		return qldbdriver.ExportCSV, ".csv", true
+	}
+	return 0, "", false
+}
+
+// writeSchema writes the schema of a table to a file as indented JSON.
+func writeSchema(path string, schema qldbdriver.TableSchema) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(schema)
+	closeErr := file.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
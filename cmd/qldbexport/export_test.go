@@ -0,0 +1,141 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLedger struct {
+	documents map[string]string
+	format    qldbdriver.ExportFormat
+	tablesErr error
+	exportErr error
+}
+
+func (ledger *fakeLedger) GetTableNames(ctx context.Context) ([]string, error) {
+	return []string{"Person", "Vehicle"}, ledger.tablesErr
+}
+
+func (ledger *fakeLedger) ExportSnapshot(ctx context.Context, tableNames []string, open func(tableName string) (io.WriteCloser, error), fns ...func(*qldbdriver.SnapshotOptions)) ([]qldbdriver.TableSnapshotExport, error) {
+	options := &qldbdriver.SnapshotOptions{}
+	for _, fn := range fns {
+		fn(options)
+	}
+	ledger.format = options.Format
+	var exports []qldbdriver.TableSnapshotExport
+	for _, tableName := range tableNames {
+		if ledger.exportErr != nil && len(exports) > 0 {
+			return exports, ledger.exportErr
+		}
+		w, err := open(tableName)
+		if err != nil {
+			return exports, err
+		}
+		_, err = io.WriteString(w, ledger.documents[tableName])
+		closeErr := w.Close()
+		if err != nil {
+			return exports, err
+		}
+		if closeErr != nil {
+			return exports, closeErr
+		}
+		exports = append(exports, qldbdriver.TableSnapshotExport{
+			TableName: tableName,
+			Rows:      1,
+			Schema:    qldbdriver.TableSchema{Columns: []qldbdriver.ColumnSchema{{Name: "Name", Types: []string{"string"}}}},
+		})
+	}
+	return exports, nil
+}
+
+func TestExporter(t *testing.T) {
+	documents := map[string]string{"Person": "{\"Name\":\"Jane\"}\n", "Vehicle": "{\"Name\":\"Car\"}\n"}
+	runExport := func(ledger *fakeLedger, dir string, tables string) (int, string, string) {
+		stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+		exp := &exporter{ledger: ledger, format: qldbdriver.ExportJSONLines, extension: ".jsonl", dir: dir, stdout: &stdout, stderr: &stderr}
+		code := exp.run(context.Background(), tables)
+		return code, stdout.String(), stderr.String()
+	}
+	readFile := func(t *testing.T, path string) string {
+		content, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	t.Run("all tables", func(t *testing.T) {
+		dir := t.TempDir()
+		ledger := &fakeLedger{documents: documents}
+
+		code, stdout, stderr := runExport(ledger, dir, "")
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "Person: 1 documents\nVehicle: 1 documents\n", stdout)
+		assert.Empty(t, stderr)
+		assert.Equal(t, qldbdriver.ExportJSONLines, ledger.format)
+		assert.Equal(t, documents["Person"], readFile(t, filepath.Join(dir, "Person.jsonl")))
+		assert.Equal(t, documents["Vehicle"], readFile(t, filepath.Join(dir, "Vehicle.jsonl")))
+		assert.JSONEq(t, `{"columns": [{"name": "Name", "types": ["string"], "nullable": false}]}`, readFile(t, filepath.Join(dir, "Person.schema.json")))
+	})
+
+	t.Run("listed tables", func(t *testing.T) {
+		dir := t.TempDir()
+
+		code, stdout, _ := runExport(&fakeLedger{documents: documents, tablesErr: errors.New("not listed")}, dir, "Vehicle")
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "Vehicle: 1 documents\n", stdout)
+		assert.NoFileExists(t, filepath.Join(dir, "Person.jsonl"))
+	})
+
+	t.Run("tables cannot be listed", func(t *testing.T) {
+		code, _, stderr := runExport(&fakeLedger{tablesErr: errors.New("access denied")}, t.TempDir(), "")
+		assert.Equal(t, 1, code)
+		assert.Equal(t, "Failed to list the tables: access denied\n", stderr)
+	})
+
+	t.Run("export error keeps the exported tables", func(t *testing.T) {
+		dir := t.TempDir()
+		ledger := &fakeLedger{documents: documents, exportErr: errors.New("transaction expired")}
+
+		code, stdout, stderr := runExport(ledger, dir, "")
+		assert.Equal(t, 1, code)
+		assert.Equal(t, "Person: 1 documents\n", stdout)
+		assert.Equal(t, "Failed to export the tables: transaction expired\n", stderr)
+		assert.FileExists(t, filepath.Join(dir, "Person.schema.json"))
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		code, _, stderr := runExport(&fakeLedger{documents: documents}, filepath.Join(t.TempDir(), "missing"), "Person")
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, "Failed to export the tables: ")
+	})
+}
+
+func TestParseFormat(t *testing.T) {
+	format, extension, ok := parseFormat("CSV")
+	assert.True(t, ok)
+	assert.Equal(t, qldbdriver.ExportCSV, format)
+	assert.Equal(t, ".csv", extension)
+	_, _, ok = parseFormat("xml")
+	assert.False(t, ok)
+}
@@ -0,0 +1,84 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+// Command qldbexport writes a snapshot of the tables of an Amazon QLDB ledger to files, for analytics pipelines that
+// need periodic full exports. Each table is read in its own transaction and written to <table>.ion, <table>.jsonl or
+// <table>.csv, along with the schema inferred from its documents in <table>.schema.json. A table that cannot be read
+// within the maximum duration of a QLDB transaction cannot be exported. Parquet is not supported, since the driver
+// module does not depend on a Parquet encoder; convert the JSON Lines or CSV files with their schema to produce Parquet
+// files.
+//
+// Usage:
+//
+//	qldbexport -ledger <name> [-region <region>] [-format ion|json|csv] [-tables <table,...>] [-out <directory>]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver"
+)
+
+func main() {
+	os.Exit(run())
+}
+
+// run exports the tables and returns the exit code of the command, so that the deferred shutdown of the driver runs
+// before the command exits.
+func run() int {
+	ledgerName := flag.String("ledger", "", "The name of the ledger to export (required).")
+	region := flag.String("region", "", "The AWS region of the ledger. Default: the region of the AWS configuration.")
+	format := flag.String("format", "json", "The format of the exported documents: ion, json or csv.")
+	tables := flag.String("tables", "", "A comma-separated list of the tables to export. Default: all the active tables.")
+	out := flag.String("out", ".", "The directory the files are written to.")
+	flag.Parse()
+
+	if *ledgerName == "" {
+		fmt.Fprintln(os.Stderr, "The -ledger flag is required.")
+		flag.Usage()
+		return 2
+	}
+	exportFormat, extension, ok := parseFormat(*format)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown export format '%s'.\n", *format)
+		return 2
+	}
+
+	ctx := context.Background()
+	var loadOptions []func(*config.LoadOptions) error
+	if *region != "" {
+		loadOptions = append(loadOptions, config.WithRegion(*region))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, loadOptions...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load the AWS configuration: %v\n", err)
+		return 1
+	}
+
+	driver, err := qldbdriver.New(*ledgerName, qldbsession.NewFromConfig(cfg), func(options *qldbdriver.DriverOptions) {
+		options.LoggerVerbosity = qldbdriver.LogOff
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create the driver: %v\n", err)
+		return 1
+	}
+	defer driver.Shutdown(ctx)
+
+	exp := &exporter{ledger: driver, format: exportFormat, extension: extension, dir: *out, stdout: os.Stdout, stderr: os.Stderr}
+	return exp.run(ctx, *tables)
+}
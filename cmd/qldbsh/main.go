@@ -0,0 +1,80 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+// Command qldbsh is an interactive PartiQL shell for Amazon QLDB, built on the QLDB Go driver.
+//
+// Usage:
+//
+//	qldbsh -ledger <name> [-region <region>] [-format ion|json|csv]
+//
+// Statements are executed in their own transaction unless a transaction was started with the begin command. Enter
+// help at the prompt for the list of commands.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver"
+)
+
+func main() {
+	ledgerName := flag.String("ledger", "", "The name of the ledger to connect to (required).")
+	region := flag.String("region", "", "The AWS region of the ledger. Default: the region of the AWS configuration.")
+	format := flag.String("format", "ion", "The output format of results: ion, json or csv.")
+	flag.Parse()
+
+	if *ledgerName == "" {
+		fmt.Fprintln(os.Stderr, "The -ledger flag is required.")
+		flag.Usage()
+		os.Exit(2)
+	}
+	outputFormat, ok := parseFormat(*format)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown output format '%s'.\n", *format)
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+	var loadOptions []func(*config.LoadOptions) error
+	if *region != "" {
+		loadOptions = append(loadOptions, config.WithRegion(*region))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, loadOptions...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load the AWS configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	driver, err := qldbdriver.New(*ledgerName, qldbsession.NewFromConfig(cfg), func(options *qldbdriver.DriverOptions) {
+		options.LoggerVerbosity = qldbdriver.LogOff
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create the driver: %v\n", err)
+		os.Exit(1)
+	}
+	defer driver.Shutdown(ctx)
+
+	err = driver.Validate(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	sh := newShell(driver, os.Stdout, outputFormat)
+	sh.run(ctx, os.Stdin)
+}
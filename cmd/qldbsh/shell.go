@@ -0,0 +1,239 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver"
+)
+
+const helpText = `Commands:
+  begin                 Start a transaction. Statements are executed in it until commit or abort.
+  commit                Commit the current transaction.
+  abort                 Abort the current transaction.
+  show tables           List the active tables of the ledger.
+  format ion|json|csv   Set the output format of results.
+  help                  Show this help.
+  exit, quit            Leave the shell, aborting the current transaction.
+Any other input is executed as a PartiQL statement. A trailing ';' is optional.
+`
+
+// ledger is the part of the driver used by the shell.
+type ledger interface {
+	Execute(ctx context.Context, fn func(txn qldbdriver.Transaction) (interface{}, error), optFns ...func(*qldbdriver.ExecuteOptions)) (interface{}, error)
+	Export(ctx context.Context, w io.Writer, format qldbdriver.ExportFormat, statement string, parameters ...interface{}) (int, error)
+	GetTableNames(ctx context.Context) ([]string, error)
+}
+
+type shell struct {
+	ledger ledger
+	out    io.Writer
+	format qldbdriver.ExportFormat
+	txn    *interactiveTransaction
+}
+
+func newShell(ledger ledger, out io.Writer, format qldbdriver.ExportFormat) *shell {
+	return &shell{ledger: ledger, out: out, format: format}
+}
+
+func parseFormat(name string) (qldbdriver.ExportFormat, bool) {
+	switch strings.ToLower(name) {
+	case "ion":
+		return qldbdriver.ExportIonText, true
+	case "json":
+		return qldbdriver.ExportJSONLines, true
+	case "csv":
+		return qldbdriver.ExportCSV, true
+	}
+	return 0, false
+}
+
+// run reads commands and statements from in until it is exhausted or the user exits.
+func (sh *shell) run(ctx context.Context, in io.Reader) {
+	scanner := bufio.NewScanner(in)
+	for {
+		if sh.txn != nil {
+			fmt.Fprint(sh.out, "qldbsh*> ")
+		} else {
+			fmt.Fprint(sh.out, "qldbsh> ")
+		}
+		if !scanner.Scan() {
+			fmt.Fprintln(sh.out)
+			break
+		}
+		if !sh.handle(ctx, scanner.Text()) {
+			break
+		}
+	}
+	if sh.txn != nil {
+		sh.txn.finish(false)
+	}
+}
+
+// handle executes a line of input and returns false if the shell should exit.
+func (sh *shell) handle(ctx context.Context, line string) bool {
+	line = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(line), ";"))
+	command := strings.ToLower(strings.Join(strings.Fields(line), " "))
+	switch {
+	case line == "":
+	case command == "exit" || command == "quit":
+		return false
+	case command == "help":
+		fmt.Fprint(sh.out, helpText)
+	case command == "begin":
+		if sh.txn != nil {
+			fmt.Fprintln(sh.out, "A transaction is already in progress.")
+		} else {
+			sh.txn = beginInteractiveTransaction(ctx, sh.ledger, sh.out)
+		}
+	case command == "commit" || command == "abort":
+		if sh.txn == nil {
+			fmt.Fprintln(sh.out, "No transaction in progress.")
+			break
+		}
+		err := sh.txn.finish(command == "commit")
+		sh.txn = nil
+		switch {
+		case command == "abort":
+			fmt.Fprintln(sh.out, "Transaction aborted.")
+		case err != nil:
+			fmt.Fprintf(sh.out, "Commit failed: %v\n", err)
+		default:
+			fmt.Fprintln(sh.out, "Transaction committed.")
+		}
+	case command == "show tables":
+		tables, err := sh.ledger.GetTableNames(ctx)
+		if err != nil {
+			fmt.Fprintf(sh.out, "Error: %v\n", err)
+			break
+		}
+		for _, table := range tables {
+			fmt.Fprintln(sh.out, table)
+		}
+	case strings.HasPrefix(command, "format "):
+		format, ok := parseFormat(strings.TrimPrefix(command, "format "))
+		if !ok {
+			fmt.Fprintln(sh.out, "Unknown output format. Use ion, json or csv.")
+			break
+		}
+		sh.format = format
+	default:
+		sh.execute(ctx, line)
+	}
+	return true
+}
+
+// execute runs a statement in the current transaction, or in a transaction of its own.
+func (sh *shell) execute(ctx context.Context, statement string) {
+	if sh.txn == nil {
+		_, err := sh.ledger.Export(ctx, sh.out, sh.format, statement)
+		if err != nil {
+			fmt.Fprintf(sh.out, "Error: %v\n", err)
+		}
+		return
+	}
+
+	err := sh.txn.execute(statement, sh.format)
+	if err != nil {
+		fmt.Fprintf(sh.out, "Error: %v\n", err)
+		sh.txn.wait()
+		sh.txn = nil
+		fmt.Fprintln(sh.out, "Transaction aborted.")
+	}
+}
+
+// errNotRetried is returned when the driver retries an interactive transaction, since its statements cannot be
+// replayed.
+var errNotRetried = errors.New("the transaction failed and must be entered again")
+
+type request struct {
+	statement string
+	format    qldbdriver.ExportFormat
+	commit    bool
+	abort     bool
+	reply     chan error
+}
+
+// interactiveTransaction keeps a transaction open while its statements are entered, by running QLDBDriver.Execute
+// with a function that executes statements as they are received.
+type interactiveTransaction struct {
+	requests chan *request
+	done     chan error
+}
+
+func beginInteractiveTransaction(ctx context.Context, ledger ledger, out io.Writer) *interactiveTransaction {
+	it := &interactiveTransaction{requests: make(chan *request), done: make(chan error, 1)}
+	go func() {
+		attempts := 0
+		_, err := ledger.Execute(ctx, func(txn qldbdriver.Transaction) (interface{}, error) {
+			attempts++
+			if attempts > 1 {
+				return nil, errNotRetried
+			}
+			for req := range it.requests {
+				switch {
+				case req.commit:
+					return nil, nil
+				case req.abort:
+					return nil, txn.Abort()
+				}
+				_, err := qldbdriver.ExportStatement(txn, out, req.format, req.statement)
+				req.reply <- err
+				if err != nil {
+					return nil, err
+				}
+			}
+			return nil, txn.Abort()
+		})
+		it.done <- err
+	}()
+	return it
+}
+
+// execute runs a statement in the transaction. The transaction is over if an error is returned.
+func (it *interactiveTransaction) execute(statement string, format qldbdriver.ExportFormat) error {
+	req := &request{statement: statement, format: format, reply: make(chan error, 1)}
+	select {
+	case it.requests <- req:
+		return <-req.reply
+	case err := <-it.done:
+		// The transaction ended on its own, for example because it could not be started
+		it.done <- err
+		if err == nil {
+			err = errNotRetried
+		}
+		return err
+	}
+}
+
+// finish commits or aborts the transaction and returns the result of committing it.
+func (it *interactiveTransaction) finish(commit bool) error {
+	select {
+	case err := <-it.done:
+		return err
+	case it.requests <- &request{commit: commit, abort: !commit}:
+	}
+	return it.wait()
+}
+
+// wait returns the result of the transaction once it is over.
+func (it *interactiveTransaction) wait() error {
+	return <-it.done
+}
@@ -0,0 +1,170 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/amzn/ion-go/ion"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTransaction struct {
+	qldbdriver.Transaction
+	rows       [][]byte
+	statements []string
+	err        error
+}
+
+func (txn *fakeTransaction) Execute(statement string, parameters ...interface{}) (qldbdriver.Result, error) {
+	txn.statements = append(txn.statements, statement)
+	if txn.err != nil {
+		return nil, txn.err
+	}
+	return &fakeResult{rows: txn.rows, index: -1}, nil
+}
+
+type fakeResult struct {
+	qldbdriver.Result
+	rows  [][]byte
+	index int
+}
+
+func (result *fakeResult) Next(txn qldbdriver.Transaction) bool {
+	result.index++
+	return result.index < len(result.rows)
+}
+
+func (result *fakeResult) GetCurrentData() []byte {
+	return result.rows[result.index]
+}
+
+func (result *fakeResult) Err() error {
+	return nil
+}
+
+func (txn *fakeTransaction) Abort() error {
+	return errors.New("transaction aborted")
+}
+
+type fakeLedger struct {
+	txn       *fakeTransaction
+	committed int
+	aborted   int
+	exported  []string
+	startErr  error
+}
+
+func (ledger *fakeLedger) Execute(ctx context.Context, fn func(txn qldbdriver.Transaction) (interface{}, error), optFns ...func(*qldbdriver.ExecuteOptions)) (interface{}, error) {
+	if ledger.startErr != nil {
+		return nil, ledger.startErr
+	}
+	result, err := fn(ledger.txn)
+	if err != nil {
+		ledger.aborted++
+		return nil, err
+	}
+	ledger.committed++
+	return result, nil
+}
+
+func (ledger *fakeLedger) Export(ctx context.Context, w io.Writer, format qldbdriver.ExportFormat, statement string, parameters ...interface{}) (int, error) {
+	ledger.exported = append(ledger.exported, statement)
+	return qldbdriver.ExportStatement(ledger.txn, w, format, statement, parameters...)
+}
+
+func (ledger *fakeLedger) GetTableNames(ctx context.Context) ([]string, error) {
+	return []string{"Person", "Vehicle"}, nil
+}
+
+func TestShell(t *testing.T) {
+	row, err := ion.MarshalBinary(map[string]interface{}{"Name": "Jane"})
+	require.NoError(t, err)
+	runShell := func(ledger *fakeLedger, input string) string {
+		out := bytes.Buffer{}
+		newShell(ledger, &out, qldbdriver.ExportIonText).run(context.Background(), strings.NewReader(input))
+		return out.String()
+	}
+
+	t.Run("autocommit statement", func(t *testing.T) {
+		ledger := &fakeLedger{txn: &fakeTransaction{rows: [][]byte{row}}}
+
+		out := runShell(ledger, "SELECT * FROM Person;\nformat json\nSELECT * FROM Person\n")
+		assert.Equal(t, "qldbsh> {Name:\"Jane\"}\nqldbsh> qldbsh> {\"Name\":\"Jane\"}\nqldbsh> \n", out)
+		assert.Equal(t, []string{"SELECT * FROM Person", "SELECT * FROM Person"}, ledger.exported)
+	})
+
+	t.Run("show tables", func(t *testing.T) {
+		out := runShell(&fakeLedger{}, "show  TABLES\nexit\n")
+		assert.Equal(t, "qldbsh> Person\nVehicle\nqldbsh> ", out)
+	})
+
+	t.Run("commit", func(t *testing.T) {
+		ledger := &fakeLedger{txn: &fakeTransaction{rows: [][]byte{row}}}
+
+		out := runShell(ledger, "begin\nINSERT INTO Person ?\nSELECT * FROM Person\ncommit\n")
+		assert.Contains(t, out, "qldbsh*> {Name:\"Jane\"}\n")
+		assert.Contains(t, out, "Transaction committed.")
+		assert.Equal(t, []string{"INSERT INTO Person ?", "SELECT * FROM Person"}, ledger.txn.statements)
+		assert.Equal(t, 1, ledger.committed)
+		assert.Empty(t, ledger.exported)
+	})
+
+	t.Run("abort", func(t *testing.T) {
+		ledger := &fakeLedger{txn: &fakeTransaction{}}
+
+		out := runShell(ledger, "begin\nDELETE FROM Person\nabort\n")
+		assert.Contains(t, out, "Transaction aborted.")
+		assert.Equal(t, 0, ledger.committed)
+		assert.Equal(t, 1, ledger.aborted)
+	})
+
+	t.Run("exit aborts the transaction", func(t *testing.T) {
+		ledger := &fakeLedger{txn: &fakeTransaction{}}
+
+		runShell(ledger, "begin\nDELETE FROM Person\n")
+		assert.Equal(t, 0, ledger.committed)
+		assert.Equal(t, 1, ledger.aborted)
+	})
+
+	t.Run("statement error ends the transaction", func(t *testing.T) {
+		ledger := &fakeLedger{txn: &fakeTransaction{err: errors.New("syntax error")}}
+
+		out := runShell(ledger, "begin\nSELEC\ncommit\n")
+		assert.Contains(t, out, "Error: syntax error\nTransaction aborted.\nqldbsh> No transaction in progress.")
+		assert.Equal(t, 0, ledger.committed)
+	})
+
+	t.Run("transaction that cannot start", func(t *testing.T) {
+		ledger := &fakeLedger{startErr: errors.New("ledger unavailable")}
+
+		out := runShell(ledger, "begin\nSELECT * FROM Person\n")
+		assert.Contains(t, out, "Error: ledger unavailable\nTransaction aborted.")
+	})
+
+	t.Run("invalid commands", func(t *testing.T) {
+		out := runShell(&fakeLedger{}, "commit\nformat xml\nbegin\nbegin\nabort\nhelp\n")
+		assert.Contains(t, out, "No transaction in progress.")
+		assert.Contains(t, out, "Unknown output format. Use ion, json or csv.")
+		assert.Contains(t, out, "A transaction is already in progress.")
+		assert.Contains(t, out, "Commands:")
+	})
+}
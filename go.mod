@@ -7,8 +7,10 @@ require (
 	github.com/amzn/ion-hash-go v1.2.0
 	github.com/aws/aws-sdk-go-v2 v1.22.1
 	github.com/aws/aws-sdk-go-v2/config v1.22.1
+	github.com/aws/aws-sdk-go-v2/credentials v1.15.1
 	github.com/aws/aws-sdk-go-v2/service/qldb v1.18.0
 	github.com/aws/aws-sdk-go-v2/service/qldbsession v1.18.0
+	github.com/aws/aws-sdk-go-v2/service/sts v1.25.0
 	github.com/aws/smithy-go v1.16.0
 	github.com/kr/text v0.2.0 // indirect
 	github.com/stretchr/testify v1.8.4
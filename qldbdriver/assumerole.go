@@ -0,0 +1,80 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// The time before the credentials of an assumed role expire at which they are refreshed, so that a transaction in
+// progress does not fail because its credentials expired.
+const assumedRoleExpiryWindow = time.Minute
+
+// AssumeRole is an IAM role assumed to access a ledger, typically one owned by another AWS account.
+type AssumeRole struct {
+	// The ARN of the role.
+	RoleARN string
+	// The external ID required by the trust policy of the role. Default: "", no external ID.
+	ExternalID string
+	// The name of the sessions of the role, recorded by AWS CloudTrail for the commands sent with its credentials.
+	// Default: a name generated by the SDK.
+	RoleSessionName string
+	// The duration of the credentials of the role. Default: 15m.
+	Duration time.Duration
+}
+
+// AssumeRoleConfig returns a copy of cfg whose credentials are those of the role, retrieved from AWS STS with the
+// credentials of cfg. The credentials are cached and refreshed a minute before they expire. It can be used to create
+// the clients of the AWS services used along with the driver, such as the qldb.Client of
+// DriverOptions.LedgerDescriber, with the same role as NewWithAssumeRole.
+func AssumeRoleConfig(cfg aws.Config, role AssumeRole) (aws.Config, error) {
+	if role.RoleARN == "" {
+		return aws.Config{}, &qldbDriverError{"RoleARN must be provided."}
+	}
+	if role.Duration < 0 {
+		return aws.Config{}, &qldbDriverError{"Duration must be 0 or greater."}
+	}
+	provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), role.RoleARN, func(options *stscreds.AssumeRoleOptions) {
+		if role.ExternalID != "" {
+			options.ExternalID = aws.String(role.ExternalID)
+		}
+		if role.RoleSessionName != "" {
+			options.RoleSessionName = role.RoleSessionName
+		}
+		if role.Duration > 0 {
+			options.Duration = role.Duration
+		}
+	})
+	assumed := cfg.Copy()
+	assumed.Credentials = aws.NewCredentialsCache(provider, func(options *aws.CredentialsCacheOptions) {
+		options.ExpiryWindow = assumedRoleExpiryWindow
+	})
+	return assumed, nil
+}
+
+// NewWithAssumeRole creates a QLDBDriver like NewFromConfig, including its HTTP client, with a qldbsession.Client that
+// sends the commands of the driver with the credentials of the role, as returned by AssumeRoleConfig. The region of cfg
+// must be the region of the ledger. The credentials of the role are refreshed automatically, and can also be refreshed
+// when QLDB rejects them before they expire by setting DriverOptions.RetryWithRefreshedCredentials.
+func NewWithAssumeRole(ledgerName string, cfg aws.Config, role AssumeRole, fns ...func(*DriverOptions)) (*QLDBDriver, error) {
+	assumed, err := AssumeRoleConfig(cfg, role)
+	if err != nil {
+		return nil, err
+	}
+	return NewFromConfig(ledgerName, assumed, fns...)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stsRecorder records the parameters of the requests sent to AWS STS, and fails them.
+type stsRecorder struct {
+	requests []url.Values
+}
+
+func (recorder *stsRecorder) Do(request *http.Request) (*http.Response, error) {
+	body, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		return nil, err
+	}
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+	recorder.requests = append(recorder.requests, values)
+	return nil, errors.New("request not sent")
+}
+
+func TestAssumeRoleConfig(t *testing.T) {
+	newConfig := func(recorder *stsRecorder) aws.Config {
+		return aws.Config{
+			Region:      "us-east-1",
+			Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+			HTTPClient:  recorder,
+		}
+	}
+	roleARN := "arn:aws:iam::123456789012:role/LedgerAccess"
+
+	t.Run("assume role", func(t *testing.T) {
+		recorder := &stsRecorder{}
+		cfg := newConfig(recorder)
+		assumed, err := AssumeRoleConfig(cfg, AssumeRole{
+			RoleARN:         roleARN,
+			ExternalID:      "external",
+			RoleSessionName: "ledger-session",
+			Duration:        time.Hour,
+		})
+		require.NoError(t, err)
+		cache, ok := assumed.Credentials.(*aws.CredentialsCache)
+		require.True(t, ok)
+		assert.Equal(t, cfg.Credentials, newConfig(recorder).Credentials)
+
+		_, err = cache.Retrieve(context.Background())
+		assert.Error(t, err)
+		require.NotEmpty(t, recorder.requests)
+		request := recorder.requests[0]
+		assert.Equal(t, "AssumeRole", request.Get("Action"))
+		assert.Equal(t, roleARN, request.Get("RoleArn"))
+		assert.Equal(t, "external", request.Get("ExternalId"))
+		assert.Equal(t, "ledger-session", request.Get("RoleSessionName"))
+		assert.Equal(t, "3600", request.Get("DurationSeconds"))
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		recorder := &stsRecorder{}
+		assumed, err := AssumeRoleConfig(newConfig(recorder), AssumeRole{RoleARN: roleARN})
+		require.NoError(t, err)
+
+		_, err = assumed.Credentials.Retrieve(context.Background())
+		assert.Error(t, err)
+		require.NotEmpty(t, recorder.requests)
+		request := recorder.requests[0]
+		_, hasExternalID := request["ExternalId"]
+		assert.False(t, hasExternalID)
+		assert.NotEmpty(t, request.Get("RoleSessionName"))
+		assert.Equal(t, "900", request.Get("DurationSeconds"))
+	})
+
+	t.Run("invalid role", func(t *testing.T) {
+		_, err := AssumeRoleConfig(newConfig(&stsRecorder{}), AssumeRole{})
+		assert.Error(t, err)
+		_, err = AssumeRoleConfig(newConfig(&stsRecorder{}), AssumeRole{RoleARN: roleARN, Duration: -time.Second})
+		assert.Error(t, err)
+	})
+}
+
+func TestNewWithAssumeRole(t *testing.T) {
+	cfg := aws.Config{Region: "us-east-1", Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", "")}
+	createdDriver, err := NewWithAssumeRole(mockLedgerName, cfg, AssumeRole{RoleARN: "arn:aws:iam::123456789012:role/LedgerAccess"}, func(options *DriverOptions) {
+		options.LoggerVerbosity = LogOff
+	})
+	require.NoError(t, err)
+	defer createdDriver.Shutdown(context.Background())
+	assert.Equal(t, mockLedgerName, createdDriver.ledgerName)
+
+	_, err = NewWithAssumeRole(mockLedgerName, cfg, AssumeRole{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,151 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"sync"
+)
+
+// ExecuteResult is the outcome of a function executed by QLDBDriver.ExecuteAsync or by an ExecutionPool.
+type ExecuteResult struct {
+	// The identifier the function was submitted with to ExecutionPool.Submit, or "" for QLDBDriver.ExecuteAsync.
+	ID string
+	// The value returned by QLDBDriver.Execute.
+	Value interface{}
+	// The error returned by QLDBDriver.Execute.
+	Err error
+}
+
+// ExecuteAsync executes fn like Execute in a new goroutine, and sends its outcome on the returned channel, which is
+// then closed. The channel has room for the outcome, so the goroutine ends even if the outcome is never received.
+func (driver *QLDBDriver) ExecuteAsync(ctx context.Context, fn func(txn Transaction) (interface{}, error), optFns ...func(*ExecuteOptions)) <-chan ExecuteResult {
+	results := make(chan ExecuteResult, 1)
+	go func() {
+		defer close(results)
+		value, err := driver.Execute(ctx, fn, optFns...)
+		results <- ExecuteResult{Value: value, Err: err}
+	}()
+	return results
+}
+
+// ExecutionPoolOptions can be used to configure an ExecutionPool created by QLDBDriver.NewExecutionPool.
+type ExecutionPoolOptions struct {
+	// The number of functions executed at the same time. Unless the driver uses PoolExhaustionBlock, it should leave
+	// enough sessions to the other transactions of the driver, which otherwise fail with a MaxConcurrentTransactions
+	// error. Default: MaxConcurrentTransactions.
+	Workers int
+	// The number of submitted functions waiting for a worker, above which Submit blocks. It is also the number of
+	// outcomes waiting to be received from Results, above which the workers block. Default: Workers.
+	QueueSize int
+}
+
+// ExecutionPool executes the functions submitted to it with QLDBDriver.Execute on a bounded number of workers, and
+// sends their outcomes on the channel returned by Results, in the order the executions complete. The outcomes must be
+// received, or the workers stop once the channel is full.
+type ExecutionPool struct {
+	driver  *QLDBDriver
+	jobs    chan executionJob
+	results chan ExecuteResult
+	workers sync.WaitGroup
+	// lock guards closed, and is held for reading by Submit, so that jobs is not closed while a function is queued.
+	lock   sync.RWMutex
+	closed bool
+}
+
+type executionJob struct {
+	ctx    context.Context
+	id     string
+	fn     func(txn Transaction) (interface{}, error)
+	optFns []func(*ExecuteOptions)
+}
+
+// NewExecutionPool starts the workers of an ExecutionPool executing transactions with the driver. Call Close to stop
+// them.
+func (driver *QLDBDriver) NewExecutionPool(optFns ...func(*ExecutionPoolOptions)) (*ExecutionPool, error) {
+	options := &ExecutionPoolOptions{}
+	for _, fn := range optFns {
+		fn(options)
+	}
+	if options.Workers < 0 {
+		return nil, &qldbDriverError{"Workers must be 0 or greater."}
+	}
+	if options.QueueSize < 0 {
+		return nil, &qldbDriverError{"QueueSize must be 0 or greater."}
+	}
+	if options.Workers == 0 {
+		options.Workers = driver.poolOf(nil).maxConcurrentTransactions
+	}
+	if options.QueueSize == 0 {
+		options.QueueSize = options.Workers
+	}
+
+	pool := &ExecutionPool{
+		driver:  driver,
+		jobs:    make(chan executionJob, options.QueueSize),
+		results: make(chan ExecuteResult, options.QueueSize),
+	}
+	pool.workers.Add(options.Workers)
+	for i := 0; i < options.Workers; i++ {
+		go pool.work()
+	}
+	return pool, nil
+}
+
+func (pool *ExecutionPool) work() {
+	defer pool.workers.Done()
+	for job := range pool.jobs {
+		value, err := pool.driver.Execute(job.ctx, job.fn, job.optFns...)
+		pool.results <- ExecuteResult{ID: job.id, Value: value, Err: err}
+	}
+}
+
+// Submit queues fn to be executed like Execute with ctx, and its outcome to be sent on Results with the identifier
+// id. It blocks while the queue is full, and returns the error of ctx if it is done before fn could be queued, or an
+// error if the pool is closed.
+func (pool *ExecutionPool) Submit(ctx context.Context, id string, fn func(txn Transaction) (interface{}, error), optFns ...func(*ExecuteOptions)) error {
+	pool.lock.RLock()
+	defer pool.lock.RUnlock()
+	if pool.closed {
+		return &qldbDriverError{"The ExecutionPool is closed."}
+	}
+	select {
+	case pool.jobs <- executionJob{ctx: ctx, id: id, fn: fn, optFns: optFns}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Results returns the channel of the outcomes of the submitted functions. It is closed by Close once every submitted
+// function has been executed.
+func (pool *ExecutionPool) Results() <-chan ExecuteResult {
+	return pool.results
+}
+
+// Close stops accepting submissions, and waits for the submitted functions to be executed before closing Results. The
+// outcomes must keep being received meanwhile, from another goroutine. Calling Close again has no effect.
+func (pool *ExecutionPool) Close() {
+	pool.lock.Lock()
+	if pool.closed {
+		pool.lock.Unlock()
+		return
+	}
+	pool.closed = true
+	close(pool.jobs)
+	pool.lock.Unlock()
+
+	pool.workers.Wait()
+	close(pool.results)
+}
@@ -0,0 +1,150 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteAsync(t *testing.T) {
+	newDriver := func() *QLDBDriver {
+		return newMockDriver(t, &qldbsessioniface.MockClientAPI{
+			SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+				return qldbsessioniface.DefaultSendCommandOutput(params), nil
+			},
+		})
+	}
+
+	t.Run("execute async", func(t *testing.T) {
+		driver := newDriver()
+		results := driver.ExecuteAsync(context.Background(), func(txn Transaction) (interface{}, error) {
+			return "done", nil
+		})
+		result, ok := <-results
+		require.True(t, ok)
+		assert.NoError(t, result.Err)
+		assert.Equal(t, "done", result.Value)
+		_, ok = <-results
+		assert.False(t, ok)
+
+		results = driver.ExecuteAsync(context.Background(), func(txn Transaction) (interface{}, error) {
+			return nil, errMock
+		})
+		result = <-results
+		assert.True(t, errors.Is(result.Err, errMock))
+	})
+
+	t.Run("pool executes submissions", func(t *testing.T) {
+		driver := newDriver()
+		pool, err := driver.NewExecutionPool(func(options *ExecutionPoolOptions) {
+			options.Workers = 2
+			options.QueueSize = 1
+		})
+		require.NoError(t, err)
+
+		var running, peak int32
+		received := make(chan []ExecuteResult)
+		go func() {
+			var results []ExecuteResult
+			for result := range pool.Results() {
+				results = append(results, result)
+			}
+			received <- results
+		}()
+		for i := 0; i < 6; i++ {
+			require.NoError(t, pool.Submit(context.Background(), strconv.Itoa(i), func(txn Transaction) (interface{}, error) {
+				current := atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+				for {
+					previous := atomic.LoadInt32(&peak)
+					if current <= previous || atomic.CompareAndSwapInt32(&peak, previous, current) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				return txn.ID(), nil
+			}))
+		}
+		pool.Close()
+		pool.Close()
+
+		results := <-received
+		require.Len(t, results, 6)
+		ids := make([]string, len(results))
+		for i, result := range results {
+			assert.NoError(t, result.Err)
+			assert.NotEmpty(t, result.Value)
+			ids[i] = result.ID
+		}
+		sort.Strings(ids)
+		assert.Equal(t, []string{"0", "1", "2", "3", "4", "5"}, ids)
+		assert.LessOrEqual(t, atomic.LoadInt32(&peak), int32(2))
+
+		err = pool.Submit(context.Background(), "late", func(txn Transaction) (interface{}, error) { return nil, nil })
+		assert.Error(t, err)
+	})
+
+	t.Run("submit waits for room in the queue", func(t *testing.T) {
+		driver := newDriver()
+		pool, err := driver.NewExecutionPool(func(options *ExecutionPoolOptions) {
+			options.Workers = 1
+			options.QueueSize = 1
+		})
+		require.NoError(t, err)
+
+		release := make(chan struct{})
+		blocked := func(txn Transaction) (interface{}, error) {
+			<-release
+			return nil, nil
+		}
+		require.NoError(t, pool.Submit(context.Background(), "running", blocked))
+		require.NoError(t, pool.Submit(context.Background(), "queued", blocked))
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		err = pool.Submit(ctx, "rejected", blocked)
+		assert.Equal(t, context.DeadlineExceeded, err)
+
+		close(release)
+		go pool.Close()
+		count := 0
+		for range pool.Results() {
+			count++
+		}
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		driver := newDriver()
+		_, err := driver.NewExecutionPool(func(options *ExecutionPoolOptions) { options.Workers = -1 })
+		assert.Error(t, err)
+		_, err = driver.NewExecutionPool(func(options *ExecutionPoolOptions) { options.QueueSize = -1 })
+		assert.Error(t, err)
+
+		pool, err := driver.NewExecutionPool()
+		require.NoError(t, err)
+		assert.Equal(t, 10, cap(pool.jobs))
+		pool.Close()
+	})
+}
@@ -0,0 +1,199 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/amzn/ion-go/ion"
+)
+
+// FieldChangeKind describes how a top-level field of a document changed between two revisions.
+type FieldChangeKind int
+
+const (
+	// FieldAdded means that the field is present in the revision but not in the previous one.
+	FieldAdded FieldChangeKind = iota
+	// FieldRemoved means that the field is present in the previous revision but not in the revision.
+	FieldRemoved
+	// FieldModified means that the field is present in both revisions with different values.
+	FieldModified
+)
+
+// FieldChange is the change of a top-level field of a document between two consecutive revisions.
+type FieldChange struct {
+	// The name of the field.
+	Field string
+	// How the field changed.
+	Kind FieldChangeKind
+	// The value of the field in the previous revision in Ion format, or nil if the field was added.
+	OldValue []byte
+	// The value of the field in the revision in Ion format, or nil if the field was removed.
+	NewValue []byte
+}
+
+// AuditRevision is a revision of a document, along with the changes it made to the previous revision.
+type AuditRevision struct {
+	// The version number of the revision.
+	Version int64
+	// The ID of the transaction that committed the revision. QLDB does not record the identity of the caller in the
+	// revision, so applications that need it should write it to the document or correlate the transaction ID with
+	// their own logs or with AWS CloudTrail.
+	TxID string
+	// The time at which the revision was committed to the journal.
+	TxTime time.Time
+	// True if the revision records the deletion of the document.
+	Deleted bool
+	// The changes to the top-level fields of the document, ordered by field name. For the first revision every field
+	// is added, and for a deletion every field is removed.
+	Changes []FieldChange
+	// The full revision.
+	Document *Document
+}
+
+// DocumentAudit is the ordered revision history of a document, as returned by QLDBDriver.GetDocumentAudit.
+type DocumentAudit struct {
+	// The ID of the document.
+	DocumentID string
+	// The revisions of the document, ordered by version.
+	Revisions []*AuditRevision
+}
+
+// GetDocumentAudit returns the full revision history of a document, queried with the history function in a new
+// transaction, along with the fields changed by each revision. It returns a DocumentAudit without revisions if the
+// table has no document with the given ID.
+func (driver *QLDBDriver) GetDocumentAudit(ctx context.Context, tableName string, documentID string) (*DocumentAudit, error) {
+	if !tableNameRegex.MatchString(tableName) {
+		return nil, &qldbDriverError{"Invalid table name: '" + tableName + "'."}
+	}
+
+	statement := "SELECT * FROM history(" + tableName + ") AS h WHERE h.metadata.id = ?"
+	result, err := driver.Execute(ctx, func(txn Transaction) (interface{}, error) {
+		documents := make([]*Document, 0)
+		err := ExecuteStream(txn, statement, func(ionBinary []byte) error {
+			document := NewDocument(ionBinary)
+			if _, err := document.getMetadata(); err != nil {
+				return err
+			}
+			documents = append(documents, document)
+			return nil
+		}, documentID)
+		if err != nil {
+			return nil, err
+		}
+		return documents, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	documents := result.([]*Document)
+	sort.Slice(documents, func(i, j int) bool {
+		return documents[i].metadata.Version < documents[j].metadata.Version
+	})
+	audit := &DocumentAudit{DocumentID: documentID, Revisions: make([]*AuditRevision, 0, len(documents))}
+	previousFields := map[string][]byte{}
+	for _, document := range documents {
+		fields := map[string][]byte{}
+		if document.data != nil {
+			fields, err = topLevelFields(document.data)
+			if err != nil {
+				return nil, err
+			}
+		}
+		changes, err := diffFields(previousFields, fields)
+		if err != nil {
+			return nil, err
+		}
+		audit.Revisions = append(audit.Revisions, &AuditRevision{
+			Version:  document.metadata.Version,
+			TxID:     document.metadata.TxID,
+			TxTime:   document.metadata.TxTime,
+			Deleted:  document.data == nil,
+			Changes:  changes,
+			Document: document,
+		})
+		previousFields = fields
+	}
+	return audit, nil
+}
+
+// topLevelFields returns the fields of an Ion struct in Ion binary format, by field name.
+func topLevelFields(ionBinary []byte) (map[string][]byte, error) {
+	reader := ion.NewReaderBytes(ionBinary)
+	if !reader.Next() || reader.Type() != ion.StructType || reader.IsNull() {
+		if reader.Err() != nil {
+			return nil, reader.Err()
+		}
+		return nil, &qldbDriverError{"Document data must be an Ion struct."}
+	}
+	err := reader.StepIn()
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string][]byte{}
+	for reader.Next() {
+		fieldName, err := reader.FieldName()
+		if err != nil {
+			return nil, err
+		}
+		if fieldName == nil || fieldName.Text == nil {
+			continue
+		}
+		fields[*fieldName.Text], err = ionValueToBinary(reader)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if reader.Err() != nil {
+		return nil, reader.Err()
+	}
+	return fields, reader.StepOut()
+}
+
+// diffFields returns the changes from the previous fields to the current fields, ordered by field name. Values are
+// compared by Ion hash, so that struct values with reordered fields are not reported as modified.
+func diffFields(previous map[string][]byte, current map[string][]byte) ([]FieldChange, error) {
+	changes := make([]FieldChange, 0)
+	for field, oldValue := range previous {
+		newValue, ok := current[field]
+		if !ok {
+			changes = append(changes, FieldChange{Field: field, Kind: FieldRemoved, OldValue: oldValue})
+			continue
+		}
+		oldHash, err := ionBinaryToQLDBHash(oldValue)
+		if err != nil {
+			return nil, err
+		}
+		newHash, err := ionBinaryToQLDBHash(newValue)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(oldHash.hash, newHash.hash) {
+			changes = append(changes, FieldChange{Field: field, Kind: FieldModified, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+	for field, newValue := range current {
+		if _, ok := previous[field]; !ok {
+			changes = append(changes, FieldChange{Field: field, Kind: FieldAdded, NewValue: newValue})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+	return changes, nil
+}
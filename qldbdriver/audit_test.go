@@ -0,0 +1,128 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amzn/ion-go/ion"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDocumentAudit(t *testing.T) {
+	newTestDriver := func(revisions ...string) (*QLDBDriver, *qldbsessioniface.MockClientAPI) {
+		values := make([]types.ValueHolder, len(revisions))
+		for i, revision := range revisions {
+			values[i] = types.ValueHolder{IonBinary: ionTextToBinary(t, revision)}
+		}
+		mockClient := &qldbsessioniface.MockClientAPI{
+			SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+				if params.ExecuteStatement != nil {
+					return &qldbsession.SendCommandOutput{ExecuteStatement: &types.ExecuteStatementResult{
+						FirstPage: &types.Page{Values: values},
+					}}, nil
+				}
+				return qldbsessioniface.DefaultSendCommandOutput(params), nil
+			},
+		}
+		return newMockDriver(t, mockClient), mockClient
+	}
+	valueText := func(ionBinary []byte) string {
+		return strings.TrimSpace(ionToText(t, ion.NewReaderBytes(ionBinary)))
+	}
+
+	t.Run("revision chain", func(t *testing.T) {
+		testDriver, mockClient := newTestDriver(
+			`{data: {Name: "Jane", Address: {City: "Seattle", Zip: "98101"}, Age: 30}, metadata: {id: "A", version: 1, txTime: 2021-06-15T11:00:00Z, txId: "t2"}}`,
+			`{data: {Name: "Jane", Age: 30}, metadata: {id: "A", version: 0, txTime: 2021-06-15T10:00:00Z, txId: "t1"}}`,
+			`{data: {Name: "Jane", Address: {Zip: "98101", City: "Seattle"}, Age: 31}, metadata: {id: "A", version: 2, txTime: 2021-06-15T12:00:00Z, txId: "t3"}}`,
+			`{metadata: {id: "A", version: 3, txTime: 2021-06-15T13:00:00Z, txId: "t4"}}`,
+		)
+
+		audit, err := testDriver.GetDocumentAudit(context.Background(), "Person", "A")
+		require.NoError(t, err)
+		assert.Equal(t, "A", audit.DocumentID)
+		require.Len(t, audit.Revisions, 4)
+
+		first := audit.Revisions[0]
+		assert.Equal(t, int64(0), first.Version)
+		assert.Equal(t, "t1", first.TxID)
+		assert.Equal(t, time.Date(2021, 6, 15, 10, 0, 0, 0, time.UTC), first.TxTime.UTC())
+		assert.False(t, first.Deleted)
+		require.Len(t, first.Changes, 2)
+		assert.Equal(t, "Age", first.Changes[0].Field)
+		assert.Equal(t, FieldAdded, first.Changes[0].Kind)
+		assert.Nil(t, first.Changes[0].OldValue)
+		assert.Equal(t, "30", valueText(first.Changes[0].NewValue))
+
+		second := audit.Revisions[1]
+		require.Len(t, second.Changes, 1)
+		assert.Equal(t, "Address", second.Changes[0].Field)
+		assert.Equal(t, FieldAdded, second.Changes[0].Kind)
+
+		// Reordering the fields of Address is not a change
+		third := audit.Revisions[2]
+		require.Len(t, third.Changes, 1)
+		assert.Equal(t, "Age", third.Changes[0].Field)
+		assert.Equal(t, FieldModified, third.Changes[0].Kind)
+		assert.Equal(t, "30", valueText(third.Changes[0].OldValue))
+		assert.Equal(t, "31", valueText(third.Changes[0].NewValue))
+
+		deletion := audit.Revisions[3]
+		assert.True(t, deletion.Deleted)
+		require.Len(t, deletion.Changes, 3)
+		for _, change := range deletion.Changes {
+			assert.Equal(t, FieldRemoved, change.Kind)
+			assert.Nil(t, change.NewValue)
+		}
+
+		var statement string
+		for _, input := range mockClient.Inputs() {
+			if input.ExecuteStatement != nil {
+				statement = *input.ExecuteStatement.Statement
+			}
+		}
+		assert.Equal(t, "SELECT * FROM history(Person) AS h WHERE h.metadata.id = ?", statement)
+	})
+
+	t.Run("unknown document", func(t *testing.T) {
+		testDriver, _ := newTestDriver()
+
+		audit, err := testDriver.GetDocumentAudit(context.Background(), "Person", "A")
+		require.NoError(t, err)
+		assert.Empty(t, audit.Revisions)
+	})
+
+	t.Run("invalid table name", func(t *testing.T) {
+		testDriver, mockClient := newTestDriver()
+
+		_, err := testDriver.GetDocumentAudit(context.Background(), "Person) AS h, Vehicle", "A")
+		assert.Error(t, err)
+		assert.Empty(t, mockClient.Inputs())
+	})
+
+	t.Run("invalid revision", func(t *testing.T) {
+		testDriver, _ := newTestDriver(`{data: {Name: "Jane"}}`)
+
+		_, err := testDriver.GetDocumentAudit(context.Background(), "Person", "A")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,111 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+
+	"github.com/aws/smithy-go"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/errs"
+)
+
+// BadRequestCategory represents why QLDB rejected a statement, as parsed from the message of its error.
+type BadRequestCategory uint8
+
+const (
+	// BadRequestOther is for rejections that do not fall in the other categories, such as creating a table that
+	// already exists.
+	BadRequestOther BadRequestCategory = iota
+	// BadRequestNoSuchTable is for statements referencing a table, or any other variable, that does not exist.
+	BadRequestNoSuchTable
+	// BadRequestNoSuchIndex is for statements referencing an index that does not exist.
+	BadRequestNoSuchIndex
+	// BadRequestSyntax is for statements that are not valid PartiQL.
+	BadRequestSyntax
+	// BadRequestUnsupported is for valid PartiQL statements using a feature that QLDB does not support.
+	BadRequestUnsupported
+)
+
+// String returns a description of the category.
+func (category BadRequestCategory) String() string {
+	switch category {
+	case BadRequestNoSuchTable:
+		return "no such table"
+	case BadRequestNoSuchIndex:
+		return "no such index"
+	case BadRequestSyntax:
+		return "syntax error"
+	case BadRequestUnsupported:
+		return "unsupported operation"
+	}
+	return "bad request"
+}
+
+var (
+	badRequestPositionRegex    = regexp.MustCompile(`(?i)at line (\d+), column (\d+)`)
+	badRequestSyntaxRegex      = regexp.MustCompile(`(?i)(parser|lexer) error`)
+	badRequestNoSuchIndexRegex = regexp.MustCompile(`(?i)no such index|index .* does not exist`)
+	badRequestNoSuchTableRegex = regexp.MustCompile(`(?i)no such (variable|table)|table .* does not exist`)
+	badRequestUnsupportedRegex = regexp.MustCompile(`(?i)not supported|unsupported`)
+)
+
+// BadRequestError is returned when QLDB rejects a statement as invalid, wrapping the error returned by QLDB. Retrying
+// the statement fails again, so applications serving requests can report such errors as client errors when the
+// statement is derived from the request, and as server errors otherwise.
+type BadRequestError struct {
+	// Why the statement was rejected.
+	Category BadRequestCategory
+	// The position in the statement of the error, starting at 1, or 0 when QLDB did not report it.
+	Line   int
+	Column int
+	err    error
+}
+
+// Error returns the message denoting the cause of the error.
+func (e *BadRequestError) Error() string {
+	return "QLDB rejected the statement (" + e.Category.String() + "): " + e.err.Error()
+}
+
+// Unwrap returns the error returned by QLDB.
+func (e *BadRequestError) Unwrap() error {
+	return e.err
+}
+
+// wrapBadRequest wraps err in a BadRequestError if it is a bad request error returned by QLDB, and returns it
+// unchanged otherwise.
+func wrapBadRequest(err error) error {
+	var apiErr smithy.APIError
+	if !errs.IsBadRequest(err) || !errors.As(err, &apiErr) {
+		return err
+	}
+	message := apiErr.ErrorMessage()
+	badRequest := &BadRequestError{err: err}
+	switch {
+	case badRequestSyntaxRegex.MatchString(message):
+		badRequest.Category = BadRequestSyntax
+	case badRequestNoSuchIndexRegex.MatchString(message):
+		badRequest.Category = BadRequestNoSuchIndex
+	case badRequestNoSuchTableRegex.MatchString(message):
+		badRequest.Category = BadRequestNoSuchTable
+	case badRequestUnsupportedRegex.MatchString(message):
+		badRequest.Category = BadRequestUnsupported
+	}
+	if position := badRequestPositionRegex.FindStringSubmatch(message); position != nil {
+		badRequest.Line, _ = strconv.Atoi(position[1])
+		badRequest.Column, _ = strconv.Atoi(position[2])
+	}
+	return badRequest
+}
@@ -0,0 +1,104 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapBadRequest(t *testing.T) {
+	badRequest := func(message string) error {
+		return &types.BadRequestException{Message: &message}
+	}
+
+	testCases := []struct {
+		name             string
+		err              error
+		expectedCategory BadRequestCategory
+		expectedLine     int
+		expectedColumn   int
+	}{
+		{
+			name:             "no such table",
+			err:              badRequest("Semantic Error: at line 1, column 15: No such variable named 'Vehicles'; No such variable named 'Vehicles'"),
+			expectedCategory: BadRequestNoSuchTable,
+			expectedLine:     1,
+			expectedColumn:   15,
+		},
+		{
+			name:             "no such index",
+			err:              badRequest("Index with id: 4o5Uk09OcjC6PpJpLahceE does not exist"),
+			expectedCategory: BadRequestNoSuchIndex,
+		},
+		{
+			name:             "syntax error",
+			err:              badRequest("Parser Error: at line 2, column 8: Unexpected token, expected FROM"),
+			expectedCategory: BadRequestSyntax,
+			expectedLine:     2,
+			expectedColumn:   8,
+		},
+		{
+			name:             "lexer error",
+			err:              badRequest("Lexer Error: at line 1, column 30: invalid character at, '\"' [U+22]"),
+			expectedCategory: BadRequestSyntax,
+			expectedLine:     1,
+			expectedColumn:   30,
+		},
+		{
+			name:             "unsupported operation",
+			err:              badRequest("Semantic Error: at line 1, column 1: Feature 'ORDER BY' is not supported"),
+			expectedCategory: BadRequestUnsupported,
+			expectedLine:     1,
+			expectedColumn:   1,
+		},
+		{
+			name:             "other",
+			err:              &smithy.GenericAPIError{Code: "412", Message: "Table with name: USER.Vehicle already exists"},
+			expectedCategory: BadRequestOther,
+		},
+		{
+			name:             "wrapped",
+			err:              fmt.Errorf("wrapped: %w", badRequest("Parser Error: at line 1, column 1: Unexpected term")),
+			expectedCategory: BadRequestSyntax,
+			expectedLine:     1,
+			expectedColumn:   1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := wrapBadRequest(tc.err)
+
+			var actual *BadRequestError
+			require.True(t, errors.As(err, &actual))
+			assert.Equal(t, tc.expectedCategory, actual.Category)
+			assert.Equal(t, tc.expectedLine, actual.Line)
+			assert.Equal(t, tc.expectedColumn, actual.Column)
+			assert.Equal(t, tc.err, errors.Unwrap(err))
+			assert.Contains(t, err.Error(), tc.expectedCategory.String())
+		})
+	}
+
+	t.Run("other errors are unchanged", func(t *testing.T) {
+		errOther := errors.New("other")
+		assert.Equal(t, errOther, wrapBadRequest(errOther))
+		assert.Equal(t, testOCC, wrapBadRequest(testOCC))
+	})
+}
@@ -0,0 +1,197 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"time"
+
+	"github.com/amzn/ion-go/ion"
+	"github.com/aws/aws-sdk-go-v2/service/qldb"
+	"github.com/aws/aws-sdk-go-v2/service/qldb/types"
+)
+
+// BlockGetter is the subset of the qldb.Client methods used by QLDBDriver.GetBlock.
+type BlockGetter interface {
+	GetBlock(ctx context.Context, params *qldb.GetBlockInput, optFns ...func(*qldb.Options)) (*qldb.GetBlockOutput, error)
+}
+
+var _ BlockGetter = (*qldb.Client)(nil)
+
+// JournalBlock is a block of the journal of a ledger, as returned by QLDBDriver.GetBlock. A block records the
+// revisions written by a transaction, and is chained to the previous block of its strand by its hash.
+type JournalBlock struct {
+	// The location of the block in the journal.
+	BlockAddress BlockAddress `ion:"blockAddress"`
+	// The ID of the transaction that committed the block.
+	TransactionID string `ion:"transactionId"`
+	// The time at which the block was committed to the journal.
+	BlockTimestamp time.Time `ion:"blockTimestamp"`
+	// The SHA-256 hash of the block.
+	BlockHash []byte `ion:"blockHash"`
+	// The SHA-256 hash of the entries of the block, computed from EntriesHashList.
+	EntriesHash []byte `ion:"entriesHash"`
+	// The SHA-256 hash of the previous block of the strand.
+	PreviousBlockHash []byte `ion:"previousBlockHash"`
+	// The hashes of the entries of the block, from which EntriesHash is computed.
+	EntriesHashList [][]byte `ion:"entriesHashList"`
+	// The statements executed by the transaction and the documents they wrote.
+	TransactionInfo *BlockTransactionInfo `ion:"transactionInfo"`
+	// The revisions written by the transaction. The revisions of the system tables and the redacted revisions only
+	// have a hash.
+	Revisions []*Document `ion:"-"`
+	// The hashes needed to recalculate the digest from BlockHash, when a digest tip address was passed to GetBlock.
+	// Otherwise nil.
+	Proof [][]byte `ion:"-"`
+}
+
+// BlockTransactionInfo describes the statements executed by the transaction that committed a JournalBlock.
+type BlockTransactionInfo struct {
+	// The statements executed by the transaction, in the order they were executed.
+	Statements []BlockStatement `ion:"statements"`
+	// The documents written by the transaction, by document ID.
+	Documents map[string]BlockDocumentInfo `ion:"documents"`
+}
+
+// BlockStatement is a statement executed by the transaction that committed a JournalBlock.
+type BlockStatement struct {
+	// The PartiQL statement.
+	Statement string `ion:"statement"`
+	// The time at which the statement started executing.
+	StartTime time.Time `ion:"startTime"`
+	// The SHA-256 hash of the statement and of its parameters.
+	StatementDigest []byte `ion:"statementDigest"`
+}
+
+// BlockDocumentInfo describes a document written by the transaction that committed a JournalBlock.
+type BlockDocumentInfo struct {
+	// The name of the table of the document.
+	TableName string `ion:"tableName"`
+	// The ID of the table of the document.
+	TableID string `ion:"tableId"`
+	// The indexes in BlockTransactionInfo.Statements of the statements that wrote the document.
+	Statements []int `ion:"statements"`
+}
+
+// GetBlock returns the block of the journal of the ledger of the driver at address, read with the GetBlock API of the
+// QLDB control plane, for example to verify a revision or to analyze the transactions of a strand. address can be
+// taken from a revision with Document.GetBlockAddress, or from GetDocumentBlockAddress. When digestTipAddress is not
+// nil, the proof of the block against the digest ending at digestTipAddress is returned in JournalBlock.Proof.
+func (driver *QLDBDriver) GetBlock(ctx context.Context, client BlockGetter, address BlockAddress, digestTipAddress *BlockAddress) (*JournalBlock, error) {
+	if client == nil {
+		return nil, &qldbDriverError{"Provided QLDB client is nil."}
+	}
+	input := &qldb.GetBlockInput{Name: &driver.ledgerName}
+	var err error
+	input.BlockAddress, err = blockAddressValue(address)
+	if err != nil {
+		return nil, err
+	}
+	if digestTipAddress != nil {
+		input.DigestTipAddress, err = blockAddressValue(*digestTipAddress)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	output, err := client.GetBlock(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if output.Block == nil || output.Block.IonText == nil {
+		return nil, &qldbDriverError{"GetBlock returned no block."}
+	}
+	block, err := parseJournalBlock(*output.Block.IonText)
+	if err != nil {
+		return nil, err
+	}
+	if output.Proof != nil && output.Proof.IonText != nil {
+		block.Proof, err = UnmarshalProof(*output.Proof.IonText)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return block, nil
+}
+
+// GetDocumentBlockAddress returns the address of the block containing the latest committed revision of a document, or
+// nil if the table has no such document.
+func GetDocumentBlockAddress(txn Transaction, tableName string, documentID string) (*BlockAddress, error) {
+	document, err := GetCommittedDocument(txn, tableName, documentID)
+	if err != nil || document == nil {
+		return nil, err
+	}
+	return document.GetBlockAddress()
+}
+
+// blockAddressValue returns the Ion text of a block address, as expected by the QLDB control plane.
+func blockAddressValue(address BlockAddress) (*types.ValueHolder, error) {
+	ionText, err := MarshalBlockAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	return &types.ValueHolder{IonText: &ionText}, nil
+}
+
+// parseJournalBlock parses the Ion text of a block returned by the GetBlock API.
+func parseJournalBlock(ionText string) (*JournalBlock, error) {
+	block := &JournalBlock{}
+	err := ion.UnmarshalString(ionText, block)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := ion.NewReaderString(ionText)
+	if !reader.Next() {
+		if reader.Err() != nil {
+			return nil, reader.Err()
+		}
+		return nil, &qldbDriverError{"Journal block is empty."}
+	}
+	err = reader.StepIn()
+	if err != nil {
+		return nil, err
+	}
+	for reader.Next() {
+		fieldName, err := reader.FieldName()
+		if err != nil {
+			return nil, err
+		}
+		if fieldName == nil || fieldName.Text == nil || *fieldName.Text != "revisions" || reader.IsNull() {
+			continue
+		}
+		err = reader.StepIn()
+		if err != nil {
+			return nil, err
+		}
+		for reader.Next() {
+			ionBinary, err := ionValueToBinary(reader)
+			if err != nil {
+				return nil, err
+			}
+			block.Revisions = append(block.Revisions, NewDocument(ionBinary))
+		}
+		if reader.Err() != nil {
+			return nil, reader.Err()
+		}
+		err = reader.StepOut()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if reader.Err() != nil {
+		return nil, reader.Err()
+	}
+	return block, nil
+}
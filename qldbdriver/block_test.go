@@ -0,0 +1,124 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldb"
+	"github.com/aws/aws-sdk-go-v2/service/qldb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBlockGetter returns a block and a proof, and records the input of the last GetBlock call.
+type fakeBlockGetter struct {
+	block string
+	proof *string
+	input *qldb.GetBlockInput
+	err   error
+}
+
+func (getter *fakeBlockGetter) GetBlock(ctx context.Context, params *qldb.GetBlockInput, optFns ...func(*qldb.Options)) (*qldb.GetBlockOutput, error) {
+	getter.input = params
+	if getter.err != nil {
+		return nil, getter.err
+	}
+	output := &qldb.GetBlockOutput{Block: valueHolder(getter.block)}
+	if getter.proof != nil {
+		output.Proof = valueHolder(*getter.proof)
+	}
+	return output, nil
+}
+
+func valueHolder(ionText string) *types.ValueHolder {
+	return &types.ValueHolder{IonText: &ionText}
+}
+
+const testJournalBlock = `{
+  blockAddress: {strandId: "JdxjkR9bSYB5jMHWcI464T", sequenceNo: 1234},
+  transactionId: "D35qctdJRU1L1N2VhxbwSn",
+  blockTimestamp: 2019-10-25T17:20:21.009Z,
+  blockHash: {{WYLOfZClHkFF7g46ZS6rl1XMD2xtyYHSd9YEeZ7Ex2E=}},
+  entriesHash: {{xN9X96atkMvhvF3nEy6jMSVQzKjHJfz1H3bsNeg8GMA=}},
+  previousBlockHash: {{IAfZ0h22ZjvcuHPSBCDy/6XNQTsqEmeY3GW0gBae8mg=}},
+  entriesHashList: [{{F7rQIKCNn0vXVWPexilGfJn5+MCrtsSQqqVdlQxXpS4=}}],
+  transactionInfo: {
+    statements: [{statement: "INSERT INTO Vehicle ?", startTime: 2019-10-25T17:20:20.905Z, statementDigest: {{3jeSdejOgp6spJ8huZxDRUtp2fRXRqpOMtG43V0nXg8=}}}],
+    documents: {'8F0TPCmdNQ6JTRpiLj2TmW': {tableName: "Vehicle", tableId: "LY4HpLy8aGbBHrZdy6ewaI", statements: [0]}}
+  },
+  revisions: [
+    {blockAddress: {strandId: "JdxjkR9bSYB5jMHWcI464T", sequenceNo: 1234}, hash: {{IPG6zjS8HnhbGU3PxZ+SUHAk5fPyBISWoQOQdTTmN4A=}}, data: {VIN: "1N4AL11D75C109151"}, metadata: {id: "8F0TPCmdNQ6JTRpiLj2TmW", version: 0, txTime: 2019-10-25T17:20:20.918Z, txId: "D35qctdJRU1L1N2VhxbwSn"}},
+    {hash: {{gSm3xNQ+QWKObTmP8aTb8kV+4W7ev2bhnPhbzoCvkIw=}}}
+  ]
+}`
+
+func TestGetBlock(t *testing.T) {
+	testDriver := &QLDBDriver{ledgerName: mockLedgerName}
+	address := BlockAddress{StrandID: "JdxjkR9bSYB5jMHWcI464T", SequenceNo: 1234}
+
+	t.Run("block", func(t *testing.T) {
+		getter := &fakeBlockGetter{block: testJournalBlock}
+		block, err := testDriver.GetBlock(context.Background(), getter, address, nil)
+		require.NoError(t, err)
+		assert.Equal(t, mockLedgerName, *getter.input.Name)
+		assert.Contains(t, *getter.input.BlockAddress.IonText, `strandId:"JdxjkR9bSYB5jMHWcI464T"`)
+		assert.Nil(t, getter.input.DigestTipAddress)
+
+		assert.Equal(t, address, block.BlockAddress)
+		assert.Equal(t, "D35qctdJRU1L1N2VhxbwSn", block.TransactionID)
+		assert.True(t, time.Date(2019, 10, 25, 17, 20, 21, 9000000, time.UTC).Equal(block.BlockTimestamp))
+		assert.Len(t, block.BlockHash, 32)
+		assert.Len(t, block.EntriesHash, 32)
+		assert.Len(t, block.PreviousBlockHash, 32)
+		assert.Len(t, block.EntriesHashList, 1)
+		require.NotNil(t, block.TransactionInfo)
+		require.Len(t, block.TransactionInfo.Statements, 1)
+		assert.Equal(t, "INSERT INTO Vehicle ?", block.TransactionInfo.Statements[0].Statement)
+		assert.Equal(t, BlockDocumentInfo{TableName: "Vehicle", TableID: "LY4HpLy8aGbBHrZdy6ewaI", Statements: []int{0}},
+			block.TransactionInfo.Documents["8F0TPCmdNQ6JTRpiLj2TmW"])
+		assert.Nil(t, block.Proof)
+
+		require.Len(t, block.Revisions, 2)
+		id, err := block.Revisions[0].GetID()
+		require.NoError(t, err)
+		assert.Equal(t, "8F0TPCmdNQ6JTRpiLj2TmW", id)
+		hash, err := block.Revisions[1].GetHash()
+		require.NoError(t, err)
+		assert.Len(t, hash, 32)
+		_, err = block.Revisions[1].GetData()
+		assert.Error(t, err)
+	})
+
+	t.Run("proof", func(t *testing.T) {
+		proof := `[{{WYLOfZClHkFF7g46ZS6rl1XMD2xtyYHSd9YEeZ7Ex2E=}}, {{xN9X96atkMvhvF3nEy6jMSVQzKjHJfz1H3bsNeg8GMA=}}]`
+		getter := &fakeBlockGetter{block: testJournalBlock, proof: &proof}
+		tip := BlockAddress{StrandID: "JdxjkR9bSYB5jMHWcI464T", SequenceNo: 2000}
+		block, err := testDriver.GetBlock(context.Background(), getter, address, &tip)
+		require.NoError(t, err)
+		assert.Contains(t, *getter.input.DigestTipAddress.IonText, "sequenceNo:2000")
+		assert.Len(t, block.Proof, 2)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := testDriver.GetBlock(context.Background(), nil, address, nil)
+		assert.Error(t, err)
+		_, err = testDriver.GetBlock(context.Background(), &fakeBlockGetter{err: errMock}, address, nil)
+		assert.Equal(t, errMock, err)
+		_, err = testDriver.GetBlock(context.Background(), &fakeBlockGetter{block: "{blockAddress: 1}"}, address, nil)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,142 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+)
+
+var (
+	documentReadRegex = regexp.MustCompile(`(?is)^\s*SELECT\s.*?\sFROM\s+([A-Za-z_][A-Za-z0-9_]*)(?:\s(.*))?$`)
+	documentIDRegex   = regexp.MustCompile(`(?i)\bBY\s+[A-Za-z_]|\bmetadata\.id\s*=`)
+	writeTargetRegex  = regexp.MustCompile(`(?i)^\s*(?:INSERT\s+INTO|UPDATE|DELETE\s+FROM|FROM)\s+([A-Za-z_][A-Za-z0-9_]*)`)
+	selectRegex       = regexp.MustCompile(`(?i)^\s*SELECT\s`)
+	selectWordRegex   = regexp.MustCompile(`(?i)\bSELECT\b`)
+	joinRegex         = regexp.MustCompile(`(?i)\bJOIN\b`)
+	whereRegex        = regexp.MustCompile(`(?i)\bWHERE\b`)
+)
+
+// documentCache holds the values returned by statements reading documents by ID within a transaction, so that
+// repeated reads of the same documents with the same parameters are not sent to QLDB again. A read of documents by ID
+// is a SELECT of a single table through a BY clause or a condition on metadata.id. The entries of a table are dropped
+// as soon as a statement that is not a SELECT writes to it, and every entry is dropped when the table written to
+// cannot be determined.
+type documentCache struct {
+	entries map[string]*documentCacheEntry
+}
+
+type documentCacheEntry struct {
+	table  string
+	values []types.ValueHolder
+}
+
+func newDocumentCache() *documentCache {
+	return &documentCache{entries: make(map[string]*documentCacheEntry)}
+}
+
+// invalidate drops the entries of the table written by statement, or every entry if the table cannot be determined.
+func (cache *documentCache) invalidate(statement string) {
+	match := writeTargetRegex.FindStringSubmatch(statement)
+	if match == nil {
+		cache.entries = make(map[string]*documentCacheEntry)
+		return
+	}
+	for key, entry := range cache.entries {
+		if entry.table == match[1] {
+			delete(cache.entries, key)
+		}
+	}
+}
+
+// parseDocumentRead returns the table read by statement and whether statement is a SELECT of documents of that table
+// by ID. Statements that may read other tables, through a nested SELECT, a JOIN or a comma in the FROM clause, are not
+// reads of documents by ID, since their cached values would not be invalidated by writes to the other tables.
+func parseDocumentRead(statement string) (string, bool) {
+	match := documentReadRegex.FindStringSubmatch(statement)
+	if match == nil || !documentIDRegex.MatchString(match[2]) {
+		return "", false
+	}
+	if len(selectWordRegex.FindAllStringIndex(statement, 2)) > 1 || joinRegex.MatchString(match[2]) {
+		return "", false
+	}
+	fromClause := match[2]
+	if where := whereRegex.FindStringIndex(fromClause); where != nil {
+		fromClause = fromClause[:where[0]]
+	}
+	if strings.Contains(fromClause, ",") {
+		return "", false
+	}
+	return strings.TrimPrefix(match[1], committedViewPrefix), true
+}
+
+// executeCached executes a statement through the document cache. Reads of documents by ID are served from the cache
+// when the same statement and parameters were executed earlier in the transaction, and statements that are not reads
+// invalidate the cached values of the table they write to.
+func (txn *transaction) executeCached(ctx context.Context, statement string, parameters ...interface{}) (*result, error) {
+	table, isDocumentRead := parseDocumentRead(statement)
+	if !isDocumentRead {
+		if !selectRegex.MatchString(statement) {
+			txn.documentCache.invalidate(statement)
+		}
+		return txn.executeStatement(ctx, statement, parameters...)
+	}
+
+	statementHash, err := toStatementHash(statement, txn.wrapParameters(parameters))
+	if err != nil {
+		return nil, err
+	}
+	key := string(statementHash.hash)
+	if entry, ok := txn.documentCache.entries[key]; ok {
+		txn.logger.logf(LogDebug, "Serving statement from the document cache of transaction %s.", *txn.id)
+		res := &result{
+			ctx:           ctx,
+			communicator:  txn.communicator,
+			txnID:         txn.id,
+			pageValues:    entry.values,
+			logger:        txn.logger,
+			ioUsage:       newIOUsage(0, 0),
+			timingInfo:    newTimingInformation(0),
+			statementHash: statementHash.hash,
+			statement:     statement,
+			paramCount:    len(parameters),
+			sizeLimit:     txn.resultSizeLimit,
+			capabilities:  txn.capabilities,
+		}
+		txn.results = append(txn.results, res)
+		return res, nil
+	}
+
+	res, err := txn.executeStatement(ctx, statement, parameters...)
+	if err != nil {
+		return nil, err
+	}
+	values := append([]types.ValueHolder(nil), res.pageValues...)
+	for res.pageToken != nil {
+		err = res.getNextPage()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, res.pageValues...)
+	}
+	res.pageValues = values
+	res.index = 0
+	// The values are shared with the cache entry.
+	res.releaseRows = false
+	txn.documentCache.entries[key] = &documentCacheEntry{table: table, values: values}
+	return res, nil
+}
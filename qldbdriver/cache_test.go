@@ -0,0 +1,182 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocumentRead(t *testing.T) {
+	testCases := []struct {
+		statement      string
+		table          string
+		isDocumentRead bool
+	}{
+		{"SELECT * FROM Person BY pid WHERE pid = ?", "Person", true},
+		{"select p.name from Person AS p BY pid where pid = ?", "Person", true},
+		{"SELECT * FROM _ql_committed_Person WHERE metadata.id = ?", "Person", true},
+		{"SELECT * FROM Person WHERE name = ?", "", false},
+		{"SELECT * FROM history(Person) AS h WHERE h.metadata.id = ?", "", false},
+		{"UPDATE Person BY pid SET name = ? WHERE pid = ?", "", false},
+		{"SELECT * FROM Person BY pid WHERE pid IN (?, ?)", "Person", true},
+		{"SELECT * FROM Person AS p BY pid, Vehicle AS v WHERE pid = ? AND v.owner = p.name", "", false},
+		{"SELECT * FROM Person AS p BY pid JOIN Vehicle AS v ON v.owner = p.name WHERE pid = ?", "", false},
+		{"SELECT * FROM Person BY pid WHERE pid = ? AND name IN (SELECT owner FROM Vehicle)", "", false},
+		{"SELECT (SELECT v.vin FROM Vehicle AS v) AS vins FROM Person BY pid WHERE pid = ?", "", false},
+	}
+
+	for _, testCase := range testCases {
+		table, isDocumentRead := parseDocumentRead(testCase.statement)
+		assert.Equal(t, testCase.table, table, testCase.statement)
+		assert.Equal(t, testCase.isDocumentRead, isDocumentRead, testCase.statement)
+	}
+}
+
+func TestDocumentCache(t *testing.T) {
+	readStatement := "SELECT * FROM Person BY pid WHERE pid = ?"
+	mockValues := []types.ValueHolder{{IonBinary: []byte{1}}}
+	mockNextValues := []types.ValueHolder{{IonBinary: []byte{2}}}
+	mockNextPageToken := "mockToken"
+	executeResult := types.ExecuteStatementResult{
+		FirstPage: &types.Page{NextPageToken: &mockNextPageToken, Values: mockValues},
+	}
+	fetchPageResult := types.FetchPageResult{Page: &types.Page{Values: mockNextValues}}
+
+	newCachingTransaction := func() (*transaction, *mockTransactionService) {
+		mockHash, _ := toQLDBHash(mockTxnID)
+		mockService := new(mockTransactionService)
+		mockService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&executeResult, nil)
+		mockService.On("fetchPage", mock.Anything, mock.Anything, mock.Anything).Return(&fetchPageResult, nil)
+		return &transaction{
+			communicator:  mockService,
+			id:            &mockTxnID,
+			logger:        &qldbLogger{logger: defaultLogger{}, verbosity: LogOff},
+			commitHash:    mockHash,
+			documentCache: newDocumentCache(),
+		}, mockService
+	}
+
+	readAll := func(res *result) [][]byte {
+		rows := make([][]byte, 0)
+		for res.Next(nil) {
+			rows = append(rows, res.GetCurrentData())
+		}
+		require.NoError(t, res.Err())
+		return rows
+	}
+
+	t.Run("repeated read is served from the cache", func(t *testing.T) {
+		testTransaction, mockService := newCachingTransaction()
+
+		first, err := testTransaction.execute(context.Background(), readStatement, "123")
+		require.NoError(t, err)
+		commitHash := testTransaction.commitHash
+		second, err := testTransaction.execute(context.Background(), readStatement, "123")
+		require.NoError(t, err)
+
+		assert.Equal(t, [][]byte{{1}, {2}}, readAll(first))
+		assert.Equal(t, [][]byte{{1}, {2}}, readAll(second))
+		assert.Equal(t, commitHash, testTransaction.commitHash)
+		assert.Equal(t, 1, testTransaction.statementCount)
+		mockService.AssertNumberOfCalls(t, "executeStatement", 1)
+		mockService.AssertNumberOfCalls(t, "fetchPage", 1)
+	})
+
+	t.Run("cached rows are not released", func(t *testing.T) {
+		testTransaction, _ := newCachingTransaction()
+		testTransaction.releaseConsumedRows = true
+
+		first, err := testTransaction.execute(context.Background(), readStatement, "123")
+		require.NoError(t, err)
+		assert.Equal(t, [][]byte{{1}, {2}}, readAll(first))
+		second, err := testTransaction.execute(context.Background(), readStatement, "123")
+		require.NoError(t, err)
+		assert.Equal(t, [][]byte{{1}, {2}}, readAll(second))
+	})
+
+	t.Run("different parameters are not served from the cache", func(t *testing.T) {
+		testTransaction, mockService := newCachingTransaction()
+
+		_, err := testTransaction.execute(context.Background(), readStatement, "123")
+		require.NoError(t, err)
+		_, err = testTransaction.execute(context.Background(), readStatement, "456")
+		require.NoError(t, err)
+
+		mockService.AssertNumberOfCalls(t, "executeStatement", 2)
+	})
+
+	t.Run("write invalidates the table", func(t *testing.T) {
+		testTransaction, mockService := newCachingTransaction()
+
+		_, err := testTransaction.execute(context.Background(), readStatement, "123")
+		require.NoError(t, err)
+		_, err = testTransaction.execute(context.Background(), "SELECT * FROM Vehicle BY vid WHERE vid = ?", "123")
+		require.NoError(t, err)
+		_, err = testTransaction.execute(context.Background(), "UPDATE Person SET name = ? WHERE pid = ?", "name", "123")
+		require.NoError(t, err)
+		_, err = testTransaction.execute(context.Background(), readStatement, "123")
+		require.NoError(t, err)
+		_, err = testTransaction.execute(context.Background(), "SELECT * FROM Vehicle BY vid WHERE vid = ?", "123")
+		require.NoError(t, err)
+
+		mockService.AssertNumberOfCalls(t, "executeStatement", 4)
+	})
+
+	t.Run("read of several tables is not cached", func(t *testing.T) {
+		testTransaction, mockService := newCachingTransaction()
+		joinStatement := "SELECT * FROM Person AS p BY pid, Vehicle AS v WHERE pid = ? AND v.owner = p.name"
+
+		_, err := testTransaction.execute(context.Background(), joinStatement, "123")
+		require.NoError(t, err)
+		_, err = testTransaction.execute(context.Background(), "UPDATE Vehicle SET owner = ?", "name")
+		require.NoError(t, err)
+		_, err = testTransaction.execute(context.Background(), joinStatement, "123")
+		require.NoError(t, err)
+
+		mockService.AssertNumberOfCalls(t, "executeStatement", 3)
+		assert.Empty(t, testTransaction.documentCache.entries)
+	})
+
+	t.Run("statement without table invalidates everything", func(t *testing.T) {
+		testTransaction, mockService := newCachingTransaction()
+
+		_, err := testTransaction.execute(context.Background(), readStatement, "123")
+		require.NoError(t, err)
+		_, err = testTransaction.execute(context.Background(), "CREATE INDEX ON Person (pid)")
+		require.NoError(t, err)
+		_, err = testTransaction.execute(context.Background(), readStatement, "123")
+		require.NoError(t, err)
+
+		mockService.AssertNumberOfCalls(t, "executeStatement", 3)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		testTransaction, mockService := newCachingTransaction()
+		testTransaction.documentCache = nil
+
+		_, err := testTransaction.execute(context.Background(), readStatement, "123")
+		require.NoError(t, err)
+		_, err = testTransaction.execute(context.Background(), readStatement, "123")
+		require.NoError(t, err)
+
+		mockService.AssertNumberOfCalls(t, "executeStatement", 2)
+		mockService.AssertNotCalled(t, "fetchPage", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,93 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import "sync/atomic"
+
+// Capability represents whether QLDB returns an optional field in the responses to the commands of the driver.
+type Capability uint32
+
+const (
+	// CapabilityUnknown means that no response that could carry the field was received yet.
+	CapabilityUnknown Capability = iota
+	// CapabilitySupported means that at least one response carried the field.
+	CapabilitySupported
+	// CapabilityUnsupported means that responses were received, but none of them carried the field.
+	CapabilityUnsupported
+)
+
+// String returns a description of the capability.
+func (capability Capability) String() string {
+	switch capability {
+	case CapabilitySupported:
+		return "supported"
+	case CapabilityUnsupported:
+		return "unsupported"
+	}
+	return "unknown"
+}
+
+// ServerCapabilities reports which optional fields of the responses to ExecuteStatement and FetchPage commands are
+// returned by QLDB, as detected from the responses received by a driver. The QLDB Session API has no version
+// negotiation, so a field is only known to be supported once a response carried it. While a field is unsupported, the
+// metrics derived from it are reported as such instead of as zeros: IOUsage.Reported returns false for ConsumedIOs,
+// and TimingInformation.Reported for TimingInformation.
+type ServerCapabilities struct {
+	// Whether the responses carry the read and write IOs consumed by statements.
+	ConsumedIOs Capability
+	// Whether the responses carry the server-side processing time of statements.
+	TimingInformation Capability
+}
+
+// serverCapabilities detects the ServerCapabilities of a driver from the responses of its sessions. A nil
+// serverCapabilities detects nothing and reports every capability as unknown.
+type serverCapabilities struct {
+	consumedIOs       uint32
+	timingInformation uint32
+}
+
+// observe records whether a response carried the optional fields.
+func (capabilities *serverCapabilities) observe(consumedIOs bool, timingInformation bool) {
+	if capabilities == nil {
+		return
+	}
+	observeCapability(&capabilities.consumedIOs, consumedIOs)
+	observeCapability(&capabilities.timingInformation, timingInformation)
+}
+
+// observeCapability marks the capability supported when the field was carried, or unsupported when it was not and
+// the capability is still unknown, since a single response carrying the field shows that QLDB supports it.
+func observeCapability(capability *uint32, carried bool) {
+	if carried {
+		atomic.StoreUint32(capability, uint32(CapabilitySupported))
+		return
+	}
+	atomic.CompareAndSwapUint32(capability, uint32(CapabilityUnknown), uint32(CapabilityUnsupported))
+}
+
+// snapshot returns the capabilities detected so far.
+func (capabilities *serverCapabilities) snapshot() ServerCapabilities {
+	if capabilities == nil {
+		return ServerCapabilities{}
+	}
+	return ServerCapabilities{
+		ConsumedIOs:       Capability(atomic.LoadUint32(&capabilities.consumedIOs)),
+		TimingInformation: Capability(atomic.LoadUint32(&capabilities.timingInformation)),
+	}
+}
+
+// ServerCapabilities returns which optional response fields QLDB was detected to return to the driver.
+func (driver *QLDBDriver) ServerCapabilities() ServerCapabilities {
+	return driver.capabilities.snapshot()
+}
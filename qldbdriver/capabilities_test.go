@@ -0,0 +1,96 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerCapabilities(t *testing.T) {
+	t.Run("observe", func(t *testing.T) {
+		capabilities := &serverCapabilities{}
+		assert.Equal(t, ServerCapabilities{}, capabilities.snapshot())
+
+		capabilities.observe(false, true)
+		assert.Equal(t, ServerCapabilities{ConsumedIOs: CapabilityUnsupported, TimingInformation: CapabilitySupported}, capabilities.snapshot())
+		capabilities.observe(true, false)
+		assert.Equal(t, ServerCapabilities{ConsumedIOs: CapabilitySupported, TimingInformation: CapabilitySupported}, capabilities.snapshot())
+
+		var disabled *serverCapabilities
+		disabled.observe(true, true)
+		assert.Equal(t, ServerCapabilities{}, disabled.snapshot())
+		assert.Equal(t, "unknown", CapabilityUnknown.String())
+		assert.Equal(t, "unsupported", CapabilityUnsupported.String())
+	})
+
+	newTestDriver := func(reportMetrics bool) *QLDBDriver {
+		return newMockDriver(t, &qldbsessioniface.MockClientAPI{
+			SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+				output := qldbsessioniface.DefaultSendCommandOutput(params)
+				if params.ExecuteStatement != nil && reportMetrics {
+					output.ExecuteStatement.ConsumedIOs = &types.IOUsage{ReadIOs: 3, WriteIOs: 1}
+					output.ExecuteStatement.TimingInformation = &types.TimingInformation{ProcessingTimeMilliseconds: 7}
+				}
+				return output, nil
+			},
+		})
+	}
+	execute := func(t *testing.T, testDriver *QLDBDriver) (Result, BufferedResult) {
+		var res Result
+		buffered, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+			var err error
+			res, err = txn.Execute("SELECT * FROM Vehicle")
+			if err != nil {
+				return nil, err
+			}
+			return txn.BufferResult(res)
+		})
+		require.NoError(t, err)
+		return res, buffered.(BufferedResult)
+	}
+
+	t.Run("supported", func(t *testing.T) {
+		testDriver := newTestDriver(true)
+		res, buffered := execute(t, testDriver)
+
+		assert.Equal(t, ServerCapabilities{ConsumedIOs: CapabilitySupported, TimingInformation: CapabilitySupported}, testDriver.ServerCapabilities())
+		require.NotNil(t, res.GetConsumedIOs().GetReadIOs())
+		assert.Equal(t, int64(3), *res.GetConsumedIOs().GetReadIOs())
+		require.NotNil(t, buffered.GetTimingInformation().GetProcessingTimeMilliseconds())
+		assert.Equal(t, int64(7), *buffered.GetTimingInformation().GetProcessingTimeMilliseconds())
+		assert.True(t, buffered.GetConsumedIOs().Reported())
+		assert.Equal(t, int64(1), buffered.GetConsumedIOs().WriteIOs())
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		testDriver := newTestDriver(false)
+		res, buffered := execute(t, testDriver)
+
+		assert.Equal(t, ServerCapabilities{ConsumedIOs: CapabilityUnsupported, TimingInformation: CapabilityUnsupported}, testDriver.ServerCapabilities())
+		// The deprecated getters keep returning counts for existing callers
+		assert.Equal(t, int64(0), *res.GetConsumedIOs().GetReadIOs())
+		assert.Equal(t, int64(0), *res.GetTimingInformation().GetProcessingTimeMilliseconds())
+		assert.Equal(t, int64(0), *buffered.GetConsumedIOs().GetReadIOs())
+		assert.Equal(t, int64(0), *buffered.GetTimingInformation().GetProcessingTimeMilliseconds())
+		assert.False(t, res.GetConsumedIOs().Reported())
+		assert.False(t, buffered.GetTimingInformation().Reported())
+	})
+}
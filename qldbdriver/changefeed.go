@@ -0,0 +1,409 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ChangeType describes what a revision delivered by a ChangeFeed did to its document.
+type ChangeType int
+
+const (
+	// ChangeInsert means that the revision is the first version of the document.
+	ChangeInsert ChangeType = iota
+	// ChangeUpdate means that the revision updated an existing document.
+	ChangeUpdate
+	// ChangeDelete means that the revision deleted the document, and has no data.
+	ChangeDelete
+)
+
+// Change is a revision of a document delivered to a ChangeSink. It does not depend on how the revision was read, so a
+// consumer of a QLDB journal stream can build Change values from the revision records of the stream and apply them
+// to the same sinks as a ChangeFeed.
+type Change struct {
+	// The table of the document.
+	TableName string
+	// The ID of the document.
+	DocumentID string
+	// The version number of the revision.
+	Version int64
+	// What the revision did to the document.
+	Type ChangeType
+	// The ID of the transaction that committed the revision.
+	TxID string
+	// The time at which the revision was committed to the journal.
+	TxTime time.Time
+	// The location of the block in which the revision was committed.
+	BlockAddress BlockAddress
+	// The full revision. Its data is missing for a ChangeDelete.
+	Revision *Document
+}
+
+// ChangeSink applies the revisions of a ledger to another data store, such as a DynamoDB table, an SQL database or a
+// search index, to maintain a materialized read replica of the ledger data.
+//
+// A batch of changes can be delivered more than once, when Apply returns an error or when the ChangeFeed stops before
+// its position is saved, so Apply should be idempotent, for example by upserting the documents by ID and ignoring
+// revisions with a version older than the one already applied.
+type ChangeSink interface {
+	// Apply the changes, which are ordered by block and belong to a single table.
+	Apply(ctx context.Context, changes []Change) error
+}
+
+// ChangeCheckpoint is the position of a ChangeFeed in the history of a table: every revision committed in a block up
+// to BlockAddress has been applied.
+type ChangeCheckpoint struct {
+	// The table the position applies to.
+	TableName string
+	// The time at which the revisions of the last applied block were committed.
+	TxTime time.Time
+	// The address of the last applied block.
+	BlockAddress BlockAddress
+}
+
+// ChangeCheckpointStore persists the positions of a ChangeFeed, so that it resumes where it stopped when restarted.
+type ChangeCheckpointStore interface {
+	// LoadCheckpoint returns the saved position of the table, or nil if there is none.
+	LoadCheckpoint(ctx context.Context, tableName string) (*ChangeCheckpoint, error)
+	// SaveCheckpoint persists the position of a table.
+	SaveCheckpoint(ctx context.Context, checkpoint ChangeCheckpoint) error
+}
+
+// ChangeFeedOptions can be used to configure a ChangeFeed during construction.
+type ChangeFeedOptions struct {
+	// The tables whose revisions are delivered. Default: every active table of the ledger, listed again on each poll.
+	Tables []string
+	// The interval between two polls. Default: 10s.
+	Interval time.Duration
+	// The maximum number of changes per call to ChangeSink.Apply. The revisions of a block are never split across
+	// batches, so a batch holding a single block can be larger. Default: 100.
+	BatchSize int
+	// Where the feed starts in the history of a table that has no saved position. Default: when the feed is started.
+	StartTime time.Time
+	// The span of commit times read from the history of a table in a single transaction, so that a feed far behind
+	// catches up without exceeding the duration of a transaction. The span doubles after a window without revisions.
+	// Default: 1m.
+	Window time.Duration
+	// Persists the positions of the feed. Default: nil, in which case they are only kept in memory.
+	Checkpoints ChangeCheckpointStore
+}
+
+// ChangeFeedStats counts the polls made by a ChangeFeed.
+type ChangeFeedStats struct {
+	// The number of polls that completed.
+	Polls int64
+	// The number of polls that failed to complete, for example because the sink returned an error.
+	Errors int64
+	// The number of changes applied to the sink.
+	Changes int64
+}
+
+// ChangeFeed periodically polls the history of the tables of the ledger of a driver, and pushes the revisions committed
+// since the previous poll to a ChangeSink. A poll that fails is retried from the last applied block on the next poll.
+// Call QLDBDriver.StartChangeFeed for a valid ChangeFeed.
+type ChangeFeed struct {
+	driver      *QLDBDriver
+	sink        ChangeSink
+	options     ChangeFeedOptions
+	lock        sync.Mutex
+	pollLock    sync.Mutex
+	stats       ChangeFeedStats
+	checkpoints map[string]*ChangeCheckpoint
+	stop        chan struct{}
+	done        chan struct{}
+	closeOnce   sync.Once
+}
+
+// StartChangeFeed starts a ChangeFeed for the ledger of the driver, which polls the ledger every
+// ChangeFeedOptions.Interval until Stop is called or the driver is shut down.
+func (driver *QLDBDriver) StartChangeFeed(sink ChangeSink, fns ...func(*ChangeFeedOptions)) (*ChangeFeed, error) {
+	if sink == nil {
+		return nil, &qldbDriverError{"Provided ChangeSink is nil."}
+	}
+	options := ChangeFeedOptions{Interval: 10 * time.Second, BatchSize: 100, StartTime: time.Now(), Window: time.Minute}
+	for _, fn := range fns {
+		fn(&options)
+	}
+	if options.Interval <= 0 {
+		return nil, &qldbDriverError{"Interval must be greater than 0."}
+	}
+	if options.BatchSize < 1 {
+		return nil, &qldbDriverError{"BatchSize must be 1 or greater."}
+	}
+	if options.Window <= 0 {
+		return nil, &qldbDriverError{"Window must be greater than 0."}
+	}
+	for _, tableName := range options.Tables {
+		if !tableNameRegex.MatchString(tableName) {
+			return nil, &qldbDriverError{"Invalid table name: '" + tableName + "'."}
+		}
+	}
+
+	feed := &ChangeFeed{
+		driver:      driver,
+		sink:        sink,
+		options:     options,
+		checkpoints: make(map[string]*ChangeCheckpoint),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	go feed.run()
+	return feed, nil
+}
+
+func (feed *ChangeFeed) run() {
+	defer close(feed.done)
+	ticker := time.NewTicker(feed.options.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-feed.stop:
+			return
+		case <-ticker.C:
+			feed.driver.lock.Lock()
+			closed := feed.driver.isClosed
+			feed.driver.lock.Unlock()
+			if closed {
+				return
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), feed.options.Interval)
+			_, err := feed.Poll(ctx)
+			cancel()
+			if err != nil {
+				feed.driver.logger.logf(LogInfo, "Change feed poll could not complete.\nCaused by '%v'", err)
+			}
+		}
+	}
+}
+
+// Stop stops the periodic polls, waiting for a poll in progress to complete.
+func (feed *ChangeFeed) Stop() {
+	feed.closeOnce.Do(func() {
+		close(feed.stop)
+	})
+	<-feed.done
+}
+
+// Stats returns the polls made since the ChangeFeed was started.
+func (feed *ChangeFeed) Stats() ChangeFeedStats {
+	feed.lock.Lock()
+	defer feed.lock.Unlock()
+	return feed.stats
+}
+
+// Poll reads the revisions committed to the tables of the feed since their last applied block, applies them to the
+// sink in batches, and returns the number of changes applied. It is called periodically, but can also be called
+// directly, for example to catch up before serving reads from the replica. Changes applied before an error is returned
+// are not delivered again, unless the position of their table could not be saved.
+func (feed *ChangeFeed) Poll(ctx context.Context) (int, error) {
+	feed.pollLock.Lock()
+	defer feed.pollLock.Unlock()
+	applied, err := feed.poll(ctx)
+	feed.lock.Lock()
+	defer feed.lock.Unlock()
+	feed.stats.Changes += int64(applied)
+	if err != nil {
+		feed.stats.Errors++
+		return applied, err
+	}
+	feed.stats.Polls++
+	return applied, nil
+}
+
+func (feed *ChangeFeed) poll(ctx context.Context) (int, error) {
+	tableNames := feed.options.Tables
+	if len(tableNames) == 0 {
+		var err error
+		tableNames, err = feed.driver.GetTableNames(ctx)
+		if err != nil {
+			return 0, err
+		}
+	}
+	applied := 0
+	for _, tableName := range tableNames {
+		if !tableNameRegex.MatchString(tableName) {
+			continue
+		}
+		count, err := feed.pollTable(ctx, tableName)
+		applied += count
+		if err != nil {
+			return applied, err
+		}
+	}
+	return applied, nil
+}
+
+// pollTable applies the revisions of a table committed after its checkpoint, reading them window by window, and
+// advances the checkpoint after each batch. The last window has no end, so that revisions committed at a time ahead of
+// the clock of the client are not missed.
+func (feed *ChangeFeed) pollTable(ctx context.Context, tableName string) (int, error) {
+	checkpoint, err := feed.checkpoint(ctx, tableName)
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	window := feed.options.Window
+	for from := checkpoint.TxTime; ; {
+		end := from.Add(window)
+		unbounded := !end.Before(time.Now())
+		if unbounded {
+			end = time.Time{}
+		}
+		changes, err := feed.readChanges(ctx, checkpoint, from, end)
+		if err != nil {
+			return applied, err
+		}
+		if len(changes) == 0 {
+			window *= 2
+		} else {
+			window = feed.options.Window
+		}
+
+		for len(changes) > 0 {
+			batch := changes[:changeBatchSize(changes, feed.options.BatchSize)]
+			err = feed.sink.Apply(ctx, batch)
+			if err != nil {
+				return applied, err
+			}
+			applied += len(batch)
+			changes = changes[len(batch):]
+
+			last := batch[len(batch)-1]
+			checkpoint = &ChangeCheckpoint{TableName: tableName, TxTime: last.TxTime, BlockAddress: last.BlockAddress}
+			feed.checkpoints[tableName] = checkpoint
+			if feed.options.Checkpoints != nil {
+				err = feed.options.Checkpoints.SaveCheckpoint(ctx, *checkpoint)
+				if err != nil {
+					return applied, err
+				}
+			}
+		}
+		if unbounded {
+			break
+		}
+		from = end
+	}
+	if applied > 0 {
+		feed.driver.logger.logf(LogDebug, "Applied %d changes of table %s to the change sink.", applied, tableName)
+	}
+	return applied, nil
+}
+
+// checkpoint returns the position of a table, loading it from the ChangeCheckpointStore the first time. A table without
+// a position starts at ChangeFeedOptions.StartTime.
+func (feed *ChangeFeed) checkpoint(ctx context.Context, tableName string) (*ChangeCheckpoint, error) {
+	if checkpoint, ok := feed.checkpoints[tableName]; ok {
+		return checkpoint, nil
+	}
+	var checkpoint *ChangeCheckpoint
+	if feed.options.Checkpoints != nil {
+		var err error
+		checkpoint, err = feed.options.Checkpoints.LoadCheckpoint(ctx, tableName)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if checkpoint == nil {
+		checkpoint = &ChangeCheckpoint{TableName: tableName, TxTime: feed.options.StartTime, BlockAddress: BlockAddress{SequenceNo: -1}}
+	}
+	feed.checkpoints[tableName] = checkpoint
+	return checkpoint, nil
+}
+
+// readChanges returns the revisions of a table committed in the blocks after the checkpoint, ordered by block and then
+// by document ID. The history function filters on commit time, which blocks committed in the same millisecond share,
+// so revisions are also filtered on the sequence number of their block, which increases with every block of the
+// single strand of a ledger. Only the revisions committed from the start time up to the end time are read, or up to the
+// current time when end is zero.
+func (feed *ChangeFeed) readChanges(ctx context.Context, checkpoint *ChangeCheckpoint, start time.Time, end time.Time) ([]Change, error) {
+	statement := "SELECT * FROM history(" + checkpoint.TableName + ", ?) AS h"
+	parameters := []interface{}{start}
+	if !end.IsZero() {
+		statement = "SELECT * FROM history(" + checkpoint.TableName + ", ?, ?) AS h"
+		parameters = append(parameters, end)
+	}
+	changes, err := feed.driver.Execute(ctx, func(txn Transaction) (interface{}, error) {
+		changes := make([]Change, 0)
+		err := ExecuteStream(txn, statement, func(ionBinary []byte) error {
+			document := NewDocument(ionBinary)
+			metadata, err := document.getMetadata()
+			if err != nil {
+				return err
+			}
+			blockAddress, err := document.GetBlockAddress()
+			if err != nil {
+				return err
+			}
+			if blockAddress.SequenceNo <= checkpoint.BlockAddress.SequenceNo {
+				return nil
+			}
+			changeType := ChangeUpdate
+			switch {
+			case document.isDeletion():
+				changeType = ChangeDelete
+			case metadata.Version == 0:
+				changeType = ChangeInsert
+			}
+			changes = append(changes, Change{
+				TableName:    checkpoint.TableName,
+				DocumentID:   metadata.ID,
+				Version:      metadata.Version,
+				Type:         changeType,
+				TxID:         metadata.TxID,
+				TxTime:       metadata.TxTime,
+				BlockAddress: *blockAddress,
+				Revision:     document,
+			})
+			return nil
+		}, parameters...)
+		if err != nil {
+			return nil, err
+		}
+		return changes, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := changes.([]Change)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].BlockAddress.SequenceNo != sorted[j].BlockAddress.SequenceNo {
+			return sorted[i].BlockAddress.SequenceNo < sorted[j].BlockAddress.SequenceNo
+		}
+		return sorted[i].DocumentID < sorted[j].DocumentID
+	})
+	return sorted, nil
+}
+
+// changeBatchSize returns the number of changes of the next batch: as many whole blocks as fit in batchSize, or the
+// first block if it does not fit on its own.
+func changeBatchSize(changes []Change, batchSize int) int {
+	size := 0
+	for size < len(changes) {
+		end := size + 1
+		for end < len(changes) && changes[end].BlockAddress.SequenceNo == changes[size].BlockAddress.SequenceNo {
+			end++
+		}
+		if size > 0 && end > batchSize {
+			break
+		}
+		size = end
+	}
+	return size
+}
@@ -0,0 +1,281 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amzn/ion-go/ion"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryChangeSink keeps the applied batches in memory, and fails with err when it is set.
+type memoryChangeSink struct {
+	lock    sync.Mutex
+	batches [][]Change
+	err     error
+}
+
+func (sink *memoryChangeSink) Apply(ctx context.Context, changes []Change) error {
+	sink.lock.Lock()
+	defer sink.lock.Unlock()
+	if sink.err != nil {
+		return sink.err
+	}
+	sink.batches = append(sink.batches, changes)
+	return nil
+}
+
+func (sink *memoryChangeSink) applied() int {
+	sink.lock.Lock()
+	defer sink.lock.Unlock()
+	count := 0
+	for _, batch := range sink.batches {
+		count += len(batch)
+	}
+	return count
+}
+
+// memoryCheckpointStore keeps the saved checkpoints in memory.
+type memoryCheckpointStore struct {
+	checkpoints map[string]ChangeCheckpoint
+}
+
+func (store *memoryCheckpointStore) LoadCheckpoint(ctx context.Context, tableName string) (*ChangeCheckpoint, error) {
+	checkpoint, ok := store.checkpoints[tableName]
+	if !ok {
+		return nil, nil
+	}
+	return &checkpoint, nil
+}
+
+func (store *memoryCheckpointStore) SaveCheckpoint(ctx context.Context, checkpoint ChangeCheckpoint) error {
+	store.checkpoints[checkpoint.TableName] = checkpoint
+	return nil
+}
+
+func TestChangeFeed(t *testing.T) {
+	rows := map[string][]string{
+		"SELECT name FROM information_schema.user_tables WHERE status = 'ACTIVE'": {`{name: "Vehicle"}`},
+		"SELECT * FROM history(Vehicle, ?, ?) AS h": {
+			`{blockAddress: {strandId: "S", sequenceNo: 11}, data: {VIN: "1"}, metadata: {id: "A", version: 1, txTime: 2023-01-01T00:00:01Z, txId: "T2"}}`,
+			`{blockAddress: {strandId: "S", sequenceNo: 10}, data: {VIN: "2"}, metadata: {id: "B", version: 0, txTime: 2023-01-01T00:00:00Z, txId: "T1"}}`,
+			`{blockAddress: {strandId: "S", sequenceNo: 10}, data: {VIN: "1"}, metadata: {id: "A", version: 0, txTime: 2023-01-01T00:00:00Z, txId: "T1"}}`,
+			`{blockAddress: {strandId: "S", sequenceNo: 12}, metadata: {id: "B", version: 1, txTime: 2023-01-01T00:00:02Z, txId: "T3"}}`,
+		},
+	}
+	rows["SELECT * FROM history(Vehicle, ?) AS h"] = rows["SELECT * FROM history(Vehicle, ?, ?) AS h"]
+	testDriver := newMockDriver(t, &qldbsessioniface.MockClientAPI{
+		SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+			output := qldbsessioniface.DefaultSendCommandOutput(params)
+			if params.ExecuteStatement != nil {
+				for _, row := range rows[*params.ExecuteStatement.Statement] {
+					output.ExecuteStatement.FirstPage.Values = append(output.ExecuteStatement.FirstPage.Values, types.ValueHolder{IonBinary: ionTextToBinary(t, row)})
+				}
+			}
+			return output, nil
+		},
+	})
+	startTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("poll", func(t *testing.T) {
+		sink := &memoryChangeSink{}
+		store := &memoryCheckpointStore{checkpoints: map[string]ChangeCheckpoint{}}
+		feed, err := testDriver.StartChangeFeed(sink, func(options *ChangeFeedOptions) {
+			options.Interval = time.Hour
+			options.BatchSize = 2
+			options.StartTime = startTime
+			options.Checkpoints = store
+		})
+		require.NoError(t, err)
+		defer feed.Stop()
+
+		applied, err := feed.Poll(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 4, applied)
+		require.Len(t, sink.batches, 2)
+		require.Len(t, sink.batches[0], 2)
+		assert.Equal(t, "A", sink.batches[0][0].DocumentID)
+		assert.Equal(t, ChangeInsert, sink.batches[0][0].Type)
+		assert.Equal(t, "B", sink.batches[0][1].DocumentID)
+		require.Len(t, sink.batches[1], 2)
+		assert.Equal(t, ChangeUpdate, sink.batches[1][0].Type)
+		assert.Equal(t, int64(1), sink.batches[1][0].Version)
+		assert.Equal(t, "T2", sink.batches[1][0].TxID)
+		assert.Equal(t, ChangeDelete, sink.batches[1][1].Type)
+		assert.Equal(t, "Vehicle", sink.batches[1][1].TableName)
+		assert.Equal(t, int64(12), store.checkpoints["Vehicle"].BlockAddress.SequenceNo)
+
+		applied, err = feed.Poll(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 0, applied)
+
+		stats := feed.Stats()
+		assert.Equal(t, int64(2), stats.Polls)
+		assert.Equal(t, int64(4), stats.Changes)
+	})
+
+	t.Run("resume from checkpoint", func(t *testing.T) {
+		sink := &memoryChangeSink{}
+		store := &memoryCheckpointStore{checkpoints: map[string]ChangeCheckpoint{
+			"Vehicle": {TableName: "Vehicle", TxTime: startTime, BlockAddress: BlockAddress{StrandID: "S", SequenceNo: 11}},
+		}}
+		feed, err := testDriver.StartChangeFeed(sink, func(options *ChangeFeedOptions) {
+			options.Interval = time.Hour
+			options.Tables = []string{"Vehicle"}
+			options.Checkpoints = store
+		})
+		require.NoError(t, err)
+		defer feed.Stop()
+
+		applied, err := feed.Poll(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, applied)
+		assert.Equal(t, "B", sink.batches[0][0].DocumentID)
+	})
+
+	t.Run("sink error", func(t *testing.T) {
+		sink := &memoryChangeSink{err: errMock}
+		feed, err := testDriver.StartChangeFeed(sink, func(options *ChangeFeedOptions) {
+			options.Interval = time.Hour
+			options.StartTime = startTime
+		})
+		require.NoError(t, err)
+		defer feed.Stop()
+
+		_, err = feed.Poll(context.Background())
+		assert.Equal(t, errMock, err)
+		assert.Equal(t, int64(1), feed.Stats().Errors)
+
+		sink.err = nil
+		applied, err := feed.Poll(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 4, applied)
+	})
+
+	t.Run("periodic", func(t *testing.T) {
+		sink := &memoryChangeSink{}
+		feed, err := testDriver.StartChangeFeed(sink, func(options *ChangeFeedOptions) {
+			options.Interval = time.Millisecond
+			options.StartTime = startTime
+		})
+		require.NoError(t, err)
+		assert.Eventually(t, func() bool {
+			return sink.applied() == 4
+		}, time.Second, time.Millisecond)
+		feed.Stop()
+		feed.Stop()
+	})
+
+	t.Run("windows", func(t *testing.T) {
+		revisions := []string{
+			`{blockAddress: {strandId: "S", sequenceNo: 1}, data: {VIN: "1"}, metadata: {id: "A", version: 0, txTime: 2023-01-01T00:00:30Z, txId: "T1"}}`,
+			`{blockAddress: {strandId: "S", sequenceNo: 2}, data: {VIN: "2"}, metadata: {id: "B", version: 0, txTime: 2023-01-01T00:10:00Z, txId: "T2"}}`,
+		}
+		var windows [][]time.Time
+		windowDriver := newMockDriver(t, &qldbsessioniface.MockClientAPI{
+			SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+				output := qldbsessioniface.DefaultSendCommandOutput(params)
+				if params.ExecuteStatement == nil {
+					return output, nil
+				}
+				var window []time.Time
+				for _, parameter := range params.ExecuteStatement.Parameters {
+					var bound time.Time
+					require.NoError(t, ion.Unmarshal(parameter.IonBinary, &bound))
+					window = append(window, bound)
+				}
+				windows = append(windows, window)
+				for _, revision := range revisions {
+					var document struct {
+						Metadata struct {
+							TxTime time.Time `ion:"txTime"`
+						} `ion:"metadata"`
+					}
+					require.NoError(t, ion.UnmarshalString(revision, &document))
+					txTime := document.Metadata.TxTime
+					if txTime.Before(window[0]) || len(window) == 2 && txTime.After(window[1]) {
+						continue
+					}
+					output.ExecuteStatement.FirstPage.Values = append(output.ExecuteStatement.FirstPage.Values, types.ValueHolder{IonBinary: ionTextToBinary(t, revision)})
+				}
+				return output, nil
+			},
+		})
+		sink := &memoryChangeSink{}
+		feed, err := windowDriver.StartChangeFeed(sink, func(options *ChangeFeedOptions) {
+			options.Interval = time.Hour
+			options.Tables = []string{"Vehicle"}
+			options.StartTime = startTime
+		})
+		require.NoError(t, err)
+		defer feed.Stop()
+
+		applied, err := feed.Poll(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 2, applied)
+		require.True(t, len(windows) > 3)
+		assert.Equal(t, []time.Time{startTime, startTime.Add(time.Minute)}, windows[0])
+		assert.Equal(t, []time.Time{startTime.Add(time.Minute), startTime.Add(2 * time.Minute)}, windows[1])
+		// Windows without revisions double
+		assert.Equal(t, []time.Time{startTime.Add(2 * time.Minute), startTime.Add(4 * time.Minute)}, windows[2])
+		assert.Len(t, windows[len(windows)-1], 1)
+		for i := 1; i < len(windows); i++ {
+			assert.Equal(t, windows[i-1][1], windows[i][0])
+		}
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := testDriver.StartChangeFeed(nil)
+		assert.Error(t, err)
+		_, err = testDriver.StartChangeFeed(&memoryChangeSink{}, func(options *ChangeFeedOptions) {
+			options.Interval = 0
+		})
+		assert.Error(t, err)
+		_, err = testDriver.StartChangeFeed(&memoryChangeSink{}, func(options *ChangeFeedOptions) {
+			options.BatchSize = 0
+		})
+		assert.Error(t, err)
+		_, err = testDriver.StartChangeFeed(&memoryChangeSink{}, func(options *ChangeFeedOptions) {
+			options.Window = 0
+		})
+		assert.Error(t, err)
+		_, err = testDriver.StartChangeFeed(&memoryChangeSink{}, func(options *ChangeFeedOptions) {
+			options.Tables = []string{"Vehicle; DROP"}
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestChangeBatchSize(t *testing.T) {
+	block := func(sequenceNo ...int64) []Change {
+		changes := make([]Change, len(sequenceNo))
+		for i, no := range sequenceNo {
+			changes[i].BlockAddress.SequenceNo = no
+		}
+		return changes
+	}
+	assert.Equal(t, 2, changeBatchSize(block(1, 2, 3), 2))
+	assert.Equal(t, 3, changeBatchSize(block(1, 1, 1, 2), 2))
+	assert.Equal(t, 1, changeBatchSize(block(1, 2, 2), 2))
+	assert.Equal(t, 3, changeBatchSize(block(1, 2, 2), 3))
+	assert.Equal(t, 0, changeBatchSize(nil, 2))
+}
@@ -0,0 +1,86 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"strconv"
+)
+
+// ChunkedExecuteOptions can be used to configure a single call to QLDBDriver.ExecuteChunked.
+type ChunkedExecuteOptions struct {
+	// Called after each chunk is committed, with the number of items committed so far and the total number of items.
+	// Default: nil.
+	OnProgress func(committedItems int, totalItems int)
+	// The options used to execute each chunk. Default: nil.
+	ExecuteOptions []func(*ExecuteOptions)
+}
+
+// ChunkError is returned by QLDBDriver.ExecuteChunked when a chunk could not be committed. The chunks before it were
+// committed, and the chunks after it were not executed.
+type ChunkError struct {
+	// The number of items committed before the failure, which is also the index of the first item of the failed chunk.
+	CommittedItems int
+	// The index following the last item of the failed chunk.
+	ChunkEnd int
+	err      error
+}
+
+// Error returns the message denoting the cause of the error.
+func (e *ChunkError) Error() string {
+	return "Failed to commit items " + strconv.Itoa(e.CommittedItems) + " to " + strconv.Itoa(e.ChunkEnd-1) + ": " + e.err.Error()
+}
+
+// Unwrap returns the error returned by QLDBDriver.Execute for the failed chunk.
+func (e *ChunkError) Unwrap() error {
+	return e.err
+}
+
+// ExecuteChunked splits itemCount items into chunks of up to chunkSize items and commits each chunk in its own
+// transaction, retried like any function provided to Execute. The function is called with the index of the first item
+// of the chunk and the index following its last item, so that it can write items[start:end] of the caller's slice.
+//
+// Chunks are committed in order, and the first chunk that cannot be committed stops the execution with a ChunkError
+// reporting how many items were committed. This is meant for data loads that exceed the limits of a single transaction.
+func (driver *QLDBDriver) ExecuteChunked(ctx context.Context, itemCount int, chunkSize int, fn func(txn Transaction, start int, end int) error, optFns ...func(*ChunkedExecuteOptions)) error {
+	if itemCount < 0 {
+		return &qldbDriverError{"itemCount must be 0 or greater."}
+	}
+	if chunkSize < 1 {
+		return &qldbDriverError{"chunkSize must be 1 or greater."}
+	}
+
+	options := &ChunkedExecuteOptions{}
+	for _, optFn := range optFns {
+		optFn(options)
+	}
+
+	for start := 0; start < itemCount; start += chunkSize {
+		end := start + chunkSize
+		if end > itemCount {
+			end = itemCount
+		}
+		_, err := driver.Execute(ctx, func(txn Transaction) (interface{}, error) {
+			return nil, fn(txn, start, end)
+		}, options.ExecuteOptions...)
+		if err != nil {
+			return &ChunkError{CommittedItems: start, ChunkEnd: end, err: err}
+		}
+		driver.logger.logf(LogDebug, "Committed items %d to %d of %d.", start, end-1, itemCount)
+		if options.OnProgress != nil {
+			options.OnProgress(end, itemCount)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteChunked(t *testing.T) {
+	newTestDriver := func() *QLDBDriver {
+		mockCommitTransaction.CommitDigest = []byte{167, 123, 231, 255, 170, 172, 35, 142, 73, 31, 239, 199, 252, 120, 175, 217, 235, 220, 184, 200, 85, 203, 140, 230, 151, 221, 131, 255, 163, 151, 170, 210}
+		mockSession := new(mockQLDBSession)
+		mockSession.On("SendCommand", mock.Anything, mock.Anything, mock.Anything).Return(&mockSendCommandWithTxID, nil)
+		return newMockDriver(t, mockSession, func(options *DriverOptions) {
+			options.RetryPolicy = RetryPolicy{
+				MaxRetryLimit: 0,
+				Backoff:       ExponentialBackoffStrategy{SleepBase: time.Millisecond, SleepCap: time.Millisecond}}
+		})
+	}
+
+	t.Run("items are split into chunks", func(t *testing.T) {
+		testDriver := newTestDriver()
+		var chunks [][2]int
+		var progress []int
+
+		err := testDriver.ExecuteChunked(context.Background(), 7, 3, func(txn Transaction, start int, end int) error {
+			chunks = append(chunks, [2]int{start, end})
+			return nil
+		}, func(options *ChunkedExecuteOptions) {
+			options.OnProgress = func(committedItems int, totalItems int) {
+				assert.Equal(t, 7, totalItems)
+				progress = append(progress, committedItems)
+			}
+		})
+		require.NoError(t, err)
+		assert.Equal(t, [][2]int{{0, 3}, {3, 6}, {6, 7}}, chunks)
+		assert.Equal(t, []int{3, 6, 7}, progress)
+	})
+
+	t.Run("no items", func(t *testing.T) {
+		testDriver := newTestDriver()
+		err := testDriver.ExecuteChunked(context.Background(), 0, 3, func(txn Transaction, start int, end int) error {
+			t.Fail()
+			return nil
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed chunk stops execution", func(t *testing.T) {
+		testDriver := newTestDriver()
+		calls := 0
+
+		err := testDriver.ExecuteChunked(context.Background(), 10, 4, func(txn Transaction, start int, end int) error {
+			calls++
+			if start == 4 {
+				return errMock
+			}
+			return nil
+		})
+		var chunkErr *ChunkError
+		require.True(t, errors.As(err, &chunkErr))
+		assert.Equal(t, 4, chunkErr.CommittedItems)
+		assert.Equal(t, 8, chunkErr.ChunkEnd)
+		assert.Equal(t, errMock, errors.Unwrap(err))
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		testDriver := newTestDriver()
+		fn := func(txn Transaction, start int, end int) error { return nil }
+		assert.Error(t, testDriver.ExecuteChunked(context.Background(), -1, 3, fn))
+		assert.Error(t, testDriver.ExecuteChunked(context.Background(), 3, 0, fn))
+	})
+}
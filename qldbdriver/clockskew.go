@@ -0,0 +1,98 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"sync/atomic"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// clockSkewDetector compares the clock of the host with the clock of QLDB, as measured by the SDK from the Date header
+// of the responses, since the results of QLDB do not carry timestamps. A nil clockSkewDetector detects nothing.
+type clockSkewDetector struct {
+	threshold time.Duration
+	// lastSkew is the skew of the latest response, in nanoseconds, positive when the clock of QLDB is ahead.
+	lastSkew int64
+	// skewed is 1 from the response whose skew exceeded the threshold until a response whose skew does not, so that a
+	// skew is logged once rather than for every command.
+	skewed uint32
+}
+
+func newClockSkewDetector(threshold time.Duration) *clockSkewDetector {
+	if threshold <= 0 {
+		return nil
+	}
+	return &clockSkewDetector{threshold: threshold}
+}
+
+// observe records the skew of a response, if the SDK measured it.
+func (detector *clockSkewDetector) observe(logger *qldbLogger, metadata middleware.Metadata) {
+	if detector == nil {
+		return
+	}
+	if skew, ok := awsmiddleware.GetAttemptSkew(metadata); ok {
+		detector.check(logger, skew)
+	}
+}
+
+// check records skew, and logs it when it starts or stops exceeding the threshold.
+func (detector *clockSkewDetector) check(logger *qldbLogger, skew time.Duration) {
+	atomic.StoreInt64(&detector.lastSkew, int64(skew))
+	magnitude := skew
+	if magnitude < 0 {
+		magnitude = -magnitude
+	}
+	if magnitude > detector.threshold {
+		if atomic.CompareAndSwapUint32(&detector.skewed, 0, 1) {
+			logger.logf(LogInfo, "The local clock is %v %s the clock of QLDB, which exceeds ClockSkewThreshold of %v. "+
+				"A skewed clock makes signed requests fail and transactions appear to expire early or late; synchronize "+
+				"the clock of the host, for example with NTP.", magnitude, skewDirection(skew), detector.threshold)
+		}
+	} else if atomic.CompareAndSwapUint32(&detector.skewed, 1, 0) {
+		logger.logf(LogInfo, "The local clock is back within ClockSkewThreshold of %v from the clock of QLDB.", detector.threshold)
+	}
+}
+
+// skew returns the skew of the latest response, or 0 if none was observed.
+func (detector *clockSkewDetector) skew() time.Duration {
+	if detector == nil {
+		return 0
+	}
+	return time.Duration(atomic.LoadInt64(&detector.lastSkew))
+}
+
+// thresholdString returns the threshold for the diagnostics, which is 0 when the detection is disabled.
+func (detector *clockSkewDetector) thresholdString() string {
+	if detector == nil {
+		return time.Duration(0).String()
+	}
+	return detector.threshold.String()
+}
+
+func skewDirection(skew time.Duration) string {
+	if skew > 0 {
+		return "behind"
+	}
+	return "ahead of"
+}
+
+// ClockSkew returns the difference between the clock of QLDB and the local clock measured on the latest response,
+// positive when the local clock is behind. It is 0 before any response, or when DriverOptions.ClockSkewThreshold is 0.
+// The Date header it is measured from has a resolution of one second.
+func (driver *QLDBDriver) ClockSkew() time.Duration {
+	return driver.clockSkew.skew()
+}
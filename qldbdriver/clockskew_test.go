@@ -0,0 +1,96 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClockSkewDetector(t *testing.T) {
+	t.Run("logs once per skew", func(t *testing.T) {
+		recorder := &recordingLogger{}
+		logger := &qldbLogger{logger: recorder, verbosity: LogInfo}
+		detector := newClockSkewDetector(time.Minute)
+
+		detector.check(logger, 10*time.Second)
+		assert.Empty(t, recorder.messages)
+		assert.Equal(t, 10*time.Second, detector.skew())
+
+		detector.check(logger, -2*time.Minute)
+		detector.check(logger, -3*time.Minute)
+		require.Len(t, recorder.messages, 1)
+		assert.Contains(t, recorder.messages[0], "2m0s ahead of the clock of QLDB")
+		assert.Equal(t, -3*time.Minute, detector.skew())
+
+		detector.check(logger, 0)
+		require.Len(t, recorder.messages, 2)
+		assert.Contains(t, recorder.messages[1], "back within ClockSkewThreshold")
+
+		detector.check(logger, 5*time.Minute)
+		require.Len(t, recorder.messages, 3)
+		assert.Contains(t, recorder.messages[2], "5m0s behind the clock of QLDB")
+	})
+
+	t.Run("ignores responses without skew", func(t *testing.T) {
+		recorder := &recordingLogger{}
+		logger := &qldbLogger{logger: recorder, verbosity: LogInfo}
+		detector := newClockSkewDetector(time.Second)
+
+		detector.observe(logger, middleware.Metadata{})
+		assert.Empty(t, recorder.messages)
+		assert.Equal(t, time.Duration(0), detector.skew())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		detector := newClockSkewDetector(0)
+		assert.Nil(t, detector)
+		detector.observe(mockLogger, middleware.Metadata{})
+		assert.Equal(t, time.Duration(0), detector.skew())
+		assert.Equal(t, "0s", detector.thresholdString())
+		assert.Equal(t, time.Duration(0), (&QLDBDriver{}).ClockSkew())
+	})
+
+	t.Run("threshold option", func(t *testing.T) {
+		cfg, err := config.LoadDefaultConfig(context.TODO())
+		require.NoError(t, err)
+		qldbSession := qldbsession.NewFromConfig(cfg)
+
+		driver, err := New(mockLedgerName, qldbSession, func(options *DriverOptions) {
+			options.LoggerVerbosity = LogOff
+		})
+		require.NoError(t, err)
+		assert.Equal(t, time.Minute, driver.clockSkew.threshold)
+
+		driver, err = New(mockLedgerName, qldbSession, func(options *DriverOptions) {
+			options.LoggerVerbosity = LogOff
+			options.ClockSkewThreshold = 0
+		})
+		require.NoError(t, err)
+		assert.Nil(t, driver.clockSkew)
+
+		_, err = New(mockLedgerName, qldbSession, func(options *DriverOptions) {
+			options.LoggerVerbosity = LogOff
+			options.ClockSkewThreshold = -time.Second
+		})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,85 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"regexp"
+)
+
+const committedViewPrefix string = "_ql_committed_"
+
+var tableNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,127}$`)
+
+// IsValidIdentifier returns true if name can be used as a table or field name in a statement without quoting: a letter
+// or an underscore followed by up to 127 letters, digits or underscores.
+func IsValidIdentifier(name string) bool {
+	return tableNameRegex.MatchString(name)
+}
+
+// CommittedViewName returns the name of the committed view of a table, which exposes the full revisions of its
+// documents including metadata, hash and block address.
+func CommittedViewName(tableName string) string {
+	return committedViewPrefix + tableName
+}
+
+// QueryCommittedView executes a SELECT * query on the committed view of a table within the transaction and returns the
+// matching revisions. whereClause is optional and, when not empty, is appended to the query after a WHERE keyword,
+// for example `data.Name = ?`. Use parameters for any values referenced by whereClause.
+func QueryCommittedView(txn Transaction, tableName string, whereClause string, parameters ...interface{}) ([]*Document, error) {
+	if !tableNameRegex.MatchString(tableName) {
+		return nil, &qldbDriverError{"Invalid table name: '" + tableName + "'."}
+	}
+
+	statement := "SELECT * FROM " + CommittedViewName(tableName)
+	if whereClause != "" {
+		statement += " WHERE " + whereClause
+	}
+
+	documents := make([]*Document, 0)
+	err := ExecuteStream(txn, statement, func(ionBinary []byte) error {
+		documents = append(documents, NewDocument(ionBinary))
+		return nil
+	}, parameters...)
+	if err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+// GetCommittedDocument returns the latest committed revision of the document with the given ID, or nil if there is
+// no such document in the table.
+func GetCommittedDocument(txn Transaction, tableName string, documentID string) (*Document, error) {
+	documents, err := QueryCommittedView(txn, tableName, "metadata.id = ?", documentID)
+	if err != nil {
+		return nil, err
+	}
+	if len(documents) == 0 {
+		return nil, nil
+	}
+	return documents[0], nil
+}
+
+// VerifyCommitByTable returns a function for ExecuteOptions.VerifyCommit that considers a transaction committed if the
+// committed view of the table contains a revision written by that transaction. It should be used with a table that is
+// written by every successful execution of the function. A revision that was superseded by a later transaction is not
+// found, so the table should not be updated concurrently by other transactions.
+func VerifyCommitByTable(tableName string) func(txn Transaction, transactionID string) (bool, error) {
+	return func(txn Transaction, transactionID string) (bool, error) {
+		documents, err := QueryCommittedView(txn, tableName, "metadata.txId = ?", transactionID)
+		if err != nil {
+			return false, err
+		}
+		return len(documents) > 0, nil
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommittedView(t *testing.T) {
+	mockID := "txnID"
+	mockHash, _ := toQLDBHash(mockTxnID)
+	testExecutor := &transactionExecutor{
+		ctx: context.Background(),
+		txn: &transaction{id: &mockID, logger: mockLogger, commitHash: mockHash},
+	}
+	statementIs := func(expected string) interface{} {
+		return mock.MatchedBy(func(statement *string) bool { return *statement == expected })
+	}
+
+	t.Run("CommittedViewName", func(t *testing.T) {
+		assert.Equal(t, "_ql_committed_Vehicle", CommittedViewName("Vehicle"))
+	})
+
+	t.Run("QueryCommittedView", func(t *testing.T) {
+		mockExecuteResult := types.ExecuteStatementResult{
+			FirstPage: &types.Page{Values: []types.ValueHolder{{IonBinary: ionTextToBinary(t, mockRevision)}}},
+		}
+
+		t.Run("with where clause", func(t *testing.T) {
+			mockService := new(mockTransactionService)
+			mockService.On("executeStatement", mock.Anything, statementIs("SELECT * FROM _ql_committed_Vehicle WHERE data.VIN = ?"), mock.Anything, mock.Anything).
+				Return(&mockExecuteResult, nil)
+			testExecutor.txn.communicator = mockService
+
+			documents, err := QueryCommittedView(testExecutor, "Vehicle", "data.VIN = ?", "1N4AL11D75C109151")
+			require.NoError(t, err)
+			require.Len(t, documents, 1)
+			id, err := documents[0].GetID()
+			require.NoError(t, err)
+			assert.Equal(t, "8F0TPCmdNQ6JTRpiLj2TmW", id)
+		})
+
+		t.Run("without where clause", func(t *testing.T) {
+			mockService := new(mockTransactionService)
+			mockService.On("executeStatement", mock.Anything, statementIs("SELECT * FROM _ql_committed_Vehicle"), mock.Anything, mock.Anything).
+				Return(&mockExecuteResult, nil)
+			testExecutor.txn.communicator = mockService
+
+			documents, err := QueryCommittedView(testExecutor, "Vehicle", "")
+			require.NoError(t, err)
+			assert.Len(t, documents, 1)
+		})
+
+		t.Run("invalid table name", func(t *testing.T) {
+			documents, err := QueryCommittedView(testExecutor, "Vehicle; DELETE FROM Person", "")
+			assert.Error(t, err)
+			assert.Nil(t, documents)
+		})
+
+		t.Run("execute error", func(t *testing.T) {
+			mockService := new(mockTransactionService)
+			mockService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&mockExecuteResult, errMock)
+			testExecutor.txn.communicator = mockService
+
+			documents, err := QueryCommittedView(testExecutor, "Vehicle", "")
+			assert.Equal(t, errMock, err)
+			assert.Nil(t, documents)
+		})
+	})
+
+	t.Run("GetCommittedDocument", func(t *testing.T) {
+		t.Run("found", func(t *testing.T) {
+			mockExecuteResult := types.ExecuteStatementResult{
+				FirstPage: &types.Page{Values: []types.ValueHolder{{IonBinary: ionTextToBinary(t, mockRevision)}}},
+			}
+			mockService := new(mockTransactionService)
+			mockService.On("executeStatement", mock.Anything, statementIs("SELECT * FROM _ql_committed_Vehicle WHERE metadata.id = ?"), mock.Anything, mock.Anything).
+				Return(&mockExecuteResult, nil)
+			testExecutor.txn.communicator = mockService
+
+			document, err := GetCommittedDocument(testExecutor, "Vehicle", "8F0TPCmdNQ6JTRpiLj2TmW")
+			require.NoError(t, err)
+			require.NotNil(t, document)
+			version, err := document.GetVersion()
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), version)
+		})
+
+		t.Run("not found", func(t *testing.T) {
+			mockExecuteResult := types.ExecuteStatementResult{FirstPage: &types.Page{}}
+			mockService := new(mockTransactionService)
+			mockService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&mockExecuteResult, nil)
+			testExecutor.txn.communicator = mockService
+
+			document, err := GetCommittedDocument(testExecutor, "Vehicle", "unknown")
+			assert.NoError(t, err)
+			assert.Nil(t, document)
+		})
+	})
+	t.Run("VerifyCommitByTable", func(t *testing.T) {
+		verify := VerifyCommitByTable("Vehicle")
+
+		t.Run("committed", func(t *testing.T) {
+			mockExecuteResult := types.ExecuteStatementResult{
+				FirstPage: &types.Page{Values: []types.ValueHolder{{IonBinary: ionTextToBinary(t, mockRevision)}}},
+			}
+			mockService := new(mockTransactionService)
+			mockService.On("executeStatement", mock.Anything, statementIs("SELECT * FROM _ql_committed_Vehicle WHERE metadata.txId = ?"), mock.Anything, mock.Anything).
+				Return(&mockExecuteResult, nil)
+			testExecutor.txn.communicator = mockService
+
+			committed, err := verify(testExecutor, "HgXAkLjAtV0HQ4lNYdzX60")
+			assert.NoError(t, err)
+			assert.True(t, committed)
+		})
+
+		t.Run("not committed", func(t *testing.T) {
+			mockExecuteResult := types.ExecuteStatementResult{FirstPage: &types.Page{}}
+			mockService := new(mockTransactionService)
+			mockService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&mockExecuteResult, nil)
+			testExecutor.txn.communicator = mockService
+
+			committed, err := verify(testExecutor, "unknown")
+			assert.NoError(t, err)
+			assert.False(t, committed)
+		})
+
+		t.Run("execute error", func(t *testing.T) {
+			mockService := new(mockTransactionService)
+			mockService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&types.ExecuteStatementResult{}, errMock)
+			testExecutor.txn.communicator = mockService
+
+			committed, err := verify(testExecutor, "unknown")
+			assert.Equal(t, errMock, err)
+			assert.False(t, committed)
+		})
+	})
+}
+
+func TestIsValidIdentifier(t *testing.T) {
+	assert.True(t, IsValidIdentifier("Person"))
+	assert.True(t, IsValidIdentifier("_person_2"))
+	assert.False(t, IsValidIdentifier(""))
+	assert.False(t, IsValidIdentifier("2Person"))
+	assert.False(t, IsValidIdentifier("Person; DROP TABLE Person"))
+}
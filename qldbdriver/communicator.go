@@ -39,19 +39,32 @@ type communicator struct {
 	service      qldbsessioniface.ClientAPI
 	sessionToken *string
 	logger       *qldbLogger
+	// The SDK retryer used for the commands that are safe to retry.
+	retryer aws.Retryer
+	// Whether the driver retries the FetchPage commands itself, in which case they are sent without the SDK retryer so
+	// that the retries do not multiply.
+	retriesFetchPage bool
+	// The options applied to every command after the options of the driver.
+	clientOptions []func(*qldbsession.Options)
+	clockSkew     *clockSkewDetector
 }
 
-func startSession(ctx context.Context, ledgerName string, service qldbsessioniface.ClientAPI, logger *qldbLogger) (*communicator, error) {
+// startSession starts a session whose commands are sent with clientOptions. startOptions only apply to the
+// StartSession command.
+func startSession(ctx context.Context, ledgerName string, service qldbsessioniface.ClientAPI, logger *qldbLogger, retryer aws.Retryer, clientOptions []func(*qldbsession.Options), startOptions ...func(*qldbsession.Options)) (*communicator, error) {
 	startSession := &types.StartSessionRequest{LedgerName: &ledgerName}
 	sendInput := &qldbsession.SendCommandInput{StartSession: startSession}
-	result, err := service.SendCommand(ctx, sendInput, func(options *qldbsession.Options) {
-		options.Retryer = aws.NopRetryer{}
-		options.APIOptions = append(options.APIOptions, middleware.AddUserAgentKey(userAgentString))
-	})
+	result, err := service.SendCommand(ctx, sendInput, append(commandOptions(retryer, clientOptions), startOptions...)...)
 	if err != nil {
 		return nil, err
 	}
-	return &communicator{service, result.StartSession.SessionToken, logger}, nil
+	return &communicator{
+		service:       service,
+		sessionToken:  result.StartSession.SessionToken,
+		logger:        logger,
+		retryer:       retryer,
+		clientOptions: clientOptions,
+	}, nil
 }
 
 func (communicator *communicator) abortTransaction(ctx context.Context) (*types.AbortTransactionResult, error) {
@@ -83,7 +96,7 @@ func (communicator *communicator) executeStatement(ctx context.Context, statemen
 	sendInput := &qldbsession.SendCommandInput{ExecuteStatement: executeStatement}
 	result, err := communicator.sendCommand(ctx, sendInput)
 	if err != nil {
-		return nil, err
+		return nil, wrapBadRequest(err)
 	}
 	return result.ExecuteStatement, nil
 }
@@ -101,7 +114,11 @@ func (communicator *communicator) endSession(ctx context.Context) (*types.EndSes
 func (communicator *communicator) fetchPage(ctx context.Context, pageToken *string, txnID *string) (*types.FetchPageResult, error) {
 	fetchPage := &types.FetchPageRequest{NextPageToken: pageToken, TransactionId: txnID}
 	sendInput := &qldbsession.SendCommandInput{FetchPage: fetchPage}
-	result, err := communicator.sendCommand(ctx, sendInput)
+	retryer := communicator.retryer
+	if communicator.retriesFetchPage {
+		retryer = nil
+	}
+	result, err := communicator.sendCommandWithRetryer(ctx, sendInput, retryer)
 	if err != nil {
 		return nil, err
 	}
@@ -119,10 +136,40 @@ func (communicator *communicator) startTransaction(ctx context.Context) (*types.
 }
 
 func (communicator *communicator) sendCommand(ctx context.Context, command *qldbsession.SendCommandInput) (*qldbsession.SendCommandOutput, error) {
+	return communicator.sendCommandWithRetryer(ctx, command, nil)
+}
+
+func (communicator *communicator) sendCommandWithRetryer(ctx context.Context, command *qldbsession.SendCommandInput, retryer aws.Retryer) (*qldbsession.SendCommandOutput, error) {
 	command.SessionToken = communicator.sessionToken
-	communicator.logger.logf(LogDebug, "%v", command)
-	return communicator.service.SendCommand(ctx, command, func(options *qldbsession.Options) {
-		options.Retryer = aws.NopRetryer{}
+	logger := communicator.logger.forContext(ctx)
+	if logger.level() >= LogDebug {
+		logger.log(LogDebug, describeCommand(command, logger.redaction))
+	}
+	output, err := communicator.service.SendCommand(ctx, command, commandOptions(retryer, communicator.clientOptions)...)
+	if err == nil {
+		communicator.clockSkew.observe(logger, output.ResultMetadata)
+	}
+	return output, err
+}
+
+// refreshCredentials forces the credentials provider of the client to retrieve credentials again for the command, if
+// it caches them. The cache is shared by the commands of the client, so later commands use the new credentials too.
+func refreshCredentials(options *qldbsession.Options) {
+	if cache, ok := options.Credentials.(interface{ Invalidate() }); ok {
+		cache.Invalidate()
+	}
+}
+
+// commandOptions returns the options for sending a command with the SDK retryer, or without SDK retries if it is nil,
+// followed by the client options.
+func commandOptions(retryer aws.Retryer, clientOptions []func(*qldbsession.Options)) []func(*qldbsession.Options) {
+	if retryer == nil {
+		retryer = aws.NopRetryer{}
+	}
+	optFns := make([]func(*qldbsession.Options), 0, len(clientOptions)+1)
+	optFns = append(optFns, func(options *qldbsession.Options) {
+		options.Retryer = retryer
 		options.APIOptions = append(options.APIOptions, middleware.AddUserAgentKey(userAgentString))
 	})
+	return append(optFns, clientOptions...)
 }
@@ -18,17 +18,20 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
 	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestStartSession(t *testing.T) {
 	t.Run("error", func(t *testing.T) {
 		mockSession := new(mockQLDBSession)
 		mockSession.On("SendCommand", mock.Anything, mock.Anything, mock.Anything).Return(&mockSendCommand, errMock)
-		communicator, err := startSession(context.Background(), "ledgerName", mockSession, mockLogger)
+		communicator, err := startSession(context.Background(), "ledgerName", mockSession, mockLogger, nil, nil)
 
 		assert.Equal(t, err, errMock)
 		assert.Nil(t, communicator)
@@ -37,12 +40,30 @@ func TestStartSession(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockSession := new(mockQLDBSession)
 		mockSession.On("SendCommand", mock.Anything, mock.Anything, mock.Anything).Return(&mockSendCommand, nil)
-		communicator, err := startSession(context.Background(), "ledgerName", mockSession, mockLogger)
+		communicator, err := startSession(context.Background(), "ledgerName", mockSession, mockLogger, nil, nil)
 		assert.NoError(t, err)
 
 		assert.Equal(t, communicator.sessionToken, &mockSessionToken)
 		assert.NoError(t, err)
 	})
+
+	t.Run("SDK retries disabled by default", func(t *testing.T) {
+		mockSession := new(mockQLDBSession)
+		mockSession.On("SendCommand", mock.Anything, mock.Anything, retryerIs(aws.NopRetryer{})).Return(&mockSendCommand, nil)
+		_, err := startSession(context.Background(), "ledgerName", mockSession, mockLogger, nil, nil)
+		assert.NoError(t, err)
+		mockSession.AssertExpectations(t)
+	})
+
+	t.Run("SDK retryer", func(t *testing.T) {
+		retryer := retry.NewStandard()
+		mockSession := new(mockQLDBSession)
+		mockSession.On("SendCommand", mock.Anything, mock.Anything, retryerIs(retryer)).Return(&mockSendCommand, nil)
+		communicator, err := startSession(context.Background(), "ledgerName", mockSession, mockLogger, retryer, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, retryer, communicator.retryer)
+		mockSession.AssertExpectations(t)
+	})
 }
 
 func TestAbortTransaction(t *testing.T) {
@@ -118,6 +139,18 @@ func TestExecuteStatement(t *testing.T) {
 		assert.Nil(t, result)
 	})
 
+	t.Run("bad request", func(t *testing.T) {
+		mockSession := new(mockQLDBSession)
+		mockSession.On("SendCommand", mock.Anything, mock.Anything, mock.Anything).Return(&mockSendCommand, testBadReq)
+		testCommunicator.service = mockSession
+		result, err := testCommunicator.executeStatement(context.Background(), nil, nil, nil)
+
+		var badRequest *BadRequestError
+		require.True(t, errors.As(err, &badRequest))
+		assert.Equal(t, testBadReq, errors.Unwrap(err))
+		assert.Nil(t, result)
+	})
+
 	t.Run("success", func(t *testing.T) {
 		mockSession := new(mockQLDBSession)
 		mockSession.On("SendCommand", mock.Anything, mock.Anything, mock.Anything).Return(&mockSendCommand, nil)
@@ -183,6 +216,35 @@ func TestFetchPage(t *testing.T) {
 		assert.Equal(t, result, &mockFetchPage)
 		assert.NoError(t, err)
 	})
+
+	t.Run("SDK retryer", func(t *testing.T) {
+		retryer := retry.NewStandard()
+		mockSession := new(mockQLDBSession)
+		mockSession.On("SendCommand", mock.Anything, mock.Anything, retryerIs(retryer)).Return(&mockSendCommand, nil)
+		testCommunicator.service = mockSession
+		testCommunicator.retryer = retryer
+		defer func() { testCommunicator.retryer = nil }()
+		_, err := testCommunicator.fetchPage(context.Background(), nil, nil)
+
+		assert.NoError(t, err)
+		mockSession.AssertExpectations(t)
+	})
+
+	t.Run("no SDK retryer when the driver retries", func(t *testing.T) {
+		mockSession := new(mockQLDBSession)
+		mockSession.On("SendCommand", mock.Anything, mock.Anything, retryerIs(aws.NopRetryer{})).Return(&mockSendCommand, nil)
+		testCommunicator.service = mockSession
+		testCommunicator.retryer = retry.NewStandard()
+		testCommunicator.retriesFetchPage = true
+		defer func() {
+			testCommunicator.retryer = nil
+			testCommunicator.retriesFetchPage = false
+		}()
+		_, err := testCommunicator.fetchPage(context.Background(), nil, nil)
+
+		assert.NoError(t, err)
+		mockSession.AssertExpectations(t)
+	})
 }
 
 func TestStartTransaction(t *testing.T) {
@@ -228,7 +290,54 @@ func TestSendCommand(t *testing.T) {
 	assert.Equal(t, err, errMock)
 }
 
-var mockLogger = &qldbLogger{defaultLogger{}, LogOff}
+func TestSendCommandIgnoresSDKRetryer(t *testing.T) {
+	mockSession := new(mockQLDBSession)
+	mockSession.On("SendCommand", mock.Anything, mock.Anything, retryerIs(aws.NopRetryer{})).Return(&mockSendCommand, nil)
+	testCommunicator := communicator{
+		service:      mockSession,
+		sessionToken: &mockSessionToken,
+		logger:       mockLogger,
+		retryer:      retry.NewStandard(),
+	}
+
+	_, err := testCommunicator.commitTransaction(context.Background(), nil, nil)
+
+	assert.NoError(t, err)
+	mockSession.AssertExpectations(t)
+}
+
+func TestClientOptions(t *testing.T) {
+	setRegion := func(options *qldbsession.Options) {
+		options.Region = "us-west-2"
+	}
+	hasRegion := mock.MatchedBy(func(optFns []func(*qldbsession.Options)) bool {
+		options := qldbsession.Options{}
+		for _, optFn := range optFns {
+			optFn(&options)
+		}
+		return options.Region == "us-west-2" && options.Retryer == aws.NopRetryer{}
+	})
+	mockSession := new(mockQLDBSession)
+	mockSession.On("SendCommand", mock.Anything, mock.Anything, hasRegion).Return(&mockSendCommand, nil)
+
+	communicator, err := startSession(context.Background(), "ledgerName", mockSession, mockLogger, nil, []func(*qldbsession.Options){setRegion})
+	assert.NoError(t, err)
+	_, err = communicator.startTransaction(context.Background())
+	assert.NoError(t, err)
+	mockSession.AssertNumberOfCalls(t, "SendCommand", 2)
+}
+
+func retryerIs(expected aws.Retryer) interface{} {
+	return mock.MatchedBy(func(optFns []func(*qldbsession.Options)) bool {
+		options := qldbsession.Options{}
+		for _, optFn := range optFns {
+			optFn(&options)
+		}
+		return options.Retryer == expected
+	})
+}
+
+var mockLogger = &qldbLogger{logger: defaultLogger{}, verbosity: LogOff}
 var errMock = errors.New("mock")
 
 var mockSessionToken = "token"
@@ -0,0 +1,89 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// requestCompression is a serialize middleware compressing with gzip the body of ExecuteStatement commands of at least
+// minBytes, so that statements with large parameters use less bandwidth. It runs before the request is signed, so that
+// the signature covers the compressed body.
+type requestCompression struct {
+	minBytes int
+}
+
+// withRequestCompression returns a client option adding the requestCompression middleware to every command.
+func withRequestCompression(minBytes int) func(*qldbsession.Options) {
+	return func(options *qldbsession.Options) {
+		options.APIOptions = append(options.APIOptions, func(stack *middleware.Stack) error {
+			return stack.Serialize.Insert(&requestCompression{minBytes: minBytes}, "OperationSerializer", middleware.After)
+		})
+	}
+}
+
+// ID returns the identifier of the middleware.
+func (m *requestCompression) ID() string {
+	return "QLDBRequestCompression"
+}
+
+// HandleSerialize compresses the serialized body of an ExecuteStatement command that is at least minBytes long.
+func (m *requestCompression) HandleSerialize(ctx context.Context, in middleware.SerializeInput, next middleware.SerializeHandler) (middleware.SerializeOutput, middleware.Metadata, error) {
+	input, ok := in.Parameters.(*qldbsession.SendCommandInput)
+	if !ok || input.ExecuteStatement == nil {
+		return next.HandleSerialize(ctx, in)
+	}
+	request, ok := in.Request.(*smithyhttp.Request)
+	if !ok || request.GetStream() == nil {
+		return next.HandleSerialize(ctx, in)
+	}
+
+	body, err := ioutil.ReadAll(request.GetStream())
+	if err != nil {
+		return middleware.SerializeOutput{}, middleware.Metadata{}, err
+	}
+	if len(body) >= m.minBytes {
+		body, err = gzipBytes(body)
+		if err != nil {
+			return middleware.SerializeOutput{}, middleware.Metadata{}, err
+		}
+		request.Header.Set("Content-Encoding", "gzip")
+	}
+	request, err = request.SetStream(bytes.NewReader(body))
+	if err != nil {
+		return middleware.SerializeOutput{}, middleware.Metadata{}, err
+	}
+	in.Request = request
+	return next.HandleSerialize(ctx, in)
+}
+
+// gzipBytes returns data compressed with gzip.
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
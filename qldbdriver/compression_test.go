@@ -0,0 +1,101 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingHTTPClient struct {
+	request *http.Request
+	body    []byte
+}
+
+func (c *recordingHTTPClient) Do(request *http.Request) (*http.Response, error) {
+	body, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.request = request
+	c.body = body
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/x-amz-json-1.0"}},
+		Body:       ioutil.NopCloser(strings.NewReader("{}")),
+		Request:    request,
+	}, nil
+}
+
+func TestRequestCompression(t *testing.T) {
+	httpClient := &recordingHTTPClient{}
+	client := qldbsession.New(qldbsession.Options{
+		Region:      "us-east-1",
+		HTTPClient:  httpClient,
+		Credentials: aws.AnonymousCredentials{},
+		Retryer:     aws.NopRetryer{},
+	}, withRequestCompression(512))
+	sessionToken := "token"
+	txnID := "txnID"
+	executeStatement := func(statement string) *qldbsession.SendCommandInput {
+		return &qldbsession.SendCommandInput{
+			SessionToken:     &sessionToken,
+			ExecuteStatement: &types.ExecuteStatementRequest{Statement: &statement, TransactionId: &txnID},
+		}
+	}
+
+	t.Run("large statement is compressed", func(t *testing.T) {
+		statement := "SELECT * FROM Person WHERE name = '" + strings.Repeat("a", 1024) + "'"
+		_, err := client.SendCommand(context.Background(), executeStatement(statement))
+		require.NoError(t, err)
+
+		assert.Equal(t, "gzip", httpClient.request.Header.Get("Content-Encoding"))
+		assert.Equal(t, int64(len(httpClient.body)), httpClient.request.ContentLength)
+		reader, err := gzip.NewReader(bytes.NewReader(httpClient.body))
+		require.NoError(t, err)
+		body, err := ioutil.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), statement)
+	})
+
+	t.Run("small statement is not compressed", func(t *testing.T) {
+		_, err := client.SendCommand(context.Background(), executeStatement("SELECT * FROM Person"))
+		require.NoError(t, err)
+
+		assert.Empty(t, httpClient.request.Header.Get("Content-Encoding"))
+		assert.Contains(t, string(httpClient.body), "SELECT * FROM Person")
+	})
+
+	t.Run("other commands are not compressed", func(t *testing.T) {
+		ledgerName := strings.Repeat("a", 1024)
+		_, err := client.SendCommand(context.Background(), &qldbsession.SendCommandInput{
+			StartSession: &types.StartSessionRequest{LedgerName: &ledgerName},
+		})
+		require.NoError(t, err)
+
+		assert.Empty(t, httpClient.request.Header.Get("Content-Encoding"))
+		assert.Contains(t, string(httpClient.body), ledgerName)
+	})
+}
@@ -0,0 +1,70 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"bytes"
+	"strings"
+)
+
+// GetRevisionHash returns the hash of the latest committed revision of a document, or nil if the table has no such
+// document. The hash can be handed to a client, for example as an HTTP ETag, and later passed to UpdateIfMatch or
+// DeleteIfMatch to write the document only if it was not modified in the meantime.
+func GetRevisionHash(txn Transaction, tableName string, documentID string) ([]byte, error) {
+	document, err := GetCommittedDocument(txn, tableName, documentID)
+	if err != nil || document == nil {
+		return nil, err
+	}
+	return document.GetHash()
+}
+
+// checkRevisionHash returns a PreconditionFailedError if the latest committed revision of a document does not have the
+// expected hash. Since the revision is read in txn, a concurrent write to the document makes the transaction fail with
+// an OCC conflict, and the check is made again when the transaction is retried.
+func checkRevisionHash(txn Transaction, tableName string, documentID string, expectedHash []byte) error {
+	actualHash, err := GetRevisionHash(txn, tableName, documentID)
+	if err != nil {
+		return err
+	}
+	if actualHash == nil || !bytes.Equal(actualHash, expectedHash) {
+		return &PreconditionFailedError{TableName: tableName, DocumentID: documentID, ExpectedHash: expectedHash, ActualHash: actualHash}
+	}
+	return nil
+}
+
+// UpdateIfMatch updates the fields of a document described by changes, as BuildUpdate does, if the latest committed
+// revision of the document still has the hash expectedHash, as returned by GetRevisionHash. Otherwise it returns a
+// PreconditionFailedError without writing anything.
+func UpdateIfMatch(txn Transaction, tableName string, documentID string, expectedHash []byte, changes interface{}) (Result, error) {
+	builder, err := buildAssignments(changes, "d")
+	if err != nil {
+		return nil, err
+	}
+	err = checkRevisionHash(txn, tableName, documentID, expectedHash)
+	if err != nil {
+		return nil, err
+	}
+	statement := "UPDATE " + tableName + " AS d BY docId SET " + strings.Join(builder.assignments, ", ") + " WHERE docId = ?"
+	return txn.Execute(statement, append(builder.parameters, documentID)...)
+}
+
+// DeleteIfMatch deletes a document if its latest committed revision still has the hash expectedHash, as returned by
+// GetRevisionHash. Otherwise it returns a PreconditionFailedError without writing anything.
+func DeleteIfMatch(txn Transaction, tableName string, documentID string, expectedHash []byte) (Result, error) {
+	err := checkRevisionHash(txn, tableName, documentID, expectedHash)
+	if err != nil {
+		return nil, err
+	}
+	return txn.Execute("DELETE FROM "+tableName+" AS d BY docId WHERE docId = ?", documentID)
+}
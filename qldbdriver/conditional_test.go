@@ -0,0 +1,123 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConditionalWrites(t *testing.T) {
+	mockID := "txnID"
+	mockHash, _ := toQLDBHash(mockTxnID)
+	newExecutor := func(revisions ...string) (*transactionExecutor, *mockTransactionService) {
+		page := &types.Page{}
+		for _, revision := range revisions {
+			page.Values = append(page.Values, types.ValueHolder{IonBinary: ionTextToBinary(t, revision)})
+		}
+		mockService := new(mockTransactionService)
+		mockService.On("executeStatement", mock.Anything, mock.MatchedBy(func(statement *string) bool {
+			return *statement == "SELECT * FROM _ql_committed_Vehicle WHERE metadata.id = ?"
+		}), mock.Anything, mock.Anything).Return(&types.ExecuteStatementResult{FirstPage: page}, nil)
+		return &transactionExecutor{
+			ctx: context.Background(),
+			txn: &transaction{id: &mockID, logger: mockLogger, commitHash: mockHash, communicator: mockService},
+		}, mockService
+	}
+	expectWrite := func(mockService *mockTransactionService, expected string) {
+		mockService.On("executeStatement", mock.Anything, mock.MatchedBy(func(statement *string) bool {
+			return *statement == expected
+		}), mock.Anything, mock.Anything).Return(&types.ExecuteStatementResult{FirstPage: &types.Page{}}, nil).Once()
+	}
+	documentID := "8F0TPCmdNQ6JTRpiLj2TmW"
+	currentHash := []byte("hello")
+	staleHash := []byte("stale")
+
+	t.Run("GetRevisionHash", func(t *testing.T) {
+		testExecutor, _ := newExecutor(mockRevision)
+		hash, err := GetRevisionHash(testExecutor, "Vehicle", documentID)
+		require.NoError(t, err)
+		assert.Equal(t, currentHash, hash)
+
+		testExecutor, _ = newExecutor()
+		hash, err = GetRevisionHash(testExecutor, "Vehicle", documentID)
+		require.NoError(t, err)
+		assert.Nil(t, hash)
+	})
+
+	t.Run("UpdateIfMatch", func(t *testing.T) {
+		t.Run("matching hash", func(t *testing.T) {
+			testExecutor, mockService := newExecutor(mockRevision)
+			expectWrite(mockService, "UPDATE Vehicle AS d BY docId SET d.Color = ? WHERE docId = ?")
+
+			_, err := UpdateIfMatch(testExecutor, "Vehicle", documentID, currentHash, map[string]interface{}{"Color": "red"})
+			require.NoError(t, err)
+			mockService.AssertExpectations(t)
+		})
+
+		t.Run("stale hash", func(t *testing.T) {
+			testExecutor, mockService := newExecutor(mockRevision)
+
+			_, err := UpdateIfMatch(testExecutor, "Vehicle", documentID, staleHash, map[string]interface{}{"Color": "red"})
+			var preconditionFailed *PreconditionFailedError
+			require.True(t, errors.As(err, &preconditionFailed))
+			assert.Equal(t, staleHash, preconditionFailed.ExpectedHash)
+			assert.Equal(t, currentHash, preconditionFailed.ActualHash)
+			assert.Equal(t, documentID, preconditionFailed.DocumentID)
+			mockService.AssertNumberOfCalls(t, "executeStatement", 1)
+		})
+
+		t.Run("deleted document", func(t *testing.T) {
+			testExecutor, _ := newExecutor()
+
+			_, err := UpdateIfMatch(testExecutor, "Vehicle", documentID, currentHash, map[string]interface{}{"Color": "red"})
+			var preconditionFailed *PreconditionFailedError
+			require.True(t, errors.As(err, &preconditionFailed))
+			assert.Nil(t, preconditionFailed.ActualHash)
+		})
+
+		t.Run("no changes", func(t *testing.T) {
+			testExecutor, mockService := newExecutor(mockRevision)
+
+			_, err := UpdateIfMatch(testExecutor, "Vehicle", documentID, currentHash, map[string]interface{}{})
+			assert.Error(t, err)
+			mockService.AssertNotCalled(t, "executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	})
+
+	t.Run("DeleteIfMatch", func(t *testing.T) {
+		t.Run("matching hash", func(t *testing.T) {
+			testExecutor, mockService := newExecutor(mockRevision)
+			expectWrite(mockService, "DELETE FROM Vehicle AS d BY docId WHERE docId = ?")
+
+			_, err := DeleteIfMatch(testExecutor, "Vehicle", documentID, currentHash)
+			require.NoError(t, err)
+			mockService.AssertExpectations(t)
+		})
+
+		t.Run("stale hash", func(t *testing.T) {
+			testExecutor, _ := newExecutor(mockRevision)
+
+			_, err := DeleteIfMatch(testExecutor, "Vehicle", documentID, staleHash)
+			var preconditionFailed *PreconditionFailedError
+			assert.True(t, errors.As(err, &preconditionFailed))
+		})
+	})
+}
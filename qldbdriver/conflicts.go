@@ -0,0 +1,132 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/amzn/ion-go/ion"
+)
+
+// maxConflictStatements bounds the number of distinct statements whose OCC conflicts are counted.
+const maxConflictStatements = 1024
+
+// StatementConflictStats counts the OCC conflicts of the transactions that executed a statement, as returned by
+// QLDBDriver.OCCConflictStats. See DriverOptions.TrackOCCConflicts.
+type StatementConflictStats struct {
+	// The PartiQL statement, with its literals redacted.
+	Statement string
+	// The number of transactions that executed the statement and failed to commit because of an OCC conflict.
+	Conflicts int64
+	// When the latest conflict was counted.
+	LastConflict time.Time
+	// The ID of the latest transaction that executed the statement and failed because of an OCC conflict.
+	LastTransactionID string
+	// The parameters of the statement in the latest conflicting transaction, in Ion text, when
+	// DriverOptions.CaptureOCCConflictParameters is set. Otherwise nil.
+	LastParameters []string
+}
+
+// occConflictTracker counts the OCC conflicts of the statements executed by the transactions of a driver.
+type occConflictTracker struct {
+	captureParameters bool
+	lock              sync.Mutex
+	statements        map[string]*StatementConflictStats
+}
+
+func newOCCConflictTracker(captureParameters bool) *occConflictTracker {
+	return &occConflictTracker{
+		captureParameters: captureParameters,
+		statements:        make(map[string]*StatementConflictStats),
+	}
+}
+
+// record counts an OCC conflict of txn for each distinct statement it executed. Statements are counted with their
+// literals redacted, so that the statements that only differ by their literals are counted together.
+func (tracker *occConflictTracker) record(txn *transaction) {
+	now := time.Now()
+	counted := make(map[string]bool, len(txn.results))
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+	for _, res := range txn.results {
+		statement := redactStatement(res.statement)
+		if counted[statement] {
+			continue
+		}
+		counted[statement] = true
+		stats, ok := tracker.statements[statement]
+		if !ok {
+			if len(tracker.statements) >= maxConflictStatements {
+				continue
+			}
+			stats = &StatementConflictStats{Statement: statement}
+			tracker.statements[statement] = stats
+		}
+		stats.Conflicts++
+		stats.LastConflict = now
+		stats.LastTransactionID = *txn.id
+		if tracker.captureParameters {
+			stats.LastParameters = parametersToText(res.parameters)
+		}
+	}
+}
+
+// snapshot returns a copy of the stats, most conflicts first.
+func (tracker *occConflictTracker) snapshot() []StatementConflictStats {
+	tracker.lock.Lock()
+	stats := make([]StatementConflictStats, 0, len(tracker.statements))
+	for _, statement := range tracker.statements {
+		copied := *statement
+		copied.LastParameters = append([]string(nil), statement.LastParameters...)
+		stats = append(stats, copied)
+	}
+	tracker.lock.Unlock()
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Conflicts != stats[j].Conflicts {
+			return stats[i].Conflicts > stats[j].Conflicts
+		}
+		return stats[i].Statement < stats[j].Statement
+	})
+	return stats
+}
+
+// parametersToText returns the Ion text of parameters, or a placeholder for the ones that cannot be marshaled.
+func parametersToText(parameters []interface{}) []string {
+	if parameters == nil {
+		return nil
+	}
+	texts := make([]string, len(parameters))
+	for i, parameter := range parameters {
+		text, err := ion.MarshalText(parameter)
+		if err != nil {
+			texts[i] = "<unmarshalable>"
+			continue
+		}
+		texts[i] = string(text)
+	}
+	return texts
+}
+
+// OCCConflictStats returns, for each statement executed by a transaction that failed to commit because of an OCC
+// conflict, the number of such conflicts, most conflicts first, up to 1024 statements. The statements that conflict
+// most often point at the documents that concurrent transactions contend for, which may be worth splitting or
+// accessing in a different order. It returns nil unless DriverOptions.TrackOCCConflicts is set.
+func (driver *QLDBDriver) OCCConflictStats() []StatementConflictStats {
+	if driver.occConflicts == nil {
+		return nil
+	}
+	return driver.occConflicts.snapshot()
+}
@@ -0,0 +1,104 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOCCConflictStats(t *testing.T) {
+	newDriver := func(captureParameters bool) *QLDBDriver {
+		commits := 0
+		return newMockDriver(t, &qldbsessioniface.MockClientAPI{
+			SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+				if params.CommitTransaction != nil {
+					commits++
+					if commits <= 2 {
+						return nil, testOCC
+					}
+				}
+				return qldbsessioniface.DefaultSendCommandOutput(params), nil
+			},
+		}, func(options *DriverOptions) {
+			options.RetryPolicy = RetryPolicy{MaxRetryLimit: 4, Backoff: ExponentialBackoffStrategy{SleepBase: time.Millisecond, SleepCap: time.Millisecond}}
+			options.TrackOCCConflicts = true
+			options.CaptureOCCConflictParameters = captureParameters
+		})
+	}
+	transfer := func(txn Transaction) (interface{}, error) {
+		for _, statement := range []string{
+			"UPDATE Account SET balance = balance - ? WHERE id = ?",
+			"UPDATE Account SET balance = balance - ? WHERE id = ?",
+			"SELECT balance FROM Account WHERE id = 'A-1'",
+		} {
+			if _, err := txn.Execute(statement, 10, "A-2"); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	}
+
+	t.Run("counts", func(t *testing.T) {
+		testDriver := newDriver(false)
+		_, err := testDriver.Execute(context.Background(), transfer)
+		require.NoError(t, err)
+
+		stats := testDriver.OCCConflictStats()
+		require.Len(t, stats, 2)
+		assert.Equal(t, "SELECT balance FROM Account WHERE id = '?'", stats[0].Statement)
+		assert.Equal(t, "UPDATE Account SET balance = balance - ? WHERE id = ?", stats[1].Statement)
+		for _, stat := range stats {
+			assert.Equal(t, int64(2), stat.Conflicts)
+			assert.Equal(t, qldbsessioniface.MockTransactionID, stat.LastTransactionID)
+			assert.False(t, stat.LastConflict.IsZero())
+			assert.Nil(t, stat.LastParameters)
+		}
+
+		buf := bytes.Buffer{}
+		require.NoError(t, testDriver.DumpDiagnostics(&buf))
+		var bundle diagnostics
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &bundle))
+		assert.True(t, bundle.Configuration.TrackOCCConflicts)
+		require.Len(t, bundle.OCCConflicts, 2)
+		assert.Equal(t, int64(2), bundle.OCCConflicts[0].Conflicts)
+		assert.NotContains(t, buf.String(), "A-1")
+	})
+
+	t.Run("parameters", func(t *testing.T) {
+		testDriver := newDriver(true)
+		_, err := testDriver.Execute(context.Background(), transfer)
+		require.NoError(t, err)
+
+		stats := testDriver.OCCConflictStats()
+		require.Len(t, stats, 2)
+		assert.Equal(t, []string{"10", `"A-2"`}, stats[1].LastParameters)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		testDriver := newDriver(false)
+		testDriver.occConflicts = nil
+		_, err := testDriver.Execute(context.Background(), transfer)
+		require.NoError(t, err)
+		assert.Nil(t, testDriver.OCCConflictStats())
+	})
+}
@@ -0,0 +1,110 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"reflect"
+)
+
+// As stores result, typically the value returned by QLDBDriver.Execute, in the value pointed to by target, which must
+// be a non-nil pointer to a type that result is assignable to. A ResultTypeError describing the dynamic type of result
+// is returned otherwise, instead of the panic of a failed type assertion.
+//
+//	result, err := driver.Execute(ctx, fn)
+//	var person Person
+//	err = qldbdriver.As(result, &person)
+//
+// A nil result is stored as the zero value of a pointer, interface, slice or map target.
+func As(result interface{}, target interface{}) error {
+	targetValue := reflect.ValueOf(target)
+	if targetValue.Kind() != reflect.Ptr || targetValue.IsNil() {
+		return &qldbDriverError{"The target of As must be a non-nil pointer."}
+	}
+	elem := targetValue.Elem()
+	if result == nil {
+		switch elem.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+			elem.Set(reflect.Zero(elem.Type()))
+			return nil
+		}
+		return resultTypeError(elem.Type().String(), result)
+	}
+	resultValue := reflect.ValueOf(result)
+	if !resultValue.Type().AssignableTo(elem.Type()) {
+		return resultTypeError(elem.Type().String(), result)
+	}
+	elem.Set(resultValue)
+	return nil
+}
+
+// AsInt returns result as an int. Any integer type is accepted, such as the int64 of an Ion integer, as long as the
+// value fits in an int.
+func AsInt(result interface{}) (int, error) {
+	value := reflect.ValueOf(result)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		integer := value.Int()
+		if int64(int(integer)) != integer {
+			break
+		}
+		return int(integer), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		integer := value.Uint()
+		if integer > uint64(^uint(0)>>1) {
+			break
+		}
+		return int(integer), nil
+	}
+	return 0, resultTypeError("int", result)
+}
+
+// AsString returns result as a string. Types whose underlying type is string are accepted.
+func AsString(result interface{}) (string, error) {
+	value := reflect.ValueOf(result)
+	if value.Kind() != reflect.String {
+		return "", resultTypeError("string", result)
+	}
+	return value.String(), nil
+}
+
+// AsSlice returns the elements of result, which can be a slice or an array of any type. A nil result is returned as a
+// nil slice.
+func AsSlice(result interface{}) ([]interface{}, error) {
+	if result == nil {
+		return nil, nil
+	}
+	value := reflect.ValueOf(result)
+	switch value.Kind() {
+	case reflect.Slice:
+		if value.IsNil() {
+			return nil, nil
+		}
+	case reflect.Array:
+	default:
+		return nil, resultTypeError("slice", result)
+	}
+	elements := make([]interface{}, value.Len())
+	for i := range elements {
+		elements[i] = value.Index(i).Interface()
+	}
+	return elements, nil
+}
+
+func resultTypeError(expected string, result interface{}) *ResultTypeError {
+	actual := "nil"
+	if result != nil {
+		actual = reflect.TypeOf(result).String()
+	}
+	return &ResultTypeError{Expected: expected, Actual: actual}
+}
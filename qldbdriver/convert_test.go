@@ -0,0 +1,125 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAs(t *testing.T) {
+	type person struct {
+		Name string
+	}
+
+	t.Run("assignable", func(t *testing.T) {
+		var target person
+		require.NoError(t, As(person{Name: "Alice"}, &target))
+		assert.Equal(t, "Alice", target.Name)
+	})
+
+	t.Run("interface", func(t *testing.T) {
+		var target error
+		require.NoError(t, As(errMock, &target))
+		assert.Equal(t, errMock, target)
+	})
+
+	t.Run("nil result", func(t *testing.T) {
+		target := &person{}
+		require.NoError(t, As(nil, &target))
+		assert.Nil(t, target)
+
+		var value person
+		var typeErr *ResultTypeError
+		require.ErrorAs(t, As(nil, &value), &typeErr)
+		assert.Equal(t, "nil", typeErr.Actual)
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		var target person
+		err := As("Alice", &target)
+		var typeErr *ResultTypeError
+		require.ErrorAs(t, err, &typeErr)
+		assert.Equal(t, "qldbdriver.person", typeErr.Expected)
+		assert.Equal(t, "string", typeErr.Actual)
+		assert.Equal(t, "Expected a result of type qldbdriver.person, got string.", err.Error())
+	})
+
+	t.Run("invalid target", func(t *testing.T) {
+		var target person
+		assert.Error(t, As(person{}, target))
+		assert.Error(t, As(person{}, (*person)(nil)))
+	})
+}
+
+func TestAsInt(t *testing.T) {
+	for _, result := range []interface{}{int(5), int8(5), int32(5), int64(5), uint(5), uint64(5)} {
+		value, err := AsInt(result)
+		require.NoError(t, err)
+		assert.Equal(t, 5, value)
+	}
+
+	_, err := AsInt(uint64(math.MaxUint64))
+	assert.Error(t, err)
+
+	_, err = AsInt("5")
+	var typeErr *ResultTypeError
+	require.ErrorAs(t, err, &typeErr)
+	assert.Equal(t, "int", typeErr.Expected)
+	assert.Equal(t, "string", typeErr.Actual)
+
+	_, err = AsInt(nil)
+	assert.Error(t, err)
+}
+
+func TestAsString(t *testing.T) {
+	type name string
+
+	value, err := AsString("Alice")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", value)
+
+	value, err = AsString(name("Bob"))
+	require.NoError(t, err)
+	assert.Equal(t, "Bob", value)
+
+	_, err = AsString(5)
+	assert.Error(t, err)
+}
+
+func TestAsSlice(t *testing.T) {
+	elements, err := AsSlice([]string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"a", "b"}, elements)
+
+	elements, err = AsSlice([2]int{1, 2})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{1, 2}, elements)
+
+	elements, err = AsSlice(nil)
+	require.NoError(t, err)
+	assert.Nil(t, elements)
+
+	elements, err = AsSlice([]string(nil))
+	require.NoError(t, err)
+	assert.Nil(t, elements)
+
+	_, err = AsSlice(map[string]int{})
+	var typeErr *ResultTypeError
+	require.ErrorAs(t, err, &typeErr)
+	assert.Equal(t, "map[string]int", typeErr.Actual)
+}
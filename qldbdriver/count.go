@@ -0,0 +1,103 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+
+	"github.com/amzn/ion-go/ion"
+)
+
+// countRow is the row returned by SELECT COUNT(*), whose unnamed column is named _1 by QLDB.
+type countRow struct {
+	Count *int64 `ion:"_1"`
+}
+
+// Count returns the number of documents of a table matching whereClause, using SELECT COUNT(*). whereClause is
+// optional and, when not empty, is appended to the query after a WHERE keyword, for example `Color = ?`. Use parameters
+// for any values referenced by whereClause. Documents soft deleted by SoftDelete are not counted when
+// DriverOptions.SoftDeleteField is set.
+//
+// The query runs in its own transaction, with the same retries as Execute.
+func (driver *QLDBDriver) Count(ctx context.Context, tableName string, whereClause string, parameters ...interface{}) (int64, error) {
+	if !tableNameRegex.MatchString(tableName) {
+		return 0, &qldbDriverError{"Invalid table name: '" + tableName + "'."}
+	}
+	statement := selectStatement("SELECT COUNT(*) FROM ", tableName, driver.excludeSoftDeleted(whereClause))
+	count, err := driver.readFlights.do(ctx, "Count", statement, parameters, func(ctx context.Context) (interface{}, error) {
+		return driver.Execute(ctx, func(txn Transaction) (interface{}, error) {
+			result, err := txn.Execute(statement, parameters...)
+			if err != nil {
+				return nil, err
+			}
+			if !result.Next(txn) {
+				if result.Err() != nil {
+					return nil, result.Err()
+				}
+				return nil, &qldbDriverError{"SELECT COUNT(*) returned no row."}
+			}
+			row := countRow{}
+			err = ion.Unmarshal(result.GetCurrentData(), &row)
+			if err != nil {
+				return nil, err
+			}
+			if row.Count == nil {
+				return nil, &qldbDriverError{"SELECT COUNT(*) returned no count."}
+			}
+			return *row.Count, nil
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count.(int64), nil
+}
+
+// Exists returns whether a table has a document matching whereClause. whereClause is optional and, when not empty, is
+// appended to the query after a WHERE keyword, for example `VIN = ?`. Use parameters for any values referenced by
+// whereClause. The query projects a constant instead of the matching documents, using SELECT 1, and stops at the first
+// one. Documents soft deleted by SoftDelete are ignored when DriverOptions.SoftDeleteField is set.
+//
+// The query runs in its own transaction, with the same retries as Execute.
+func (driver *QLDBDriver) Exists(ctx context.Context, tableName string, whereClause string, parameters ...interface{}) (bool, error) {
+	if !tableNameRegex.MatchString(tableName) {
+		return false, &qldbDriverError{"Invalid table name: '" + tableName + "'."}
+	}
+	statement := selectStatement("SELECT 1 FROM ", tableName, driver.excludeSoftDeleted(whereClause))
+	exists, err := driver.readFlights.do(ctx, "Exists", statement, parameters, func(ctx context.Context) (interface{}, error) {
+		return driver.Execute(ctx, func(txn Transaction) (interface{}, error) {
+			result, err := txn.Execute(statement, parameters...)
+			if err != nil {
+				return nil, err
+			}
+			if result.Next(txn) {
+				return true, nil
+			}
+			return false, result.Err()
+		})
+	})
+	if err != nil {
+		return false, err
+	}
+	return exists.(bool), nil
+}
+
+// selectStatement appends tableName and the optional whereClause to a SELECT clause.
+func selectStatement(selectClause string, tableName string, whereClause string) string {
+	statement := selectClause + tableName
+	if whereClause != "" {
+		statement += " WHERE " + whereClause
+	}
+	return statement
+}
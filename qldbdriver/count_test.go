@@ -0,0 +1,115 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountAndExists(t *testing.T) {
+	newTestDriver := func(rows ...[]byte) (*QLDBDriver, *qldbsessioniface.MockClientAPI) {
+		mockClient := &qldbsessioniface.MockClientAPI{
+			SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+				output := qldbsessioniface.DefaultSendCommandOutput(params)
+				if params.ExecuteStatement != nil {
+					for _, row := range rows {
+						output.ExecuteStatement.FirstPage.Values = append(output.ExecuteStatement.FirstPage.Values, types.ValueHolder{IonBinary: row})
+					}
+				}
+				return output, nil
+			},
+		}
+		return newMockDriver(t, mockClient), mockClient
+	}
+	executedStatements := func(mockClient *qldbsessioniface.MockClientAPI) []string {
+		var statements []string
+		for _, input := range mockClient.Inputs() {
+			if input.ExecuteStatement != nil {
+				statements = append(statements, *input.ExecuteStatement.Statement)
+			}
+		}
+		return statements
+	}
+
+	t.Run("count", func(t *testing.T) {
+		testDriver, mockClient := newTestDriver(ionTextToBinary(t, `{_1: 42}`))
+
+		count, err := testDriver.Count(context.Background(), "Vehicle", "Color = ?", "red")
+		require.NoError(t, err)
+		assert.Equal(t, int64(42), count)
+		assert.Equal(t, []string{"SELECT COUNT(*) FROM Vehicle WHERE Color = ?"}, executedStatements(mockClient))
+	})
+
+	t.Run("count without row", func(t *testing.T) {
+		testDriver, _ := newTestDriver()
+
+		_, err := testDriver.Count(context.Background(), "Vehicle", "")
+		assert.Error(t, err)
+	})
+
+	t.Run("exists", func(t *testing.T) {
+		testDriver, mockClient := newTestDriver(ionTextToBinary(t, `{_1: 1}`))
+
+		exists, err := testDriver.Exists(context.Background(), "Vehicle", "VIN = ?", "1")
+		require.NoError(t, err)
+		assert.True(t, exists)
+		assert.Equal(t, []string{"SELECT 1 FROM Vehicle WHERE VIN = ?"}, executedStatements(mockClient))
+	})
+
+	t.Run("exists stops at the first row", func(t *testing.T) {
+		mockClient := &qldbsessioniface.MockClientAPI{
+			SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+				output := qldbsessioniface.DefaultSendCommandOutput(params)
+				if params.ExecuteStatement != nil {
+					output.ExecuteStatement.FirstPage.Values = []types.ValueHolder{{IonBinary: ionTextToBinary(t, `{_1: 1}`)}}
+					output.ExecuteStatement.FirstPage.NextPageToken = aws.String("next")
+				}
+				return output, nil
+			},
+		}
+
+		exists, err := newMockDriver(t, mockClient).Exists(context.Background(), "Vehicle", "")
+		require.NoError(t, err)
+		assert.True(t, exists)
+		for _, input := range mockClient.Inputs() {
+			assert.Nil(t, input.FetchPage)
+		}
+	})
+
+	t.Run("does not exist", func(t *testing.T) {
+		testDriver, mockClient := newTestDriver()
+
+		exists, err := testDriver.Exists(context.Background(), "Vehicle", "")
+		require.NoError(t, err)
+		assert.False(t, exists)
+		assert.Equal(t, []string{"SELECT 1 FROM Vehicle"}, executedStatements(mockClient))
+	})
+
+	t.Run("invalid table name", func(t *testing.T) {
+		testDriver, _ := newTestDriver()
+
+		_, err := testDriver.Count(context.Background(), "Vehicle WHERE 1 = 1", "")
+		assert.Error(t, err)
+		_, err = testDriver.Exists(context.Background(), "Vehicle WHERE 1 = 1", "")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,76 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"encoding/base64"
+
+	"github.com/amzn/ion-go/ion"
+)
+
+// Cursor is an opaque position within the result set of a statement.
+//
+// A Cursor is obtained from GetCursor and can be serialized with String to be handed to a client, for example as the
+// continuation token of a paginated HTTP endpoint. Use ParseCursor to restore it and ExecuteFromCursor to resume
+// iteration. QLDB page tokens are only valid within the transaction that produced them, so resuming in a
+// different transaction re-executes the statement and skips the rows that were already consumed. The page token is
+// not included in the string, so that a client can neither read it nor have a forged one sent to QLDB: a parsed
+// Cursor always resumes by re-executing the statement.
+type Cursor struct {
+	statementHash []byte
+	position      int64
+	transactionID string
+	pageToken     *string
+}
+
+type cursorState struct {
+	StatementHash []byte `ion:"statementHash"`
+	Position      int64  `ion:"position"`
+	TransactionID string `ion:"transactionId"`
+}
+
+// ParseCursor restores a Cursor from a string previously returned by Cursor.String.
+func ParseCursor(token string) (*Cursor, error) {
+	ionBinary, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, &qldbDriverError{"Invalid cursor: " + err.Error()}
+	}
+	state := new(cursorState)
+	err = ion.Unmarshal(ionBinary, state)
+	if err != nil {
+		return nil, &qldbDriverError{"Invalid cursor: " + err.Error()}
+	}
+	if len(state.StatementHash) != hashSize || state.Position < 0 {
+		return nil, &qldbDriverError{"Invalid cursor."}
+	}
+	return &Cursor{statementHash: state.StatementHash, position: state.Position, transactionID: state.TransactionID}, nil
+}
+
+// String returns the cursor encoded as an opaque, URL-safe string, without its page token.
+func (cursor *Cursor) String() string {
+	state := cursorState{cursor.statementHash, cursor.position, cursor.transactionID}
+	// Can ignore error here since cursorState only contains marshallable fields
+	ionBinary, _ := ion.MarshalBinary(state)
+	return base64.RawURLEncoding.EncodeToString(ionBinary)
+}
+
+// GetPosition returns the number of rows that were consumed from the result set when the cursor was created.
+func (cursor *Cursor) GetPosition() int64 {
+	return cursor.position
+}
+
+// GetTransactionID returns the ID of the transaction in which the cursor was created.
+func (cursor *Cursor) GetTransactionID() string {
+	return cursor.transactionID
+}
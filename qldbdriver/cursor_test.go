@@ -0,0 +1,65 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T) {
+	mockHash, _ := toQLDBHash("mockStatement")
+	mockToken := "mockToken"
+
+	t.Run("round trip leaves out the page token", func(t *testing.T) {
+		cursor := &Cursor{statementHash: mockHash.hash, position: 5, transactionID: mockTxnID, pageToken: &mockToken}
+
+		parsed, err := ParseCursor(cursor.String())
+		require.NoError(t, err)
+		assert.Equal(t, &Cursor{statementHash: mockHash.hash, position: 5, transactionID: mockTxnID}, parsed)
+		assert.NotContains(t, string(mustDecodeCursor(t, cursor.String())), mockToken)
+	})
+
+	t.Run("round trip without page token", func(t *testing.T) {
+		cursor := &Cursor{statementHash: mockHash.hash, position: 0, transactionID: mockTxnID}
+
+		parsed, err := ParseCursor(cursor.String())
+		require.NoError(t, err)
+		assert.Equal(t, cursor, parsed)
+	})
+
+	t.Run("invalid encoding", func(t *testing.T) {
+		cursor, err := ParseCursor("not a cursor!")
+		assert.Error(t, err)
+		assert.Nil(t, cursor)
+	})
+
+	t.Run("invalid hash", func(t *testing.T) {
+		cursor := &Cursor{statementHash: []byte{1}, position: 1}
+
+		parsed, err := ParseCursor(cursor.String())
+		assert.Error(t, err)
+		assert.Nil(t, parsed)
+	})
+}
+
+// mustDecodeCursor returns the Ion binary encoded in a string returned by Cursor.String.
+func mustDecodeCursor(t *testing.T, token string) []byte {
+	ionBinary, err := base64.RawURLEncoding.DecodeString(token)
+	require.NoError(t, err)
+	return ionBinary
+}
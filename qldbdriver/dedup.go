@@ -0,0 +1,127 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/amzn/ion-go/ion"
+)
+
+// readFlights shares the outcome of a read among the identical reads started while it is in flight, so that they
+// result in a single transaction. Nothing is kept once a read completes: a read started after it executes again. A call
+// whose context is done stops waiting for the shared outcome, and the calls waiting for a read that failed because the
+// context of its own call was done execute the read again. A nil readFlights shares nothing.
+type readFlights struct {
+	lock    sync.Mutex
+	flights map[string]*readFlight
+}
+
+type readFlight struct {
+	// done is closed once value and err are set.
+	done  chan struct{}
+	value interface{}
+	err   error
+	// canceled is whether the read failed because its context was done, in which case its outcome is not shared.
+	canceled bool
+}
+
+func newReadFlights() *readFlights {
+	return &readFlights{flights: make(map[string]*readFlight)}
+}
+
+// do returns the outcome of the read in flight by the helper kind of statement with parameters, or calls read with ctx
+// and shares its outcome if there is none. A read waiting for the outcome of another one stops waiting when ctx is
+// done, and calls read itself if the other read failed because its own context was done.
+func (flights *readFlights) do(ctx context.Context, kind string, statement string, parameters []interface{}, read func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if flights == nil {
+		return read(ctx)
+	}
+	key := readKey(kind, statement, parameters)
+	if key == "" {
+		return read(ctx)
+	}
+	for {
+		flights.lock.Lock()
+		flight, ok := flights.flights[key]
+		if !ok {
+			flight = &readFlight{done: make(chan struct{})}
+			flights.flights[key] = flight
+			flights.lock.Unlock()
+			return flights.lead(ctx, key, flight, read)
+		}
+		flights.lock.Unlock()
+		select {
+		case <-flight.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if !flight.canceled {
+			return flight.value, flight.err
+		}
+	}
+}
+
+// lead calls read for the flight under key, and shares its outcome with the reads waiting for it.
+func (flights *readFlights) lead(ctx context.Context, key string, flight *readFlight, read func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	completed := false
+	defer func() {
+		if !completed {
+			flight.err = &qldbDriverError{"The shared read panicked."}
+		}
+		flights.lock.Lock()
+		// The flight may have been forgotten and replaced by another one
+		if flights.flights[key] == flight {
+			delete(flights.flights, key)
+		}
+		flights.lock.Unlock()
+		close(flight.done)
+	}()
+	flight.value, flight.err = read(ctx)
+	flight.canceled = flight.err != nil && ctx.Err() != nil
+	completed = true
+	return flight.value, flight.err
+}
+
+// forget detaches the read in flight by the helper kind of statement with parameters, if any, so that the reads started
+// afterwards execute again instead of sharing its outcome. The reads already waiting for it still share its outcome.
+func (flights *readFlights) forget(kind string, statement string, parameters []interface{}) {
+	if flights == nil {
+		return
+	}
+	key := readKey(kind, statement, parameters)
+	flights.lock.Lock()
+	defer flights.lock.Unlock()
+	delete(flights.flights, key)
+}
+
+// readKey returns the key identifying a read by the helper kind of a statement with parameters, or "" if a parameter
+// cannot be marshaled, in which case the read is not shared.
+func readKey(kind string, statement string, parameters []interface{}) string {
+	var key strings.Builder
+	key.WriteString(kind)
+	key.WriteByte(0)
+	key.WriteString(statement)
+	for _, parameter := range parameters {
+		ionBinary, err := ion.MarshalBinary(parameter)
+		if err != nil {
+			return ""
+		}
+		key.WriteByte(0)
+		key.Write(ionBinary)
+	}
+	return key.String()
+}
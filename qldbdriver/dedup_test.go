@@ -0,0 +1,247 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitForJoins gives the reads started by a test the time to join the read in flight.
+func waitForJoins() {
+	time.Sleep(20 * time.Millisecond)
+}
+
+func TestReadFlights(t *testing.T) {
+	t.Run("shares the read in flight", func(t *testing.T) {
+		flights := newReadFlights()
+		release := make(chan struct{})
+		var reads int32
+		read := func(ctx context.Context) (interface{}, error) {
+			atomic.AddInt32(&reads, 1)
+			<-release
+			return "value", nil
+		}
+
+		var wg sync.WaitGroup
+		values := make([]interface{}, 4)
+		for i := range values {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				values[i], _ = flights.do(context.Background(), "Count", "SELECT COUNT(*) FROM Person WHERE id = ?", []interface{}{1}, read)
+			}(i)
+			if i == 0 {
+				require.Eventually(t, func() bool { return atomic.LoadInt32(&reads) == 1 }, time.Second, time.Millisecond)
+			}
+		}
+		waitForJoins()
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&reads))
+		assert.Equal(t, []interface{}{"value", "value", "value", "value"}, values)
+		assert.Empty(t, flights.flights)
+
+		// Nothing is kept once the read completed
+		_, err := flights.do(context.Background(), "Count", "SELECT COUNT(*) FROM Person WHERE id = ?", []interface{}{1}, read)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&reads))
+	})
+
+	t.Run("shares errors", func(t *testing.T) {
+		flights := newReadFlights()
+		release := make(chan struct{})
+		done := make(chan error)
+		go func() {
+			_, err := flights.do(context.Background(), "Exists", "SELECT * FROM Person", nil, func(ctx context.Context) (interface{}, error) {
+				<-release
+				return nil, errMock
+			})
+			done <- err
+		}()
+		require.Eventually(t, func() bool {
+			flights.lock.Lock()
+			defer flights.lock.Unlock()
+			return len(flights.flights) == 1
+		}, time.Second, time.Millisecond)
+		go func() {
+			_, err := flights.do(context.Background(), "Exists", "SELECT * FROM Person", nil, func(ctx context.Context) (interface{}, error) { return true, nil })
+			done <- err
+		}()
+		waitForJoins()
+		close(release)
+		assert.True(t, errors.Is(<-done, errMock))
+		assert.True(t, errors.Is(<-done, errMock))
+	})
+
+	t.Run("waiter context done", func(t *testing.T) {
+		flights := newReadFlights()
+		release := make(chan struct{})
+		defer close(release)
+		go func() {
+			_, _ = flights.do(context.Background(), "Count", "SELECT COUNT(*) FROM Person", nil, func(ctx context.Context) (interface{}, error) {
+				<-release
+				return int64(1), nil
+			})
+		}()
+		require.Eventually(t, func() bool {
+			flights.lock.Lock()
+			defer flights.lock.Unlock()
+			return len(flights.flights) == 1
+		}, time.Second, time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := flights.do(ctx, "Count", "SELECT COUNT(*) FROM Person", nil, func(ctx context.Context) (interface{}, error) {
+			return int64(2), nil
+		})
+		assert.Equal(t, context.DeadlineExceeded, err)
+	})
+
+	t.Run("leader context done", func(t *testing.T) {
+		flights := newReadFlights()
+		leaderCtx, cancelLeader := context.WithCancel(context.Background())
+		leaderErr := make(chan error)
+		go func() {
+			_, err := flights.do(leaderCtx, "Count", "SELECT COUNT(*) FROM Person", nil, func(ctx context.Context) (interface{}, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			})
+			leaderErr <- err
+		}()
+		require.Eventually(t, func() bool {
+			flights.lock.Lock()
+			defer flights.lock.Unlock()
+			return len(flights.flights) == 1
+		}, time.Second, time.Millisecond)
+
+		value := make(chan interface{})
+		go func() {
+			count, err := flights.do(context.Background(), "Count", "SELECT COUNT(*) FROM Person", nil, func(ctx context.Context) (interface{}, error) {
+				return int64(2), nil
+			})
+			assert.NoError(t, err)
+			value <- count
+		}()
+		waitForJoins()
+		cancelLeader()
+		assert.Equal(t, context.Canceled, <-leaderErr)
+		assert.Equal(t, int64(2), <-value)
+	})
+
+	t.Run("forgotten read is not shared", func(t *testing.T) {
+		flights := newReadFlights()
+		release := make(chan struct{})
+		done := make(chan interface{})
+		go func() {
+			value, _ := flights.do(context.Background(), "Count", "SELECT COUNT(*) FROM Person", nil, func(ctx context.Context) (interface{}, error) {
+				<-release
+				return 1, nil
+			})
+			done <- value
+		}()
+		require.Eventually(t, func() bool {
+			flights.lock.Lock()
+			defer flights.lock.Unlock()
+			return len(flights.flights) == 1
+		}, time.Second, time.Millisecond)
+
+		flights.forget("Count", "SELECT COUNT(*) FROM Person", nil)
+		value, err := flights.do(context.Background(), "Count", "SELECT COUNT(*) FROM Person", nil, func(ctx context.Context) (interface{}, error) {
+			return 2, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, value)
+		close(release)
+		assert.Equal(t, 1, <-done)
+		assert.Empty(t, flights.flights)
+	})
+
+	t.Run("keys", func(t *testing.T) {
+		statement := "SELECT * FROM Person WHERE id = ?"
+		assert.Equal(t, readKey("Exists", statement, []interface{}{1}), readKey("Exists", statement, []interface{}{1}))
+		assert.NotEqual(t, readKey("Exists", statement, []interface{}{1}), readKey("Exists", statement, []interface{}{2}))
+		assert.NotEqual(t, readKey("Exists", statement, []interface{}{1}), readKey("QueryTyped", statement, []interface{}{1}))
+		assert.Equal(t, "", readKey("Exists", statement, []interface{}{make(chan int)}))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		var flights *readFlights
+		var reads int
+		for i := 0; i < 2; i++ {
+			_, err := flights.do(context.Background(), "Count", "SELECT COUNT(*) FROM Person", nil, func(ctx context.Context) (interface{}, error) {
+				reads++
+				return int64(0), nil
+			})
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 2, reads)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		flights := newReadFlights()
+		assert.Panics(t, func() {
+			_, _ = flights.do(context.Background(), "Count", "SELECT COUNT(*) FROM Person", nil, func(ctx context.Context) (interface{}, error) { panic("read") })
+		})
+		assert.Empty(t, flights.flights)
+	})
+}
+
+func TestDeduplicateReads(t *testing.T) {
+	release := make(chan struct{})
+	var statements int32
+	driver := newMockDriver(t, &qldbsessioniface.MockClientAPI{
+		SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+			output := qldbsessioniface.DefaultSendCommandOutput(params)
+			if params.ExecuteStatement != nil {
+				atomic.AddInt32(&statements, 1)
+				<-release
+				output.ExecuteStatement.FirstPage.Values = []types.ValueHolder{{IonBinary: ionTextToBinary(t, "{_1: 3}")}}
+			}
+			return output, nil
+		},
+	}, func(options *DriverOptions) {
+		options.DeduplicateReads = true
+	})
+
+	var wg sync.WaitGroup
+	counts := make([]int64, 3)
+	for i := range counts {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			counts[i], _ = driver.Count(context.Background(), "Person", "id = ?", 1)
+		}(i)
+		if i == 0 {
+			require.Eventually(t, func() bool { return atomic.LoadInt32(&statements) == 1 }, time.Second, time.Millisecond)
+		}
+	}
+	waitForJoins()
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&statements))
+	assert.Equal(t, []int64{3, 3, 3}, counts)
+}
@@ -0,0 +1,399 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/errs"
+)
+
+// RetryErrorClass classifies the error that caused QLDBDriver.Execute to retry a transaction.
+type RetryErrorClass string
+
+const (
+	// RetryOCCConflict is for transactions that failed to commit because of an OCC conflict.
+	RetryOCCConflict RetryErrorClass = "OCC conflict"
+	// RetryInvalidSession is for transactions whose session expired or was otherwise invalid.
+	RetryInvalidSession RetryErrorClass = "invalid session"
+	// RetryServerError is for transactions that failed because of an internal failure or unavailability of QLDB.
+	RetryServerError RetryErrorClass = "server error"
+	// RetryThrottled is for transactions that QLDB throttled.
+	RetryThrottled RetryErrorClass = "throttled"
+	// RetryAttemptDeadline is for attempts that exceeded their share of RetryPolicy.MaxElapsedTime.
+	RetryAttemptDeadline RetryErrorClass = "attempt deadline exceeded"
+	// RetryCredentials is for transactions retried with refreshed credentials, see
+	// DriverOptions.RetryWithRefreshedCredentials.
+	RetryCredentials RetryErrorClass = "credentials"
+	// RetryOther is for the other retried errors.
+	RetryOther RetryErrorClass = "other"
+)
+
+// defaultRetryLogSize is the number of retry events kept when DriverOptions.RetryLogSize is 0.
+const defaultRetryLogSize = 64
+
+// RetryEvent is a retry of a transaction by QLDBDriver.Execute, as returned by QLDBDriver.RecentRetries.
+type RetryEvent struct {
+	// When the retry was decided.
+	Time time.Time
+	// The ID of the transaction that failed, or "" if it failed to start.
+	TransactionID string
+	// The number of the retry attempt, starting at 1.
+	Attempt int
+	// The class of the error that caused the retry.
+	ErrorClass RetryErrorClass
+	// The message of the error that caused the retry.
+	Error string
+	// The delay before the retry attempt.
+	Delay time.Duration
+}
+
+// retryLog is a ring buffer of the latest retry events of a driver. The zero value keeps defaultRetryLogSize events,
+// and a negative size disables it.
+type retryLog struct {
+	lock   sync.Mutex
+	size   int
+	events []RetryEvent
+	next   int
+}
+
+// record adds an event, replacing the oldest one when the log is full.
+func (log *retryLog) record(event RetryEvent) {
+	log.lock.Lock()
+	defer log.lock.Unlock()
+	size := log.size
+	if size == 0 {
+		size = defaultRetryLogSize
+	}
+	if size < 0 {
+		return
+	}
+	if len(log.events) < size {
+		log.events = append(log.events, event)
+		return
+	}
+	log.events[log.next] = event
+	log.next = (log.next + 1) % size
+}
+
+// snapshot returns a copy of the events, oldest first.
+func (log *retryLog) snapshot() []RetryEvent {
+	log.lock.Lock()
+	defer log.lock.Unlock()
+	events := make([]RetryEvent, 0, len(log.events))
+	events = append(events, log.events[log.next:]...)
+	return append(events, log.events[:log.next]...)
+}
+
+// retryErrorClass returns the class of a retried error.
+func retryErrorClass(err error, attemptExpired bool) RetryErrorClass {
+	switch {
+	case attemptExpired || errors.Is(err, context.DeadlineExceeded):
+		return RetryAttemptDeadline
+	case errs.IsOCCConflict(err):
+		return RetryOCCConflict
+	case errs.IsSessionExpired(err):
+		return RetryInvalidSession
+	case errs.IsServerError(err):
+		return RetryServerError
+	case errs.IsThrottling(err):
+		return RetryThrottled
+	case errs.IsCredentialsError(err):
+		return RetryCredentials
+	}
+	return RetryOther
+}
+
+// recordRetry records a retry decided by Execute.
+func (driver *QLDBDriver) recordRetry(txnErr *txnError, attempt int, attemptExpired bool, delay time.Duration) {
+	err := txnErr.unwrap()
+	driver.retryLog.record(RetryEvent{
+		Time:          time.Now(),
+		TransactionID: txnErr.transactionID,
+		Attempt:       attempt,
+		ErrorClass:    retryErrorClass(err, attemptExpired),
+		Error:         err.Error(),
+		Delay:         delay,
+	})
+}
+
+// RecentRetries returns the latest retries of transactions by Execute, oldest first, up to DriverOptions.RetryLogSize
+// of them. The retries are recorded regardless of the logging verbosity, so that they can be inspected after an
+// incident. They are also included in the bundle written by DumpDiagnostics.
+func (driver *QLDBDriver) RecentRetries() []RetryEvent {
+	return driver.retryLog.snapshot()
+}
+
+// diagnostics is the support bundle written by QLDBDriver.DumpDiagnostics.
+type diagnostics struct {
+	DriverVersion      string                  `json:"driverVersion"`
+	GoVersion          string                  `json:"goVersion"`
+	GeneratedAt        time.Time               `json:"generatedAt"`
+	Configuration      configurationDiagnostic `json:"configuration"`
+	Pool               poolDiagnostic          `json:"pool"`
+	SessionAcquisition acquisitionDiagnostic   `json:"sessionAcquisition"`
+	RecentRetries      []retryDiagnostic       `json:"recentRetries"`
+	OCCConflicts       []conflictDiagnostic    `json:"occConflicts,omitempty"`
+	ServerCapabilities capabilitiesDiagnostic  `json:"serverCapabilities"`
+}
+
+type configurationDiagnostic struct {
+	LedgerName                string         `json:"ledgerName"`
+	MaxConcurrentTransactions int            `json:"maxConcurrentTransactions"`
+	PoolExhaustionPolicy      string         `json:"poolExhaustionPolicy"`
+	PoolExhaustionTimeout     string         `json:"poolExhaustionTimeout"`
+	MaxRetryLimit             int            `json:"maxRetryLimit"`
+	MaxElapsedTime            string         `json:"maxElapsedTime"`
+	Backoff                   string         `json:"backoff"`
+	ThrottleBackoff           string         `json:"throttleBackoff"`
+	LoggerVerbosity           string         `json:"loggerVerbosity"`
+	LogRedaction              string         `json:"logRedaction"`
+	SlowTransactionThreshold  string         `json:"slowTransactionThreshold"`
+	MaxSessionIdleTime        string         `json:"maxSessionIdleTime"`
+	SessionRefresh            bool           `json:"sessionRefresh"`
+	TranslateError            bool           `json:"translateError"`
+	AfterCommit               bool           `json:"afterCommit"`
+	StatementHooks            bool           `json:"statementHooks"`
+	ClockSkewThreshold        string         `json:"clockSkewThreshold"`
+	SDKRetryer                bool           `json:"sdkRetryer"`
+	ClientOptions             int            `json:"clientOptions"`
+	FIPSEndpoint              bool           `json:"fipsEndpoint"`
+	DualStackEndpoint         bool           `json:"dualStackEndpoint"`
+	StatementLimit            int            `json:"statementLimit"`
+	FetchPageRetryLimit       int            `json:"fetchPageRetryLimit"`
+	MaxResultRows             int64          `json:"maxResultRows"`
+	MaxResultBytes            int64          `json:"maxResultBytes"`
+	TableNamesCacheTTL        string         `json:"tableNamesCacheTTL"`
+	StrictStatements          bool           `json:"strictStatements"`
+	IdleGapThreshold          string         `json:"idleGapThreshold"`
+	FailOnIdleGap             bool           `json:"failOnIdleGap"`
+	LintStatements            bool           `json:"lintStatements"`
+	TrackOCCConflicts         bool           `json:"trackOCCConflicts"`
+	SoftDeleteField           string         `json:"softDeleteField,omitempty"`
+	DebugSessionLeaks         bool           `json:"debugSessionLeaks"`
+	ReleaseConsumedRows       bool           `json:"releaseConsumedRows"`
+	DeduplicateReads          bool           `json:"deduplicateReads"`
+	RefreshCredentials        bool           `json:"refreshCredentials"`
+	LedgerRegionCheck         bool           `json:"ledgerRegionCheck"`
+	PoolScalingInterval       string         `json:"poolScalingInterval"`
+	MinIdleSessions           int            `json:"minIdleSessions"`
+	PoolPartitions            map[string]int `json:"poolPartitions,omitempty"`
+}
+
+type poolDiagnostic struct {
+	Closed                          bool           `json:"closed"`
+	TransactionsInProgress          int            `json:"transactionsInProgress"`
+	SessionsCheckedOut              int            `json:"sessionsCheckedOut"`
+	OldestCheckout                  string         `json:"oldestCheckout,omitempty"`
+	ScaledCapacity                  int            `json:"scaledCapacity,omitempty"`
+	PartitionTransactionsInProgress map[string]int `json:"partitionTransactionsInProgress,omitempty"`
+}
+
+type capabilitiesDiagnostic struct {
+	ConsumedIOs       string `json:"consumedIOs"`
+	TimingInformation string `json:"timingInformation"`
+}
+
+type acquisitionDiagnostic struct {
+	Reused              int64  `json:"reused"`
+	Created             int64  `json:"created"`
+	CreateFailures      int64  `json:"createFailures"`
+	Rejected            int64  `json:"rejected"`
+	Throttled           int64  `json:"throttled"`
+	PermitWait          string `json:"permitWait"`
+	StartSessionLatency string `json:"startSessionLatency"`
+}
+
+type retryDiagnostic struct {
+	Time          time.Time       `json:"time"`
+	TransactionID string          `json:"transactionId"`
+	Attempt       int             `json:"attempt"`
+	ErrorClass    RetryErrorClass `json:"errorClass"`
+	Error         string          `json:"error"`
+	Delay         string          `json:"delay"`
+}
+
+type conflictDiagnostic struct {
+	Statement    string    `json:"statement"`
+	Conflicts    int64     `json:"conflicts"`
+	LastConflict time.Time `json:"lastConflict"`
+}
+
+// DumpDiagnostics writes to w a support bundle describing the driver, meant to be attached to bug reports: the versions
+// of the driver and of Go, the configuration of the driver, the state of its session pool, its session acquisition
+// stats, its recent retries and its OCC conflict stats, without their parameters. The bundle is indented JSON. The
+// ledger name is redacted, and so are the string literals of the error messages, which may quote the values of a
+// statement.
+func (driver *QLDBDriver) DumpDiagnostics(w io.Writer) error {
+	driver.lock.Lock()
+	closed := driver.isClosed
+	driver.lock.Unlock()
+
+	resultSizeLimit := resultSizeLimit{}
+	if driver.resultSizeLimit != nil {
+		resultSizeLimit = *driver.resultSizeLimit
+	}
+
+	driver.optionsLock.RLock()
+	bundle := diagnostics{
+		DriverVersion: version,
+		GoVersion:     runtime.Version(),
+		GeneratedAt:   time.Now().UTC(),
+		Configuration: configurationDiagnostic{
+			LedgerName:                "<redacted>",
+			MaxConcurrentTransactions: driver.maxConcurrentTransactions,
+			PoolExhaustionPolicy:      poolExhaustionPolicyName(driver.poolExhaustionPolicy),
+			PoolExhaustionTimeout:     driver.poolExhaustionTimeout.String(),
+			MaxRetryLimit:             driver.retryPolicy.MaxRetryLimit,
+			MaxElapsedTime:            driver.retryPolicy.MaxElapsedTime.String(),
+			Backoff:                   fmt.Sprintf("%+v", driver.retryPolicy.Backoff),
+			ThrottleBackoff:           fmt.Sprintf("%+v", driver.retryPolicy.ThrottleBackoff),
+			SlowTransactionThreshold:  driver.slowTransactionThreshold.String(),
+			MaxSessionIdleTime:        driver.maxSessionIdleTime.String(),
+			SessionRefresh:            driver.sessionRefresher != nil,
+			SDKRetryer:                driver.sdkRetryer != nil,
+			TranslateError:            driver.translateError != nil,
+			AfterCommit:               driver.afterCommit != nil,
+			StatementHooks:            driver.statementHooks != nil,
+			ClockSkewThreshold:        driver.clockSkew.thresholdString(),
+			ClientOptions:             len(driver.clientOptions),
+			FIPSEndpoint:              driver.useFIPSEndpoint,
+			DualStackEndpoint:         driver.useDualStackEndpoint,
+			StatementLimit:            driver.statementLimit,
+			FetchPageRetryLimit:       driver.fetchPageRetry.retryLimit(),
+			MaxResultRows:             resultSizeLimit.rows,
+			MaxResultBytes:            resultSizeLimit.bytes,
+			TableNamesCacheTTL:        driver.tableNamesCacheTTL.String(),
+			StrictStatements:          driver.strictStatements,
+			IdleGapThreshold:          driver.idleGapThreshold.String(),
+			FailOnIdleGap:             driver.failOnIdleGap,
+			LintStatements:            driver.linter != nil,
+			TrackOCCConflicts:         driver.occConflicts != nil,
+			SoftDeleteField:           driver.softDeleteField,
+			DebugSessionLeaks:         driver.sessionCheckouts.captureStacks,
+			ReleaseConsumedRows:       driver.releaseConsumedRows,
+			DeduplicateReads:          driver.readFlights != nil,
+			RefreshCredentials:        driver.refreshCredentials,
+			LedgerRegionCheck:         driver.ledgerRegionCheck != nil,
+		},
+		Pool: poolDiagnostic{Closed: closed},
+	}
+	bundle.Configuration.PoolScalingInterval = time.Duration(0).String()
+	if driver.poolScaler != nil {
+		bundle.Configuration.PoolScalingInterval = driver.poolScaler.interval.String()
+		bundle.Configuration.MinIdleSessions = driver.poolScaler.minIdleSessions
+		bundle.Pool.ScaledCapacity = driver.poolScaler.capacity
+	}
+	driver.optionsLock.RUnlock()
+	if driver.logger != nil {
+		bundle.Configuration.LoggerVerbosity = logLevelName(driver.logger.level())
+		bundle.Configuration.LogRedaction = logRedactionName(driver.logger.redaction)
+	}
+	if driver.semaphore != nil {
+		bundle.Pool.TransactionsInProgress = driver.semaphore.inUse()
+	}
+	if len(driver.partitions) > 0 {
+		bundle.Configuration.PoolPartitions = make(map[string]int, len(driver.partitions))
+		bundle.Pool.PartitionTransactionsInProgress = make(map[string]int, len(driver.partitions))
+		for name, partition := range driver.partitions {
+			bundle.Configuration.PoolPartitions[name] = partition.maxConcurrentTransactions
+			bundle.Pool.PartitionTransactionsInProgress[name] = partition.semaphore.inUse()
+		}
+	}
+	checkouts := driver.SuspectedSessionLeaks(0)
+	bundle.Pool.SessionsCheckedOut = len(checkouts)
+	if len(checkouts) > 0 {
+		bundle.Pool.OldestCheckout = time.Since(checkouts[0].CheckedOutAt).String()
+	}
+	stats := driver.SessionAcquisitionStats()
+	capabilities := driver.ServerCapabilities()
+	bundle.ServerCapabilities = capabilitiesDiagnostic{
+		ConsumedIOs:       capabilities.ConsumedIOs.String(),
+		TimingInformation: capabilities.TimingInformation.String(),
+	}
+	bundle.SessionAcquisition = acquisitionDiagnostic{
+		Reused:              stats.Reused,
+		Created:             stats.Created,
+		CreateFailures:      stats.CreateFailures,
+		Rejected:            stats.Rejected,
+		Throttled:           stats.Throttled,
+		PermitWait:          stats.PermitWait.String(),
+		StartSessionLatency: stats.StartSessionLatency.String(),
+	}
+	bundle.RecentRetries = make([]retryDiagnostic, 0)
+	for _, retry := range driver.RecentRetries() {
+		bundle.RecentRetries = append(bundle.RecentRetries, retryDiagnostic{
+			Time:          retry.Time.UTC(),
+			TransactionID: retry.TransactionID,
+			Attempt:       retry.Attempt,
+			ErrorClass:    retry.ErrorClass,
+			Error:         redactStatement(retry.Error),
+			Delay:         retry.Delay.String(),
+		})
+	}
+	for _, conflict := range driver.OCCConflictStats() {
+		bundle.OCCConflicts = append(bundle.OCCConflicts, conflictDiagnostic{
+			Statement:    conflict.Statement,
+			Conflicts:    conflict.Conflicts,
+			LastConflict: conflict.LastConflict.UTC(),
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(bundle)
+}
+
+func poolExhaustionPolicyName(policy PoolExhaustionPolicy) string {
+	switch policy {
+	case PoolExhaustionFail:
+		return "fail"
+	case PoolExhaustionBlock:
+		return "block"
+	case PoolExhaustionGrow:
+		return "grow"
+	}
+	return "unknown"
+}
+
+func logRedactionName(redaction LogRedaction) string {
+	switch redaction {
+	case LogRedactHash:
+		return "hash"
+	case LogRedactAll:
+		return "all"
+	case LogRedactNone:
+		return "none"
+	}
+	return "unknown"
+}
+
+func logLevelName(level LogLevel) string {
+	switch level {
+	case LogOff:
+		return "off"
+	case LogInfo:
+		return "info"
+	case LogDebug:
+		return "debug"
+	}
+	return "unknown"
+}
@@ -0,0 +1,133 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/aws/smithy-go"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryLog(t *testing.T) {
+	t.Run("ring buffer", func(t *testing.T) {
+		log := retryLog{size: 3}
+		for attempt := 1; attempt <= 5; attempt++ {
+			log.record(RetryEvent{Attempt: attempt})
+		}
+
+		events := log.snapshot()
+		require.Len(t, events, 3)
+		assert.Equal(t, 3, events[0].Attempt)
+		assert.Equal(t, 4, events[1].Attempt)
+		assert.Equal(t, 5, events[2].Attempt)
+	})
+
+	t.Run("default size", func(t *testing.T) {
+		log := retryLog{}
+		for attempt := 1; attempt <= defaultRetryLogSize+1; attempt++ {
+			log.record(RetryEvent{Attempt: attempt})
+		}
+		assert.Len(t, log.snapshot(), defaultRetryLogSize)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		log := retryLog{size: -1}
+		log.record(RetryEvent{Attempt: 1})
+		assert.Empty(t, log.snapshot())
+	})
+
+	t.Run("error classes", func(t *testing.T) {
+		assert.Equal(t, RetryOCCConflict, retryErrorClass(testOCC, false))
+		assert.Equal(t, RetryInvalidSession, retryErrorClass(testISE, false))
+		assert.Equal(t, RetryServerError, retryErrorClass(&smithy.GenericAPIError{Code: "InternalFailure"}, false))
+		assert.Equal(t, RetryThrottled, retryErrorClass(&smithy.GenericAPIError{Code: "ThrottlingException"}, false))
+		assert.Equal(t, RetryAttemptDeadline, retryErrorClass(errors.New("canceled"), true))
+		assert.Equal(t, RetryOther, retryErrorClass(errors.New("other"), false))
+	})
+}
+
+func TestRecentRetries(t *testing.T) {
+	commits := 0
+	testDriver := newMockDriver(t, &qldbsessioniface.MockClientAPI{
+		SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+			if params.CommitTransaction != nil {
+				commits++
+				if commits <= 2 {
+					return nil, testOCC
+				}
+			}
+			return qldbsessioniface.DefaultSendCommandOutput(params), nil
+		},
+	}, func(options *DriverOptions) {
+		options.RetryPolicy = RetryPolicy{MaxRetryLimit: 4, Backoff: ExponentialBackoffStrategy{SleepBase: time.Millisecond, SleepCap: time.Millisecond}}
+	})
+
+	_, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	retries := testDriver.RecentRetries()
+	require.Len(t, retries, 2)
+	for i, retry := range retries {
+		assert.Equal(t, i+1, retry.Attempt)
+		assert.Equal(t, RetryOCCConflict, retry.ErrorClass)
+		assert.Equal(t, qldbsessioniface.MockTransactionID, retry.TransactionID)
+		assert.NotEmpty(t, retry.Error)
+		assert.False(t, retry.Time.IsZero())
+	}
+
+	buf := bytes.Buffer{}
+	require.NoError(t, testDriver.DumpDiagnostics(&buf))
+	var bundle diagnostics
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &bundle))
+	assert.Equal(t, version, bundle.DriverVersion)
+	assert.NotEmpty(t, bundle.GoVersion)
+	assert.Equal(t, "<redacted>", bundle.Configuration.LedgerName)
+	assert.NotContains(t, buf.String(), mockLedgerName)
+	assert.Equal(t, 10, bundle.Configuration.MaxConcurrentTransactions)
+	assert.Equal(t, 4, bundle.Configuration.MaxRetryLimit)
+	assert.Equal(t, "fail", bundle.Configuration.PoolExhaustionPolicy)
+	assert.False(t, bundle.Pool.Closed)
+	assert.Equal(t, 0, bundle.Pool.SessionsCheckedOut)
+	assert.Equal(t, int64(1), bundle.SessionAcquisition.Created)
+	require.Len(t, bundle.RecentRetries, 2)
+	assert.Equal(t, 2, bundle.RecentRetries[1].Attempt)
+	assert.Equal(t, RetryOCCConflict, bundle.RecentRetries[1].ErrorClass)
+}
+
+func TestDumpDiagnosticsRedactsRetryErrors(t *testing.T) {
+	testDriver := &QLDBDriver{
+		ledgerName: mockLedgerName,
+	}
+	testDriver.recordRetry(&txnError{transactionID: "txn", err: errors.New("SELECT * FROM T WHERE ssn = '123-45-6789'")}, 1, false, time.Millisecond)
+
+	buf := bytes.Buffer{}
+	require.NoError(t, testDriver.DumpDiagnostics(&buf))
+	assert.NotContains(t, buf.String(), "123-45-6789")
+	var bundle diagnostics
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &bundle))
+	require.Len(t, bundle.RecentRetries, 1)
+	assert.Equal(t, "txn", bundle.RecentRetries[0].TransactionID)
+	assert.Equal(t, "1ms", bundle.RecentRetries[0].Delay)
+}
@@ -0,0 +1,390 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/amzn/ion-go/ion"
+	"github.com/aws/aws-sdk-go-v2/service/qldb"
+	"github.com/aws/aws-sdk-go-v2/service/qldb/types"
+)
+
+// DigestClient is the subset of the qldb.Client methods used by a DigestVerifier.
+type DigestClient interface {
+	GetDigest(ctx context.Context, params *qldb.GetDigestInput, optFns ...func(*qldb.Options)) (*qldb.GetDigestOutput, error)
+	GetRevision(ctx context.Context, params *qldb.GetRevisionInput, optFns ...func(*qldb.Options)) (*qldb.GetRevisionOutput, error)
+}
+
+var _ DigestClient = (*qldb.Client)(nil)
+
+// LedgerDigest is a digest of the journal of a ledger, which covers every block up to its tip address.
+type LedgerDigest struct {
+	// The SHA-256 hash of the journal up to TipAddress.
+	Digest []byte
+	// The address of the last block covered by the digest.
+	TipAddress BlockAddress
+	// When the digest was fetched.
+	FetchedAt time.Time
+}
+
+// DigestStore persists the digests fetched by a DigestVerifier, so that they can later be used to prove that the
+// journal was not altered, independently of the ledger.
+type DigestStore interface {
+	// SaveDigest persists a digest of the ledger.
+	SaveDigest(ctx context.Context, digest LedgerDigest) error
+}
+
+// DigestVerifierOptions can be used to configure a DigestVerifier during construction.
+type DigestVerifierOptions struct {
+	// The interval between two verifications. Default: 1h.
+	Interval time.Duration
+	// The maximum number of revisions verified against each digest. Default: 5.
+	SampleSize int
+	// How far back from the digest the revisions are sampled. Default: 1h.
+	Lookback time.Duration
+	// Called, in addition to logging at LogInfo level, for each revision that fails verification. Default: nil.
+	OnFailure func(failure DigestVerificationFailure)
+}
+
+// DigestVerificationFailure is a revision whose hash could not be proven against a digest of the ledger.
+type DigestVerificationFailure struct {
+	// The table of the revision.
+	TableName string
+	// The ID of the document of the revision.
+	DocumentID string
+	// The address of the block of the revision.
+	BlockAddress BlockAddress
+	// The digest the revision was verified against.
+	Digest LedgerDigest
+	// Why the verification failed.
+	Reason string
+}
+
+// DigestVerificationReport is the outcome of a verification by a DigestVerifier.
+type DigestVerificationReport struct {
+	// The digest fetched and saved by the verification.
+	Digest LedgerDigest
+	// The number of sampled revisions proven against the digest.
+	Verified int
+	// The sampled revisions that could not be proven against the digest.
+	Failures []DigestVerificationFailure
+}
+
+// DigestVerifierStats counts the verifications made by a DigestVerifier.
+type DigestVerifierStats struct {
+	// The number of verifications that completed.
+	Runs int64
+	// The number of verifications that failed to complete, for example because a QLDB API could not be called.
+	Errors int64
+	// The number of revisions proven against a digest.
+	Verified int64
+	// The number of revisions that could not be proven against a digest.
+	Failures int64
+	// The digest fetched by the latest verification that completed, or nil.
+	LastDigest *LedgerDigest
+}
+
+// DigestVerifier periodically pins the digest of the ledger of a driver to a DigestStore, and verifies a random sample
+// of the revisions committed shortly before the digest against it with the GetRevision API of the QLDB control plane.
+// Call QLDBDriver.StartDigestVerifier for a valid DigestVerifier.
+type DigestVerifier struct {
+	driver     *QLDBDriver
+	client     DigestClient
+	store      DigestStore
+	options    DigestVerifierOptions
+	random     *rand.Rand
+	lock       sync.Mutex
+	verifyLock sync.Mutex
+	stats      DigestVerifierStats
+	stop       chan struct{}
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+// StartDigestVerifier starts a DigestVerifier for the ledger of the driver, which verifies the ledger every
+// DigestVerifierOptions.Interval until Stop is called or the driver is shut down.
+func (driver *QLDBDriver) StartDigestVerifier(client DigestClient, store DigestStore, fns ...func(*DigestVerifierOptions)) (*DigestVerifier, error) {
+	if client == nil {
+		return nil, &qldbDriverError{"Provided QLDB client is nil."}
+	}
+	if store == nil {
+		return nil, &qldbDriverError{"Provided DigestStore is nil."}
+	}
+	options := DigestVerifierOptions{Interval: time.Hour, SampleSize: 5, Lookback: time.Hour}
+	for _, fn := range fns {
+		fn(&options)
+	}
+	if options.Interval <= 0 {
+		return nil, &qldbDriverError{"Interval must be greater than 0."}
+	}
+	if options.SampleSize < 1 {
+		return nil, &qldbDriverError{"SampleSize must be 1 or greater."}
+	}
+	if options.Lookback <= 0 {
+		return nil, &qldbDriverError{"Lookback must be greater than 0."}
+	}
+
+	verifier := &DigestVerifier{
+		driver:  driver,
+		client:  client,
+		store:   store,
+		options: options,
+		random:  rand.New(rand.NewSource(time.Now().UnixNano())),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go verifier.run()
+	return verifier, nil
+}
+
+func (verifier *DigestVerifier) run() {
+	defer close(verifier.done)
+	ticker := time.NewTicker(verifier.options.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-verifier.stop:
+			return
+		case <-ticker.C:
+			verifier.driver.lock.Lock()
+			closed := verifier.driver.isClosed
+			verifier.driver.lock.Unlock()
+			if closed {
+				return
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), verifier.options.Interval)
+			_, err := verifier.Verify(ctx)
+			cancel()
+			if err != nil {
+				verifier.driver.logger.logf(LogInfo, "Digest verification could not complete.\nCaused by '%v'", err)
+			}
+		}
+	}
+}
+
+// Stop stops the periodic verifications, waiting for a verification in progress to complete.
+func (verifier *DigestVerifier) Stop() {
+	verifier.closeOnce.Do(func() {
+		close(verifier.stop)
+	})
+	<-verifier.done
+}
+
+// Stats returns the verifications made since the DigestVerifier was started.
+func (verifier *DigestVerifier) Stats() DigestVerifierStats {
+	verifier.lock.Lock()
+	defer verifier.lock.Unlock()
+	return verifier.stats
+}
+
+// sampledRevision is a revision sampled for verification.
+type sampledRevision struct {
+	TableName    string
+	DocumentID   string       `ion:"id"`
+	BlockAddress BlockAddress `ion:"blockAddress"`
+	Hash         []byte       `ion:"hash"`
+}
+
+// Verify fetches the digest of the ledger, saves it to the DigestStore, and verifies against it a random sample of the
+// revisions committed within DigestVerifierOptions.Lookback before it. It is called periodically, but can also be
+// called directly, for example on demand from an operations endpoint, in which case it waits for a verification in
+// progress to complete. An error is returned if the verification could not complete, whereas revisions that fail
+// verification are reported in the DigestVerificationReport.
+func (verifier *DigestVerifier) Verify(ctx context.Context) (*DigestVerificationReport, error) {
+	verifier.verifyLock.Lock()
+	defer verifier.verifyLock.Unlock()
+	report, err := verifier.verify(ctx)
+	verifier.lock.Lock()
+	defer verifier.lock.Unlock()
+	if err != nil {
+		verifier.stats.Errors++
+		return nil, err
+	}
+	verifier.stats.Runs++
+	verifier.stats.Verified += int64(report.Verified)
+	verifier.stats.Failures += int64(len(report.Failures))
+	digest := report.Digest
+	verifier.stats.LastDigest = &digest
+	return report, nil
+}
+
+func (verifier *DigestVerifier) verify(ctx context.Context) (*DigestVerificationReport, error) {
+	ledgerName := verifier.driver.ledgerName
+	output, err := verifier.client.GetDigest(ctx, &qldb.GetDigestInput{Name: &ledgerName})
+	if err != nil {
+		return nil, err
+	}
+	if output.DigestTipAddress == nil || output.DigestTipAddress.IonText == nil {
+		return nil, &qldbDriverError{"GetDigest returned no digest tip address."}
+	}
+	digest := LedgerDigest{Digest: output.Digest, FetchedAt: time.Now()}
+	digest.TipAddress, err = UnmarshalBlockAddress(*output.DigestTipAddress.IonText)
+	if err != nil {
+		return nil, err
+	}
+	err = verifier.store.SaveDigest(ctx, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	samples, err := verifier.sampleRevisions(ctx, digest)
+	if err != nil {
+		return nil, err
+	}
+	report := &DigestVerificationReport{Digest: digest}
+	for _, sample := range samples {
+		reason, err := verifier.verifyRevision(ctx, sample, digest, output.DigestTipAddress)
+		if err != nil {
+			return nil, err
+		}
+		if reason == "" {
+			report.Verified++
+			continue
+		}
+		failure := DigestVerificationFailure{
+			TableName:    sample.TableName,
+			DocumentID:   sample.DocumentID,
+			BlockAddress: sample.BlockAddress,
+			Digest:       digest,
+			Reason:       reason,
+		}
+		report.Failures = append(report.Failures, failure)
+		verifier.driver.logger.logf(LogInfo, "Digest verification failed for document %s of table %s in block %s/%d: %s",
+			failure.DocumentID, failure.TableName, failure.BlockAddress.StrandID, failure.BlockAddress.SequenceNo, reason)
+		if verifier.options.OnFailure != nil {
+			verifier.options.OnFailure(failure)
+		}
+	}
+	verifier.driver.logger.logf(LogDebug, "Verified %d revisions against the digest at block %s/%d.",
+		report.Verified, digest.TipAddress.StrandID, digest.TipAddress.SequenceNo)
+	return report, nil
+}
+
+// sampleRevisions returns up to SampleSize revisions, chosen at random among the revisions of the user tables committed
+// within Lookback before the digest was fetched and covered by the digest.
+func (verifier *DigestVerifier) sampleRevisions(ctx context.Context, digest LedgerDigest) ([]sampledRevision, error) {
+	tableNames, err := verifier.driver.GetTableNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	since := digest.FetchedAt.Add(-verifier.options.Lookback)
+	samples, err := verifier.driver.Execute(ctx, func(txn Transaction) (interface{}, error) {
+		var samples []sampledRevision
+		seen := 0
+		for _, tableName := range tableNames {
+			if !tableNameRegex.MatchString(tableName) {
+				continue
+			}
+			statement := "SELECT h.metadata.id AS id, h.blockAddress AS blockAddress, h.hash AS hash FROM history(" +
+				tableName + ", ?) AS h"
+			err := ExecuteStream(txn, statement, func(ionBinary []byte) error {
+				revision := sampledRevision{TableName: tableName}
+				err := ion.Unmarshal(ionBinary, &revision)
+				if err != nil {
+					return err
+				}
+				if revision.BlockAddress.StrandID != digest.TipAddress.StrandID ||
+					revision.BlockAddress.SequenceNo > digest.TipAddress.SequenceNo {
+					return nil
+				}
+				// Reservoir sampling keeps each revision with the same probability.
+				seen++
+				if len(samples) < verifier.options.SampleSize {
+					samples = append(samples, revision)
+				} else if i := verifier.random.Intn(seen); i < len(samples) {
+					samples[i] = revision
+				}
+				return nil
+			}, since)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return samples, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return samples.([]sampledRevision), nil
+}
+
+// verifyRevision proves a sampled revision against digest with the GetRevision API, and returns why it failed, or ""
+// if it was proven.
+func (verifier *DigestVerifier) verifyRevision(ctx context.Context, sample sampledRevision, digest LedgerDigest, digestTipAddress *types.ValueHolder) (string, error) {
+	blockAddress, err := blockAddressValue(sample.BlockAddress)
+	if err != nil {
+		return "", err
+	}
+	ledgerName := verifier.driver.ledgerName
+	output, err := verifier.client.GetRevision(ctx, &qldb.GetRevisionInput{
+		Name:             &ledgerName,
+		BlockAddress:     blockAddress,
+		DocumentId:       &sample.DocumentID,
+		DigestTipAddress: digestTipAddress,
+	})
+	if err != nil {
+		return "", err
+	}
+	if output.Revision == nil || output.Revision.IonText == nil || output.Proof == nil || output.Proof.IonText == nil {
+		return "GetRevision returned no revision or proof", nil
+	}
+
+	revision := struct {
+		Hash []byte `ion:"hash"`
+	}{}
+	err = ion.UnmarshalString(*output.Revision.IonText, &revision)
+	if err != nil {
+		return "the revision returned by GetRevision cannot be parsed: " + err.Error(), nil
+	}
+	if !bytes.Equal(revision.Hash, sample.Hash) {
+		return "the hash of the revision in the journal does not match the hash read from the table", nil
+	}
+	proof, err := UnmarshalProof(*output.Proof.IonText)
+	if err != nil {
+		return "the proof returned by GetRevision cannot be parsed: " + err.Error(), nil
+	}
+	verified, err := VerifyRevisionProof(revision.Hash, proof, digest.Digest)
+	if err != nil {
+		return "the proof cannot be verified: " + err.Error(), nil
+	}
+	if !verified {
+		return "the proof does not lead to the digest", nil
+	}
+	return "", nil
+}
+
+// VerifyRevisionProof returns whether the hash of a revision, combined in order with the hashes of a proof returned by
+// the GetRevision API, yields a digest of the ledger. An error is returned if a hash is not a SHA-256 hash.
+func VerifyRevisionProof(revisionHash []byte, proof [][]byte, digest []byte) (bool, error) {
+	if len(revisionHash) != hashSize {
+		return false, &qldbDriverError{"invalid hash"}
+	}
+	candidate := &qldbHash{revisionHash[:hashSize:hashSize]}
+	for _, proofHash := range proof {
+		if len(proofHash) != hashSize {
+			return false, &qldbDriverError{"invalid hash"}
+		}
+		var err error
+		candidate, err = candidate.dot(&qldbHash{proofHash[:hashSize:hashSize]})
+		if err != nil {
+			return false, err
+		}
+	}
+	return bytes.Equal(candidate.hash, digest), nil
+}
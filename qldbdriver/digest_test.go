@@ -0,0 +1,222 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldb"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	sessiontypes "github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDigestClient proves the revisions of revisionHashes with proof against digest.
+type fakeDigestClient struct {
+	digest         []byte
+	proof          [][]byte
+	revisionHashes map[string][]byte
+	err            error
+}
+
+func (client *fakeDigestClient) GetDigest(ctx context.Context, params *qldb.GetDigestInput, optFns ...func(*qldb.Options)) (*qldb.GetDigestOutput, error) {
+	if client.err != nil {
+		return nil, client.err
+	}
+	return &qldb.GetDigestOutput{Digest: client.digest, DigestTipAddress: valueHolder(`{strandId: "S", sequenceNo: 100}`)}, nil
+}
+
+func (client *fakeDigestClient) GetRevision(ctx context.Context, params *qldb.GetRevisionInput, optFns ...func(*qldb.Options)) (*qldb.GetRevisionOutput, error) {
+	proof := make([]string, len(client.proof))
+	for i, hash := range client.proof {
+		proof[i] = "{{" + base64.StdEncoding.EncodeToString(hash) + "}}"
+	}
+	revision := fmt.Sprintf(`{hash: {{%s}}}`, base64.StdEncoding.EncodeToString(client.revisionHashes[*params.DocumentId]))
+	return &qldb.GetRevisionOutput{
+		Revision: valueHolder(revision),
+		Proof:    valueHolder("[" + strings.Join(proof, ", ") + "]"),
+	}, nil
+}
+
+// memoryDigestStore keeps the saved digests in memory.
+type memoryDigestStore struct {
+	lock    sync.Mutex
+	digests []LedgerDigest
+}
+
+func (store *memoryDigestStore) SaveDigest(ctx context.Context, digest LedgerDigest) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	store.digests = append(store.digests, digest)
+	return nil
+}
+
+func testHash(value string) []byte {
+	hash := sha256.Sum256([]byte(value))
+	return hash[:]
+}
+
+func TestVerifyRevisionProof(t *testing.T) {
+	revisionHash, first, second := testHash("revision"), testHash("first"), testHash("second")
+	intermediate, err := (&qldbHash{revisionHash}).dot(&qldbHash{first})
+	require.NoError(t, err)
+	digest, err := intermediate.dot(&qldbHash{second})
+	require.NoError(t, err)
+
+	verified, err := VerifyRevisionProof(revisionHash, [][]byte{first, second}, digest.hash)
+	require.NoError(t, err)
+	assert.True(t, verified)
+	verified, err = VerifyRevisionProof(revisionHash, [][]byte{second, first}, digest.hash)
+	require.NoError(t, err)
+	assert.False(t, verified)
+	_, err = VerifyRevisionProof(revisionHash[:16], [][]byte{first}, digest.hash)
+	assert.Error(t, err)
+}
+
+func TestDigestVerifier(t *testing.T) {
+	proven, tampered, proof := testHash("proven"), testHash("tampered"), testHash("proof")
+	digest, err := (&qldbHash{proven}).dot(&qldbHash{proof})
+	require.NoError(t, err)
+	rows := map[string][]string{
+		"SELECT name FROM information_schema.user_tables WHERE status = 'ACTIVE'": {`{name: "Vehicle"}`},
+		"SELECT h.metadata.id AS id, h.blockAddress AS blockAddress, h.hash AS hash FROM history(Vehicle, ?) AS h": {
+			fmt.Sprintf(`{id: "A", blockAddress: {strandId: "S", sequenceNo: 10}, hash: {{%s}}}`, base64.StdEncoding.EncodeToString(proven)),
+			fmt.Sprintf(`{id: "B", blockAddress: {strandId: "S", sequenceNo: 11}, hash: {{%s}}}`, base64.StdEncoding.EncodeToString(tampered)),
+			fmt.Sprintf(`{id: "C", blockAddress: {strandId: "S", sequenceNo: 101}, hash: {{%s}}}`, base64.StdEncoding.EncodeToString(proven)),
+		},
+	}
+	testDriver := newMockDriver(t, &qldbsessioniface.MockClientAPI{
+		SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+			output := qldbsessioniface.DefaultSendCommandOutput(params)
+			if params.ExecuteStatement != nil {
+				for _, row := range rows[*params.ExecuteStatement.Statement] {
+					output.ExecuteStatement.FirstPage.Values = append(output.ExecuteStatement.FirstPage.Values, sessiontypes.ValueHolder{IonBinary: ionTextToBinary(t, row)})
+				}
+			}
+			return output, nil
+		},
+	})
+	client := &fakeDigestClient{
+		digest:         digest.hash,
+		proof:          [][]byte{proof},
+		revisionHashes: map[string][]byte{"A": proven, "B": proven},
+	}
+
+	t.Run("verify", func(t *testing.T) {
+		store := &memoryDigestStore{}
+		var failures []DigestVerificationFailure
+		verifier, err := testDriver.StartDigestVerifier(client, store, func(options *DigestVerifierOptions) {
+			options.SampleSize = 10
+			options.OnFailure = func(failure DigestVerificationFailure) {
+				failures = append(failures, failure)
+			}
+		})
+		require.NoError(t, err)
+		defer verifier.Stop()
+
+		report, err := verifier.Verify(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Verified)
+		require.Len(t, report.Failures, 1)
+		assert.Equal(t, "B", report.Failures[0].DocumentID)
+		assert.Equal(t, "Vehicle", report.Failures[0].TableName)
+		assert.Equal(t, report.Failures, failures)
+		assert.Equal(t, BlockAddress{StrandID: "S", SequenceNo: 100}, report.Digest.TipAddress)
+
+		require.Len(t, store.digests, 1)
+		assert.Equal(t, digest.hash, store.digests[0].Digest)
+
+		stats := verifier.Stats()
+		assert.Equal(t, int64(1), stats.Runs)
+		assert.Equal(t, int64(1), stats.Verified)
+		assert.Equal(t, int64(1), stats.Failures)
+		require.NotNil(t, stats.LastDigest)
+	})
+
+	t.Run("sample size", func(t *testing.T) {
+		verifier, err := testDriver.StartDigestVerifier(client, &memoryDigestStore{}, func(options *DigestVerifierOptions) {
+			options.SampleSize = 1
+		})
+		require.NoError(t, err)
+		defer verifier.Stop()
+
+		report, err := verifier.Verify(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Verified+len(report.Failures))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		verifier, err := testDriver.StartDigestVerifier(&fakeDigestClient{err: errMock}, &memoryDigestStore{})
+		require.NoError(t, err)
+		defer verifier.Stop()
+
+		_, err = verifier.Verify(context.Background())
+		assert.Equal(t, errMock, err)
+		assert.Equal(t, int64(1), verifier.Stats().Errors)
+	})
+
+	t.Run("periodic", func(t *testing.T) {
+		store := &memoryDigestStore{}
+		verifier, err := testDriver.StartDigestVerifier(client, store, func(options *DigestVerifierOptions) {
+			options.Interval = time.Millisecond
+		})
+		require.NoError(t, err)
+		assert.Eventually(t, func() bool {
+			return verifier.Stats().Runs > 0
+		}, time.Second, time.Millisecond)
+		verifier.Stop()
+		verifier.Stop()
+	})
+
+	t.Run("concurrent verifications", func(t *testing.T) {
+		verifier, err := testDriver.StartDigestVerifier(client, &memoryDigestStore{}, func(options *DigestVerifierOptions) {
+			options.Interval = time.Millisecond
+			options.SampleSize = 1
+		})
+		require.NoError(t, err)
+		defer verifier.Stop()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := verifier.Verify(context.Background())
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+		assert.GreaterOrEqual(t, verifier.Stats().Runs, int64(4))
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := testDriver.StartDigestVerifier(nil, &memoryDigestStore{})
+		assert.Error(t, err)
+		_, err = testDriver.StartDigestVerifier(client, nil)
+		assert.Error(t, err)
+		_, err = testDriver.StartDigestVerifier(client, &memoryDigestStore{}, func(options *DigestVerifierOptions) {
+			options.SampleSize = 0
+		})
+		assert.Error(t, err)
+	})
+}
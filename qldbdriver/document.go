@@ -0,0 +1,203 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"sync"
+	"time"
+
+	"github.com/amzn/ion-go/ion"
+)
+
+// BlockAddress is the location of a block in the journal of a ledger.
+type BlockAddress struct {
+	StrandID   string `ion:"strandId"`
+	SequenceNo int64  `ion:"sequenceNo"`
+}
+
+// Document is a revision of a document, as returned by queries on the committed view of a table
+// (SELECT * FROM _ql_committed_TableName) or on the history function.
+//
+// The revision is parsed lazily on the first call to one of its accessors.
+type Document struct {
+	ionBinary    []byte
+	once         sync.Once
+	err          error
+	data         []byte
+	metadata     *revisionMetadata
+	hash         []byte
+	blockAddress *BlockAddress
+}
+
+type revisionMetadata struct {
+	ID      string    `ion:"id"`
+	Version int64     `ion:"version"`
+	TxTime  time.Time `ion:"txTime"`
+	TxID    string    `ion:"txId"`
+}
+
+// NewDocument wraps the Ion binary of a document revision.
+func NewDocument(ionBinary []byte) *Document {
+	return &Document{ionBinary: ionBinary}
+}
+
+// GetIonBinary returns the whole revision in Ion format.
+func (document *Document) GetIonBinary() []byte {
+	return document.ionBinary
+}
+
+// GetData returns the user data of the revision in Ion format.
+func (document *Document) GetData() ([]byte, error) {
+	if err := document.parse(); err != nil {
+		return nil, err
+	}
+	if document.data == nil {
+		return nil, missingFieldError("data")
+	}
+	return document.data, nil
+}
+
+// UnmarshalData unmarshals the user data of the revision into v using ion.Unmarshal.
+func (document *Document) UnmarshalData(v interface{}) error {
+	data, err := document.GetData()
+	if err != nil {
+		return err
+	}
+	return ion.Unmarshal(data, v)
+}
+
+// GetID returns the unique ID of the document, from metadata.id.
+func (document *Document) GetID() (string, error) {
+	metadata, err := document.getMetadata()
+	if err != nil {
+		return "", err
+	}
+	return metadata.ID, nil
+}
+
+// GetVersion returns the version number of the revision, from metadata.version.
+func (document *Document) GetVersion() (int64, error) {
+	metadata, err := document.getMetadata()
+	if err != nil {
+		return 0, err
+	}
+	return metadata.Version, nil
+}
+
+// GetTxTime returns the time at which the revision was committed to the journal, from metadata.txTime.
+func (document *Document) GetTxTime() (time.Time, error) {
+	metadata, err := document.getMetadata()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return metadata.TxTime, nil
+}
+
+// GetTxID returns the ID of the transaction that committed the revision, from metadata.txId.
+func (document *Document) GetTxID() (string, error) {
+	metadata, err := document.getMetadata()
+	if err != nil {
+		return "", err
+	}
+	return metadata.TxID, nil
+}
+
+// GetHash returns the SHA-256 hash of the revision.
+func (document *Document) GetHash() ([]byte, error) {
+	if err := document.parse(); err != nil {
+		return nil, err
+	}
+	if document.hash == nil {
+		return nil, missingFieldError("hash")
+	}
+	return document.hash, nil
+}
+
+// GetBlockAddress returns the location of the block in which the revision was committed.
+func (document *Document) GetBlockAddress() (*BlockAddress, error) {
+	if err := document.parse(); err != nil {
+		return nil, err
+	}
+	if document.blockAddress == nil {
+		return nil, missingFieldError("blockAddress")
+	}
+	return document.blockAddress, nil
+}
+
+func (document *Document) getMetadata() (*revisionMetadata, error) {
+	if err := document.parse(); err != nil {
+		return nil, err
+	}
+	if document.metadata == nil {
+		return nil, missingFieldError("metadata")
+	}
+	return document.metadata, nil
+}
+
+func (document *Document) parse() error {
+	document.once.Do(func() {
+		document.err = document.parseRevision()
+	})
+	return document.err
+}
+
+func (document *Document) parseRevision() error {
+	reader := ion.NewReaderBytes(document.ionBinary)
+	if !reader.Next() {
+		if reader.Err() != nil {
+			return reader.Err()
+		}
+		return &qldbDriverError{"Document revision is empty."}
+	}
+	if reader.Type() != ion.StructType || reader.IsNull() {
+		return &qldbDriverError{"Document revision must be an Ion struct."}
+	}
+
+	err := reader.StepIn()
+	if err != nil {
+		return err
+	}
+	for reader.Next() {
+		fieldName, err := reader.FieldName()
+		if err != nil {
+			return err
+		}
+		if fieldName == nil || fieldName.Text == nil {
+			continue
+		}
+		switch *fieldName.Text {
+		case "data":
+			document.data, err = ionValueToBinary(reader)
+		case "metadata":
+			document.metadata = new(revisionMetadata)
+			err = unmarshalCurrentValue(reader, document.metadata)
+		case "hash":
+			err = unmarshalCurrentValue(reader, &document.hash)
+		case "blockAddress":
+			document.blockAddress = new(BlockAddress)
+			err = unmarshalCurrentValue(reader, document.blockAddress)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if reader.Err() != nil {
+		return reader.Err()
+	}
+	return reader.StepOut()
+}
+
+func missingFieldError(fieldName string) error {
+	return &qldbDriverError{"Document revision does not contain field '" + fieldName + "'."}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mockRevision = `{
+	blockAddress: {strandId: "JdxjkR9bSYB5jMHWcI464T", sequenceNo: 12},
+	hash: {{aGVsbG8=}},
+	data: {Name: "Jane", Age: 30},
+	metadata: {id: "8F0TPCmdNQ6JTRpiLj2TmW", version: 2, txTime: 2021-06-15T10:30:45.123Z, txId: "L7S9iJqcn9W2M4qOEn1ax9"}
+}`
+
+func TestDocument(t *testing.T) {
+	t.Run("committed view revision", func(t *testing.T) {
+		document := NewDocument(ionTextToBinary(t, mockRevision))
+
+		id, err := document.GetID()
+		require.NoError(t, err)
+		assert.Equal(t, "8F0TPCmdNQ6JTRpiLj2TmW", id)
+
+		version, err := document.GetVersion()
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), version)
+
+		txTime, err := document.GetTxTime()
+		require.NoError(t, err)
+		assert.True(t, time.Date(2021, 6, 15, 10, 30, 45, 123000000, time.UTC).Equal(txTime))
+
+		txID, err := document.GetTxID()
+		require.NoError(t, err)
+		assert.Equal(t, "L7S9iJqcn9W2M4qOEn1ax9", txID)
+
+		hash, err := document.GetHash()
+		require.NoError(t, err)
+		assert.Equal(t, []byte("hello"), hash)
+
+		blockAddress, err := document.GetBlockAddress()
+		require.NoError(t, err)
+		assert.Equal(t, &BlockAddress{StrandID: "JdxjkR9bSYB5jMHWcI464T", SequenceNo: 12}, blockAddress)
+
+		data, err := document.GetData()
+		require.NoError(t, err)
+		assert.Equal(t, ionTextToBinary(t, `{Name: "Jane", Age: 30}`), data)
+
+		person := struct {
+			Name string `ion:"Name"`
+			Age  int    `ion:"Age"`
+		}{}
+		require.NoError(t, document.UnmarshalData(&person))
+		assert.Equal(t, "Jane", person.Name)
+		assert.Equal(t, 30, person.Age)
+	})
+
+	t.Run("missing fields", func(t *testing.T) {
+		document := NewDocument(ionTextToBinary(t, `{data: {Name: "Jane"}}`))
+
+		_, err := document.GetData()
+		assert.NoError(t, err)
+		_, err = document.GetID()
+		assert.Error(t, err)
+		_, err = document.GetHash()
+		assert.Error(t, err)
+		_, err = document.GetBlockAddress()
+		assert.Error(t, err)
+	})
+
+	t.Run("not a struct", func(t *testing.T) {
+		document := NewDocument(ionTextToBinary(t, `[1, 2]`))
+
+		_, err := document.GetData()
+		assert.Error(t, err)
+		_, err = document.GetVersion()
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid Ion", func(t *testing.T) {
+		document := NewDocument([]byte{0xe0, 0x01, 0x00, 0xea, 0xff})
+
+		_, err := document.GetData()
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,88 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hostRecorder records the host of the requests it is sent, and fails them.
+type hostRecorder struct {
+	hosts []string
+}
+
+func (recorder *hostRecorder) Do(request *http.Request) (*http.Response, error) {
+	recorder.hosts = append(recorder.hosts, request.URL.Host)
+	return nil, errors.New("request not sent")
+}
+
+func TestEndpointOptions(t *testing.T) {
+	resolveHost := func(t *testing.T, fns ...func(*DriverOptions)) string {
+		recorder := &hostRecorder{}
+		client := qldbsession.New(qldbsession.Options{
+			Region:      "us-east-1",
+			Credentials: aws.AnonymousCredentials{},
+			HTTPClient:  recorder,
+		})
+		createdDriver, err := New(mockLedgerName, client, append([]func(*DriverOptions){func(options *DriverOptions) {
+			options.LoggerVerbosity = LogOff
+		}}, fns...)...)
+		require.NoError(t, err)
+		defer createdDriver.Shutdown(context.Background())
+
+		assert.Error(t, createdDriver.Validate(context.Background()))
+		require.NotEmpty(t, recorder.hosts)
+		return recorder.hosts[0]
+	}
+
+	t.Run("default", func(t *testing.T) {
+		assert.Equal(t, "session.qldb.us-east-1.amazonaws.com", resolveHost(t))
+	})
+
+	t.Run("FIPS", func(t *testing.T) {
+		assert.Equal(t, "session.qldb-fips.us-east-1.amazonaws.com", resolveHost(t, func(options *DriverOptions) {
+			options.UseFIPSEndpoint = true
+		}))
+	})
+
+	t.Run("dual-stack", func(t *testing.T) {
+		assert.Equal(t, "session.qldb.us-east-1.api.aws", resolveHost(t, func(options *DriverOptions) {
+			options.UseDualStackEndpoint = true
+		}))
+	})
+
+	t.Run("FIPS dual-stack", func(t *testing.T) {
+		assert.Equal(t, "session.qldb-fips.us-east-1.api.aws", resolveHost(t, func(options *DriverOptions) {
+			options.UseFIPSEndpoint = true
+			options.UseDualStackEndpoint = true
+		}))
+	})
+
+	t.Run("client options applied after", func(t *testing.T) {
+		assert.Equal(t, "session.qldb.us-east-1.amazonaws.com", resolveHost(t, func(options *DriverOptions) {
+			options.UseFIPSEndpoint = true
+			options.ClientOptions = []func(*qldbsession.Options){func(options *qldbsession.Options) {
+				options.EndpointOptions.UseFIPSEndpoint = aws.FIPSEndpointStateDisabled
+			}}
+		}))
+	})
+}
@@ -13,6 +13,13 @@ and limitations under the License.
 
 package qldbdriver
 
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // qldbDriverError is returned when an error caused by QLDBDriver has occurred.
 type qldbDriverError struct {
 	errorMessage string
@@ -23,13 +30,371 @@ func (e *qldbDriverError) Error() string {
 	return e.errorMessage
 }
 
-type txnError struct {
-	transactionID string
-	message       string
+// AmbiguousCommitError is returned by QLDBDriver.Execute when the commit of a transaction failed in a way that leaves
+// it unknown whether the transaction was committed, for example when QLDB responded with an internal failure.
+// Functions declared with ExecuteOptions.NonIdempotent are not retried after such a failure, so that their side effects
+// are not duplicated.
+type AmbiguousCommitError struct {
+	// The ID of the transaction whose commit outcome is unknown.
+	TransactionID string
+	// False if ExecuteOptions.VerifyCommit determined that the transaction was not committed, true otherwise.
+	CommittedMaybe bool
+	err            error
+}
+
+// Error returns the message denoting the cause of the error.
+func (e *AmbiguousCommitError) Error() string {
+	if !e.CommittedMaybe {
+		return "Transaction " + e.TransactionID + " was verified as not committed: " + e.err.Error()
+	}
+	return "Outcome of committing transaction " + e.TransactionID + " is unknown: " + e.err.Error()
+}
+
+// Unwrap returns the last error encountered by QLDBDriver.Execute.
+func (e *AmbiguousCommitError) Unwrap() error {
+	return e.err
+}
+
+// LedgerUnavailableError is returned by QLDBDriver.Validate when a session could not be started on the ledger, for
+// example because the ledger does not exist, is not active, or access to it is denied.
+type LedgerUnavailableError struct {
+	// The name of the ledger the driver is configured for.
+	LedgerName string
+	err        error
+}
+
+// Error returns the message denoting the cause of the error.
+func (e *LedgerUnavailableError) Error() string {
+	return "Ledger " + e.LedgerName + " is unavailable: " + e.err.Error()
+}
+
+// Unwrap returns the error returned by QLDB when starting a session.
+func (e *LedgerUnavailableError) Unwrap() error {
+	return e.err
+}
+
+// LedgerRegionError is returned when the DriverOptions.LedgerDescriber shows that the ledger is not in the region of
+// the qldbsession.Client, which QLDB otherwise reports as a BadRequestException that does not mention the region.
+type LedgerRegionError struct {
+	// The name of the ledger the driver is configured for.
+	LedgerName string
+	// The region of the qldbsession.Client.
+	ClientRegion string
+	// The region of the LedgerDescriber.
+	DescriberRegion string
+	// The region of the ledger, or "" if the LedgerDescriber did not find the ledger.
+	LedgerRegion string
+	err          error
+}
+
+// Error returns the message denoting the cause of the error.
+func (e *LedgerRegionError) Error() string {
+	if e.LedgerRegion == "" {
+		return "Ledger " + e.LedgerName + " was not found in region " + e.DescriberRegion + ". Check that the ledger " +
+			"exists in region " + e.ClientRegion + " of the qldbsession.Client."
+	}
+	return "Ledger " + e.LedgerName + " is in region " + e.LedgerRegion + ", but the qldbsession.Client is configured " +
+		"for region " + e.ClientRegion + "."
+}
+
+// Unwrap returns the ResourceNotFoundException returned by DescribeLedger if the ledger was not found, or nil.
+func (e *LedgerRegionError) Unwrap() error {
+	return e.err
+}
+
+// StatementLimitError is returned when executing a statement would exceed DriverOptions.StatementLimit. The statement
+// is not sent to QLDB and the transaction is not retried, so the work should be split across transactions.
+type StatementLimitError struct {
+	// The ID of the transaction that reached the limit.
+	TransactionID string
+	// The maximum number of statements allowed per transaction.
+	Limit int
+}
+
+// Error returns the message denoting the cause of the error.
+func (e *StatementLimitError) Error() string {
+	return "Transaction " + e.TransactionID + " reached the limit of " + strconv.Itoa(e.Limit) + " statements per transaction."
+}
+
+// TransactionIdleError is returned, with DriverOptions.FailOnIdleGap, when a transaction stayed idle for longer than
+// DriverOptions.IdleGapThreshold before a statement or its commit, for example because the function passed to Execute
+// made a slow call to another service. The statement is not sent to QLDB and the transaction is not retried, since a
+// retry would be idle for as long. See QLDBDriver.SplitTransaction to move the slow work outside of the transaction.
+type TransactionIdleError struct {
+	// The ID of the idle transaction.
+	TransactionID string
+	// How long the transaction was idle.
+	Gap time.Duration
+	// The DriverOptions.IdleGapThreshold that was exceeded.
+	Threshold time.Duration
+}
+
+// Error returns the message denoting the cause of the error.
+func (e *TransactionIdleError) Error() string {
+	return "Transaction " + e.TransactionID + " was idle for " + e.Gap.String() + ", exceeding the threshold of " +
+		e.Threshold.String() + "."
+}
+
+// ResultTooLargeError is returned by Result.Err when reading the next row of the result of a statement would exceed
+// DriverOptions.MaxResultRows or DriverOptions.MaxResultBytes, for example because a query is missing a condition. The
+// row is not returned and the transaction is not retried, since a retry would read as many rows.
+type ResultTooLargeError struct {
+	// The ID of the transaction that executed the statement.
+	TransactionID string
+	// The statement, with its literals redacted.
+	Statement string
+	// The number of rows the result would have returned with the row.
+	Rows int64
+	// The number of bytes of Ion binary the result would have returned with the row.
+	Bytes int64
+	// The DriverOptions.MaxResultRows limit, or 0 if the rows are not limited.
+	MaxRows int64
+	// The DriverOptions.MaxResultBytes limit, or 0 if the bytes are not limited.
+	MaxBytes int64
+}
+
+// Error returns the message denoting the cause of the error.
+func (e *ResultTooLargeError) Error() string {
+	if e.MaxRows > 0 && e.Rows > e.MaxRows {
+		return "The result of statement '" + e.Statement + "' in transaction " + e.TransactionID + " exceeded the limit of " +
+			strconv.FormatInt(e.MaxRows, 10) + " rows."
+	}
+	return "The result of statement '" + e.Statement + "' in transaction " + e.TransactionID + " exceeded the limit of " +
+		strconv.FormatInt(e.MaxBytes, 10) + " bytes."
+}
+
+// CursorSkipError is returned by ExecuteFromCursor when resuming a cursor would read and skip more rows than
+// ExecuteOptions.MaxCursorSkip. The statement is not executed.
+type CursorSkipError struct {
+	// The position of the cursor, which is the number of rows that would be skipped.
+	Position int64
+	// The ExecuteOptions.MaxCursorSkip limit.
+	Limit int64
+}
+
+// Error returns the message denoting the cause of the error.
+func (e *CursorSkipError) Error() string {
+	return "Resuming the cursor would skip " + strconv.FormatInt(e.Position, 10) + " rows, more than the limit of " +
+		strconv.FormatInt(e.Limit, 10) + " rows."
+}
+
+// ParameterSizeError is returned when the parameters of a statement exceed a QLDB quota, either because a struct
+// parameter is larger than the maximum size of a document, or because the parameters together are larger than the
+// maximum size of a transaction. The statement is not sent to QLDB, which would otherwise reject it with a
+// BadRequestException.
+type ParameterSizeError struct {
+	// The index of the parameter that is too large, or that made the parameters of the statement too large.
+	Index int
+	// The size in bytes of the parameter, or of the parameters of the statement up to Index, in Ion binary.
+	Size int
+	// The quota in bytes that was exceeded.
+	Limit int
+}
+
+// Error returns the message denoting the cause of the error.
+func (e *ParameterSizeError) Error() string {
+	return "Parameter " + strconv.Itoa(e.Index) + " of the statement is too large for QLDB: " + strconv.Itoa(e.Size) +
+		" bytes exceed the limit of " + strconv.Itoa(e.Limit) + " bytes."
+}
+
+// PreconditionFailedError is returned by UpdateIfMatch and DeleteIfMatch when the latest revision of the document no
+// longer has the expected hash, because the document was modified or deleted since the hash was read.
+type PreconditionFailedError struct {
+	// The name of the table of the document.
+	TableName string
+	// The ID of the document.
+	DocumentID string
+	// The hash that the latest revision was expected to have.
+	ExpectedHash []byte
+	// The hash of the latest revision, or nil if the table has no such document.
+	ActualHash []byte
+}
+
+// Error returns the message denoting the cause of the error.
+func (e *PreconditionFailedError) Error() string {
+	if e.ActualHash == nil {
+		return "Document " + e.DocumentID + " of table " + e.TableName + " does not exist."
+	}
+	return "Document " + e.DocumentID + " of table " + e.TableName + " was modified since its revision hash was read."
+}
+
+// ResultTypeError is returned by As, AsInt, AsString and AsSlice when the result of a transaction does not have the
+// expected type.
+type ResultTypeError struct {
+	// The expected type.
+	Expected string
+	// The dynamic type of the result, or "nil".
+	Actual string
+}
+
+// Error returns the message denoting the cause of the error.
+func (e *ResultTypeError) Error() string {
+	return "Expected a result of type " + e.Expected + ", got " + e.Actual + "."
+}
+
+// StreamingResultError is returned by Result.Err when the next page of a Result is needed after its transaction was
+// committed, typically because the function passed to QLDBDriver.Execute returned the Result. The function should
+// return the BufferedResult of Transaction.BufferResult instead, or be executed with ExecuteOptions.BufferResult.
+type StreamingResultError struct {
+	// The ID of the committed transaction.
+	TransactionID string
+}
+
+// Error returns the message denoting the cause of the error.
+func (e *StreamingResultError) Error() string {
+	return "Cannot fetch the next page of a Result after its transaction " + e.TransactionID + " was committed. " +
+		"Return the BufferedResult of Transaction.BufferResult from the function passed to Execute, or set " +
+		"ExecuteOptions.BufferResult."
+}
+
+// StepError is returned when a step of a workflow run by RunWorkflow or QLDBDriver.ExecuteWorkflow fails. The steps
+// before it were executed in the same transaction, so none of their changes are committed.
+type StepError struct {
+	// The name of the step that failed.
+	Step string
+	// The index of the step that failed.
+	Index int
+	err   error
+}
+
+// Error returns the message denoting the cause of the error.
+func (e *StepError) Error() string {
+	return "Step " + strconv.Itoa(e.Index) + " '" + e.Step + "' of the workflow failed: " + e.err.Error()
+}
+
+// Unwrap returns the error returned by the step.
+func (e *StepError) Unwrap() error {
+	return e.err
+}
+
+// SavepointRollbackError is returned by SavepointTransaction.RollbackTo to roll the transaction of
+// QLDBDriver.ExecuteWithSavepoints back to a savepoint. It is returned by ExecuteWithSavepoints when the function rolled
+// back more than maxSavepointRollbacks times, or rolled back a transaction whose commit outcome was unknown.
+type SavepointRollbackError struct {
+	// The name of the savepoint.
+	Savepoint string
+	err       error
+	replay    []savepointStatement
+}
+
+// Error returns the message denoting the cause of the error.
+func (e *SavepointRollbackError) Error() string {
+	message := "Transaction rolled back to savepoint '" + e.Savepoint + "'"
+	if e.err != nil {
+		message += ": " + e.err.Error()
+	}
+	return message
+}
+
+// Unwrap returns the cause of the rollback passed to SavepointTransaction.RollbackTo.
+func (e *SavepointRollbackError) Unwrap() error {
+	return e.err
+}
+
+// QueryError is returned by QLDBDriver.QueryParallel when one of the statements fails. The other statements are
+// cancelled.
+type QueryError struct {
+	// The index of the statement that failed.
+	Index int
+	err   error
+}
+
+// Error returns the message denoting the cause of the error.
+func (e *QueryError) Error() string {
+	return "Statement " + strconv.Itoa(e.Index) + " failed: " + e.err.Error()
+}
+
+// Unwrap returns the error of the statement.
+func (e *QueryError) Unwrap() error {
+	return e.err
+}
+
+// DeadlineWouldExceedError is returned by QLDBDriver.Execute instead of retrying when the delay before the retry would
+// end after the deadline of its context or of RetryPolicy.MaxElapsedTime. See RetryPolicy.TruncateBackoff.
+type DeadlineWouldExceedError struct {
+	// The delay before the retry.
+	Delay time.Duration
+	// The time that remained before the deadline.
+	Remaining time.Duration
+	err       error
+}
+
+// Error returns the message denoting the cause of the error.
+func (e *DeadlineWouldExceedError) Error() string {
+	return "Retrying after " + e.Delay.String() + " would exceed the deadline in " + e.Remaining.String() + ": " + e.err.Error()
+}
+
+// Unwrap returns the error that caused the retry.
+func (e *DeadlineWouldExceedError) Unwrap() error {
+	return e.err
+}
+
+// Is reports whether target is context.DeadlineExceeded, so that the error is handled like the expiry of the context.
+func (e *DeadlineWouldExceedError) Is(target error) bool {
+	return target == context.DeadlineExceeded
+}
+
+// TransactionExpiredError is returned by QLDBDriver.Execute when the transaction exceeded the maximum lifetime of a QLDB
+// transaction, which usually means that the function passed to Execute ran for too long. Unlike an expired session, such
+// a failure is not retried.
+type TransactionExpiredError struct {
+	// The ID of the expired transaction.
+	TransactionID string
 	err           error
-	canRetry      bool
-	abortSuccess  bool
-	isISE         bool
+}
+
+// Error returns the message denoting the cause of the error.
+func (e *TransactionExpiredError) Error() string {
+	return "Transaction " + e.TransactionID + " has expired: " + e.err.Error()
+}
+
+// Unwrap returns the InvalidSessionException returned by QLDB.
+func (e *TransactionExpiredError) Unwrap() error {
+	return e.err
+}
+
+// CredentialsError is returned when a request could not be authenticated, as classified by errs.IsCredentialsError.
+// The transaction is not retried, since it fails again until the credentials are fixed, unless
+// DriverOptions.RetryWithRefreshedCredentials is set.
+type CredentialsError struct {
+	err error
+}
+
+// Error returns the message denoting the cause of the error, with guidance on fixing the credentials.
+func (e *CredentialsError) Error() string {
+	return "Failed to authenticate with QLDB: " + e.err.Error() + ". Check that the credentials provider of the " +
+		"qldbsession.Client can retrieve credentials, that they have not expired, and that they are allowed to access " +
+		"the ledger."
+}
+
+// Unwrap returns the error returned by the SDK.
+func (e *CredentialsError) Unwrap() error {
+	return e.err
+}
+
+// ModelError is returned by ValidateModel when a model cannot be stored in or read from QLDB as intended.
+type ModelError struct {
+	// The name of the model type.
+	Model string
+	// The problems found in the model, one per field.
+	Problems []string
+}
+
+// Error returns the message listing the problems found in the model.
+func (e *ModelError) Error() string {
+	return "Model " + e.Model + " is invalid: " + strings.Join(e.Problems, "; ")
+}
+
+type txnError struct {
+	transactionID   string
+	message         string
+	err             error
+	canRetry        bool
+	abortSuccess    bool
+	isISE           bool
+	isCredentials   bool
+	isThrottle      bool
+	ambiguousCommit bool
 }
 
 func (e *txnError) unwrap() error {
@@ -0,0 +1,140 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+// Package errs classifies the errors returned by the QLDB driver, so that applications can handle them without
+// depending on the exception types of the QLDB Session service or on their messages.
+//
+// The helpers unwrap errors with errors.As, so they also classify errors wrapped by the driver, such as
+// qldbdriver.AmbiguousCommitError, or by the application.
+package errs
+
+import (
+	"errors"
+	"regexp"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/aws/smithy-go"
+)
+
+var transactionExpiredRegex = regexp.MustCompile(`Transaction\s.*\shas\sexpired`)
+
+// credentialsErrorCodes are the codes of the errors returned by AWS services that reject the credentials of a request.
+var credentialsErrorCodes = map[string]bool{
+	"AccessDeniedException":       true,
+	"ExpiredTokenException":       true,
+	"IncompleteSignature":         true,
+	"InvalidClientTokenId":        true,
+	"InvalidSignatureException":   true,
+	"MissingAuthenticationToken":  true,
+	"UnrecognizedClientException": true,
+}
+
+// throttlingErrorCodes are the codes of the errors returned by AWS services that throttle a request.
+var throttlingErrorCodes = map[string]bool{
+	"Throttling":               true,
+	"ThrottlingException":      true,
+	"TooManyRequestsException": true,
+}
+
+// IsRetryable returns true if the error is one that QLDBDriver.Execute retries with a new transaction: an OCC
+// conflict, an expired or otherwise invalid session, an internal failure or unavailability of QLDB, or a throttled
+// request.
+func IsRetryable(err error) bool {
+	return IsOCCConflict(err) || IsSessionExpired(err) || IsServerError(err) || IsThrottling(err)
+}
+
+// IsOCCConflict returns true if the transaction failed to commit because of an optimistic concurrency control conflict
+// with another transaction.
+func IsOCCConflict(err error) bool {
+	var occ *types.OccConflictException
+	return errors.As(err, &occ)
+}
+
+// IsSessionExpired returns true if the session is no longer valid, for example because it expired or was closed by
+// QLDB. It returns false for an expired transaction, see IsTransactionExpired.
+func IsSessionExpired(err error) bool {
+	var ise *types.InvalidSessionException
+	return errors.As(err, &ise) && !transactionExpiredRegex.MatchString(ise.ErrorMessage())
+}
+
+// IsTransactionExpired returns true if the transaction exceeded the maximum lifetime of a QLDB transaction. Such a
+// transaction is not retried, since a retry would likely take as long.
+func IsTransactionExpired(err error) bool {
+	var ise *types.InvalidSessionException
+	return errors.As(err, &ise) && transactionExpiredRegex.MatchString(ise.ErrorMessage())
+}
+
+// IsCapacityExceeded returns true if QLDB rejected the request because the ledger exceeded its capacity.
+func IsCapacityExceeded(err error) bool {
+	var capacityExceeded *types.CapacityExceededException
+	return errors.As(err, &capacityExceeded)
+}
+
+// IsBadRequest returns true if QLDB rejected the request as invalid, for example a statement with a syntax error or
+// referencing a table that does not exist. Such a request fails again if it is retried. Depending on the version of
+// the SDK, QLDB statement errors are returned either as a BadRequestException or as an unmodeled API error with the
+// code 412.
+func IsBadRequest(err error) bool {
+	var badRequest *types.BadRequestException
+	if errors.As(err, &badRequest) {
+		return true
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		code := apiErr.ErrorCode()
+		return code == "BadRequestException" || code == "412"
+	}
+	return false
+}
+
+// IsServerError returns true if QLDB failed to process the request because of an internal failure or unavailability.
+func IsServerError(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		code := apiErr.ErrorCode()
+		return code == "InternalFailure" || code == "ServiceUnavailable"
+	}
+	return false
+}
+
+// IsThrottling returns true if QLDB throttled the request because the rate of requests exceeded the limits of the
+// account, either with a ThrottlingException or with a "Rate exceeded" error.
+func IsThrottling(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return throttlingErrorCodes[apiErr.ErrorCode()] || apiErr.ErrorMessage() == "Rate exceeded"
+	}
+	return false
+}
+
+// IsCredentialsError returns true if the request could not be authenticated: either the credentials provider of the
+// client failed to retrieve credentials, for example because the role to assume does not exist or STS throttled the
+// request, or QLDB rejected the credentials with a 401 or 403 status, for example because they expired or are not
+// allowed to access the ledger.
+func IsCredentialsError(err error) bool {
+	var signingErr *v4.SigningError
+	if errors.As(err, &signingErr) {
+		return true
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && credentialsErrorCodes[apiErr.ErrorCode()] {
+		return true
+	}
+	var responseErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &responseErr) {
+		status := responseErr.HTTPStatusCode()
+		return status == 401 || status == 403
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassification(t *testing.T) {
+	occ := &types.OccConflictException{Message: stringPtr("conflict")}
+	sessionExpired := &types.InvalidSessionException{Message: stringPtr("Session expired")}
+	transactionExpired := &types.InvalidSessionException{Message: stringPtr("Transaction 324weqr2 has expired")}
+	capacityExceeded := &types.CapacityExceededException{Message: stringPtr("capacity")}
+	internalFailure := &smithy.GenericAPIError{Code: "InternalFailure", Message: "failure"}
+	serviceUnavailable := &smithy.GenericAPIError{Code: "ServiceUnavailable", Message: "unavailable"}
+	throttling := &smithy.GenericAPIError{Code: "ThrottlingException", Message: "throttled"}
+	rateExceeded := &smithy.GenericAPIError{Code: "400", Message: "Rate exceeded"}
+	badRequest := &types.BadRequestException{Message: stringPtr("bad request")}
+	unmodeledBadRequest := &smithy.GenericAPIError{Code: "412", Message: "Table with name: T already exists"}
+	other := errors.New("other")
+
+	testCases := []struct {
+		name               string
+		err                error
+		retryable          bool
+		occConflict        bool
+		sessionExpired     bool
+		transactionExpired bool
+		capacityExceeded   bool
+		serverError        bool
+		badRequest         bool
+		throttling         bool
+	}{
+		{"OCC conflict", occ, true, true, false, false, false, false, false, false},
+		{"session expired", sessionExpired, true, false, true, false, false, false, false, false},
+		{"transaction expired", transactionExpired, false, false, false, true, false, false, false, false},
+		{"capacity exceeded", capacityExceeded, false, false, false, false, true, false, false, false},
+		{"internal failure", internalFailure, true, false, false, false, false, true, false, false},
+		{"service unavailable", serviceUnavailable, true, false, false, false, false, true, false, false},
+		{"throttling", throttling, true, false, false, false, false, false, false, true},
+		{"rate exceeded", rateExceeded, true, false, false, false, false, false, false, true},
+		{"bad request", badRequest, false, false, false, false, false, false, true, false},
+		{"unmodeled bad request", unmodeledBadRequest, false, false, false, false, false, false, true, false},
+		{"other error", other, false, false, false, false, false, false, false, false},
+		{"nil", nil, false, false, false, false, false, false, false, false},
+		{"wrapped OCC conflict", fmt.Errorf("wrapped: %w", occ), true, true, false, false, false, false, false, false},
+		{"wrapped transaction expired", fmt.Errorf("wrapped: %w", transactionExpired), false, false, false, true, false, false, false, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.retryable, IsRetryable(tc.err))
+			assert.Equal(t, tc.occConflict, IsOCCConflict(tc.err))
+			assert.Equal(t, tc.sessionExpired, IsSessionExpired(tc.err))
+			assert.Equal(t, tc.transactionExpired, IsTransactionExpired(tc.err))
+			assert.Equal(t, tc.capacityExceeded, IsCapacityExceeded(tc.err))
+			assert.Equal(t, tc.serverError, IsServerError(tc.err))
+			assert.Equal(t, tc.badRequest, IsBadRequest(tc.err))
+			assert.Equal(t, tc.throttling, IsThrottling(tc.err))
+		})
+	}
+}
+
+func TestIsCredentialsError(t *testing.T) {
+	responseError := func(status int) error {
+		return &smithy.OperationError{ServiceID: "QLDB Session", OperationName: "SendCommand", Err: &awshttp.ResponseError{
+			ResponseError: &smithyhttp.ResponseError{
+				Response: &smithyhttp.Response{Response: &http.Response{StatusCode: status}},
+				Err:      errors.New("response error"),
+			},
+		}}
+	}
+
+	testCases := []struct {
+		name        string
+		err         error
+		credentials bool
+	}{
+		{"credentials retrieval", &smithy.OperationError{Err: &v4.SigningError{Err: errors.New("failed to retrieve credentials: throttled")}}, true},
+		{"expired token", &smithy.GenericAPIError{Code: "ExpiredTokenException", Message: "expired"}, true},
+		{"unrecognized client", &smithy.GenericAPIError{Code: "UnrecognizedClientException", Message: "invalid token"}, true},
+		{"forbidden", responseError(403), true},
+		{"unauthorized", responseError(401), true},
+		{"server error", responseError(500), false},
+		{"internal failure", &smithy.GenericAPIError{Code: "InternalFailure", Message: "failure"}, false},
+		{"bad request", &types.BadRequestException{Message: stringPtr("bad request")}, false},
+		{"other error", errors.New("other"), false},
+		{"nil", nil, false},
+		{"wrapped", fmt.Errorf("wrapped: %w", responseError(403)), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.credentials, IsCredentialsError(tc.err))
+		})
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,257 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/amzn/ion-go/ion"
+)
+
+// ExportFormat is the format in which QLDBDriver.Export writes rows.
+type ExportFormat int
+
+const (
+	// ExportIonText writes each row as an Ion text value on its own line.
+	ExportIonText ExportFormat = iota
+	// ExportJSONLines writes each row as a JSON value on its own line, converted with the default JSONOptions.
+	ExportJSONLines
+	// ExportCSV writes rows as CSV records, preceded by a header record. The columns are the fields of the first row,
+	// in order, and fields of later rows that are not columns are dropped. Strings and symbols are written as is, and
+	// other values are converted to JSON. Rows that are not structs, as returned by SELECT VALUE, are written to a
+	// single column named "value".
+	ExportCSV
+)
+
+// Export executes a statement in a new transaction and streams the result set to w in the given format, returning the
+// number of rows written. Pages are fetched as rows are written, so a slow writer slows down the export rather than
+// the result set being held in memory.
+//
+// The transaction is retried like with Execute as long as nothing was written to w. Once rows were written, a failure
+// is returned instead of writing the rows again.
+func (driver *QLDBDriver) Export(ctx context.Context, w io.Writer, format ExportFormat, statement string, parameters ...interface{}) (int, error) {
+	if format < ExportIonText || format > ExportCSV {
+		return 0, &qldbDriverError{"Invalid export format: " + strconv.Itoa(int(format)) + "."}
+	}
+
+	counter := &countingWriter{writer: w}
+	rows := 0
+	_, err := driver.Execute(ctx, func(txn Transaction) (interface{}, error) {
+		if counter.written > 0 {
+			return nil, &qldbDriverError{"Export failed after writing " + strconv.Itoa(rows) + " rows and cannot be retried."}
+		}
+		var err error
+		rows, err = ExportStatement(txn, counter, format, statement, parameters...)
+		return nil, err
+	})
+	return rows, err
+}
+
+// ExportStatement executes a statement within the transaction and streams the result set to w in the given format,
+// returning the number of rows written. Unlike QLDBDriver.Export, it does not prevent rows from being written again
+// when the transaction is retried.
+func ExportStatement(txn Transaction, w io.Writer, format ExportFormat, statement string, parameters ...interface{}) (int, error) {
+	if format < ExportIonText || format > ExportCSV {
+		return 0, &qldbDriverError{"Invalid export format: " + strconv.Itoa(int(format)) + "."}
+	}
+
+	rows := 0
+	exporter := newRowExporter(w, format)
+	err := ExecuteStream(txn, statement, func(ionBinary []byte) error {
+		err := exporter.writeRow(ionBinary)
+		if err != nil {
+			return err
+		}
+		rows++
+		return nil
+	}, parameters...)
+	if err != nil {
+		return rows, err
+	}
+	return rows, exporter.flush()
+}
+
+// countingWriter counts the bytes written to a writer.
+type countingWriter struct {
+	writer  io.Writer
+	written int64
+}
+
+func (counter *countingWriter) Write(p []byte) (int, error) {
+	n, err := counter.writer.Write(p)
+	counter.written += int64(n)
+	return n, err
+}
+
+type rowExporter interface {
+	writeRow(ionBinary []byte) error
+	flush() error
+}
+
+func newRowExporter(w io.Writer, format ExportFormat) rowExporter {
+	switch format {
+	case ExportJSONLines:
+		return &jsonLinesExporter{w}
+	case ExportCSV:
+		return &csvExporter{writer: csv.NewWriter(w)}
+	default:
+		return &ionTextExporter{w}
+	}
+}
+
+type ionTextExporter struct {
+	writer io.Writer
+}
+
+func (exporter *ionTextExporter) writeRow(ionBinary []byte) error {
+	reader := ion.NewReaderBytes(ionBinary)
+	if !reader.Next() {
+		return reader.Err()
+	}
+	// The text writer ends the value with a new line when finished
+	writer := ion.NewTextWriter(exporter.writer)
+	err := copyIonValue(reader, writer)
+	if err != nil {
+		return err
+	}
+	return writer.Finish()
+}
+
+func (exporter *ionTextExporter) flush() error {
+	return nil
+}
+
+type jsonLinesExporter struct {
+	writer io.Writer
+}
+
+func (exporter *jsonLinesExporter) writeRow(ionBinary []byte) error {
+	converted, err := ionToJSON(ionBinary)
+	if err != nil {
+		return err
+	}
+	_, err = exporter.writer.Write(append(converted, '\n'))
+	return err
+}
+
+func (exporter *jsonLinesExporter) flush() error {
+	return nil
+}
+
+type csvExporter struct {
+	writer      *csv.Writer
+	columns     []string
+	wroteHeader bool
+}
+
+func (exporter *csvExporter) writeRow(ionBinary []byte) error {
+	reader := ion.NewReaderBytes(ionBinary)
+	if !reader.Next() {
+		return reader.Err()
+	}
+
+	fields := map[string]string{}
+	isStruct := reader.Type() == ion.StructType && !reader.IsNull()
+	if !isStruct {
+		value, err := csvValue(reader)
+		if err != nil {
+			return err
+		}
+		fields["value"] = value
+	} else {
+		err := reader.StepIn()
+		if err != nil {
+			return err
+		}
+		names := make([]string, 0)
+		for reader.Next() {
+			fieldName, err := reader.FieldName()
+			if err != nil {
+				return err
+			}
+			if fieldName == nil || fieldName.Text == nil {
+				continue
+			}
+			if _, ok := fields[*fieldName.Text]; !ok {
+				names = append(names, *fieldName.Text)
+			}
+			fields[*fieldName.Text], err = csvValue(reader)
+			if err != nil {
+				return err
+			}
+		}
+		if reader.Err() != nil {
+			return reader.Err()
+		}
+		if exporter.columns == nil {
+			exporter.columns = names
+		}
+	}
+
+	if exporter.columns == nil {
+		exporter.columns = []string{"value"}
+	}
+	if !exporter.wroteHeader {
+		err := exporter.writer.Write(exporter.columns)
+		if err != nil {
+			return err
+		}
+		exporter.wroteHeader = true
+	}
+	record := make([]string, len(exporter.columns))
+	for i, column := range exporter.columns {
+		record[i] = fields[column]
+	}
+	err := exporter.writer.Write(record)
+	if err != nil {
+		return err
+	}
+	// Flush every row so that the output is streamed rather than buffered by the CSV writer
+	exporter.writer.Flush()
+	return exporter.writer.Error()
+}
+
+func (exporter *csvExporter) flush() error {
+	exporter.writer.Flush()
+	return exporter.writer.Error()
+}
+
+// csvValue returns the value the reader is positioned on as a CSV field. Strings and symbols are returned as is, Ion
+// nulls are returned as empty fields and other values are converted to JSON.
+func csvValue(reader ion.Reader) (string, error) {
+	if reader.IsNull() {
+		return "", nil
+	}
+	switch reader.Type() {
+	case ion.StringType:
+		val, err := reader.StringValue()
+		if err != nil {
+			return "", err
+		}
+		return *val, nil
+	case ion.SymbolType:
+		val, err := reader.SymbolValue()
+		if err != nil || val.Text == nil {
+			return "", err
+		}
+		return *val.Text, nil
+	}
+	buf := bytes.Buffer{}
+	err := writeJSONValue(reader, &buf, &JSONOptions{})
+	return buf.String(), err
+}
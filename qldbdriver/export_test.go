@@ -0,0 +1,139 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExport(t *testing.T) {
+	rows := []string{
+		`{Name: "Jane", Age: 30, Tags: ["a"]}`,
+		`{Name: "John, Jr.", Extra: 1, Age: null}`,
+	}
+	newTestDriver := func(rows []string, failExecute int) *QLDBDriver {
+		values := make([]types.ValueHolder, len(rows))
+		for i, row := range rows {
+			values[i] = types.ValueHolder{IonBinary: ionTextToBinary(t, row)}
+		}
+		nextPageToken := "nextPage"
+		executions := 0
+		mockClient := &qldbsessioniface.MockClientAPI{
+			SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+				switch {
+				case params.ExecuteStatement != nil:
+					executions++
+					if executions <= failExecute {
+						return nil, testOCC
+					}
+					return &qldbsession.SendCommandOutput{ExecuteStatement: &types.ExecuteStatementResult{
+						FirstPage: &types.Page{Values: values[:1], NextPageToken: &nextPageToken},
+					}}, nil
+				case params.FetchPage != nil:
+					return &qldbsession.SendCommandOutput{FetchPage: &types.FetchPageResult{
+						Page: &types.Page{Values: values[1:]},
+					}}, nil
+				}
+				return qldbsessioniface.DefaultSendCommandOutput(params), nil
+			},
+		}
+		return newMockDriver(t, mockClient, func(options *DriverOptions) {
+			options.RetryPolicy = RetryPolicy{
+				MaxRetryLimit: 2,
+				Backoff:       ExponentialBackoffStrategy{SleepBase: time.Millisecond, SleepCap: time.Millisecond}}
+		})
+	}
+
+	t.Run("Ion text", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		count, err := newTestDriver(rows, 0).Export(context.Background(), &buf, ExportIonText, "SELECT * FROM Person")
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, "{Name:\"Jane\",Age:30,Tags:[\"a\"]}\n{Name:\"John, Jr.\",Extra:1,Age:null.null}\n", buf.String())
+	})
+
+	t.Run("JSON lines", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		count, err := newTestDriver(rows, 0).Export(context.Background(), &buf, ExportJSONLines, "SELECT * FROM Person")
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, "{\"Name\":\"Jane\",\"Age\":30,\"Tags\":[\"a\"]}\n{\"Name\":\"John, Jr.\",\"Extra\":1,\"Age\":null}\n", buf.String())
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		count, err := newTestDriver(rows, 0).Export(context.Background(), &buf, ExportCSV, "SELECT * FROM Person")
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, "Name,Age,Tags\nJane,30,\"[\"\"a\"\"]\"\n\"John, Jr.\",,\n", buf.String())
+	})
+
+	t.Run("CSV of values", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		_, err := newTestDriver([]string{`"Jane"`, `12.50`}, 0).Export(context.Background(), &buf, ExportCSV, "SELECT VALUE Name FROM Person")
+		require.NoError(t, err)
+		assert.Equal(t, "value\nJane\n12.50\n", buf.String())
+	})
+
+	t.Run("retried before writing", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		count, err := newTestDriver(rows, 1).Export(context.Background(), &buf, ExportJSONLines, "SELECT * FROM Person")
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		_, err := newTestDriver(rows, 0).Export(context.Background(), &bytes.Buffer{}, ExportFormat(7), "SELECT * FROM Person")
+		assert.Error(t, err)
+	})
+}
+
+func TestExportNotRetriedAfterWriting(t *testing.T) {
+	buf := bytes.Buffer{}
+	commits := 0
+	value := ionTextToBinary(t, `{Name: "Jane"}`)
+	mockClient := &qldbsessioniface.MockClientAPI{
+		SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+			switch {
+			case params.ExecuteStatement != nil:
+				return &qldbsession.SendCommandOutput{ExecuteStatement: &types.ExecuteStatementResult{
+					FirstPage: &types.Page{Values: []types.ValueHolder{{IonBinary: value}}},
+				}}, nil
+			case params.CommitTransaction != nil:
+				commits++
+				return nil, testOCC
+			}
+			return qldbsessioniface.DefaultSendCommandOutput(params), nil
+		},
+	}
+	testDriver := newMockDriver(t, mockClient, func(options *DriverOptions) {
+		options.RetryPolicy = RetryPolicy{
+			MaxRetryLimit: 2,
+			Backoff:       ExponentialBackoffStrategy{SleepBase: time.Millisecond, SleepCap: time.Millisecond}}
+	})
+
+	_, err := testDriver.Export(context.Background(), &buf, ExportJSONLines, "SELECT * FROM Person")
+	assert.Error(t, err)
+	assert.Equal(t, 1, commits)
+	assert.Equal(t, "{\"Name\":\"Jane\"}\n", buf.String())
+}
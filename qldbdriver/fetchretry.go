@@ -0,0 +1,74 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/errs"
+)
+
+// fetchPageRetry retries the FetchPage commands of the results of a transaction that fail transiently. Fetching the
+// page of a token again returns the same page, so a retry does not lose or repeat the rows read so far.
+type fetchPageRetry struct {
+	limit   int
+	backoff BackoffStrategy
+}
+
+// canRetryFetchPage returns whether a FetchPage command that failed with err can be retried within its transaction: when QLDB
+// failed internally, was unavailable, exceeded its capacity or throttled the command, but not when the session or
+// transaction is no longer valid, or the context of the transaction is done.
+func canRetryFetchPage(ctx context.Context, err error) bool {
+	return ctx.Err() == nil && (errs.IsServerError(err) || errs.IsCapacityExceeded(err) || errs.IsThrottling(err))
+}
+
+// fetchPage fetches the page of pageToken, retrying up to the limit of retry when it fails transiently. A nil retry
+// does not retry.
+func (retry *fetchPageRetry) fetchPage(ctx context.Context, communicator qldbService, logger *qldbLogger, pageToken *string, txnID *string) (*types.FetchPageResult, error) {
+	nextPage, err := communicator.fetchPage(ctx, pageToken, txnID)
+	for attempt := 1; err != nil && attempt <= retry.retryLimit() && canRetryFetchPage(ctx, err); attempt++ {
+		delay := retry.backoff.Delay(attempt)
+		logger.logf(LogDebug, "Failed to fetch a page of transaction %s. Retrying FetchPage #%d in %v.\nCaused by '%v'",
+			*txnID, attempt, delay, err)
+		sleepWithContext(ctx, delay)
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		nextPage, err = communicator.fetchPage(ctx, pageToken, txnID)
+	}
+	return nextPage, err
+}
+
+// retryLimit returns the maximum number of retries of a FetchPage command, which is 0 for a nil retry.
+func (retry *fetchPageRetry) retryLimit() int {
+	if retry == nil {
+		return 0
+	}
+	return retry.limit
+}
+
+// fetchPageRetryFromOptions returns the retries of FetchPage commands configured by options, or nil if they are
+// disabled.
+func fetchPageRetryFromOptions(options *DriverOptions) *fetchPageRetry {
+	if options.FetchPageRetryLimit <= 0 {
+		return nil
+	}
+	backoff := options.FetchPageBackoff
+	if backoff == nil {
+		backoff = ExponentialBackoffStrategy{SleepBase: 10 * time.Millisecond, SleepCap: time.Second}
+	}
+	return &fetchPageRetry{limit: options.FetchPageRetryLimit, backoff: backoff}
+}
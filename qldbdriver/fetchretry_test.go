@@ -0,0 +1,129 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// constantBackoff delays every retry by the same duration.
+type constantBackoff time.Duration
+
+func (backoff constantBackoff) Delay(retryAttempt int) time.Duration {
+	return time.Duration(backoff)
+}
+
+func TestFetchPageRetry(t *testing.T) {
+	serverErr := &smithy.GenericAPIError{Code: "ServiceUnavailable"}
+	nextPage := &types.FetchPageResult{Page: &types.Page{Values: []types.ValueHolder{{IonBinary: []byte{1}}}}}
+	newTestResult := func(mockService *mockTransactionService, limit int) *result {
+		token := "token"
+		return &result{
+			ctx:          context.Background(),
+			communicator: mockService,
+			txnID:        &mockTxnID,
+			pageToken:    &token,
+			logger:       mockLogger,
+			ioUsage:      newIOUsage(0, 0),
+			timingInfo:   newTimingInformation(0),
+			fetchRetry:   &fetchPageRetry{limit: limit, backoff: constantBackoff(time.Millisecond)},
+		}
+	}
+
+	t.Run("transient failure", func(t *testing.T) {
+		mockService := new(mockTransactionService)
+		mockService.On("fetchPage", mock.Anything, mock.Anything, mock.Anything).Return(&types.FetchPageResult{}, serverErr).Twice()
+		mockService.On("fetchPage", mock.Anything, mock.Anything, mock.Anything).Return(nextPage, nil).Once()
+		res := newTestResult(mockService, 2)
+
+		require.True(t, res.Next(nil))
+		assert.Equal(t, []byte{1}, res.GetCurrentData())
+		mockService.AssertNumberOfCalls(t, "fetchPage", 3)
+	})
+
+	t.Run("throttled", func(t *testing.T) {
+		mockService := new(mockTransactionService)
+		mockService.On("fetchPage", mock.Anything, mock.Anything, mock.Anything).Return(&types.FetchPageResult{}, &smithy.GenericAPIError{Code: "ThrottlingException"}).Once()
+		mockService.On("fetchPage", mock.Anything, mock.Anything, mock.Anything).Return(nextPage, nil).Once()
+		res := newTestResult(mockService, 2)
+
+		require.True(t, res.Next(nil))
+		mockService.AssertNumberOfCalls(t, "fetchPage", 2)
+	})
+
+	t.Run("limit exceeded", func(t *testing.T) {
+		mockService := new(mockTransactionService)
+		mockService.On("fetchPage", mock.Anything, mock.Anything, mock.Anything).Return(&types.FetchPageResult{}, serverErr)
+		res := newTestResult(mockService, 2)
+
+		assert.False(t, res.Next(nil))
+		assert.Equal(t, serverErr, res.Err())
+		mockService.AssertNumberOfCalls(t, "fetchPage", 3)
+	})
+
+	t.Run("not retryable", func(t *testing.T) {
+		mockService := new(mockTransactionService)
+		mockService.On("fetchPage", mock.Anything, mock.Anything, mock.Anything).Return(&types.FetchPageResult{}, errMock)
+		res := newTestResult(mockService, 2)
+
+		assert.False(t, res.Next(nil))
+		assert.Equal(t, errMock, res.Err())
+		mockService.AssertNumberOfCalls(t, "fetchPage", 1)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		mockService := new(mockTransactionService)
+		mockService.On("fetchPage", mock.Anything, mock.Anything, mock.Anything).Return(&types.FetchPageResult{}, serverErr)
+		res := newTestResult(mockService, 0)
+		res.fetchRetry = nil
+
+		assert.False(t, res.Next(nil))
+		mockService.AssertNumberOfCalls(t, "fetchPage", 1)
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		mockService := new(mockTransactionService)
+		mockService.On("fetchPage", mock.Anything, mock.Anything, mock.Anything).Return(&types.FetchPageResult{}, serverErr)
+		res := newTestResult(mockService, 2)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		res.ctx = ctx
+
+		assert.False(t, res.Next(nil))
+		mockService.AssertNumberOfCalls(t, "fetchPage", 1)
+	})
+}
+
+func TestFetchPageRetryFromOptions(t *testing.T) {
+	assert.Nil(t, fetchPageRetryFromOptions(&DriverOptions{}))
+
+	retry := fetchPageRetryFromOptions(defaultDriverOptions())
+	require.NotNil(t, retry)
+	assert.Equal(t, 2, retry.retryLimit())
+	assert.Equal(t, ExponentialBackoffStrategy{SleepBase: 10 * time.Millisecond, SleepCap: time.Second}, retry.backoff)
+
+	retry = fetchPageRetryFromOptions(&DriverOptions{FetchPageRetryLimit: 1, FetchPageBackoff: constantBackoff(time.Second)})
+	assert.Equal(t, constantBackoff(time.Second), retry.backoff)
+
+	var disabled *fetchPageRetry
+	assert.Equal(t, 0, disabled.retryLimit())
+}
@@ -0,0 +1,225 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"sort"
+
+	"github.com/amzn/ion-go/ion"
+)
+
+// DocumentNode is a document of a graph saved by SaveDocumentGraph, which references the documents of other nodes by
+// their document IDs.
+type DocumentNode struct {
+	// The table the document is inserted into.
+	TableName string
+	// The document to insert, which must marshal to an Ion struct.
+	Document interface{}
+	// The nodes referenced by the document, by the name of the top-level field set to their document ID. The field
+	// replaces any field of the same name of Document.
+	References map[string]*DocumentNode
+	// The ID of an existing document, which is referenced by other nodes but not inserted. Document, TableName and
+	// References are ignored when it is set. Default: "", the document is inserted.
+	DocumentID string
+}
+
+// insertedDocumentRow is a row returned by an INSERT statement.
+type insertedDocumentRow struct {
+	DocumentID string `ion:"documentId"`
+}
+
+// SaveDocumentGraph inserts the documents of a graph of nodes within txn, each document after the documents it
+// references, and sets the reference fields of each document to the IDs QLDB generated for the referenced documents.
+// The nodes referenced by the provided nodes are saved too. It returns the document ID of every node of the graph,
+// including the nodes referencing existing documents. The nodes are not modified, so the function passed to
+// QLDBDriver.Execute can be retried.
+//
+// Each document is inserted by its own statement, and all of them count towards the QLDB quota on the number of
+// documents written by a transaction. A graph with a cycle of references returns an error, since the document IDs are
+// only known once the documents are inserted.
+func SaveDocumentGraph(txn Transaction, nodes ...*DocumentNode) (map[*DocumentNode]string, error) {
+	order, err := documentGraphOrder(nodes)
+	if err != nil {
+		return nil, err
+	}
+	marshalOptions := IonMarshalOptions{}
+	if executor, ok := executorOf(txn); ok {
+		marshalOptions = executor.txn.marshalOptions
+	}
+
+	ids := make(map[*DocumentNode]string, len(order))
+	for _, node := range order {
+		if node.DocumentID != "" {
+			ids[node] = node.DocumentID
+			continue
+		}
+		document := &referencingDocument{document: node.Document, marshalOptions: marshalOptions}
+		if len(node.References) > 0 {
+			document.references = make(map[string]string, len(node.References))
+			for field, referenced := range node.References {
+				document.references[field] = ids[referenced]
+			}
+		}
+		result, err := txn.Execute("INSERT INTO "+node.TableName+" ?", document)
+		if err != nil {
+			return nil, err
+		}
+		if !result.Next(txn) {
+			if result.Err() != nil {
+				return nil, result.Err()
+			}
+			return nil, &qldbDriverError{"INSERT into " + node.TableName + " returned no document ID."}
+		}
+		row := insertedDocumentRow{}
+		err = ion.Unmarshal(result.GetCurrentData(), &row)
+		if err != nil {
+			return nil, err
+		}
+		ids[node] = row.DocumentID
+	}
+	return ids, nil
+}
+
+// documentGraphOrder returns the nodes of the graph reachable from nodes, each one after the nodes it references.
+func documentGraphOrder(nodes []*DocumentNode) ([]*DocumentNode, error) {
+	const (
+		visiting = iota + 1
+		visited
+	)
+	states := make(map[*DocumentNode]int)
+	var order []*DocumentNode
+	var visit func(node *DocumentNode) error
+	visit = func(node *DocumentNode) error {
+		if node == nil {
+			return &qldbDriverError{"Document graph contains a nil node."}
+		}
+		switch states[node] {
+		case visiting:
+			return &qldbDriverError{"Document graph contains a cycle of references through table '" + node.TableName + "'."}
+		case visited:
+			return nil
+		}
+		if node.DocumentID == "" {
+			if !tableNameRegex.MatchString(node.TableName) {
+				return &qldbDriverError{"Invalid table name: '" + node.TableName + "'."}
+			}
+			if node.Document == nil {
+				return &qldbDriverError{"Document graph contains a node without a document or document ID."}
+			}
+			states[node] = visiting
+			fields := make([]string, 0, len(node.References))
+			for field := range node.References {
+				fields = append(fields, field)
+			}
+			sort.Strings(fields)
+			for _, field := range fields {
+				if err := visit(node.References[field]); err != nil {
+					return err
+				}
+			}
+		}
+		states[node] = visited
+		order = append(order, node)
+		return nil
+	}
+	for _, node := range nodes {
+		if err := visit(node); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// referencingDocument is a document parameter whose top-level fields named after references are set to the
+// referenced document IDs.
+type referencingDocument struct {
+	document       interface{}
+	references     map[string]string
+	marshalOptions IonMarshalOptions
+}
+
+// MarshalIon writes the document with its reference fields. The annotations of the marshal options are left to the
+// ionParameter wrapping the document.
+func (document *referencingDocument) MarshalIon(writer ion.Writer) error {
+	options := document.marshalOptions
+	options.Annotations = nil
+	ionBinary, err := ion.MarshalBinary(wrapParameters([]interface{}{document.document}, options)[0])
+	if err != nil {
+		return err
+	}
+	reader := ion.NewReaderBytes(ionBinary)
+	if !reader.Next() || reader.Type() != ion.StructType || reader.IsNull() {
+		if reader.Err() != nil {
+			return reader.Err()
+		}
+		return &qldbDriverError{"Document of a document graph must be an Ion struct."}
+	}
+	annotations, err := reader.Annotations()
+	if err != nil {
+		return err
+	}
+	for _, annotation := range annotations {
+		err = writer.Annotation(toWritableSymbol(annotation))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = writer.BeginStruct()
+	if err != nil {
+		return err
+	}
+	err = reader.StepIn()
+	if err != nil {
+		return err
+	}
+	for reader.Next() {
+		fieldName, err := reader.FieldName()
+		if err != nil {
+			return err
+		}
+		if fieldName.Text != nil {
+			if _, ok := document.references[*fieldName.Text]; ok {
+				continue
+			}
+		}
+		err = writer.FieldName(toWritableSymbol(*fieldName))
+		if err != nil {
+			return err
+		}
+		err = copyIonValue(reader, writer)
+		if err != nil {
+			return err
+		}
+	}
+	if reader.Err() != nil {
+		return reader.Err()
+	}
+	fields := make([]string, 0, len(document.references))
+	for field := range document.references {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		err = writer.FieldName(ion.NewSymbolTokenFromString(field))
+		if err != nil {
+			return err
+		}
+		err = writer.WriteString(document.references[field])
+		if err != nil {
+			return err
+		}
+	}
+	return writer.EndStruct()
+}
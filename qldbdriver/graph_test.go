@@ -0,0 +1,111 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/amzn/ion-go/ion"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveDocumentGraph(t *testing.T) {
+	mockHash, _ := toQLDBHash(mockTxnID)
+	type insert struct {
+		statement string
+		document  map[string]interface{}
+	}
+	newExecutor := func(t *testing.T, inserts *[]insert) *transactionExecutor {
+		mockService := new(mockTransactionService)
+		for i := 1; i <= 3; i++ {
+			id := ionTextToBinary(t, fmt.Sprintf(`{documentId: "id%d"}`, i))
+			mockService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) {
+					document := map[string]interface{}{}
+					require.NoError(t, ion.Unmarshal(args.Get(2).([]types.ValueHolder)[0].IonBinary, &document))
+					*inserts = append(*inserts, insert{*args.Get(1).(*string), document})
+				}).
+				Return(&types.ExecuteStatementResult{FirstPage: &types.Page{Values: []types.ValueHolder{{IonBinary: id}}}}, nil).
+				Once()
+		}
+		return &transactionExecutor{
+			ctx: context.Background(),
+			txn: &transaction{communicator: mockService, id: &mockTxnID, logger: mockLogger, commitHash: mockHash},
+		}
+	}
+
+	t.Run("dependency order", func(t *testing.T) {
+		owner := &DocumentNode{TableName: "Person", Document: map[string]interface{}{"Name": "Jane"}}
+		registration := &DocumentNode{
+			TableName:  "VehicleRegistration",
+			Document:   map[string]interface{}{"VIN": "1N4AL11D75C109151", "Owner": "placeholder"},
+			References: map[string]*DocumentNode{"Owner": owner, "Manufacturer": {DocumentID: "existing"}},
+		}
+
+		var inserts []insert
+		ids, err := SaveDocumentGraph(newExecutor(t, &inserts), registration, owner)
+		require.NoError(t, err)
+		require.Len(t, inserts, 2)
+		assert.Equal(t, "INSERT INTO Person ?", inserts[0].statement)
+		assert.Equal(t, "INSERT INTO VehicleRegistration ?", inserts[1].statement)
+		assert.Equal(t, map[string]interface{}{"VIN": "1N4AL11D75C109151", "Owner": "id1", "Manufacturer": "existing"}, inserts[1].document)
+		assert.Equal(t, "id1", ids[owner])
+		assert.Equal(t, "id2", ids[registration])
+		assert.Equal(t, "existing", ids[registration.References["Manufacturer"]])
+		assert.Len(t, ids, 3)
+		assert.Equal(t, "", owner.DocumentID)
+	})
+
+	t.Run("struct document", func(t *testing.T) {
+		type vehicle struct {
+			VIN   string `ion:"VIN"`
+			Owner string `ion:"Owner,omitempty"`
+		}
+		owner := &DocumentNode{TableName: "Person", Document: map[string]interface{}{"Name": "Jane"}}
+		var inserts []insert
+		_, err := SaveDocumentGraph(newExecutor(t, &inserts), &DocumentNode{
+			TableName:  "Vehicle",
+			Document:   vehicle{VIN: "1N4AL11D75C109151"},
+			References: map[string]*DocumentNode{"Owner": owner},
+		})
+		require.NoError(t, err)
+		require.Len(t, inserts, 2)
+		assert.Equal(t, map[string]interface{}{"VIN": "1N4AL11D75C109151", "Owner": "id1"}, inserts[1].document)
+	})
+
+	t.Run("invalid graphs", func(t *testing.T) {
+		first := &DocumentNode{TableName: "Person", Document: map[string]interface{}{}}
+		second := &DocumentNode{TableName: "Person", Document: map[string]interface{}{}, References: map[string]*DocumentNode{"Ref": first}}
+		first.References = map[string]*DocumentNode{"Ref": second}
+
+		var inserts []insert
+		executor := newExecutor(t, &inserts)
+		_, err := SaveDocumentGraph(executor, first)
+		assert.Error(t, err)
+		_, err = SaveDocumentGraph(executor, &DocumentNode{TableName: "Person; DROP", Document: map[string]interface{}{}})
+		assert.Error(t, err)
+		_, err = SaveDocumentGraph(executor, &DocumentNode{TableName: "Person"})
+		assert.Error(t, err)
+		_, err = SaveDocumentGraph(executor, nil)
+		assert.Error(t, err)
+		_, err = SaveDocumentGraph(executor, &DocumentNode{TableName: "Person", Document: "not a struct"})
+		assert.Error(t, err)
+		assert.Empty(t, inserts)
+	})
+}
@@ -0,0 +1,168 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/amzn/ion-go/ion"
+)
+
+// DocumentGrowth is the size and revision count of a document sampled by GetDocumentGrowth.
+type DocumentGrowth struct {
+	// The unique ID of the document.
+	DocumentID string
+	// The size of the user data of the current revision, in bytes of Ion binary.
+	Size int
+	// The fraction of the QLDB maximum document size used by the current revision.
+	SizeRatio float64
+	// The number of revisions of the document, from the history function.
+	Revisions int64
+}
+
+// DocumentGrowthReport summarizes the sizes and revision counts of the documents of a table sampled by
+// GetDocumentGrowth.
+type DocumentGrowthReport struct {
+	// The name of the table.
+	TableName string
+	// The sampled documents, largest first.
+	Documents []DocumentGrowth
+	// The size of the largest sampled document, in bytes of Ion binary.
+	MaxSize int
+	// The mean size of the sampled documents, in bytes of Ion binary.
+	MeanSize int
+	// The number of sampled documents using at least 80% of the QLDB maximum document size.
+	NearSizeLimit int
+	// The highest number of revisions of a sampled document.
+	MaxRevisions int64
+	// The mean number of revisions of the sampled documents.
+	MeanRevisions float64
+}
+
+// MostRevised returns the n sampled documents with the most revisions, most revised first. Documents that are
+// updated often are the ones most likely to cause OCC conflicts between concurrent transactions.
+func (report *DocumentGrowthReport) MostRevised(n int) []DocumentGrowth {
+	documents := make([]DocumentGrowth, len(report.Documents))
+	copy(documents, report.Documents)
+	sort.SliceStable(documents, func(i, j int) bool {
+		return documents[i].Revisions > documents[j].Revisions
+	})
+	if n < len(documents) {
+		documents = documents[:n]
+	}
+	return documents
+}
+
+// historyIDRow is a row of the revision IDs read from the history function.
+type historyIDRow struct {
+	ID string `ion:"id"`
+}
+
+// GetDocumentGrowth samples up to sampleSize documents of a table from its committed view, and reports the size of
+// their current revision and their number of revisions, read from the history function. It helps to find the
+// documents approaching the QLDB maximum document size, and the documents updated often enough to cause OCC conflicts.
+//
+// The sampled documents are the first ones returned by QLDB, not a random selection. Only the pages needed for the
+// sample are read from the committed view, but the history of the sampled documents is read in full, so the sample
+// should be kept small for tables with many revisions. Two statements are executed within txn.
+func GetDocumentGrowth(txn Transaction, tableName string, sampleSize int) (*DocumentGrowthReport, error) {
+	if !tableNameRegex.MatchString(tableName) {
+		return nil, &qldbDriverError{"Invalid table name: '" + tableName + "'."}
+	}
+	if sampleSize <= 0 {
+		return nil, &qldbDriverError{"sampleSize must be greater than 0."}
+	}
+
+	result, err := txn.Execute("SELECT data, metadata FROM _ql_committed_" + tableName)
+	if err != nil {
+		return nil, err
+	}
+	report := &DocumentGrowthReport{TableName: tableName}
+	index := make(map[string]int)
+	for len(report.Documents) < sampleSize && result.Next(txn) {
+		document := NewDocument(result.GetCurrentData())
+		id, err := document.GetID()
+		if err != nil {
+			return nil, err
+		}
+		data, err := document.GetData()
+		if err != nil {
+			return nil, err
+		}
+		index[id] = len(report.Documents)
+		report.Documents = append(report.Documents, DocumentGrowth{
+			DocumentID: id,
+			Size:       len(data),
+			SizeRatio:  float64(len(data)) / float64(MaxDocumentSize),
+		})
+	}
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+	if len(report.Documents) == 0 {
+		return report, nil
+	}
+
+	err = countRevisions(txn, tableName, report.Documents, index)
+	if err != nil {
+		return nil, err
+	}
+
+	totalSize := 0
+	var totalRevisions int64
+	for _, document := range report.Documents {
+		totalSize += document.Size
+		totalRevisions += document.Revisions
+		if document.Size > report.MaxSize {
+			report.MaxSize = document.Size
+		}
+		if document.Revisions > report.MaxRevisions {
+			report.MaxRevisions = document.Revisions
+		}
+		if document.Size >= MaxDocumentSize-MaxDocumentSize/5 {
+			report.NearSizeLimit++
+		}
+	}
+	report.MeanSize = totalSize / len(report.Documents)
+	report.MeanRevisions = float64(totalRevisions) / float64(len(report.Documents))
+	sort.SliceStable(report.Documents, func(i, j int) bool {
+		return report.Documents[i].Size > report.Documents[j].Size
+	})
+	return report, nil
+}
+
+// countRevisions sets the number of revisions of documents, whose positions are indexed by document ID, from the
+// history function.
+func countRevisions(txn Transaction, tableName string, documents []DocumentGrowth, index map[string]int) error {
+	placeholders := make([]string, len(documents))
+	parameters := make([]interface{}, len(documents))
+	for i, document := range documents {
+		placeholders[i] = "?"
+		parameters[i] = document.DocumentID
+	}
+	statement := "SELECT h.metadata.id AS id FROM history(" + tableName + ") AS h WHERE h.metadata.id IN (" +
+		strings.Join(placeholders, ", ") + ")"
+	return ExecuteStream(txn, statement, func(ionBinary []byte) error {
+		row := historyIDRow{}
+		err := ion.Unmarshal(ionBinary, &row)
+		if err != nil {
+			return err
+		}
+		if i, ok := index[row.ID]; ok {
+			documents[i].Revisions++
+		}
+		return nil
+	}, parameters...)
+}
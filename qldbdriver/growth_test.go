@@ -0,0 +1,97 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDocumentGrowth(t *testing.T) {
+	mockHash, _ := toQLDBHash(mockTxnID)
+	newExecutor := func(mockService *mockTransactionService) *transactionExecutor {
+		return &transactionExecutor{
+			ctx: context.Background(),
+			txn: &transaction{communicator: mockService, id: &mockTxnID, logger: mockLogger, commitHash: mockHash},
+		}
+	}
+	statementIs := func(expected string) interface{} {
+		return mock.MatchedBy(func(statement *string) bool { return *statement == expected })
+	}
+	newExecuteResult := func(rows ...string) *types.ExecuteStatementResult {
+		values := make([]types.ValueHolder, len(rows))
+		for i, row := range rows {
+			values[i] = types.ValueHolder{IonBinary: ionTextToBinary(t, row)}
+		}
+		return &types.ExecuteStatementResult{FirstPage: &types.Page{Values: values}}
+	}
+	large := strings.Repeat("x", 110*1024)
+
+	t.Run("sample", func(t *testing.T) {
+		mockService := new(mockTransactionService)
+		mockService.On("executeStatement", mock.Anything, statementIs("SELECT data, metadata FROM _ql_committed_Person"), mock.Anything, mock.Anything).
+			Return(newExecuteResult(
+				`{data: {Name: "Jane"}, metadata: {id: "A", version: 2}}`,
+				`{data: {Name: "`+large+`"}, metadata: {id: "B", version: 0}}`,
+				`{data: {Name: "John"}, metadata: {id: "C", version: 0}}`,
+			), nil)
+		mockService.On("executeStatement", mock.Anything, statementIs("SELECT h.metadata.id AS id FROM history(Person) AS h WHERE h.metadata.id IN (?, ?)"), mock.Anything, mock.Anything).
+			Return(newExecuteResult(`{id: "A"}`, `{id: "B"}`, `{id: "A"}`, `{id: "A"}`), nil)
+
+		report, err := GetDocumentGrowth(newExecutor(mockService), "Person", 2)
+		require.NoError(t, err)
+		assert.Equal(t, "Person", report.TableName)
+		require.Len(t, report.Documents, 2)
+		assert.Equal(t, "B", report.Documents[0].DocumentID)
+		assert.Equal(t, report.Documents[0].Size, report.MaxSize)
+		assert.Greater(t, report.Documents[0].SizeRatio, 0.8)
+		assert.Equal(t, int64(1), report.Documents[0].Revisions)
+		assert.Equal(t, "A", report.Documents[1].DocumentID)
+		assert.Equal(t, int64(3), report.Documents[1].Revisions)
+		assert.Equal(t, 1, report.NearSizeLimit)
+		assert.Equal(t, int64(3), report.MaxRevisions)
+		assert.Equal(t, 2.0, report.MeanRevisions)
+		assert.Equal(t, (report.Documents[0].Size+report.Documents[1].Size)/2, report.MeanSize)
+
+		mostRevised := report.MostRevised(1)
+		require.Len(t, mostRevised, 1)
+		assert.Equal(t, "A", mostRevised[0].DocumentID)
+		assert.Equal(t, "B", report.Documents[0].DocumentID)
+	})
+
+	t.Run("empty table", func(t *testing.T) {
+		mockService := new(mockTransactionService)
+		mockService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(newExecuteResult(), nil)
+
+		report, err := GetDocumentGrowth(newExecutor(mockService), "Person", 10)
+		require.NoError(t, err)
+		assert.Empty(t, report.Documents)
+		mockService.AssertNumberOfCalls(t, "executeStatement", 1)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		mockService := new(mockTransactionService)
+		_, err := GetDocumentGrowth(newExecutor(mockService), "Person; DROP", 10)
+		assert.Error(t, err)
+		_, err = GetDocumentGrowth(newExecutor(mockService), "Person", 0)
+		assert.Error(t, err)
+		mockService.AssertNotCalled(t, "executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
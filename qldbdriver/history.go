@@ -0,0 +1,117 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"sort"
+	"time"
+)
+
+// TableSnapshot is the state of a table at a point in time, as returned by GetTableAsOf.
+type TableSnapshot struct {
+	// The time the snapshot was taken at.
+	AsOf time.Time
+	// The address of the last journal block at or before AsOf that contains a revision of the table, or nil if the
+	// table had no revisions at AsOf.
+	BlockAddress *BlockAddress
+	// The latest revision at or before AsOf of each document that existed at AsOf, ordered by document ID.
+	Documents []*Document
+}
+
+// GetTableAsOf returns the state of a table as of a point in time, using the history function filtered on
+// metadata.txTime. Documents deleted at or before asOf are not included. The query reads the whole history of the
+// table, so it is best suited to reporting on tables of moderate size.
+func GetTableAsOf(txn Transaction, tableName string, asOf time.Time) (*TableSnapshot, error) {
+	latest, blockAddress, err := queryHistoryAsOf(txn, tableName, asOf, "")
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &TableSnapshot{AsOf: asOf, BlockAddress: blockAddress, Documents: make([]*Document, 0, len(latest))}
+	for _, document := range latest {
+		if !document.isDeletion() {
+			snapshot.Documents = append(snapshot.Documents, document)
+		}
+	}
+	sort.Slice(snapshot.Documents, func(i, j int) bool {
+		return snapshot.Documents[i].metadata.ID < snapshot.Documents[j].metadata.ID
+	})
+	return snapshot, nil
+}
+
+// GetDocumentAsOf returns the revision of a document that was current as of a point in time, using the history
+// function filtered on metadata.txTime. It returns nil if the document did not exist at asOf or was deleted at or
+// before asOf.
+func GetDocumentAsOf(txn Transaction, tableName string, documentID string, asOf time.Time) (*Document, error) {
+	latest, _, err := queryHistoryAsOf(txn, tableName, asOf, " AND h.metadata.id = ?", documentID)
+	if err != nil {
+		return nil, err
+	}
+	document := latest[documentID]
+	if document == nil || document.isDeletion() {
+		return nil, nil
+	}
+	return document, nil
+}
+
+// queryHistoryAsOf returns the latest revision at or before asOf of each document of a table, by document ID, and the
+// address of the block containing the latest of these revisions.
+func queryHistoryAsOf(txn Transaction, tableName string, asOf time.Time, condition string, parameters ...interface{}) (map[string]*Document, *BlockAddress, error) {
+	if !tableNameRegex.MatchString(tableName) {
+		return nil, nil, &qldbDriverError{"Invalid table name: '" + tableName + "'."}
+	}
+
+	statement := "SELECT * FROM history(" + tableName + ") AS h WHERE h.metadata.txTime <= ?" + condition
+	latest := make(map[string]*Document)
+	var latestBlock *Document
+	err := ExecuteStream(txn, statement, func(ionBinary []byte) error {
+		document := NewDocument(ionBinary)
+		metadata, err := document.getMetadata()
+		if err != nil {
+			return err
+		}
+		if current, ok := latest[metadata.ID]; !ok || current.metadata.Version < metadata.Version {
+			latest[metadata.ID] = document
+		}
+		if latestBlock == nil || latestBlock.isBefore(document) {
+			latestBlock = document
+		}
+		return nil
+	}, append([]interface{}{asOf}, parameters...)...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if latestBlock == nil {
+		return latest, nil, nil
+	}
+	return latest, latestBlock.blockAddress, nil
+}
+
+// isDeletion returns whether the revision records the deletion of its document, in which case it has no data.
+func (document *Document) isDeletion() bool {
+	return document.parse() == nil && document.data == nil
+}
+
+// isBefore returns whether the revision was committed before other, by transaction time and then by block sequence
+// number. Both revisions must have been parsed.
+func (document *Document) isBefore(other *Document) bool {
+	if !document.metadata.TxTime.Equal(other.metadata.TxTime) {
+		return document.metadata.TxTime.Before(other.metadata.TxTime)
+	}
+	if document.blockAddress == nil || other.blockAddress == nil {
+		return other.blockAddress != nil
+	}
+	return document.blockAddress.SequenceNo < other.blockAddress.SequenceNo
+}
@@ -0,0 +1,116 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryAsOf(t *testing.T) {
+	mockID := "txnID"
+	mockHash, _ := toQLDBHash(mockTxnID)
+	testExecutor := &transactionExecutor{
+		ctx: context.Background(),
+		txn: &transaction{id: &mockID, logger: mockLogger, commitHash: mockHash},
+	}
+	statementIs := func(expected string) interface{} {
+		return mock.MatchedBy(func(statement *string) bool { return *statement == expected })
+	}
+	asOf := time.Date(2021, 6, 15, 12, 0, 0, 0, time.UTC)
+	revisions := []string{
+		`{blockAddress: {strandId: "S", sequenceNo: 10}, data: {Name: "Jane"}, metadata: {id: "A", version: 0, txTime: 2021-06-15T10:00:00Z, txId: "t1"}}`,
+		`{blockAddress: {strandId: "S", sequenceNo: 12}, data: {Name: "Janet"}, metadata: {id: "A", version: 1, txTime: 2021-06-15T11:00:00Z, txId: "t3"}}`,
+		`{blockAddress: {strandId: "S", sequenceNo: 10}, data: {Name: "John"}, metadata: {id: "B", version: 0, txTime: 2021-06-15T10:00:00Z, txId: "t1"}}`,
+		`{blockAddress: {strandId: "S", sequenceNo: 11}, metadata: {id: "B", version: 1, txTime: 2021-06-15T10:30:00Z, txId: "t2"}}`,
+	}
+	newExecuteResult := func(revisions ...string) *types.ExecuteStatementResult {
+		values := make([]types.ValueHolder, len(revisions))
+		for i, revision := range revisions {
+			values[i] = types.ValueHolder{IonBinary: ionTextToBinary(t, revision)}
+		}
+		return &types.ExecuteStatementResult{FirstPage: &types.Page{Values: values}}
+	}
+
+	t.Run("GetTableAsOf", func(t *testing.T) {
+		mockService := new(mockTransactionService)
+		mockService.On("executeStatement", mock.Anything, statementIs("SELECT * FROM history(Person) AS h WHERE h.metadata.txTime <= ?"), mock.Anything, mock.Anything).
+			Return(newExecuteResult(revisions[1], revisions[0], revisions[3], revisions[2]), nil)
+		testExecutor.txn.communicator = mockService
+
+		snapshot, err := GetTableAsOf(testExecutor, "Person", asOf)
+		require.NoError(t, err)
+		assert.Equal(t, asOf, snapshot.AsOf)
+		assert.Equal(t, &BlockAddress{StrandID: "S", SequenceNo: 12}, snapshot.BlockAddress)
+		require.Len(t, snapshot.Documents, 1)
+		txID, err := snapshot.Documents[0].GetTxID()
+		require.NoError(t, err)
+		assert.Equal(t, "t3", txID)
+	})
+
+	t.Run("GetTableAsOf before any revision", func(t *testing.T) {
+		mockService := new(mockTransactionService)
+		mockService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(newExecuteResult(), nil)
+		testExecutor.txn.communicator = mockService
+
+		snapshot, err := GetTableAsOf(testExecutor, "Person", asOf)
+		require.NoError(t, err)
+		assert.Nil(t, snapshot.BlockAddress)
+		assert.Empty(t, snapshot.Documents)
+	})
+
+	t.Run("GetDocumentAsOf", func(t *testing.T) {
+		mockService := new(mockTransactionService)
+		mockService.On("executeStatement", mock.Anything, statementIs("SELECT * FROM history(Person) AS h WHERE h.metadata.txTime <= ? AND h.metadata.id = ?"), mock.Anything, mock.Anything).
+			Return(newExecuteResult(revisions[0], revisions[1]), nil)
+		testExecutor.txn.communicator = mockService
+
+		document, err := GetDocumentAsOf(testExecutor, "Person", "A", asOf)
+		require.NoError(t, err)
+		require.NotNil(t, document)
+		version, err := document.GetVersion()
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), version)
+	})
+
+	t.Run("GetDocumentAsOf deleted", func(t *testing.T) {
+		mockService := new(mockTransactionService)
+		mockService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(newExecuteResult(revisions[2], revisions[3]), nil)
+		testExecutor.txn.communicator = mockService
+
+		document, err := GetDocumentAsOf(testExecutor, "Person", "B", asOf)
+		require.NoError(t, err)
+		assert.Nil(t, document)
+	})
+
+	t.Run("invalid table name", func(t *testing.T) {
+		_, err := GetTableAsOf(testExecutor, "Person) AS h; DELETE FROM Person", asOf)
+		assert.Error(t, err)
+	})
+
+	t.Run("execute error", func(t *testing.T) {
+		mockService := new(mockTransactionService)
+		mockService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(newExecuteResult(), errMock)
+		testExecutor.txn.communicator = mockService
+
+		_, err := GetDocumentAsOf(testExecutor, "Person", "A", asOf)
+		assert.Equal(t, errMock, err)
+	})
+}
@@ -0,0 +1,71 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"time"
+)
+
+// StatementEvent describes a statement executed within a transaction, as passed to DriverOptions.BeforeStatement and
+// DriverOptions.AfterStatement.
+type StatementEvent struct {
+	// The ID of the transaction executing the statement.
+	TransactionID string
+	// The statement, with its literals redacted unless DriverOptions.LogRedaction is LogRedactNone.
+	Statement string
+	// The number of parameters of the statement.
+	ParameterCount int
+	// The time spent executing the statement, up to its first page of results. Always 0 in BeforeStatement.
+	Duration time.Duration
+	// The error returned by the statement, or nil if it succeeded. Always nil in BeforeStatement.
+	Err error
+}
+
+// statementHooks calls the hooks of a driver around the statements of its transactions. A nil statementHooks calls
+// no hook.
+type statementHooks struct {
+	before func(ctx context.Context, event StatementEvent) error
+	after  func(ctx context.Context, event StatementEvent)
+}
+
+// newStatementHooks returns the hooks calling before and after, or nil if both are nil.
+func newStatementHooks(before func(ctx context.Context, event StatementEvent) error, after func(ctx context.Context, event StatementEvent)) *statementHooks {
+	if before == nil && after == nil {
+		return nil
+	}
+	return &statementHooks{before: before, after: after}
+}
+
+// run executes the statement with execute between the hooks. An error returned by the before hook is returned
+// without executing the statement, and the after hook is then not called.
+func (hooks *statementHooks) run(ctx context.Context, txn *transaction, statement string, parameterCount int, execute func() (*result, error)) (*result, error) {
+	if hooks == nil {
+		return execute()
+	}
+	event := StatementEvent{TransactionID: *txn.id, Statement: txn.logger.statement(statement), ParameterCount: parameterCount}
+	if hooks.before != nil {
+		if err := hooks.before(ctx, event); err != nil {
+			return nil, err
+		}
+	}
+	start := time.Now()
+	res, err := execute()
+	if hooks.after != nil {
+		event.Duration = time.Since(start)
+		event.Err = err
+		hooks.after(ctx, event)
+	}
+	return res, err
+}
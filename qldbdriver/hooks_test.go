@@ -0,0 +1,100 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatementHooks(t *testing.T) {
+	var sent []string
+	var before, after []StatementEvent
+	errNotAllowed := errors.New("statement not allowed")
+	testDriver := newMockDriver(t, &qldbsessioniface.MockClientAPI{
+		SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+			if params.ExecuteStatement != nil {
+				sent = append(sent, *params.ExecuteStatement.Statement)
+				if *params.ExecuteStatement.Statement == "SELECT * FROM Missing" {
+					return nil, errMock
+				}
+			}
+			return qldbsessioniface.DefaultSendCommandOutput(params), nil
+		},
+	}, func(options *DriverOptions) {
+		options.BeforeStatement = func(ctx context.Context, event StatementEvent) error {
+			before = append(before, event)
+			if event.Statement == "DELETE FROM Vehicle" {
+				return errNotAllowed
+			}
+			return nil
+		}
+		options.AfterStatement = func(ctx context.Context, event StatementEvent) {
+			after = append(after, event)
+		}
+	})
+	reset := func() {
+		sent, before, after = nil, nil, nil
+	}
+
+	t.Run("executed statement", func(t *testing.T) {
+		reset()
+		_, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+			return txn.Execute("SELECT * FROM Vehicle WHERE VIN = '1' AND Year > ?", 2000)
+		})
+		require.NoError(t, err)
+
+		require.Len(t, before, 1)
+		assert.Equal(t, "SELECT * FROM Vehicle WHERE VIN = '?' AND Year > ?", before[0].Statement)
+		assert.Equal(t, 1, before[0].ParameterCount)
+		assert.NotEmpty(t, before[0].TransactionID)
+		assert.Zero(t, before[0].Duration)
+		require.Len(t, after, 1)
+		assert.Equal(t, before[0].Statement, after[0].Statement)
+		assert.NoError(t, after[0].Err)
+	})
+
+	t.Run("failed statement", func(t *testing.T) {
+		reset()
+		_, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+			return txn.Execute("SELECT * FROM Missing")
+		})
+		assert.Error(t, err)
+
+		require.Len(t, after, 1)
+		assert.Equal(t, errMock, after[0].Err)
+	})
+
+	t.Run("rejected statement", func(t *testing.T) {
+		reset()
+		_, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+			return txn.Execute("DELETE FROM Vehicle")
+		})
+		assert.Equal(t, errNotAllowed, err)
+
+		assert.Len(t, before, 1)
+		assert.Empty(t, after)
+		assert.Empty(t, sent)
+	})
+
+	t.Run("no hooks", func(t *testing.T) {
+		assert.Nil(t, newStatementHooks(nil, nil))
+	})
+}
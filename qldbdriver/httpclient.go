@@ -0,0 +1,111 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+)
+
+// HTTPClientOptions can be used to configure the HTTP client built by NewHTTPClient.
+type HTTPClientOptions struct {
+	// The number of idle connections kept open to QLDB, which should be the maximum number of transactions in progress
+	// so that every session can send its commands without opening a connection. Default: 50, the default of
+	// DriverOptions.MaxConcurrentTransactions.
+	MaxIdleConnections int
+	// The maximum time to establish a TCP connection. Default: 5s.
+	ConnectTimeout time.Duration
+	// The maximum time to complete a TLS handshake. Default: 5s.
+	TLSHandshakeTimeout time.Duration
+	// The time after which an idle connection is closed. Default: 90s.
+	IdleConnectionTimeout time.Duration
+	// The interval of the TCP keep-alive probes of open connections. Default: 30s.
+	KeepAlive time.Duration
+}
+
+// NewHTTPClient builds an HTTP client for the QLDB Session API, to be set as the HTTPClient of aws.Config or of
+// qldbsession.Options. Unlike the default client of the SDK, which keeps 10 idle connections per host, it keeps enough
+// idle connections for the transactions of a driver, and reuses TLS sessions when it opens connections, which avoids
+// opening and closing connections at high concurrency. See NewFromConfig for a driver using such a client.
+func NewHTTPClient(fns ...func(*HTTPClientOptions)) *awshttp.BuildableClient {
+	return tuneHTTPClient(awshttp.NewBuildableClient(), fns...)
+}
+
+// tuneHTTPClient returns a copy of client with the settings of NewHTTPClient.
+func tuneHTTPClient(client *awshttp.BuildableClient, fns ...func(*HTTPClientOptions)) *awshttp.BuildableClient {
+	options := &HTTPClientOptions{
+		MaxIdleConnections:    50,
+		ConnectTimeout:        5 * time.Second,
+		TLSHandshakeTimeout:   5 * time.Second,
+		IdleConnectionTimeout: 90 * time.Second,
+		KeepAlive:             30 * time.Second,
+	}
+	for _, fn := range fns {
+		fn(options)
+	}
+
+	return client.
+		WithDialerOptions(func(dialer *net.Dialer) {
+			dialer.Timeout = options.ConnectTimeout
+			dialer.KeepAlive = options.KeepAlive
+		}).
+		WithTransportOptions(func(transport *http.Transport) {
+			transport.MaxIdleConns = options.MaxIdleConnections
+			transport.MaxIdleConnsPerHost = options.MaxIdleConnections
+			transport.IdleConnTimeout = options.IdleConnectionTimeout
+			transport.TLSHandshakeTimeout = options.TLSHandshakeTimeout
+			if transport.TLSClientConfig == nil {
+				transport.TLSClientConfig = &tls.Config{MinVersion: awshttp.DefaultHTTPTransportTLSMinVersion}
+			} else {
+				transport.TLSClientConfig = transport.TLSClientConfig.Clone()
+			}
+			transport.TLSClientConfig.ClientSessionCache = tls.NewLRUClientSessionCache(options.MaxIdleConnections)
+		})
+}
+
+// NewFromConfig creates a QLDBDriver with a QLDB Session client created from cfg, and verifies the configuration like
+// New. When cfg has no HTTP client, the client of the session is built as by NewHTTPClient, with as many idle
+// connections as the driver may have transactions in progress. A custom HTTP client set in cfg is used as is.
+func NewFromConfig(ledgerName string, cfg aws.Config, fns ...func(*DriverOptions)) (*QLDBDriver, error) {
+	options := defaultDriverOptions()
+	for _, fn := range fns {
+		fn(options)
+	}
+	connections := options.MaxConcurrentTransactions
+	if options.PoolExhaustionPolicy == PoolExhaustionGrow {
+		connections = options.MaxBurstTransactions
+		if connections == 0 {
+			connections = 2 * options.MaxConcurrentTransactions
+		}
+	}
+	connections += partitionSessions(options)
+
+	var sessionFns []func(*qldbsession.Options)
+	if cfg.HTTPClient == nil && connections > 0 {
+		httpClient := NewHTTPClient(func(httpOptions *HTTPClientOptions) {
+			httpOptions.MaxIdleConnections = connections
+		})
+		sessionFns = append(sessionFns, func(sessionOptions *qldbsession.Options) {
+			sessionOptions.HTTPClient = httpClient
+		})
+	}
+	qldbSession := qldbsession.NewFromConfig(cfg, sessionFns...)
+	return newDriver(ledgerName, qldbSession, options)
+}
@@ -0,0 +1,117 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		client := NewHTTPClient()
+
+		transport := client.GetTransport()
+		assert.Equal(t, 50, transport.MaxIdleConns)
+		assert.Equal(t, 50, transport.MaxIdleConnsPerHost)
+		assert.Equal(t, 90*time.Second, transport.IdleConnTimeout)
+		assert.Equal(t, 5*time.Second, transport.TLSHandshakeTimeout)
+		require.NotNil(t, transport.TLSClientConfig)
+		assert.NotNil(t, transport.TLSClientConfig.ClientSessionCache)
+		assert.Equal(t, uint16(awshttp.DefaultHTTPTransportTLSMinVersion), transport.TLSClientConfig.MinVersion)
+
+		dialer := client.GetDialer()
+		assert.Equal(t, 5*time.Second, dialer.Timeout)
+		assert.Equal(t, 30*time.Second, dialer.KeepAlive)
+	})
+
+	t.Run("options", func(t *testing.T) {
+		client := NewHTTPClient(func(options *HTTPClientOptions) {
+			options.MaxIdleConnections = 200
+			options.ConnectTimeout = time.Second
+			options.TLSHandshakeTimeout = 2 * time.Second
+			options.IdleConnectionTimeout = time.Minute
+			options.KeepAlive = 10 * time.Second
+		})
+
+		transport := client.GetTransport()
+		assert.Equal(t, 200, transport.MaxIdleConns)
+		assert.Equal(t, 200, transport.MaxIdleConnsPerHost)
+		assert.Equal(t, time.Minute, transport.IdleConnTimeout)
+		assert.Equal(t, 2*time.Second, transport.TLSHandshakeTimeout)
+
+		dialer := client.GetDialer()
+		assert.Equal(t, time.Second, dialer.Timeout)
+		assert.Equal(t, 10*time.Second, dialer.KeepAlive)
+	})
+}
+
+func TestNewFromConfig(t *testing.T) {
+	cfg := aws.Config{Region: "us-east-1", Credentials: aws.AnonymousCredentials{}}
+
+	t.Run("default HTTP client", func(t *testing.T) {
+		driver, err := NewFromConfig(mockLedgerName, cfg, func(options *DriverOptions) {
+			options.LoggerVerbosity = LogOff
+			options.MaxConcurrentTransactions = 20
+		})
+		require.NoError(t, err)
+		defer driver.Shutdown(context.Background())
+
+		assert.Equal(t, mockLedgerName, driver.ledgerName)
+		assert.Equal(t, 20, driver.maxConcurrentTransactions)
+	})
+
+	t.Run("custom HTTP client is kept", func(t *testing.T) {
+		httpClient := &recordingHTTPClient{}
+		customCfg := cfg.Copy()
+		customCfg.HTTPClient = httpClient
+
+		driver, err := NewFromConfig(mockLedgerName, customCfg, func(options *DriverOptions) {
+			options.LoggerVerbosity = LogOff
+		})
+		require.NoError(t, err)
+		defer driver.Shutdown(context.Background())
+
+		_, err = driver.qldbSession.SendCommand(context.Background(), &qldbsession.SendCommandInput{})
+		require.NoError(t, err)
+		assert.NotNil(t, httpClient.request)
+	})
+
+	t.Run("options applied once", func(t *testing.T) {
+		calls := 0
+		driver, err := NewFromConfig(mockLedgerName, cfg, func(options *DriverOptions) {
+			calls++
+			options.LoggerVerbosity = LogOff
+		})
+		require.NoError(t, err)
+		defer driver.Shutdown(context.Background())
+
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := NewFromConfig(mockLedgerName, cfg, func(options *DriverOptions) {
+			options.LoggerVerbosity = LogOff
+			options.MaxConcurrentTransactions = -1
+		})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,79 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+)
+
+// operationRecord is the document recording an operation in the table of RecordOperation.
+type operationRecord struct {
+	OperationKey string `ion:"OperationKey"`
+}
+
+// RecordOperation records within txn that the operation identified by key was applied, by inserting a document with
+// an OperationKey field into a table dedicated to that purpose. It returns false without writing anything if the
+// operation was already recorded, in which case the caller should not apply it again.
+//
+// Since the key is read and written in the same transaction, two transactions recording the same key concurrently
+// conflict, and only one of them can commit. The table must be created beforehand, with an index on OperationKey so
+// that the key lookup does not scan the table:
+//
+//	CREATE TABLE Operations
+//	CREATE INDEX ON Operations (OperationKey)
+func RecordOperation(txn Transaction, tableName string, key string) (bool, error) {
+	if !tableNameRegex.MatchString(tableName) {
+		return false, &qldbDriverError{"Invalid table name: '" + tableName + "'."}
+	}
+	if key == "" {
+		return false, &qldbDriverError{"The operation key must not be empty."}
+	}
+
+	result, err := txn.Execute("SELECT OperationKey FROM "+tableName+" WHERE OperationKey = ?", key)
+	if err != nil {
+		return false, err
+	}
+	if result.Next(txn) {
+		return false, nil
+	}
+	if result.Err() != nil {
+		return false, result.Err()
+	}
+	_, err = txn.Execute("INSERT INTO "+tableName+" ?", &operationRecord{OperationKey: key})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ExecuteOnce executes fn in a transaction like Execute, unless the operation identified by key was already applied by
+// a committed transaction, so that a write can be retried safely by an at-least-once delivery such as an SQS queue. The
+// key is recorded with RecordOperation in tableName, in the same transaction as the writes of fn.
+//
+// It returns the result of fn and true if fn was applied, or nil and false if the operation was already applied.
+func (driver *QLDBDriver) ExecuteOnce(ctx context.Context, tableName string, key string, fn func(txn Transaction) (interface{}, error), optFns ...func(*ExecuteOptions)) (interface{}, bool, error) {
+	applied := false
+	result, err := driver.Execute(ctx, func(txn Transaction) (interface{}, error) {
+		var err error
+		applied, err = RecordOperation(txn, tableName, key)
+		if err != nil || !applied {
+			return nil, err
+		}
+		return fn(txn)
+	}, optFns...)
+	if err != nil {
+		return nil, false, err
+	}
+	return result, applied, nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteOnce(t *testing.T) {
+	newTestDriver := func(recorded bool) (*QLDBDriver, *qldbsessioniface.MockClientAPI) {
+		mockClient := &qldbsessioniface.MockClientAPI{
+			SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+				output := qldbsessioniface.DefaultSendCommandOutput(params)
+				if recorded && params.ExecuteStatement != nil && strings.HasPrefix(*params.ExecuteStatement.Statement, "SELECT") {
+					output.ExecuteStatement.FirstPage.Values = []types.ValueHolder{{IonBinary: ionTextToBinary(t, `{OperationKey: "msg-1"}`)}}
+				}
+				return output, nil
+			},
+		}
+		return newMockDriver(t, mockClient), mockClient
+	}
+	executedStatements := func(mockClient *qldbsessioniface.MockClientAPI) []string {
+		var statements []string
+		for _, input := range mockClient.Inputs() {
+			if input.ExecuteStatement != nil {
+				statements = append(statements, *input.ExecuteStatement.Statement)
+			}
+		}
+		return statements
+	}
+	write := func(txn Transaction) (interface{}, error) {
+		_, err := txn.Execute("UPDATE Account SET Balance = 0")
+		return "written", err
+	}
+
+	t.Run("new operation", func(t *testing.T) {
+		testDriver, mockClient := newTestDriver(false)
+
+		result, applied, err := testDriver.ExecuteOnce(context.Background(), "Operations", "msg-1", write)
+		require.NoError(t, err)
+		assert.True(t, applied)
+		assert.Equal(t, "written", result)
+		assert.Equal(t, []string{
+			"SELECT OperationKey FROM Operations WHERE OperationKey = ?",
+			"INSERT INTO Operations ?",
+			"UPDATE Account SET Balance = 0",
+		}, executedStatements(mockClient))
+	})
+
+	t.Run("operation already applied", func(t *testing.T) {
+		testDriver, mockClient := newTestDriver(true)
+
+		result, applied, err := testDriver.ExecuteOnce(context.Background(), "Operations", "msg-1", write)
+		require.NoError(t, err)
+		assert.False(t, applied)
+		assert.Nil(t, result)
+		assert.Equal(t, []string{"SELECT OperationKey FROM Operations WHERE OperationKey = ?"}, executedStatements(mockClient))
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		testDriver, _ := newTestDriver(false)
+
+		_, _, err := testDriver.ExecuteOnce(context.Background(), "Operations; DROP", "msg-1", write)
+		assert.Error(t, err)
+		_, _, err = testDriver.ExecuteOnce(context.Background(), "Operations", "", write)
+		assert.Error(t, err)
+	})
+}
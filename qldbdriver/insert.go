@@ -0,0 +1,202 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"strings"
+
+	"github.com/amzn/ion-go/ion"
+)
+
+// InsertOptions can be used to configure a single call to InsertDocuments or QLDBDriver.InsertChunked.
+type InsertOptions struct {
+	// The maximum size in bytes of the Ion binary parameters of an INSERT statement. Default: the QLDB quota on the
+	// size of a transaction, 4 MB.
+	StatementSize int
+	// The maximum number of documents inserted by an INSERT statement. Default: 0, which only limits statements by
+	// StatementSize.
+	DocumentsPerStatement int
+	// The maximum size in bytes of the documents inserted by a transaction of QLDBDriver.InsertChunked. Default: the
+	// QLDB quota on the size of a transaction, 4 MB.
+	TransactionSize int
+	// The maximum number of documents inserted by a transaction of QLDBDriver.InsertChunked. Default: the QLDB quota on
+	// the number of documents written by a transaction, 40.
+	DocumentsPerTransaction int
+	// Called by QLDBDriver.InsertChunked after each transaction is committed. See ChunkedExecuteOptions.OnProgress.
+	// Default: nil.
+	OnProgress func(committedItems int, totalItems int)
+	// The options used by QLDBDriver.InsertChunked to execute each transaction. Default: nil.
+	ExecuteOptions []func(*ExecuteOptions)
+}
+
+// InsertBatch is a range of the documents passed to InsertDocuments or QLDBDriver.InsertChunked, documents[Start:End].
+type InsertBatch struct {
+	// The index of the first document of the batch.
+	Start int
+	// The index following the last document of the batch.
+	End int
+}
+
+// InsertReport describes how InsertDocuments or QLDBDriver.InsertChunked split the documents to insert.
+type InsertReport struct {
+	// The documents inserted by each INSERT statement, in the order the statements were executed.
+	Statements []InsertBatch
+	// The documents inserted by each transaction of QLDBDriver.InsertChunked, in the order the transactions were
+	// committed. Empty for InsertDocuments, which inserts the documents within the caller's transaction.
+	Transactions []InsertBatch
+}
+
+// InsertDocuments inserts documents into a table within txn, splitting them across as few INSERT statements as
+// InsertOptions.StatementSize and InsertOptions.DocumentsPerStatement allow. A statement inserting several documents
+// has the form INSERT INTO table << ?, ? >>.
+//
+// All the documents are written by the same transaction, so they remain subject to the QLDB quotas on the size of a
+// transaction and on the number of documents it writes. Use QLDBDriver.InsertChunked to insert more documents.
+func InsertDocuments(txn Transaction, tableName string, documents []interface{}, fns ...func(*InsertOptions)) (*InsertReport, error) {
+	if !tableNameRegex.MatchString(tableName) {
+		return nil, &qldbDriverError{"Invalid table name: '" + tableName + "'."}
+	}
+	options := newInsertOptions(fns)
+	marshalOptions := IonMarshalOptions{}
+	if executor, ok := executorOf(txn); ok {
+		marshalOptions = executor.txn.marshalOptions
+	}
+	sizes, err := documentSizes(documents, marshalOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	statements, err := insertBatches(txn, tableName, documents, sizes, 0, options)
+	if err != nil {
+		return nil, err
+	}
+	return &InsertReport{Statements: statements}, nil
+}
+
+// InsertChunked inserts documents into a table, splitting them across transactions of up to
+// InsertOptions.TransactionSize bytes and InsertOptions.DocumentsPerTransaction documents, and each transaction across
+// INSERT statements as InsertDocuments does. Transactions are committed in order like the chunks of ExecuteChunked, and
+// the first one that cannot be committed stops the insertion with a ChunkError reporting how many documents were
+// committed.
+//
+// The returned report describes the statements and transactions that were committed, including when an error is
+// returned.
+func (driver *QLDBDriver) InsertChunked(ctx context.Context, tableName string, documents []interface{}, fns ...func(*InsertOptions)) (*InsertReport, error) {
+	if !tableNameRegex.MatchString(tableName) {
+		return nil, &qldbDriverError{"Invalid table name: '" + tableName + "'."}
+	}
+	options := newInsertOptions(fns)
+	sizes, err := documentSizes(documents, driver.marshalOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &InsertReport{}
+	for _, batch := range splitInsertBatches(sizes, options.TransactionSize, options.DocumentsPerTransaction) {
+		var statements []InsertBatch
+		_, err := driver.Execute(ctx, func(txn Transaction) (interface{}, error) {
+			var err error
+			statements, err = insertBatches(txn, tableName, documents[batch.Start:batch.End], sizes[batch.Start:batch.End], batch.Start, options)
+			return nil, err
+		}, options.ExecuteOptions...)
+		if err != nil {
+			return report, &ChunkError{CommittedItems: batch.Start, ChunkEnd: batch.End, err: err}
+		}
+		report.Statements = append(report.Statements, statements...)
+		report.Transactions = append(report.Transactions, batch)
+		driver.logger.logf(LogDebug, "Inserted documents %d to %d of %d into %s with %d statements.",
+			batch.Start, batch.End-1, len(documents), tableName, len(statements))
+		if options.OnProgress != nil {
+			options.OnProgress(batch.End, len(documents))
+		}
+	}
+	return report, nil
+}
+
+// newInsertOptions applies fns to the default InsertOptions.
+func newInsertOptions(fns []func(*InsertOptions)) *InsertOptions {
+	options := &InsertOptions{}
+	for _, fn := range fns {
+		fn(options)
+	}
+	if options.StatementSize <= 0 {
+		options.StatementSize = MaxTransactionSize
+	}
+	if options.TransactionSize <= 0 {
+		options.TransactionSize = MaxTransactionSize
+	}
+	if options.DocumentsPerTransaction <= 0 {
+		options.DocumentsPerTransaction = MaxDocumentsPerTransaction
+	}
+	return options
+}
+
+// documentSizes returns the size in bytes of each document marshaled to Ion binary as a statement parameter.
+func documentSizes(documents []interface{}, marshalOptions IonMarshalOptions) ([]int, error) {
+	sizes := make([]int, len(documents))
+	for i, parameter := range wrapParameters(documents, marshalOptions) {
+		ionBinary, err := ion.MarshalBinary(parameter)
+		if err != nil {
+			return nil, err
+		}
+		sizes[i] = len(ionBinary)
+	}
+	return sizes, nil
+}
+
+// insertBatches inserts documents into a table within txn and returns the documents inserted by each statement, as
+// indexes offset by offset.
+func insertBatches(txn Transaction, tableName string, documents []interface{}, sizes []int, offset int, options *InsertOptions) ([]InsertBatch, error) {
+	var statements []InsertBatch
+	for _, batch := range splitInsertBatches(sizes, options.StatementSize, options.DocumentsPerStatement) {
+		_, err := txn.Execute(insertStatement(tableName, batch.End-batch.Start), documents[batch.Start:batch.End]...)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, InsertBatch{Start: offset + batch.Start, End: offset + batch.End})
+	}
+	return statements, nil
+}
+
+// splitInsertBatches greedily splits documents of the provided sizes into consecutive batches of at most maxSize bytes
+// and, if maxCount is greater than 0, at most maxCount documents. A document larger than maxSize gets a batch of its
+// own.
+func splitInsertBatches(sizes []int, maxSize int, maxCount int) []InsertBatch {
+	var batches []InsertBatch
+	batch := InsertBatch{}
+	batchSize := 0
+	for i, size := range sizes {
+		full := maxCount > 0 && batch.End-batch.Start >= maxCount
+		if batch.End > batch.Start && (full || batchSize+size > maxSize) {
+			batches = append(batches, batch)
+			batch = InsertBatch{Start: i, End: i}
+			batchSize = 0
+		}
+		batch.End++
+		batchSize += size
+	}
+	if batch.End > batch.Start {
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// insertStatement returns an INSERT statement of count documents into a table.
+func insertStatement(tableName string, count int) string {
+	if count == 1 {
+		return "INSERT INTO " + tableName + " ?"
+	}
+	return "INSERT INTO " + tableName + " << " + strings.TrimSuffix(strings.Repeat("?, ", count), ", ") + " >>"
+}
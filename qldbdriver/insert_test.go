@@ -0,0 +1,147 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitInsertBatches(t *testing.T) {
+	sizes := []int{10, 10, 30, 10, 100, 10}
+
+	assert.Equal(t, []InsertBatch{{0, 2}, {2, 4}, {4, 5}, {5, 6}}, splitInsertBatches(sizes, 40, 0))
+	assert.Equal(t, []InsertBatch{{0, 2}, {2, 4}, {4, 6}}, splitInsertBatches(sizes, 1000, 2))
+	assert.Equal(t, []InsertBatch{{0, 6}}, splitInsertBatches(sizes, 1000, 0))
+	assert.Empty(t, splitInsertBatches(nil, 1000, 0))
+}
+
+func TestInsertStatement(t *testing.T) {
+	assert.Equal(t, "INSERT INTO Person ?", insertStatement("Person", 1))
+	assert.Equal(t, "INSERT INTO Person << ?, ?, ? >>", insertStatement("Person", 3))
+}
+
+func TestInsert(t *testing.T) {
+	newTestDriver := func(failedCommit int32) (*QLDBDriver, *qldbsessioniface.MockClientAPI) {
+		var commits int32
+		mockClient := &qldbsessioniface.MockClientAPI{
+			SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+				if params.CommitTransaction != nil && atomic.AddInt32(&commits, 1) == failedCommit {
+					return nil, errors.New("commit failed")
+				}
+				return qldbsessioniface.DefaultSendCommandOutput(params), nil
+			},
+		}
+		return newMockDriver(t, mockClient, func(options *DriverOptions) {
+			options.RetryPolicy = RetryPolicy{
+				MaxRetryLimit: 0,
+				Backoff:       ExponentialBackoffStrategy{SleepBase: time.Millisecond, SleepCap: time.Millisecond}}
+		}), mockClient
+	}
+	executedStatements := func(mockClient *qldbsessioniface.MockClientAPI) []string {
+		var statements []string
+		for _, input := range mockClient.Inputs() {
+			if input.ExecuteStatement != nil {
+				statements = append(statements, *input.ExecuteStatement.Statement)
+			}
+		}
+		return statements
+	}
+	documents := []interface{}{
+		map[string]interface{}{"name": "Alice"},
+		map[string]interface{}{"name": "Bob"},
+		map[string]interface{}{"name": "Carol"},
+		map[string]interface{}{"name": "Dave"},
+		map[string]interface{}{"name": "Eve"},
+	}
+
+	t.Run("InsertDocuments splits statements", func(t *testing.T) {
+		testDriver, mockClient := newTestDriver(0)
+		result, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+			return InsertDocuments(txn, "Person", documents, func(options *InsertOptions) {
+				options.DocumentsPerStatement = 2
+			})
+		})
+		require.NoError(t, err)
+
+		report := result.(*InsertReport)
+		assert.Equal(t, []InsertBatch{{0, 2}, {2, 4}, {4, 5}}, report.Statements)
+		assert.Empty(t, report.Transactions)
+		assert.Equal(t, []string{
+			"INSERT INTO Person << ?, ? >>",
+			"INSERT INTO Person << ?, ? >>",
+			"INSERT INTO Person ?",
+		}, executedStatements(mockClient))
+	})
+
+	t.Run("InsertDocuments in a single statement", func(t *testing.T) {
+		testDriver, mockClient := newTestDriver(0)
+		_, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+			return InsertDocuments(txn, "Person", documents)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"INSERT INTO Person << ?, ?, ?, ?, ? >>"}, executedStatements(mockClient))
+	})
+
+	t.Run("InsertChunked splits transactions", func(t *testing.T) {
+		testDriver, mockClient := newTestDriver(0)
+		var progress []int
+
+		report, err := testDriver.InsertChunked(context.Background(), "Person", documents, func(options *InsertOptions) {
+			options.DocumentsPerTransaction = 3
+			options.DocumentsPerStatement = 2
+			options.OnProgress = func(committedItems int, totalItems int) {
+				assert.Equal(t, 5, totalItems)
+				progress = append(progress, committedItems)
+			}
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []InsertBatch{{0, 3}, {3, 5}}, report.Transactions)
+		assert.Equal(t, []InsertBatch{{0, 2}, {2, 3}, {3, 5}}, report.Statements)
+		assert.Equal(t, []int{3, 5}, progress)
+		assert.Equal(t, []string{
+			"INSERT INTO Person << ?, ? >>",
+			"INSERT INTO Person ?",
+			"INSERT INTO Person << ?, ? >>",
+		}, executedStatements(mockClient))
+	})
+
+	t.Run("InsertChunked stops at failed transaction", func(t *testing.T) {
+		testDriver, _ := newTestDriver(2)
+
+		report, err := testDriver.InsertChunked(context.Background(), "Person", documents, func(options *InsertOptions) {
+			options.DocumentsPerTransaction = 2
+		})
+		var chunkErr *ChunkError
+		require.True(t, errors.As(err, &chunkErr))
+		assert.Equal(t, 2, chunkErr.CommittedItems)
+		assert.Equal(t, 4, chunkErr.ChunkEnd)
+		assert.Equal(t, []InsertBatch{{0, 2}}, report.Transactions)
+		assert.Equal(t, []InsertBatch{{0, 2}}, report.Statements)
+	})
+
+	t.Run("invalid table name", func(t *testing.T) {
+		testDriver, _ := newTestDriver(0)
+		_, err := testDriver.InsertChunked(context.Background(), "Person; DROP", documents)
+		assert.Error(t, err)
+	})
+}
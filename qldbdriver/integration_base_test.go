@@ -17,23 +17,21 @@ package qldbdriver
 
 import (
 	"context"
-	"errors"
 	"flag"
 	"fmt"
-	"strings"
 	"testing"
-	"time"
 
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/qldb"
 	"github.com/aws/aws-sdk-go-v2/service/qldb/types"
 	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
 	"github.com/aws/smithy-go"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbadmin"
 	"github.com/stretchr/testify/assert"
 )
 
 type testBase struct {
-	qldb       *qldb.Client
+	admin      *qldbadmin.LedgerAdmin
 	ledgerName *string
 	regionName *string
 	logger     Logger
@@ -60,79 +58,45 @@ func createTestBase(ledgerNameBase string) *testBase {
 	client := qldb.NewFromConfig(cfg, func(options *qldb.Options) {
 		options.Region = region
 	})
+	admin, err := qldbadmin.New(client)
+	if err != nil {
+		panic(err)
+	}
 	logger := defaultLogger{}
 	ledgerName := ledgerNameBase + *ledgerSuffix
 	regionName := region
-	return &testBase{client, &ledgerName, &regionName, logger}
+	return &testBase{admin, &ledgerName, &regionName, logger}
 }
 
 func (testBase *testBase) createLedger(t *testing.T) {
 	testBase.logger.Log(fmt.Sprint("Creating ledger named ", *testBase.ledgerName, " ..."), LogInfo)
-	_, err := testBase.qldb.CreateLedger(context.TODO(), &qldb.CreateLedgerInput{
+	_, err := testBase.admin.CreateLedger(context.TODO(), &qldb.CreateLedgerInput{
 		Name:               testBase.ledgerName,
 		DeletionProtection: newBool(false),
 		PermissionsMode:    types.PermissionsModeStandard,
 	})
 	assert.NoError(t, err)
-	testBase.waitForActive()
+	testBase.logger.Log("Success. Ledger is active and ready to use.", LogInfo)
 }
 
 func (testBase *testBase) deleteLedger(t *testing.T) {
 	testBase.logger.Log(fmt.Sprint("Deleting ledger ", *testBase.ledgerName), LogInfo)
-	_, err := testBase.qldb.DeleteLedger(context.TODO(), &qldb.DeleteLedgerInput{Name: testBase.ledgerName})
+	err := testBase.admin.DeleteLedger(context.TODO(), *testBase.ledgerName)
 	if err != nil {
-		var rnf *types.ResourceNotFoundException
-		if errors.As(err, &rnf) {
-			testBase.logger.Log("Encountered resource not found", LogInfo)
-			return
-		}
-		var riu *types.ResourceInUseException
-		if errors.As(err, &riu) {
-			if strings.Contains(riu.ErrorMessage(), "Ledger is being created") {
-				testBase.logger.Log("Encountered resource still being created", LogInfo)
-				testBase.waitForActive()
-				_, err = testBase.qldb.DeleteLedger(context.TODO(), &qldb.DeleteLedgerInput{Name: testBase.ledgerName})
-			} else if strings.Contains(riu.ErrorMessage(), "Ledger is being deleted") {
-				err = nil
-			}
-		}
-		if err != nil {
-			testBase.logger.Log("Encountered error during deletion", LogInfo)
-			testBase.logger.Log(err.Error(), LogInfo)
-			t.Errorf("Failing test due to deletion failure")
-			assert.NoError(t, err)
-			return
-		}
-	}
-}
-
-func (testBase *testBase) waitForActive() {
-	testBase.logger.Log("Waiting for ledger to become active...", LogInfo)
-	for {
-		output, _ := testBase.qldb.DescribeLedger(context.TODO(), &qldb.DescribeLedgerInput{Name: testBase.ledgerName})
-		if output.State == "ACTIVE" {
-			testBase.logger.Log("Success. Ledger is active and ready to use.", LogInfo)
-			return
-		}
-		testBase.logger.Log("The ledger is still creating. Please wait...", LogInfo)
-		time.Sleep(5 * time.Second)
+		testBase.logger.Log("Encountered error during deletion", LogInfo)
+		testBase.logger.Log(err.Error(), LogInfo)
+		t.Errorf("Failing test due to deletion failure")
+		assert.NoError(t, err)
 	}
 }
 
 func (testBase *testBase) waitForDeletion() {
 	testBase.logger.Log("Waiting for ledger to be deleted...", LogInfo)
-	for {
-		_, err := testBase.qldb.DescribeLedger(context.TODO(), &qldb.DescribeLedgerInput{Name: testBase.ledgerName})
-		testBase.logger.Log("The ledger is still deleting. Please wait...", LogInfo)
-		if err != nil {
-			var rnf *types.ResourceNotFoundException
-			if errors.As(err, &rnf) {
-				testBase.logger.Log("The ledger is deleted", LogInfo)
-				return
-			}
-		}
-		time.Sleep(5 * time.Second)
+	err := testBase.admin.WaitForDeletion(context.TODO(), *testBase.ledgerName)
+	if err != nil {
+		panic(err)
 	}
+	testBase.logger.Log("The ledger is deleted", LogInfo)
 }
 
 func (testBase *testBase) getDefaultDriver() (*QLDBDriver, error) {
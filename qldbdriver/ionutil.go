@@ -0,0 +1,171 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"bytes"
+
+	"github.com/amzn/ion-go/ion"
+)
+
+// ionValueToBinary copies the value the reader is currently positioned on into a new Ion binary datagram.
+func ionValueToBinary(reader ion.Reader) ([]byte, error) {
+	buf := bytes.Buffer{}
+	writer := ion.NewBinaryWriter(&buf)
+	err := copyIonValue(reader, writer)
+	if err != nil {
+		return nil, err
+	}
+	err = writer.Finish()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// unmarshalCurrentValue unmarshals the value the reader is currently positioned on into v.
+func unmarshalCurrentValue(reader ion.Reader, v interface{}) error {
+	ionBinary, err := ionValueToBinary(reader)
+	if err != nil {
+		return err
+	}
+	return ion.Unmarshal(ionBinary, v)
+}
+
+// copyIonValue writes the value the reader is currently positioned on, including its annotations, to the writer.
+func copyIonValue(reader ion.Reader, writer ion.Writer) error {
+	annotations, err := reader.Annotations()
+	if err != nil {
+		return err
+	}
+	for _, annotation := range annotations {
+		err = writer.Annotation(toWritableSymbol(annotation))
+		if err != nil {
+			return err
+		}
+	}
+
+	if reader.IsNull() {
+		return writer.WriteNullType(reader.Type())
+	}
+
+	switch reader.Type() {
+	case ion.BoolType:
+		val, err := reader.BoolValue()
+		if err != nil {
+			return err
+		}
+		return writer.WriteBool(*val)
+	case ion.IntType:
+		val, err := reader.BigIntValue()
+		if err != nil {
+			return err
+		}
+		return writer.WriteBigInt(val)
+	case ion.FloatType:
+		val, err := reader.FloatValue()
+		if err != nil {
+			return err
+		}
+		return writer.WriteFloat(*val)
+	case ion.DecimalType:
+		val, err := reader.DecimalValue()
+		if err != nil {
+			return err
+		}
+		return writer.WriteDecimal(val)
+	case ion.TimestampType:
+		val, err := reader.TimestampValue()
+		if err != nil {
+			return err
+		}
+		return writer.WriteTimestamp(*val)
+	case ion.SymbolType:
+		val, err := reader.SymbolValue()
+		if err != nil {
+			return err
+		}
+		return writer.WriteSymbol(toWritableSymbol(*val))
+	case ion.StringType:
+		val, err := reader.StringValue()
+		if err != nil {
+			return err
+		}
+		return writer.WriteString(*val)
+	case ion.ClobType:
+		val, err := reader.ByteValue()
+		if err != nil {
+			return err
+		}
+		return writer.WriteClob(val)
+	case ion.BlobType:
+		val, err := reader.ByteValue()
+		if err != nil {
+			return err
+		}
+		return writer.WriteBlob(val)
+	case ion.ListType:
+		return copyIonContainer(reader, writer, writer.BeginList, writer.EndList)
+	case ion.SexpType:
+		return copyIonContainer(reader, writer, writer.BeginSexp, writer.EndSexp)
+	case ion.StructType:
+		return copyIonContainer(reader, writer, writer.BeginStruct, writer.EndStruct)
+	default:
+		return writer.WriteNull()
+	}
+}
+
+func copyIonContainer(reader ion.Reader, writer ion.Writer, begin func() error, end func() error) error {
+	err := begin()
+	if err != nil {
+		return err
+	}
+	err = reader.StepIn()
+	if err != nil {
+		return err
+	}
+	for reader.Next() {
+		if reader.IsInStruct() {
+			fieldName, err := reader.FieldName()
+			if err != nil {
+				return err
+			}
+			err = writer.FieldName(toWritableSymbol(*fieldName))
+			if err != nil {
+				return err
+			}
+		}
+		err = copyIonValue(reader, writer)
+		if err != nil {
+			return err
+		}
+	}
+	if reader.Err() != nil {
+		return reader.Err()
+	}
+	err = reader.StepOut()
+	if err != nil {
+		return err
+	}
+	return end()
+}
+
+// toWritableSymbol drops the local symbol ID of a symbol with known text, since it is only valid in the symbol table
+// of the reader it came from.
+func toWritableSymbol(symbol ion.SymbolToken) ion.SymbolToken {
+	if symbol.Text != nil {
+		return ion.NewSymbolTokenFromString(*symbol.Text)
+	}
+	return symbol
+}
@@ -0,0 +1,68 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/amzn/ion-go/ion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ionTextToBinary converts an Ion text value to Ion binary.
+func ionTextToBinary(t *testing.T, text string) []byte {
+	reader := ion.NewReaderString(text)
+	require.True(t, reader.Next())
+	ionBinary, err := ionValueToBinary(reader)
+	require.NoError(t, err)
+	return ionBinary
+}
+
+// ionToText renders the next value of the reader as Ion text.
+func ionToText(t *testing.T, reader ion.Reader) string {
+	require.True(t, reader.Next())
+	buf := strings.Builder{}
+	writer := ion.NewTextWriter(&buf)
+	require.NoError(t, copyIonValue(reader, writer))
+	require.NoError(t, writer.Finish())
+	return buf.String()
+}
+
+func TestIonValueToBinary(t *testing.T) {
+	values := []string{
+		"null",
+		"null.struct",
+		"true",
+		"123",
+		"123456789012345678901234567890",
+		"1.5e0",
+		"1.50",
+		"2021-06-15T10:30:45.123Z",
+		"sym",
+		"\"str\"",
+		"{{\"clob\"}}",
+		"{{aGVsbG8=}}",
+		"[1,2,[3]]",
+		"(a b c)",
+		"ann::{a:1,b:{c:[d::2]}}",
+	}
+	for _, value := range values {
+		t.Run(value, func(t *testing.T) {
+			ionBinary := ionTextToBinary(t, value)
+			assert.Equal(t, ionToText(t, ion.NewReaderString(value)), ionToText(t, ion.NewReaderBytes(ionBinary)))
+		})
+	}
+}
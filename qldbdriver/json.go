@@ -0,0 +1,320 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amzn/ion-go/ion"
+)
+
+// JSONTimestampFormat determines how Ion timestamps are converted to JSON.
+type JSONTimestampFormat int
+
+const (
+	// JSONTimestampString writes timestamps as strings in Ion text format, which follows RFC 3339 and keeps the
+	// precision of the timestamp, for example "2021-06-15T10:30:45.123Z".
+	JSONTimestampString JSONTimestampFormat = iota
+	// JSONTimestampEpochMillis writes timestamps as the number of milliseconds since the Unix epoch.
+	JSONTimestampEpochMillis
+)
+
+// JSONDecimalFormat determines how Ion decimals are converted to JSON.
+type JSONDecimalFormat int
+
+const (
+	// JSONDecimalNumber writes decimals as JSON numbers with all their digits. Consumers that parse JSON numbers as
+	// float64 may lose precision.
+	JSONDecimalNumber JSONDecimalFormat = iota
+	// JSONDecimalString writes decimals as strings, for example "12.50", so that no precision is lost by consumers.
+	JSONDecimalString
+)
+
+// JSONBinaryFormat determines how Ion blobs and clobs are converted to JSON.
+type JSONBinaryFormat int
+
+const (
+	// JSONBinaryBase64 writes blobs and clobs as base64-encoded strings.
+	JSONBinaryBase64 JSONBinaryFormat = iota
+	// JSONBinaryOmit writes blobs and clobs as null.
+	JSONBinaryOmit
+)
+
+// JSONOptions controls how Ion values are down-converted to JSON. Ion nulls of any type become JSON nulls, symbols
+// become strings, s-expressions become arrays, annotations are dropped and floats that are not finite become nulls.
+type JSONOptions struct {
+	// How timestamps are written. Default: JSONTimestampString.
+	Timestamps JSONTimestampFormat
+	// How decimals are written. Default: JSONDecimalNumber.
+	Decimals JSONDecimalFormat
+	// How blobs and clobs are written. Default: JSONBinaryBase64.
+	Binaries JSONBinaryFormat
+}
+
+// ionToJSON converts a value in Ion format to JSON.
+func ionToJSON(ionBinary []byte, fns ...func(*JSONOptions)) ([]byte, error) {
+	options := JSONOptions{}
+	for _, fn := range fns {
+		fn(&options)
+	}
+
+	reader := ion.NewReaderBytes(ionBinary)
+	if !reader.Next() {
+		if reader.Err() != nil {
+			return nil, reader.Err()
+		}
+		return nil, &qldbDriverError{"No Ion value to convert to JSON."}
+	}
+	buf := bytes.Buffer{}
+	err := writeJSONValue(reader, &buf, &options)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeJSONValue writes the value the reader is currently positioned on to buf as JSON.
+func writeJSONValue(reader ion.Reader, buf *bytes.Buffer, options *JSONOptions) error {
+	if reader.IsNull() {
+		buf.WriteString("null")
+		return nil
+	}
+
+	switch reader.Type() {
+	case ion.BoolType:
+		val, err := reader.BoolValue()
+		if err != nil {
+			return err
+		}
+		buf.WriteString(strconv.FormatBool(*val))
+	case ion.IntType:
+		val, err := reader.BigIntValue()
+		if err != nil {
+			return err
+		}
+		buf.WriteString(val.String())
+	case ion.FloatType:
+		val, err := reader.FloatValue()
+		if err != nil {
+			return err
+		}
+		if math.IsNaN(*val) || math.IsInf(*val, 0) {
+			buf.WriteString("null")
+		} else {
+			buf.WriteString(strconv.FormatFloat(*val, 'g', -1, 64))
+		}
+	case ion.DecimalType:
+		val, err := reader.DecimalValue()
+		if err != nil {
+			return err
+		}
+		// Ion text decimals end with a '.' when they have no fractional digits and use 'd' as the exponent marker
+		number := strings.Replace(strings.TrimSuffix(val.String(), "."), "d", "e", 1)
+		if options.Decimals == JSONDecimalString {
+			writeJSONString(buf, number)
+		} else {
+			buf.WriteString(number)
+		}
+	case ion.TimestampType:
+		val, err := reader.TimestampValue()
+		if err != nil {
+			return err
+		}
+		if options.Timestamps == JSONTimestampEpochMillis {
+			// UnixNano overflows for times outside of the years 1678 to 2262
+			dateTime := val.GetDateTime()
+			buf.WriteString(strconv.FormatInt(dateTime.Unix()*1000+int64(dateTime.Nanosecond())/int64(time.Millisecond), 10))
+		} else {
+			writeJSONString(buf, val.String())
+		}
+	case ion.SymbolType:
+		val, err := reader.SymbolValue()
+		if err != nil {
+			return err
+		}
+		if val.Text == nil {
+			buf.WriteString("null")
+		} else {
+			writeJSONString(buf, *val.Text)
+		}
+	case ion.StringType:
+		val, err := reader.StringValue()
+		if err != nil {
+			return err
+		}
+		writeJSONString(buf, *val)
+	case ion.ClobType, ion.BlobType:
+		val, err := reader.ByteValue()
+		if err != nil {
+			return err
+		}
+		if options.Binaries == JSONBinaryOmit {
+			buf.WriteString("null")
+		} else {
+			writeJSONString(buf, base64.StdEncoding.EncodeToString(val))
+		}
+	case ion.ListType, ion.SexpType:
+		return writeJSONContainer(reader, buf, options, '[', ']')
+	case ion.StructType:
+		return writeJSONContainer(reader, buf, options, '{', '}')
+	default:
+		buf.WriteString("null")
+	}
+	return nil
+}
+
+func writeJSONContainer(reader ion.Reader, buf *bytes.Buffer, options *JSONOptions, begin byte, end byte) error {
+	err := reader.StepIn()
+	if err != nil {
+		return err
+	}
+	buf.WriteByte(begin)
+	first := true
+	for reader.Next() {
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		if begin == '{' {
+			fieldName, err := reader.FieldName()
+			if err != nil {
+				return err
+			}
+			name := ""
+			if fieldName != nil && fieldName.Text != nil {
+				name = *fieldName.Text
+			}
+			writeJSONString(buf, name)
+			buf.WriteByte(':')
+		}
+		err = writeJSONValue(reader, buf, options)
+		if err != nil {
+			return err
+		}
+	}
+	if reader.Err() != nil {
+		return reader.Err()
+	}
+	buf.WriteByte(end)
+	return reader.StepOut()
+}
+
+func writeJSONString(buf *bytes.Buffer, value string) {
+	// Marshaling a string cannot fail
+	encoded, _ := json.Marshal(value)
+	buf.Write(encoded)
+}
+
+// jsonParameter marshals a JSON document or a value decoded from JSON as the equivalent Ion value.
+type jsonParameter struct {
+	raw   json.RawMessage
+	value interface{}
+}
+
+// toIonParameter converts a top-level parameter to a value that marshals to the intended Ion value. JSON parameters,
+// either json.RawMessage or map[string]interface{}, are up-converted to Ion, and parameters of math/big types are
+// converted with toIonNumeric. The converted value is used both to send the parameter and to compute the commit digest.
+func toIonParameter(parameter interface{}) interface{} {
+	switch value := parameter.(type) {
+	case json.RawMessage:
+		return &jsonParameter{raw: value}
+	case map[string]interface{}:
+		if value != nil {
+			return &jsonParameter{value: value}
+		}
+	}
+	return toIonNumeric(parameter)
+}
+
+// MarshalIon writes the JSON value to the Ion writer. JSON objects become Ion structs and JSON arrays become Ion lists.
+// JSON numbers in a json.RawMessage or of type json.Number become Ion ints when they are integers and Ion decimals
+// otherwise, so that no precision is lost. float64 values, as produced by decoding JSON without json.Decoder.UseNumber,
+// become Ion floats.
+func (parameter *jsonParameter) MarshalIon(writer ion.Writer) error {
+	value := parameter.value
+	if parameter.raw != nil {
+		decoder := json.NewDecoder(bytes.NewReader(parameter.raw))
+		decoder.UseNumber()
+		err := decoder.Decode(&value)
+		if err != nil {
+			return err
+		}
+		if _, err = decoder.Token(); err != io.EOF {
+			return &qldbDriverError{"JSON parameter has data after its value."}
+		}
+	}
+	return writeJSONAsIon(writer, value)
+}
+
+func writeJSONAsIon(writer ion.Writer, value interface{}) error {
+	switch value := value.(type) {
+	case nil:
+		return writer.WriteNull()
+	case map[string]interface{}:
+		err := writer.BeginStruct()
+		if err != nil {
+			return err
+		}
+		for fieldName, fieldValue := range value {
+			err = writer.FieldName(ion.NewSymbolTokenFromString(fieldName))
+			if err != nil {
+				return err
+			}
+			err = writeJSONAsIon(writer, fieldValue)
+			if err != nil {
+				return err
+			}
+		}
+		return writer.EndStruct()
+	case []interface{}:
+		err := writer.BeginList()
+		if err != nil {
+			return err
+		}
+		for _, element := range value {
+			err = writeJSONAsIon(writer, element)
+			if err != nil {
+				return err
+			}
+		}
+		return writer.EndList()
+	case json.Number:
+		text := value.String()
+		if !strings.ContainsAny(text, ".eE") {
+			integer, ok := new(big.Int).SetString(text, 10)
+			if !ok {
+				return &qldbDriverError{"Invalid JSON number: '" + text + "'."}
+			}
+			return writer.WriteBigInt(integer)
+		}
+		// ion-go parses decimals with a 'd' exponent marker
+		decimal, err := ion.ParseDecimal(strings.NewReplacer("e", "d", "E", "d").Replace(text))
+		if err != nil {
+			return err
+		}
+		return writer.WriteDecimal(decimal)
+	case json.RawMessage:
+		return (&jsonParameter{raw: value}).MarshalIon(writer)
+	default:
+		return ion.NewEncoder(writer).Encode(toIonNumeric(value))
+	}
+}
@@ -0,0 +1,190 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/amzn/ion-go/ion"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIonToJSON(t *testing.T) {
+	testCases := []struct {
+		ion  string
+		json string
+	}{
+		{"null", "null"},
+		{"null.string", "null"},
+		{"true", "true"},
+		{"123456789012345678901234567890", "123456789012345678901234567890"},
+		{"1.5e0", "1.5"},
+		{"+inf", "null"},
+		{"12.50", "12.50"},
+		{"12.", "12"},
+		{"1d3", "1e3"},
+		{"2021-06-15T10:30:45.123Z", `"2021-06-15T10:30:45.123Z"`},
+		{"Foo", `"Foo"`},
+		{`"quote\" and \n"`, `"quote\" and \n"`},
+		{"{{aGVsbG8=}}", `"aGVsbG8="`},
+		{`{{"hello"}}`, `"aGVsbG8="`},
+		{"[1, (a b), annotated::2]", `[1,["a","b"],2]`},
+		{`{Name: "Jane", Address: {City: "Seattle"}, Tags: []}`, `{"Name":"Jane","Address":{"City":"Seattle"},"Tags":[]}`},
+	}
+
+	for _, testCase := range testCases {
+		converted, err := ionToJSON(ionTextToBinary(t, testCase.ion))
+		require.NoError(t, err, testCase.ion)
+		assert.Equal(t, testCase.json, string(converted), testCase.ion)
+	}
+
+	t.Run("options", func(t *testing.T) {
+		converted, err := ionToJSON(ionTextToBinary(t, "{amount: 12.50, at: 1970-01-01T00:00:01.5Z, file: {{aGVsbG8=}}}"), func(options *JSONOptions) {
+			options.Decimals = JSONDecimalString
+			options.Timestamps = JSONTimestampEpochMillis
+			options.Binaries = JSONBinaryOmit
+		})
+		require.NoError(t, err)
+		assert.Equal(t, `{"amount":"12.50","at":1500,"file":null}`, string(converted))
+	})
+
+	t.Run("epoch millis outside of the UnixNano range", func(t *testing.T) {
+		epochMillis := func(timestamp string) string {
+			converted, err := ionToJSON(ionTextToBinary(t, timestamp), func(options *JSONOptions) {
+				options.Timestamps = JSONTimestampEpochMillis
+			})
+			require.NoError(t, err)
+			return string(converted)
+		}
+		assert.Equal(t, "253402300799999", epochMillis("9999-12-31T23:59:59.999Z"))
+		assert.Equal(t, "-62135596800000", epochMillis("0001-01-01T00:00:00Z"))
+		assert.Equal(t, "-1", epochMillis("1969-12-31T23:59:59.999Z"))
+	})
+
+	t.Run("invalid Ion", func(t *testing.T) {
+		_, err := ionToJSON([]byte{0xe0, 0x01, 0x00, 0xea, 0xff})
+		assert.Error(t, err)
+	})
+}
+
+func TestResultJSON(t *testing.T) {
+	values := [][]byte{ionTextToBinary(t, "{a: 1}"), ionTextToBinary(t, "{a: 2.0}")}
+
+	t.Run("result", func(t *testing.T) {
+		res := &result{pageValues: []types.ValueHolder{{IonBinary: values[0]}}}
+
+		_, err := GetCurrentDataJSON(res)
+		assert.Error(t, err)
+		require.True(t, res.Next(nil))
+		converted, err := GetCurrentDataJSON(res)
+		require.NoError(t, err)
+		assert.Equal(t, `{"a":1}`, string(converted))
+	})
+
+	t.Run("buffered result", func(t *testing.T) {
+		res := &bufferedResult{values: values}
+
+		converted, err := BufferedResultToJSON(res)
+		require.NoError(t, err)
+		assert.Equal(t, `[{"a":1},{"a":2.0}]`, string(converted))
+
+		require.True(t, res.Next())
+		converted, err = GetCurrentDataJSON(res, func(options *JSONOptions) { options.Decimals = JSONDecimalString })
+		require.NoError(t, err)
+		assert.Equal(t, `{"a":1}`, string(converted))
+		require.True(t, res.Next())
+		converted, err = GetCurrentDataJSON(res, func(options *JSONOptions) { options.Decimals = JSONDecimalString })
+		require.NoError(t, err)
+		assert.Equal(t, `{"a":"2.0"}`, string(converted))
+	})
+
+	t.Run("result not returned by the driver", func(t *testing.T) {
+		_, err := GetCurrentDataJSON(struct{ Result }{})
+		assert.Error(t, err)
+		_, err = BufferedResultToJSON(struct{ BufferedResult }{})
+		assert.Error(t, err)
+	})
+
+	t.Run("empty buffered result", func(t *testing.T) {
+		converted, err := BufferedResultToJSON(&bufferedResult{})
+		require.NoError(t, err)
+		assert.Equal(t, "[]", string(converted))
+	})
+}
+
+func TestJSONParameters(t *testing.T) {
+	toText := func(t *testing.T, parameter interface{}) string {
+		ionBinary, err := ion.MarshalBinary(toIonParameter(parameter))
+		require.NoError(t, err)
+		return strings.TrimSpace(ionToText(t, ion.NewReaderBytes(ionBinary)))
+	}
+
+	t.Run("raw message", func(t *testing.T) {
+		text := toText(t, json.RawMessage(`{"amount": 12.50, "count": 123456789012345678901, "rate": 1e-3, "tags": ["a", true, null]}`))
+		fields, err := topLevelFields(ionTextToBinary(t, text))
+		require.NoError(t, err)
+		assert.Equal(t, "12.50", strings.TrimSpace(ionToText(t, ion.NewReaderBytes(fields["amount"]))))
+		assert.Equal(t, "123456789012345678901", strings.TrimSpace(ionToText(t, ion.NewReaderBytes(fields["count"]))))
+		assert.Equal(t, "1d-3", strings.TrimSpace(ionToText(t, ion.NewReaderBytes(fields["rate"]))))
+		assert.Equal(t, `["a",true,null.null]`, strings.TrimSpace(ionToText(t, ion.NewReaderBytes(fields["tags"]))))
+	})
+
+	t.Run("map", func(t *testing.T) {
+		parameter := map[string]interface{}{
+			"number":  json.Number("1.0"),
+			"float":   2.5,
+			"nested":  map[string]interface{}{"raw": json.RawMessage(`[1]`)},
+			"balance": big.NewInt(7),
+		}
+		fields, err := topLevelFields(ionTextToBinary(t, toText(t, parameter)))
+		require.NoError(t, err)
+		assert.Equal(t, "1.0", strings.TrimSpace(ionToText(t, ion.NewReaderBytes(fields["number"]))))
+		assert.Equal(t, "2.5e+0", strings.TrimSpace(ionToText(t, ion.NewReaderBytes(fields["float"]))))
+		assert.Equal(t, "{raw:[1]}", strings.TrimSpace(ionToText(t, ion.NewReaderBytes(fields["nested"]))))
+		assert.Equal(t, "7", strings.TrimSpace(ionToText(t, ion.NewReaderBytes(fields["balance"]))))
+	})
+
+	t.Run("hash matches equivalent Ion struct", func(t *testing.T) {
+		txn := &transaction{}
+		jsonHash, err := toStatementHash("statement", txn.wrapParameters([]interface{}{json.RawMessage(`{"b": "x", "a": 1}`)}))
+		require.NoError(t, err)
+
+		type model struct {
+			A int    `ion:"a"`
+			B string `ion:"b"`
+		}
+		structHash, err := toStatementHash("statement", []interface{}{model{A: 1, B: "x"}})
+		require.NoError(t, err)
+		assert.Equal(t, structHash, jsonHash)
+	})
+
+	t.Run("invalid raw message", func(t *testing.T) {
+		_, err := ion.MarshalBinary(toIonParameter(json.RawMessage(`{"a": `)))
+		assert.Error(t, err)
+	})
+
+	t.Run("trailing data in raw message", func(t *testing.T) {
+		for _, raw := range []string{`{"a": 1} {"b": 2}`, `{"a": 1}]`, `1 2`} {
+			_, err := ion.MarshalBinary(toIonParameter(json.RawMessage(raw)))
+			assert.Error(t, err, raw)
+		}
+		_, err := ion.MarshalBinary(toIonParameter(json.RawMessage(" {\"a\": 1}\n")))
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,97 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SessionCheckout describes a session taken from the driver by a transaction and not returned yet. A checkout that
+// lasts much longer than the transactions of the application suggests that the session leaked, for example because
+// the function passed to Execute panicked or is blocked in a goroutine that never finishes. The permit held by a
+// leaked session counts towards DriverOptions.MaxConcurrentTransactions until the driver is shut down.
+type SessionCheckout struct {
+	// When the session was checked out.
+	CheckedOutAt time.Time
+	// The stack of the goroutine that checked out the session, captured when DriverOptions.DebugSessionLeaks is set.
+	Stack string
+}
+
+// sessionCheckouts tracks the sessions checked out of a driver. The zero value is ready to use.
+type sessionCheckouts struct {
+	lock          sync.Mutex
+	captureStacks bool
+	checkouts     map[*session]SessionCheckout
+}
+
+// checkout records that s was taken by a transaction.
+func (c *sessionCheckouts) checkout(s *session) {
+	checkout := SessionCheckout{CheckedOutAt: time.Now()}
+	if c.captureStacks {
+		checkout.Stack = string(debug.Stack())
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.checkouts == nil {
+		c.checkouts = make(map[*session]SessionCheckout)
+	}
+	c.checkouts[s] = checkout
+}
+
+// checkin records that s was returned to the pool or discarded.
+func (c *sessionCheckouts) checkin(s *session) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.checkouts, s)
+}
+
+// olderThan returns the checkouts that have lasted at least age, oldest first.
+func (c *sessionCheckouts) olderThan(age time.Duration) []SessionCheckout {
+	now := time.Now()
+	c.lock.Lock()
+	var checkouts []SessionCheckout
+	for _, checkout := range c.checkouts {
+		if now.Sub(checkout.CheckedOutAt) >= age {
+			checkouts = append(checkouts, checkout)
+		}
+	}
+	c.lock.Unlock()
+	sort.Slice(checkouts, func(i, j int) bool {
+		return checkouts[i].CheckedOutAt.Before(checkouts[j].CheckedOutAt)
+	})
+	return checkouts
+}
+
+// SuspectedSessionLeaks returns the sessions that have been checked out for at least age, oldest first. The stacks of
+// the checkouts are only captured when DriverOptions.DebugSessionLeaks is set.
+func (driver *QLDBDriver) SuspectedSessionLeaks(age time.Duration) []SessionCheckout {
+	return driver.sessionCheckouts.olderThan(age)
+}
+
+// logSessionLeaks logs the sessions still checked out when the driver is shut down.
+func (driver *QLDBDriver) logSessionLeaks() {
+	checkouts := driver.sessionCheckouts.olderThan(0)
+	if len(checkouts) == 0 {
+		return
+	}
+	driver.logger.logf(LogInfo, "Shutting down with %d sessions checked out, which may have leaked.", len(checkouts))
+	for _, checkout := range checkouts {
+		if checkout.Stack != "" {
+			driver.logger.logf(LogInfo, "Session checked out %v ago by:\n%s", time.Since(checkout.CheckedOutAt), checkout.Stack)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuspectedSessionLeaks(t *testing.T) {
+	newTestDriver := func(captureStacks bool) *QLDBDriver {
+		return newMockDriver(t, &qldbsessioniface.MockClientAPI{}, func(options *DriverOptions) {
+			options.RetryPolicy = RetryPolicy{MaxRetryLimit: 2, Backoff: ExponentialBackoffStrategy{SleepBase: time.Millisecond, SleepCap: time.Millisecond}}
+			options.DebugSessionLeaks = captureStacks
+		})
+	}
+
+	t.Run("returned sessions", func(t *testing.T) {
+		testDriver := newTestDriver(true)
+		_, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+			return nil, nil
+		})
+		require.NoError(t, err)
+		_, err = testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+			return nil, errors.New("not retried")
+		})
+		require.Error(t, err)
+
+		assert.Empty(t, testDriver.SuspectedSessionLeaks(0))
+	})
+
+	t.Run("panicking transaction", func(t *testing.T) {
+		testDriver := newTestDriver(true)
+		assert.Panics(t, func() {
+			_, _ = testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+				panic("unexpected")
+			})
+		})
+
+		leaks := testDriver.SuspectedSessionLeaks(0)
+		require.Len(t, leaks, 1)
+		assert.False(t, leaks[0].CheckedOutAt.IsZero())
+		assert.Contains(t, leaks[0].Stack, "TestSuspectedSessionLeaks")
+		assert.Empty(t, testDriver.SuspectedSessionLeaks(time.Hour))
+	})
+
+	t.Run("stacks are not captured by default", func(t *testing.T) {
+		testDriver := newTestDriver(false)
+		assert.Panics(t, func() {
+			_, _ = testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+				panic("unexpected")
+			})
+		})
+
+		leaks := testDriver.SuspectedSessionLeaks(0)
+		require.Len(t, leaks, 1)
+		assert.Empty(t, leaks[0].Stack)
+	})
+
+	t.Run("sessions in progress, oldest first", func(t *testing.T) {
+		testDriver := newTestDriver(false)
+		first, err := testDriver.getSession(context.Background(), nil)
+		require.NoError(t, err)
+		time.Sleep(time.Millisecond)
+		require.True(t, testDriver.semaphore.tryAcquire())
+		second, err := testDriver.createSession(context.Background(), nil)
+		require.NoError(t, err)
+
+		leaks := testDriver.SuspectedSessionLeaks(0)
+		require.Len(t, leaks, 2)
+		assert.True(t, leaks[0].CheckedOutAt.Before(leaks[1].CheckedOutAt))
+
+		testDriver.releaseSession(context.Background(), first)
+		testDriver.discardSession(second)
+		assert.Empty(t, testDriver.SuspectedSessionLeaks(0))
+	})
+}
@@ -0,0 +1,78 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"time"
+
+	"github.com/amzn/ion-go/ion"
+)
+
+// The QLDB quotas that the driver checks, or that applications can program against. See
+// https://docs.aws.amazon.com/qldb/latest/developerguide/limits.html. QLDB does not limit the number of statements of a
+// transaction, which are bounded by MaxDocumentsPerTransaction and MaxTransactionDuration instead; see
+// DriverOptions.StatementLimit to set a limit of your own.
+const (
+	// MaxDocumentSize is the maximum size of a document revision, in bytes of Ion binary.
+	MaxDocumentSize int = 128 * 1024
+	// MaxTransactionSize is the maximum size of the documents written by a transaction, and of the parameters of a
+	// statement, in bytes of Ion binary.
+	MaxTransactionSize int = 4 * 1024 * 1024
+	// MaxDocumentsPerTransaction is the maximum number of documents written by a transaction.
+	MaxDocumentsPerTransaction int = 40
+	// MaxTransactionDuration is the time after which QLDB expires a transaction that is not committed.
+	MaxTransactionDuration time.Duration = 30 * time.Second
+	// DefaultMaxActiveSessionsPerLedger is the default quota on the number of active sessions of a ledger, which can be
+	// raised. Every session held by a driver counts against it, so the DriverOptions.MaxConcurrentTransactions of all
+	// the drivers of a ledger should add up to less.
+	DefaultMaxActiveSessionsPerLedger int = 1500
+)
+
+// ValidateParameters returns a ParameterSizeError if a struct parameter, marshaled to Ion binary, exceeds
+// MaxDocumentSize, or if the parameters together exceed MaxTransactionSize. These are the checks made before a
+// statement is sent to QLDB, so that the documents of a statement can be validated, or split, beforehand.
+func ValidateParameters(parameters ...interface{}) error {
+	totalSize := 0
+	for i, parameter := range wrapParameters(parameters, IonMarshalOptions{}) {
+		ionBinary, err := ion.MarshalBinary(parameter)
+		if err != nil {
+			return err
+		}
+		totalSize += len(ionBinary)
+		if err := checkParameterSize(i, ionBinary, totalSize); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkParameterSize returns a ParameterSizeError if the parameter at index, of Ion binary ionBinary, is a struct
+// exceeding MaxDocumentSize, or if the parameters up to index, of totalSize bytes, exceed MaxTransactionSize. Only
+// structs are checked against MaxDocumentSize, since a struct parameter ends up in a document, as an inserted document
+// or as a value within one, while a list parameter may hold several documents, or the values of an IN clause.
+func checkParameterSize(index int, ionBinary []byte, totalSize int) error {
+	if len(ionBinary) > MaxDocumentSize && isStruct(ionBinary) {
+		return &ParameterSizeError{Index: index, Size: len(ionBinary), Limit: MaxDocumentSize}
+	}
+	if totalSize > MaxTransactionSize {
+		return &ParameterSizeError{Index: index, Size: totalSize, Limit: MaxTransactionSize}
+	}
+	return nil
+}
+
+// isStruct returns whether the first value of ionBinary is a struct.
+func isStruct(ionBinary []byte) bool {
+	reader := ion.NewReaderBytes(ionBinary)
+	return reader.Next() && reader.Type() == ion.StructType
+}
@@ -0,0 +1,69 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateParameters(t *testing.T) {
+	t.Run("within quotas", func(t *testing.T) {
+		assert.NoError(t, ValidateParameters())
+		assert.NoError(t, ValidateParameters("small", make([]byte, MaxDocumentSize-16)))
+		// Only structs are documents: a list may hold several documents, or the values of an IN clause
+		assert.NoError(t, ValidateParameters(make([]byte, MaxDocumentSize), []interface{}{make([]byte, MaxDocumentSize)}))
+	})
+
+	t.Run("document too large", func(t *testing.T) {
+		err := ValidateParameters("small", map[string]interface{}{"data": make([]byte, MaxDocumentSize)})
+		sizeErr := &ParameterSizeError{}
+		require.True(t, errors.As(err, &sizeErr))
+		assert.Equal(t, 1, sizeErr.Index)
+		assert.True(t, sizeErr.Size > MaxDocumentSize)
+		assert.Equal(t, MaxDocumentSize, sizeErr.Limit)
+	})
+
+	t.Run("JSON document too large", func(t *testing.T) {
+		// A JSON parameter is sent as the Ion value it holds, not as a blob
+		document := json.RawMessage(`{"data": "` + strings.Repeat("x", MaxDocumentSize) + `"}`)
+		err := ValidateParameters(document)
+		sizeErr := &ParameterSizeError{}
+		require.True(t, errors.As(err, &sizeErr))
+		assert.Equal(t, 0, sizeErr.Index)
+		assert.Equal(t, MaxDocumentSize, sizeErr.Limit)
+	})
+
+	t.Run("parameters too large", func(t *testing.T) {
+		document := make([]byte, MaxDocumentSize-16)
+		parameters := make([]interface{}, MaxTransactionSize/len(document)+1)
+		for i := range parameters {
+			parameters[i] = document
+		}
+		err := ValidateParameters(parameters...)
+		sizeErr := &ParameterSizeError{}
+		require.True(t, errors.As(err, &sizeErr))
+		assert.Equal(t, len(parameters)-1, sizeErr.Index)
+		assert.Equal(t, MaxTransactionSize, sizeErr.Limit)
+	})
+
+	t.Run("unmarshalable parameter", func(t *testing.T) {
+		assert.Error(t, ValidateParameters(make(chan int)))
+	})
+}
@@ -0,0 +1,129 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// LintRule identifies a QLDB anti-pattern reported by LintStatement.
+type LintRule string
+
+const (
+	// LintFullScan is for a SELECT, UPDATE or DELETE statement without an equality or IN predicate in its WHERE clause.
+	// QLDB only uses an index for such a predicate on an indexed field, or on the document ID, and otherwise reads every
+	// document of the table, which consumes read IOs in proportion to the size of the table.
+	LintFullScan LintRule = "full scan"
+	// LintSelectStar is for a SELECT * statement, which reads and transfers every field of the documents rather than
+	// the fields used by the application.
+	LintSelectStar LintRule = "SELECT *"
+	// LintLiteral is for a statement containing a string, numeric or Ion literal instead of a parameter. Such
+	// statements are harder to reuse and to audit, and are more exposed to injection.
+	LintLiteral LintRule = "literal"
+)
+
+// LintFinding is an anti-pattern found in a statement by LintStatement.
+type LintFinding struct {
+	// The anti-pattern found.
+	Rule LintRule
+	// Describes the finding and how to address it.
+	Message string
+}
+
+var (
+	// lintTargetRegex matches the statements reading documents, with the clause following their table.
+	lintTargetRegex = regexp.MustCompile(`(?is)^\s*(?:SELECT\s.*?\sFROM|UPDATE|DELETE\s+FROM)\s+[A-Za-z_][A-Za-z0-9_]*(.*)$`)
+	// lintWhereRegex matches a WHERE clause, with its predicates.
+	lintWhereRegex = regexp.MustCompile(`(?is)\bWHERE\b(.*)$`)
+	// equalityRegex matches an equality or IN predicate, excluding <=, >=, != and <>.
+	equalityRegex = regexp.MustCompile(`(?i)(?:^|[^<>!=])=(?:[^=]|$)|\bIN\s*[(<?\[]`)
+	// selectStarRegex matches a SELECT * projection.
+	selectStarRegex = regexp.MustCompile(`(?i)^\s*SELECT\s+\*`)
+)
+
+// LintStatement returns the QLDB anti-patterns found in a PartiQL statement, which are likely to consume more read IOs
+// than needed: full table scans, SELECT * projections and literals instead of parameters. It relies on simple pattern
+// matching rather than on parsing the statement and on the indexes of the tables, so it may report predicates on
+// fields that are not indexed as indexed, and is meant to review statements rather than to reject them.
+func LintStatement(statement string) []LintFinding {
+	var findings []LintFinding
+	if literals := literalRegex.FindAllString(statement, -1); len(literals) > 0 {
+		findings = append(findings, LintFinding{
+			Rule:    LintLiteral,
+			Message: "the statement contains literals, pass the values as parameters instead",
+		})
+	}
+	stripped := literalRegex.ReplaceAllString(statement, "?")
+	if selectStarRegex.MatchString(stripped) {
+		findings = append(findings, LintFinding{
+			Rule:    LintSelectStar,
+			Message: "the statement reads every field of the documents, select the fields used instead",
+		})
+	}
+	if target := lintTargetRegex.FindStringSubmatch(stripped); target != nil {
+		where := lintWhereRegex.FindStringSubmatch(target[1])
+		switch {
+		case where == nil:
+			findings = append(findings, LintFinding{
+				Rule:    LintFullScan,
+				Message: "the statement has no WHERE clause and reads every document of the table",
+			})
+		case !equalityRegex.MatchString(where[1]):
+			findings = append(findings, LintFinding{
+				Rule:    LintFullScan,
+				Message: "the WHERE clause has no equality or IN predicate that QLDB can look up in an index, add one on an indexed field",
+			})
+		}
+	}
+	return findings
+}
+
+// maxLintedStatements bounds the number of distinct statements whose findings a statementLinter remembers.
+const maxLintedStatements = 1024
+
+// statementLinter logs the findings of LintStatement for the statements executed, once per statement, see
+// DriverOptions.LintStatements.
+type statementLinter struct {
+	lock   sync.Mutex
+	linted map[string]bool
+}
+
+func newStatementLinter() *statementLinter {
+	return &statementLinter{linted: make(map[string]bool)}
+}
+
+// lint logs the findings of statement, unless they were logged already. Statements are remembered with their literals
+// redacted, so that the statements that only differ by their literals are logged once.
+func (linter *statementLinter) lint(logger *qldbLogger, statement string) {
+	redacted := redactStatement(statement)
+	linter.lock.Lock()
+	if linter.linted[redacted] || len(linter.linted) >= maxLintedStatements {
+		linter.lock.Unlock()
+		return
+	}
+	linter.linted[redacted] = true
+	linter.lock.Unlock()
+
+	findings := LintStatement(statement)
+	if len(findings) == 0 {
+		return
+	}
+	messages := make([]string, len(findings))
+	for i, finding := range findings {
+		messages[i] = string(finding.Rule) + ": " + finding.Message
+	}
+	logger.logf(LogInfo, "Statement lint: %s. Statement: %s", strings.Join(messages, "; "), redacted)
+}
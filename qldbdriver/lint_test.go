@@ -0,0 +1,86 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintStatement(t *testing.T) {
+	testCases := []struct {
+		statement string
+		rules     []LintRule
+	}{
+		{"SELECT name FROM Person WHERE id = ?", nil},
+		{"SELECT name FROM Person AS p WHERE p.id IN (?, ?)", nil},
+		{"SELECT name FROM Person BY pid WHERE pid = ?", nil},
+		{"INSERT INTO Person ?", nil},
+		{"CREATE INDEX ON Person (id)", nil},
+		{"SELECT * FROM Person WHERE id = ?", []LintRule{LintSelectStar}},
+		{"SELECT name FROM Person", []LintRule{LintFullScan}},
+		{"SELECT name FROM Person WHERE age > ?", []LintRule{LintFullScan}},
+		{"SELECT name FROM Person WHERE age >= ? AND name <> ?", []LintRule{LintFullScan}},
+		{"UPDATE Person SET age = ?", []LintRule{LintFullScan}},
+		{"DELETE FROM Person", []LintRule{LintFullScan}},
+		{"UPDATE Person SET age = ? WHERE id = ?", nil},
+		{"SELECT name FROM Person WHERE note LIKE 'a = b'", []LintRule{LintLiteral, LintFullScan}},
+		{"SELECT * FROM Person WHERE age = 42", []LintRule{LintLiteral, LintSelectStar}},
+		{"INSERT INTO Person `{name: \"Alice\"}`", []LintRule{LintLiteral}},
+		{"SELECT *\nFROM Person", []LintRule{LintSelectStar, LintFullScan}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.statement, func(t *testing.T) {
+			var rules []LintRule
+			for _, finding := range LintStatement(tc.statement) {
+				assert.NotEmpty(t, finding.Message)
+				rules = append(rules, finding.Rule)
+			}
+			assert.Equal(t, tc.rules, rules)
+		})
+	}
+}
+
+func TestLintTransaction(t *testing.T) {
+	mockHash, _ := toQLDBHash(mockTxnID)
+	mockService := new(mockTransactionService)
+	mockService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&mockExecuteResult, nil)
+	recorder := &recordingLogger{}
+	logger := &qldbLogger{logger: recorder, verbosity: LogInfo}
+	testTransaction := &transaction{
+		communicator: mockService,
+		id:           &mockTxnID,
+		logger:       logger,
+		commitHash:   mockHash,
+		linter:       newStatementLinter(),
+	}
+
+	_, err := testTransaction.execute(context.Background(), "SELECT * FROM Person WHERE age = 42")
+	require.NoError(t, err)
+	_, err = testTransaction.execute(context.Background(), "SELECT * FROM Person WHERE age = 7")
+	require.NoError(t, err)
+	_, err = testTransaction.execute(context.Background(), "SELECT name FROM Person WHERE id = ?", "someId")
+	require.NoError(t, err)
+
+	require.Len(t, recorder.messages, 1)
+	assert.Contains(t, recorder.messages[0], "SELECT * FROM Person WHERE age = ?")
+	assert.Contains(t, recorder.messages[0], string(LintLiteral))
+	assert.Contains(t, recorder.messages[0], string(LintSelectStar))
+	assert.Equal(t, 3, testTransaction.statementCount)
+}
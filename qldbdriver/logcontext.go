@@ -0,0 +1,55 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+)
+
+// loggerContextKey is the key of the Logger stored in a context by NewContextWithLogger.
+type loggerContextKey struct{}
+
+// traceIDContextKey is the key of the trace ID stored in a context by NewContextWithTraceID.
+type traceIDContextKey struct{}
+
+// NewContextWithLogger returns a copy of ctx carrying logger, which the driver uses instead of DriverOptions.Logger
+// for the log messages of the calls made with the context, such as QLDBDriver.Execute. This lets multi-tenant
+// services route the logs of the driver into the log stream of each request. The messages are still filtered by
+// DriverOptions.LoggerVerbosity.
+func NewContextWithLogger(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, logger)
+}
+
+// NewContextWithTraceID returns a copy of ctx carrying traceID, which is included in every log message of the calls
+// made with the context, such as QLDBDriver.Execute.
+func NewContextWithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDContextKey{}, traceID)
+}
+
+// forContext returns a logger that uses the Logger and trace ID carried by ctx, if any.
+func (qldbLogger *qldbLogger) forContext(ctx context.Context) *qldbLogger {
+	logger, hasLogger := ctx.Value(loggerContextKey{}).(Logger)
+	traceID, _ := ctx.Value(traceIDContextKey{}).(string)
+	if (!hasLogger || logger == nil) && traceID == "" {
+		return qldbLogger
+	}
+	scoped := *qldbLogger
+	if hasLogger && logger != nil {
+		scoped.logger = logger
+	}
+	if traceID != "" {
+		scoped.prefix += "[traceId=" + traceID + "] "
+	}
+	return &scoped
+}
@@ -0,0 +1,79 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerForContext(t *testing.T) {
+	driverLogger := &recordingLogger{}
+	logger := &qldbLogger{logger: driverLogger, verbosity: LogInfo}
+
+	t.Run("no values", func(t *testing.T) {
+		assert.Same(t, logger, logger.forContext(context.Background()))
+	})
+
+	t.Run("logger", func(t *testing.T) {
+		requestLogger := &recordingLogger{}
+		ctx := NewContextWithLogger(context.Background(), requestLogger)
+		logger.forContext(ctx).log(LogInfo, "message")
+		assert.Equal(t, []string{"[INFO] message"}, requestLogger.messages)
+		assert.Empty(t, driverLogger.messages)
+	})
+
+	t.Run("trace ID", func(t *testing.T) {
+		ctx := NewContextWithTraceID(context.Background(), "trace-1")
+		logger.forContext(ctx).withTags("op-1", nil).log(LogInfo, "message")
+		assert.Equal(t, []string{"[INFO] [traceId=trace-1] [correlationId=op-1] message"}, driverLogger.messages)
+	})
+
+	t.Run("verbosity", func(t *testing.T) {
+		requestLogger := &recordingLogger{}
+		ctx := NewContextWithLogger(context.Background(), requestLogger)
+		logger.forContext(ctx).log(LogDebug, "message")
+		assert.Empty(t, requestLogger.messages)
+	})
+}
+
+func TestExecuteWithContextLogger(t *testing.T) {
+	driverLogger := &recordingLogger{}
+	testDriver := newMockDriver(t, &qldbsessioniface.MockClientAPI{
+		SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+			return qldbsessioniface.DefaultSendCommandOutput(params), nil
+		},
+	}, func(options *DriverOptions) {
+		options.Logger = driverLogger
+		options.LoggerVerbosity = LogDebug
+	})
+
+	requestLogger := &recordingLogger{}
+	ctx := NewContextWithTraceID(NewContextWithLogger(context.Background(), requestLogger), "trace-1")
+	_, err := testDriver.Execute(ctx, func(txn Transaction) (interface{}, error) {
+		return txn.Execute("SELECT * FROM T")
+	})
+	require.NoError(t, err)
+
+	assert.Empty(t, driverLogger.messages)
+	require.NotEmpty(t, requestLogger.messages)
+	for _, message := range requestLogger.messages {
+		assert.Contains(t, message, "[traceId=trace-1]")
+	}
+}
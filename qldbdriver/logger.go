@@ -16,8 +16,14 @@ package qldbdriver
 import (
 	"fmt"
 	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
 )
 
+var literalRegex = regexp.MustCompile("'(?:[^']|'')*'|`[^`]*`|\\b\\d+(?:\\.\\d+)?(?:[eE][+-]?\\d+)?\\b")
+
 // Logger is an interface for a QLDBDriver logger.
 type Logger interface {
 	// Log the message using the built-in Golang logging package.
@@ -39,10 +45,54 @@ const (
 type qldbLogger struct {
 	logger    Logger
 	verbosity LogLevel
+	prefix    string
+	redaction LogRedaction
+	// sharedVerbosity, when set, overrides verbosity with a level shared by the copies of the logger, which
+	// QLDBDriver.UpdateOptions can change while they are in use.
+	sharedVerbosity *uint32
+}
+
+// level returns the verbosity of the logger.
+func (qldbLogger *qldbLogger) level() LogLevel {
+	if qldbLogger.sharedVerbosity != nil {
+		return LogLevel(atomic.LoadUint32(qldbLogger.sharedVerbosity))
+	}
+	return qldbLogger.verbosity
+}
+
+// setLevel changes the verbosity of the logger and of its copies.
+func (qldbLogger *qldbLogger) setLevel(verbosity LogLevel) {
+	if qldbLogger.sharedVerbosity == nil {
+		qldbLogger.sharedVerbosity = new(uint32)
+	}
+	atomic.StoreUint32(qldbLogger.sharedVerbosity, uint32(verbosity))
+}
+
+// withTags returns a logger that prefixes every message with the correlation ID and tags.
+func (qldbLogger *qldbLogger) withTags(correlationID string, tags map[string]string) *qldbLogger {
+	if correlationID == "" && len(tags) == 0 {
+		return qldbLogger
+	}
+	pairs := make([]string, 0, len(tags)+1)
+	if correlationID != "" {
+		pairs = append(pairs, "correlationId="+correlationID)
+	}
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+tags[key])
+	}
+	tagged := *qldbLogger
+	tagged.prefix += "[" + strings.Join(pairs, " ") + "] "
+	return &tagged
 }
 
 func (qldbLogger *qldbLogger) log(verbosityLevel LogLevel, message string) {
-	if verbosityLevel <= qldbLogger.verbosity {
+	if verbosityLevel <= qldbLogger.level() {
+		message = qldbLogger.prefix + message
 		switch verbosityLevel {
 		case LogInfo:
 			qldbLogger.logger.Log("[INFO] "+message, verbosityLevel)
@@ -55,16 +105,32 @@ func (qldbLogger *qldbLogger) log(verbosityLevel LogLevel, message string) {
 }
 
 func (qldbLogger *qldbLogger) logf(verbosityLevel LogLevel, message string, args ...interface{}) {
-	if verbosityLevel <= qldbLogger.verbosity {
-		switch verbosityLevel {
-		case LogInfo:
-			qldbLogger.logger.Log(fmt.Sprintf("[INFO] "+message, args...), verbosityLevel)
-		case LogDebug:
-			qldbLogger.logger.Log(fmt.Sprintf("[DEBUG] "+message, args...), verbosityLevel)
+	if verbosityLevel <= qldbLogger.level() {
+		qldbLogger.log(verbosityLevel, fmt.Sprintf(message, args...))
+	}
+}
+
+// statement returns a statement as it is logged: with its literals redacted, unless the redaction is LogRedactNone.
+func (qldbLogger *qldbLogger) statement(statement string) string {
+	if qldbLogger.redaction == LogRedactNone {
+		return statement
+	}
+	return redactStatement(statement)
+}
+
+// redactStatement replaces the string, Ion and numeric literals of a statement with placeholders so that it can be logged
+// without exposing document values.
+func redactStatement(statement string) string {
+	return literalRegex.ReplaceAllStringFunc(statement, func(literal string) string {
+		switch literal[0] {
+		case '\'':
+			return "'?'"
+		case '`':
+			return "`?`"
 		default:
-			qldbLogger.logger.Log(fmt.Sprintf(message, args...), verbosityLevel)
+			return "?"
 		}
-	}
+	})
 }
 
 type defaultLogger struct{}
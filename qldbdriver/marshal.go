@@ -0,0 +1,87 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"time"
+
+	"github.com/amzn/ion-go/ion"
+)
+
+// IonMarshalOptions controls how statement parameters are marshaled to Ion before being sent to QLDB.
+//
+// The same encoding is used to compute the commit digest, so the options only affect the Ion representation of the
+// parameters and not the integrity of the transaction.
+type IonMarshalOptions struct {
+	// Write the keys of Go maps in sorted order. Default: false.
+	SortMaps bool
+	// The precision of top-level time.Time parameters. Default: ion.TimestampNoPrecision, which keeps the ion-go default
+	// of nanosecond precision.
+	TimestampPrecision ion.TimestampPrecision
+	// Annotations to add to each top-level parameter. Default: none.
+	Annotations []string
+}
+
+// WithIonMarshalOptions wraps a statement parameter so that it is marshaled using the provided options, taking
+// precedence over DriverOptions.IonMarshalOptions.
+func WithIonMarshalOptions(parameter interface{}, options IonMarshalOptions) interface{} {
+	return &ionParameter{parameter, options}
+}
+
+func (options IonMarshalOptions) isDefault() bool {
+	return !options.SortMaps && options.TimestampPrecision == ion.TimestampNoPrecision && len(options.Annotations) == 0
+}
+
+type ionParameter struct {
+	value   interface{}
+	options IonMarshalOptions
+}
+
+// MarshalIon writes the wrapped parameter to the Ion writer using the configured options.
+func (parameter *ionParameter) MarshalIon(writer ion.Writer) error {
+	if len(parameter.options.Annotations) > 0 {
+		annotations := make([]ion.SymbolToken, len(parameter.options.Annotations))
+		for i, annotation := range parameter.options.Annotations {
+			annotations[i] = ion.NewSymbolTokenFromString(annotation)
+		}
+		err := writer.Annotations(annotations...)
+		if err != nil {
+			return err
+		}
+	}
+
+	value := toIonParameter(parameter.value)
+	if dateTime, ok := value.(time.Time); ok && parameter.options.TimestampPrecision != ion.TimestampNoPrecision {
+		value = ion.NewTimestamp(dateTime, parameter.options.TimestampPrecision, timezoneKind(dateTime))
+	}
+
+	var encoderOpts ion.EncoderOpts
+	if parameter.options.SortMaps {
+		encoderOpts |= ion.EncodeSortMaps
+	}
+	return ion.NewEncoderOpts(writer, encoderOpts).Encode(value)
+}
+
+// timezoneKind determines the Ion timezone kind of a time.Time the same way ion-go does when marshaling it.
+func timezoneKind(dateTime time.Time) ion.TimezoneKind {
+	zoneName, zoneOffset := dateTime.Zone()
+	switch {
+	case zoneName != "" && zoneOffset == 0:
+		return ion.TimezoneUTC
+	case zoneName != "" && zoneOffset != 0:
+		return ion.TimezoneLocal
+	default:
+		return ion.TimezoneUnspecified
+	}
+}
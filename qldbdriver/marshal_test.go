@@ -0,0 +1,83 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/amzn/ion-go/ion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithIonMarshalOptions(t *testing.T) {
+	toText := func(t *testing.T, value interface{}) string {
+		text, err := ion.MarshalText(value)
+		require.NoError(t, err)
+		return string(text)
+	}
+
+	t.Run("sort maps", func(t *testing.T) {
+		value := map[string]int{"c": 3, "a": 1, "b": 2}
+		assert.Equal(t, "{a:1,b:2,c:3}", toText(t, WithIonMarshalOptions(value, IonMarshalOptions{SortMaps: true})))
+	})
+
+	t.Run("annotations", func(t *testing.T) {
+		value := WithIonMarshalOptions(1, IonMarshalOptions{Annotations: []string{"foo", "bar"}})
+		assert.Equal(t, "foo::bar::1", toText(t, value))
+	})
+
+	t.Run("timestamp precision", func(t *testing.T) {
+		dateTime := time.Date(2021, 6, 15, 10, 30, 45, 123, time.UTC)
+		value := WithIonMarshalOptions(dateTime, IonMarshalOptions{TimestampPrecision: ion.TimestampPrecisionDay})
+		assert.Equal(t, "2021-06-15T", toText(t, value))
+	})
+
+	t.Run("default options", func(t *testing.T) {
+		value := struct {
+			Name string `ion:"name"`
+		}{"doc"}
+		assert.Equal(t, toText(t, value), toText(t, WithIonMarshalOptions(value, IonMarshalOptions{})))
+	})
+
+	t.Run("hash uses options", func(t *testing.T) {
+		plainHash, err := toQLDBHash(1)
+		require.NoError(t, err)
+		annotatedHash, err := toQLDBHash(WithIonMarshalOptions(1, IonMarshalOptions{Annotations: []string{"foo"}}))
+		require.NoError(t, err)
+		assert.NotEqual(t, plainHash.hash, annotatedHash.hash)
+	})
+}
+
+func TestTransactionWrapParameters(t *testing.T) {
+	t.Run("default options do not wrap", func(t *testing.T) {
+		txn := &transaction{}
+		parameters := []interface{}{1, "a"}
+		assert.Equal(t, parameters, txn.wrapParameters(parameters))
+	})
+
+	t.Run("driver options wrap parameters", func(t *testing.T) {
+		txnOptions := IonMarshalOptions{SortMaps: true}
+		statementOptions := IonMarshalOptions{Annotations: []string{"foo"}}
+		txn := &transaction{marshalOptions: txnOptions}
+		explicit := WithIonMarshalOptions(2, statementOptions)
+
+		wrapped := txn.wrapParameters([]interface{}{1, explicit})
+		require.Len(t, wrapped, 2)
+		assert.Equal(t, &ionParameter{1, txnOptions}, wrapped[0])
+		// Per-statement options take precedence
+		assert.Equal(t, explicit, wrapped[1])
+	})
+}
@@ -0,0 +1,170 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"math/big"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/amzn/ion-go/ion"
+)
+
+var (
+	ionMarshalerType = reflect.TypeOf((*ion.Marshaler)(nil)).Elem()
+	bigIntType       = reflect.TypeOf(big.Int{})
+	bigFloatType     = reflect.TypeOf(big.Float{})
+)
+
+var ionTagOptions = map[string]bool{"omitempty": true, "symbol": true, "clob": true, "sexp": true, "annotations": true}
+
+// ionStructTypes are struct types that Ion encodes as scalar values rather than as Ion structs.
+var ionStructTypes = map[reflect.Type]bool{
+	reflect.TypeOf(time.Time{}):     true,
+	reflect.TypeOf(ion.Timestamp{}): true,
+	reflect.TypeOf(ion.Decimal{}):   true,
+}
+
+// ValidateModel inspects the ion struct tags of a model, the struct that documents of a table are marshaled from and
+// unmarshaled into, and returns a ModelError listing its mapping problems. model must be a struct or a pointer to a
+// struct, for example Person{}. The problems reported are:
+//   - exported fields without an ion tag, which are stored under the Go field name,
+//   - unexported fields with an ion tag, which are ignored,
+//   - unknown ion tag options,
+//   - fields mapped to the same Ion field name,
+//   - field types that cannot be marshaled to Ion, such as channels, functions, complex numbers, maps with keys that
+//     are not strings, math/big types and structs without exported fields. Types implementing ion.Marshaler are not
+//     inspected.
+//
+// Nested structs are validated as well. ValidateModel is meant to be called from tests, so that mapping mistakes are
+// caught before documents with missing or null fields are written to the ledger.
+func ValidateModel(model interface{}) error {
+	modelType := reflect.TypeOf(model)
+	if modelType != nil && modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+	if modelType == nil || modelType.Kind() != reflect.Struct {
+		return &qldbDriverError{"Model must be a struct or a pointer to a struct."}
+	}
+
+	validator := &modelValidator{visited: make(map[reflect.Type]bool)}
+	validator.validateStruct(modelType, modelType.Name())
+	if len(validator.problems) > 0 {
+		return &ModelError{Model: modelType.String(), Problems: validator.problems}
+	}
+	return nil
+}
+
+type modelValidator struct {
+	visited  map[reflect.Type]bool
+	problems []string
+}
+
+func (validator *modelValidator) addProblem(path string, problem string) {
+	validator.problems = append(validator.problems, path+": "+problem)
+}
+
+// validateStruct validates the fields of a struct type reached through path.
+func (validator *modelValidator) validateStruct(structType reflect.Type, path string) {
+	if validator.visited[structType] || ionStructTypes[structType] {
+		return
+	}
+	validator.visited[structType] = true
+
+	names := make(map[string]string)
+	if !validator.validateFields(structType, path, names) {
+		validator.addProblem(path, "struct "+structType.String()+" has no exported fields and is stored as an empty Ion struct")
+	}
+}
+
+// validateFields validates the fields of a struct type, including the fields promoted from embedded structs, and
+// records their Ion names in names. It returns whether the struct has any field mapped to Ion.
+func (validator *modelValidator) validateFields(structType reflect.Type, path string, names map[string]string) bool {
+	hasFields := false
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		fieldPath := path + "." + field.Name
+		tag, hasTag := field.Tag.Lookup("ion")
+		if tag == "-" {
+			continue
+		}
+
+		fieldType := field.Type
+		if fieldType.Name() == "" && fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		name, opts := tag, ""
+		if idx := strings.Index(tag, ","); idx != -1 {
+			name, opts = tag[:idx], tag[idx+1:]
+		}
+
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			if validator.validateFields(fieldType, fieldPath, names) {
+				hasFields = true
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			if hasTag {
+				validator.addProblem(fieldPath, "unexported field has an ion tag and is ignored")
+			}
+			continue
+		}
+
+		hasFields = true
+		if !hasTag {
+			validator.addProblem(fieldPath, "missing ion tag, the field is stored as '"+field.Name+"'")
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if other, ok := names[name]; ok {
+			validator.addProblem(fieldPath, "Ion field name '"+name+"' is also used by "+other)
+		} else {
+			names[name] = fieldPath
+		}
+		for _, opt := range strings.Split(opts, ",") {
+			if opt != "" && !ionTagOptions[opt] {
+				validator.addProblem(fieldPath, "unknown ion tag option '"+opt+"'")
+			}
+		}
+		validator.validateType(fieldType, fieldPath)
+	}
+	return hasFields
+}
+
+// validateType validates that a type reached through path can be marshaled to Ion.
+func (validator *modelValidator) validateType(valueType reflect.Type, path string) {
+	if valueType.Implements(ionMarshalerType) || reflect.PtrTo(valueType).Implements(ionMarshalerType) {
+		return
+	}
+	if valueType == bigIntType || valueType == bigFloatType {
+		validator.addProblem(path, "type "+valueType.String()+" is only supported for top-level parameters, use ion.Decimal")
+		return
+	}
+	switch valueType.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+		validator.addProblem(path, "type "+valueType.String()+" cannot be marshaled to Ion")
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		validator.validateType(valueType.Elem(), path+"[]")
+	case reflect.Map:
+		if valueType.Key().Kind() != reflect.String {
+			validator.addProblem(path, "map keys of type "+valueType.Key().String()+" cannot be marshaled to Ion, use string keys")
+		}
+		validator.validateType(valueType.Elem(), path+"[]")
+	case reflect.Struct:
+		validator.validateStruct(valueType, path)
+	}
+}
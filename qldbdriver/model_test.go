@@ -0,0 +1,85 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/amzn/ion-go/ion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type validAddress struct {
+	Street string `ion:"Street"`
+}
+
+type validAudit struct {
+	CreatedAt time.Time `ion:"CreatedAt"`
+}
+
+type validModel struct {
+	validAudit
+	Name     string                  `ion:"Name"`
+	Amount   ion.Decimal             `ion:"Amount"`
+	Tags     []string                `ion:"Tags,omitempty"`
+	Address  *validAddress           `ion:"Address"`
+	Previous []validAddress          `ion:"Previous"`
+	Extra    map[string]validAddress `ion:"Extra"`
+	Kind     string                  `ion:"Kind,symbol"`
+	Ignored  chan int                `ion:"-"`
+	internal int
+}
+
+type invalidModel struct {
+	Name     string `ion:"Name"`
+	Nickname string `ion:"Name"`
+	Age      int    `ion:"Age,omitempy"`
+	Email    string
+	Callback func()         `ion:"Callback"`
+	Counts   map[int]string `ion:"Counts"`
+	Balance  *big.Int       `ion:"Balance"`
+	secret   string         `ion:"secret"`
+}
+
+func TestValidateModel(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		assert.NoError(t, ValidateModel(validModel{}))
+		assert.NoError(t, ValidateModel(&validModel{}))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		err := ValidateModel(invalidModel{})
+		var modelErr *ModelError
+		require.True(t, errors.As(err, &modelErr))
+		assert.Equal(t, "qldbdriver.invalidModel", modelErr.Model)
+		assert.Equal(t, []string{
+			"invalidModel.Nickname: Ion field name 'Name' is also used by invalidModel.Name",
+			"invalidModel.Age: unknown ion tag option 'omitempy'",
+			"invalidModel.Email: missing ion tag, the field is stored as 'Email'",
+			"invalidModel.Callback: type func() cannot be marshaled to Ion",
+			"invalidModel.Counts: map keys of type int cannot be marshaled to Ion, use string keys",
+			"invalidModel.Balance: type big.Int is only supported for top-level parameters, use ion.Decimal",
+			"invalidModel.secret: unexported field has an ion tag and is ignored",
+		}, modelErr.Problems)
+	})
+
+	t.Run("not a struct", func(t *testing.T) {
+		assert.Error(t, ValidateModel("model"))
+		assert.Error(t, ValidateModel(nil))
+	})
+}
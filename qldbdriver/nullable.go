@@ -0,0 +1,399 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/amzn/ion-go/ion"
+)
+
+// The null types hold a field of a document that may be null or missing, which ion.Unmarshal decodes into the zero
+// value of the field in both cases. They are decoded by Unmarshal, and written as typed Ion nulls when not Valid.
+
+// NullString is a string that may be null or missing.
+type NullString struct {
+	String string
+	// Whether the field holds a value that is not null.
+	Valid bool
+	// Whether the field is present, null or not.
+	Present bool
+}
+
+// NullInt64 is an int64 that may be null or missing.
+type NullInt64 struct {
+	Int64 int64
+	// Whether the field holds a value that is not null.
+	Valid bool
+	// Whether the field is present, null or not.
+	Present bool
+}
+
+// NullFloat64 is a float64 that may be null or missing.
+type NullFloat64 struct {
+	Float64 float64
+	// Whether the field holds a value that is not null.
+	Valid bool
+	// Whether the field is present, null or not.
+	Present bool
+}
+
+// NullBool is a bool that may be null or missing.
+type NullBool struct {
+	Bool bool
+	// Whether the field holds a value that is not null.
+	Valid bool
+	// Whether the field is present, null or not.
+	Present bool
+}
+
+// NullTime is a time.Time, stored as an Ion timestamp, that may be null or missing.
+type NullTime struct {
+	Time time.Time
+	// Whether the field holds a value that is not null.
+	Valid bool
+	// Whether the field is present, null or not.
+	Present bool
+}
+
+// nullScanner is implemented by the null types to decode the value the reader is positioned on.
+type nullScanner interface {
+	scanIon(reader ion.Reader) error
+}
+
+var nullScannerType = reflect.TypeOf((*nullScanner)(nil)).Elem()
+
+// Unmarshal unmarshals a row of data in Ion binary into v like ion.Unmarshal, and also decodes the fields of the null
+// types, such as NullString, at any depth of v, so that a null field can be told apart from a missing field and from a
+// zero value. v must be a non-nil pointer.
+func Unmarshal(ionBinary []byte, v interface{}) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return &qldbDriverError{"Unmarshal requires a non-nil pointer."}
+	}
+	if !containsNullType(value.Type().Elem(), map[reflect.Type]bool{}) {
+		return ion.Unmarshal(ionBinary, v)
+	}
+
+	reader := ion.NewReaderBytes(ionBinary)
+	if !reader.Next() {
+		if reader.Err() != nil {
+			return reader.Err()
+		}
+		return ion.ErrNoInput
+	}
+	return decodeWithNullTypes(reader, value.Elem())
+}
+
+// containsNullType returns whether values of valueType may hold a null type.
+func containsNullType(valueType reflect.Type, visited map[reflect.Type]bool) bool {
+	if reflect.PtrTo(valueType).Implements(nullScannerType) {
+		return true
+	}
+	switch valueType.Kind() {
+	case reflect.Ptr, reflect.Slice:
+		return containsNullType(valueType.Elem(), visited)
+	case reflect.Struct:
+		if visited[valueType] || ionStructTypes[valueType] {
+			return false
+		}
+		visited[valueType] = true
+		for i := 0; i < valueType.NumField(); i++ {
+			field := valueType.Field(i)
+			if field.Tag.Get("ion") != "-" && containsNullType(field.Type, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// decodeWithNullTypes decodes the value the reader is positioned on into value, which is addressable. The values
+// that cannot hold a null type are unmarshaled by ion-go.
+func decodeWithNullTypes(reader ion.Reader, value reflect.Value) error {
+	if scanner, ok := value.Addr().Interface().(nullScanner); ok {
+		return scanner.scanIon(reader)
+	}
+	if !containsNullType(value.Type(), map[reflect.Type]bool{}) {
+		return unmarshalCurrentValue(reader, value.Addr().Interface())
+	}
+	if reader.IsNull() {
+		value.Set(reflect.Zero(value.Type()))
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		return decodeWithNullTypes(reader, value.Elem())
+	case reflect.Slice:
+		if reader.Type() != ion.ListType && reader.Type() != ion.SexpType {
+			return &qldbDriverError{"Cannot unmarshal Ion " + reader.Type().String() + " into " + value.Type().String() + "."}
+		}
+		return decodeSliceWithNullTypes(reader, value)
+	case reflect.Struct:
+		if reader.Type() != ion.StructType {
+			return &qldbDriverError{"Cannot unmarshal Ion " + reader.Type().String() + " into " + value.Type().String() + "."}
+		}
+		return decodeStructWithNullTypes(reader, value)
+	}
+	return unmarshalCurrentValue(reader, value.Addr().Interface())
+}
+
+func decodeSliceWithNullTypes(reader ion.Reader, value reflect.Value) error {
+	err := reader.StepIn()
+	if err != nil {
+		return err
+	}
+	slice := reflect.MakeSlice(value.Type(), 0, 0)
+	for reader.Next() {
+		slice = reflect.Append(slice, reflect.Zero(value.Type().Elem()))
+		err = decodeWithNullTypes(reader, slice.Index(slice.Len()-1))
+		if err != nil {
+			return err
+		}
+	}
+	if reader.Err() != nil {
+		return reader.Err()
+	}
+	value.Set(slice)
+	return reader.StepOut()
+}
+
+func decodeStructWithNullTypes(reader ion.Reader, value reflect.Value) error {
+	fields := structFields(value.Type(), nil)
+	err := reader.StepIn()
+	if err != nil {
+		return err
+	}
+	for reader.Next() {
+		fieldName, err := reader.FieldName()
+		if err != nil {
+			return err
+		}
+		if fieldName == nil || fieldName.Text == nil {
+			continue
+		}
+		field := findStructField(fields, *fieldName.Text)
+		if field == nil {
+			continue
+		}
+		err = decodeWithNullTypes(reader, fieldByPath(value, field.path))
+		if err != nil {
+			return err
+		}
+	}
+	if reader.Err() != nil {
+		return reader.Err()
+	}
+	return reader.StepOut()
+}
+
+// structField is a Go field of a struct and its Ion field name.
+type structField struct {
+	name string
+	path []int
+	opts string
+}
+
+// structFields returns the fields of a struct type in order, following the ion struct tags and promoting the fields of
+// embedded structs like ion-go.
+func structFields(structType reflect.Type, path []int) []structField {
+	var fields []structField
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		tag := field.Tag.Get("ion")
+		if tag == "-" {
+			continue
+		}
+		name, opts := tag, ""
+		if idx := strings.Index(tag, ","); idx != -1 {
+			name, opts = tag[:idx], tag[idx+1:]
+		}
+		fieldPath := append(append([]int{}, path...), i)
+
+		fieldType := field.Type
+		if fieldType.Name() == "" && fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			fields = append(fields, structFields(fieldType, fieldPath)...)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, structField{name: name, path: fieldPath, opts: opts})
+	}
+	return fields
+}
+
+// findStructField returns the field named name, or like ion-go the first field with the same name regardless of case.
+func findStructField(fields []structField, name string) *structField {
+	var found *structField
+	for i := range fields {
+		if fields[i].name == name {
+			return &fields[i]
+		}
+		if found == nil && strings.EqualFold(fields[i].name, name) {
+			found = &fields[i]
+		}
+	}
+	return found
+}
+
+// fieldByPath returns the field of value at path, allocating the embedded structs reached through nil pointers.
+func fieldByPath(value reflect.Value, path []int) reflect.Value {
+	for _, i := range path {
+		if value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+			value = value.Elem()
+		}
+		value = value.Field(i)
+	}
+	return value
+}
+
+func nullTypeError(ionType ion.Type, nullType string) error {
+	return &qldbDriverError{"Cannot unmarshal Ion " + ionType.String() + " into " + nullType + "."}
+}
+
+func (n *NullString) scanIon(reader ion.Reader) error {
+	*n = NullString{Present: true}
+	if reader.IsNull() {
+		return nil
+	}
+	if reader.Type() != ion.StringType && reader.Type() != ion.SymbolType {
+		return nullTypeError(reader.Type(), "NullString")
+	}
+	value, err := reader.StringValue()
+	if err != nil || value == nil {
+		return err
+	}
+	n.String, n.Valid = *value, true
+	return nil
+}
+
+// MarshalIon writes the string, or null.string when it is not Valid.
+func (n NullString) MarshalIon(writer ion.Writer) error {
+	if !n.Valid {
+		return writer.WriteNullType(ion.StringType)
+	}
+	return writer.WriteString(n.String)
+}
+
+func (n *NullInt64) scanIon(reader ion.Reader) error {
+	*n = NullInt64{Present: true}
+	if reader.IsNull() {
+		return nil
+	}
+	if reader.Type() != ion.IntType {
+		return nullTypeError(reader.Type(), "NullInt64")
+	}
+	value, err := reader.Int64Value()
+	if err != nil || value == nil {
+		return err
+	}
+	n.Int64, n.Valid = *value, true
+	return nil
+}
+
+// MarshalIon writes the int, or null.int when it is not Valid.
+func (n NullInt64) MarshalIon(writer ion.Writer) error {
+	if !n.Valid {
+		return writer.WriteNullType(ion.IntType)
+	}
+	return writer.WriteInt(n.Int64)
+}
+
+func (n *NullFloat64) scanIon(reader ion.Reader) error {
+	*n = NullFloat64{Present: true}
+	if reader.IsNull() {
+		return nil
+	}
+	if reader.Type() != ion.FloatType {
+		return nullTypeError(reader.Type(), "NullFloat64")
+	}
+	value, err := reader.FloatValue()
+	if err != nil || value == nil {
+		return err
+	}
+	n.Float64, n.Valid = *value, true
+	return nil
+}
+
+// MarshalIon writes the float, or null.float when it is not Valid.
+func (n NullFloat64) MarshalIon(writer ion.Writer) error {
+	if !n.Valid {
+		return writer.WriteNullType(ion.FloatType)
+	}
+	return writer.WriteFloat(n.Float64)
+}
+
+func (n *NullBool) scanIon(reader ion.Reader) error {
+	*n = NullBool{Present: true}
+	if reader.IsNull() {
+		return nil
+	}
+	if reader.Type() != ion.BoolType {
+		return nullTypeError(reader.Type(), "NullBool")
+	}
+	value, err := reader.BoolValue()
+	if err != nil || value == nil {
+		return err
+	}
+	n.Bool, n.Valid = *value, true
+	return nil
+}
+
+// MarshalIon writes the bool, or null.bool when it is not Valid.
+func (n NullBool) MarshalIon(writer ion.Writer) error {
+	if !n.Valid {
+		return writer.WriteNullType(ion.BoolType)
+	}
+	return writer.WriteBool(n.Bool)
+}
+
+func (n *NullTime) scanIon(reader ion.Reader) error {
+	*n = NullTime{Present: true}
+	if reader.IsNull() {
+		return nil
+	}
+	if reader.Type() != ion.TimestampType {
+		return nullTypeError(reader.Type(), "NullTime")
+	}
+	value, err := reader.TimestampValue()
+	if err != nil || value == nil {
+		return err
+	}
+	n.Time, n.Valid = value.GetDateTime(), true
+	return nil
+}
+
+// MarshalIon writes the time as a timestamp, or null.timestamp when it is not Valid.
+func (n NullTime) MarshalIon(writer ion.Writer) error {
+	if !n.Valid {
+		return writer.WriteNullType(ion.TimestampType)
+	}
+	return ion.MarshalTo(writer, n.Time)
+}
@@ -0,0 +1,130 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/amzn/ion-go/ion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nullablePerson struct {
+	Name     NullString  `ion:"name"`
+	Age      NullInt64   `ion:"age"`
+	Score    NullFloat64 `ion:"score"`
+	Active   NullBool    `ion:"active"`
+	Joined   NullTime    `ion:"joined"`
+	Nickname string      `ion:"nickname"`
+	Address  *nullableAddress
+	Phones   []nullablePhone `ion:"phones"`
+	nullableAudit
+}
+
+type nullableAddress struct {
+	Street NullString `ion:"street"`
+}
+
+type nullablePhone struct {
+	Number NullString `ion:"number"`
+}
+
+type nullableAudit struct {
+	UpdatedBy NullString `ion:"updatedBy"`
+}
+
+func TestUnmarshalNullTypes(t *testing.T) {
+	t.Run("values, nulls and missing fields", func(t *testing.T) {
+		row := ionTextToBinary(t, `{name: "Alice", age: null.int, score: 0e0, joined: 2021-01-02T03:04:05Z, nickname: "Al",
+			address: {street: null}, phones: [{number: "555"}, {}], updatedBy: "admin"}`)
+
+		var person nullablePerson
+		require.NoError(t, Unmarshal(row, &person))
+
+		assert.Equal(t, NullString{String: "Alice", Valid: true, Present: true}, person.Name)
+		assert.Equal(t, NullInt64{Present: true}, person.Age)
+		assert.Equal(t, NullFloat64{Float64: 0, Valid: true, Present: true}, person.Score)
+		assert.Equal(t, NullBool{}, person.Active)
+		assert.True(t, person.Joined.Valid)
+		assert.True(t, time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC).Equal(person.Joined.Time))
+		assert.Equal(t, "Al", person.Nickname)
+		require.NotNil(t, person.Address)
+		assert.Equal(t, NullString{Present: true}, person.Address.Street)
+		require.Len(t, person.Phones, 2)
+		assert.Equal(t, NullString{String: "555", Valid: true, Present: true}, person.Phones[0].Number)
+		assert.Equal(t, NullString{}, person.Phones[1].Number)
+		assert.Equal(t, NullString{String: "admin", Valid: true, Present: true}, person.UpdatedBy)
+	})
+
+	t.Run("top-level null type", func(t *testing.T) {
+		var name NullString
+		require.NoError(t, Unmarshal(ionTextToBinary(t, `null.string`), &name))
+		assert.Equal(t, NullString{Present: true}, name)
+	})
+
+	t.Run("without null types", func(t *testing.T) {
+		var values map[string]interface{}
+		require.NoError(t, Unmarshal(ionTextToBinary(t, `{a: 1}`), &values))
+		assert.Equal(t, map[string]interface{}{"a": 1}, values)
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+		var person nullablePerson
+		assert.Error(t, Unmarshal(ionTextToBinary(t, `{age: "ten"}`), &person))
+	})
+
+	t.Run("not a pointer", func(t *testing.T) {
+		assert.Error(t, Unmarshal(ionTextToBinary(t, `{}`), nullablePerson{}))
+	})
+}
+
+func TestMarshalNullTypes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{"string", NullString{String: "a", Valid: true}, `"a"`},
+		{"null string", NullString{}, `null.string`},
+		{"int", NullInt64{Int64: 5, Valid: true}, `5`},
+		{"null int", NullInt64{}, `null.int`},
+		{"float", NullFloat64{Float64: 1.5, Valid: true}, `1.5e+0`},
+		{"null float", NullFloat64{}, `null.float`},
+		{"bool", NullBool{Bool: true, Valid: true}, `true`},
+		{"null bool", NullBool{}, `null.bool`},
+		{"null time", NullTime{}, `null.timestamp`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := ion.MarshalText(tc.value)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, string(actual))
+		})
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		joined := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+		person := nullablePerson{Name: NullString{String: "Bob", Valid: true}, Joined: NullTime{Time: joined, Valid: true}}
+		ionBinary, err := ion.MarshalBinary(person)
+		require.NoError(t, err)
+
+		var decoded nullablePerson
+		require.NoError(t, Unmarshal(ionBinary, &decoded))
+		assert.Equal(t, "Bob", decoded.Name.String)
+		assert.True(t, joined.Equal(decoded.Joined.Time))
+		assert.Equal(t, NullInt64{Present: true}, decoded.Age)
+	})
+}
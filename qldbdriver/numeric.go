@@ -0,0 +1,127 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/amzn/ion-go/ion"
+)
+
+// bigIntParameter marshals a big.Int as an Ion int, which ion-go does not do for big.Int values.
+type bigIntParameter struct {
+	value *big.Int
+}
+
+// MarshalIon writes the wrapped integer to the Ion writer.
+func (parameter *bigIntParameter) MarshalIon(writer ion.Writer) error {
+	return writer.WriteBigInt(parameter.value)
+}
+
+// toIonNumeric converts a top-level parameter of a math/big type to a value that marshals to the equivalent Ion
+// number. big.Int values become Ion ints and big.Float values become Ion decimals, or Ion floats when infinite. Other
+// parameters are returned unchanged. The converted value is used both to send the parameter and to compute the commit
+// digest, so that they always match.
+func toIonNumeric(parameter interface{}) interface{} {
+	switch value := parameter.(type) {
+	case big.Int:
+		return &bigIntParameter{&value}
+	case *big.Int:
+		if value != nil {
+			return &bigIntParameter{value}
+		}
+	case big.Float:
+		return bigFloatToIon(&value)
+	case *big.Float:
+		if value != nil {
+			return bigFloatToIon(value)
+		}
+	}
+	return parameter
+}
+
+func bigFloatToIon(value *big.Float) interface{} {
+	if value.IsInf() {
+		return math.Inf(value.Sign())
+	}
+	decimal, _ := DecimalFromBigFloat(value)
+	return decimal
+}
+
+// DecimalFromBigFloat returns the Ion decimal with the fewest digits that converts back to the same big.Float at the
+// precision of value. It returns an error if value is infinite, since Ion decimals cannot represent infinity.
+func DecimalFromBigFloat(value *big.Float) (*ion.Decimal, error) {
+	if value.IsInf() {
+		return nil, &qldbDriverError{"Cannot convert infinite big.Float to an Ion decimal."}
+	}
+	// Text uses the form d.dddde±dd, which ion-go parses with a 'd' exponent marker
+	return ion.ParseDecimal(strings.Replace(value.Text('e', -1), "e", "d", 1))
+}
+
+// BigFloatFromDecimal returns a big.Float holding value with enough precision for DecimalFromBigFloat to return a
+// decimal equal to value. Trailing zeros of the coefficient are not preserved, so use ion.Decimal directly when the
+// scale of amounts matters.
+func BigFloatFromDecimal(value *ion.Decimal) *big.Float {
+	coefficient, exponent := value.CoEx()
+	digits := len(coefficient.String())
+	// Each decimal digit needs log2(10) < 3.33 bits, with margin for the shortest representation to be exact
+	precision := uint(digits)*4 + 64
+	result, _, _ := new(big.Float).SetPrec(precision).Parse(coefficient.String()+"e"+strconv.Itoa(int(exponent)), 10)
+	if value.Sign() == 0 && strings.HasPrefix(value.String(), "-") {
+		result.Neg(result)
+	}
+	return result
+}
+
+// DecodeBigFloat decodes an Ion decimal, int or float value, such as a row of a SELECT VALUE query, into a big.Float
+// without going through float64. Decimals are decoded with BigFloatFromDecimal. It returns nil for an Ion null.
+func DecodeBigFloat(ionBinary []byte) (*big.Float, error) {
+	reader := ion.NewReaderBytes(ionBinary)
+	if !reader.Next() {
+		if reader.Err() != nil {
+			return nil, reader.Err()
+		}
+		return nil, &qldbDriverError{"No Ion value to decode."}
+	}
+	if reader.IsNull() {
+		return nil, nil
+	}
+	switch reader.Type() {
+	case ion.DecimalType:
+		decimal, err := reader.DecimalValue()
+		if err != nil {
+			return nil, err
+		}
+		return BigFloatFromDecimal(decimal), nil
+	case ion.IntType:
+		integer, err := reader.BigIntValue()
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Float).SetPrec(uint(integer.BitLen()) + 64).SetInt(integer), nil
+	case ion.FloatType:
+		float, err := reader.FloatValue()
+		if err != nil {
+			return nil, err
+		}
+		if math.IsNaN(*float) {
+			return nil, &qldbDriverError{"Cannot decode Ion nan into a big.Float."}
+		}
+		return big.NewFloat(*float), nil
+	}
+	return nil, &qldbDriverError{"Cannot decode Ion " + reader.Type().String() + " into a big.Float."}
+}
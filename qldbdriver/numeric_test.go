@@ -0,0 +1,124 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/amzn/ion-go/ion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToIonNumeric(t *testing.T) {
+	t.Run("big.Int", func(t *testing.T) {
+		value, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+		for _, parameter := range []interface{}{value, *value} {
+			text, err := ion.MarshalText(toIonNumeric(parameter))
+			require.NoError(t, err)
+			assert.Equal(t, "123456789012345678901234567890", string(text))
+		}
+	})
+
+	t.Run("big.Float", func(t *testing.T) {
+		value, _, err := big.ParseFloat("1234.5678", 10, 128, big.ToNearestEven)
+		require.NoError(t, err)
+
+		for _, parameter := range []interface{}{value, *value} {
+			text, err := ion.MarshalText(toIonNumeric(parameter))
+			require.NoError(t, err)
+			assert.Equal(t, "1234.5678", string(text))
+		}
+	})
+
+	t.Run("infinite big.Float", func(t *testing.T) {
+		text, err := ion.MarshalText(toIonNumeric(new(big.Float).SetInf(true)))
+		require.NoError(t, err)
+		assert.Equal(t, "-inf", string(text))
+	})
+
+	t.Run("other values are unchanged", func(t *testing.T) {
+		var nilInt *big.Int
+		decimal := ion.MustParseDecimal("1.50")
+		assert.Equal(t, 1, toIonNumeric(1))
+		assert.Equal(t, decimal, toIonNumeric(decimal))
+		assert.Nil(t, toIonNumeric(nilInt))
+	})
+
+	t.Run("hash matches equivalent int", func(t *testing.T) {
+		txn := &transaction{}
+		bigHash, err := toStatementHash("statement", txn.wrapParameters([]interface{}{big.NewInt(42)}))
+		require.NoError(t, err)
+		intHash, err := toStatementHash("statement", []interface{}{42})
+		require.NoError(t, err)
+		assert.Equal(t, intHash, bigHash)
+	})
+
+	t.Run("with marshal options", func(t *testing.T) {
+		text, err := ion.MarshalText(WithIonMarshalOptions(big.NewInt(42), IonMarshalOptions{Annotations: []string{"amount"}}))
+		require.NoError(t, err)
+		assert.Equal(t, "amount::42", string(text))
+	})
+}
+
+func TestDecimalRoundTrip(t *testing.T) {
+	for _, text := range []string{"0", "1.5", "-0.1", "12345678901234567890.123456789", "1d-40", "-0"} {
+		decimal := ion.MustParseDecimal(text)
+		roundTripped, err := DecimalFromBigFloat(BigFloatFromDecimal(decimal))
+		require.NoError(t, err)
+		assert.True(t, decimal.Equal(roundTripped), "%s round-tripped to %s", text, roundTripped)
+	}
+
+	_, err := DecimalFromBigFloat(new(big.Float).SetInf(false))
+	assert.Error(t, err)
+}
+
+func TestDecodeBigFloat(t *testing.T) {
+	decode := func(value interface{}) (*big.Float, error) {
+		ionBinary, err := ion.MarshalBinary(value)
+		require.NoError(t, err)
+		return DecodeBigFloat(ionBinary)
+	}
+
+	t.Run("decimal", func(t *testing.T) {
+		result, err := decode(ion.MustParseDecimal("98765432109876543210.0123456789"))
+		require.NoError(t, err)
+		assert.Equal(t, "98765432109876543210.0123456789", result.Text('f', 10))
+	})
+
+	t.Run("int", func(t *testing.T) {
+		result, err := decode(int64(math.MaxInt64))
+		require.NoError(t, err)
+		assert.Equal(t, "9223372036854775807", result.Text('f', 0))
+	})
+
+	t.Run("float", func(t *testing.T) {
+		result, err := decode(0.5)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewFloat(0.5), result)
+	})
+
+	t.Run("null", func(t *testing.T) {
+		result, err := decode(nil)
+		require.NoError(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		_, err := decode("1.5")
+		assert.Error(t, err)
+	})
+}
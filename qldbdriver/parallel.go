@@ -0,0 +1,101 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// Statement is a PartiQL statement with its parameters, as run by QLDBDriver.QueryParallel.
+type Statement struct {
+	// The PartiQL statement.
+	Query string
+	// The parameters of the statement.
+	Parameters []interface{}
+}
+
+// QueryParallel runs independent read-only statements concurrently, each in its own transaction on its own session,
+// and returns their results in the order of the statements. At most maxParallel statements run at the same time, or
+// MaxConcurrentTransactions if maxParallel is not positive. Each statement is retried like with Execute.
+//
+// Only SELECT statements are accepted, since the statements are not executed atomically. The first statement to fail
+// cancels the others and is returned in a QueryError.
+//
+// Unless the driver uses PoolExhaustionBlock, maxParallel should leave enough sessions to the other transactions of
+// the driver, which otherwise fail with a MaxConcurrentTransactions error.
+func (driver *QLDBDriver) QueryParallel(ctx context.Context, queries []Statement, maxParallel int) ([]BufferedResult, error) {
+	for i, query := range queries {
+		if !isSelect(query.Query) {
+			return nil, &QueryError{Index: i, err: &qldbDriverError{"QueryParallel only accepts SELECT statements."}}
+		}
+	}
+	if maxParallel <= 0 {
+		maxParallel = driver.poolOf(nil).maxConcurrentTransactions
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]BufferedResult, len(queries))
+	var firstErr error
+	var errOnce sync.Once
+	var wg sync.WaitGroup
+	permits := make(chan struct{}, maxParallel)
+	for i := range queries {
+		select {
+		case permits <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int, query Statement) {
+			defer wg.Done()
+			defer func() { <-permits }()
+			result, err := driver.Execute(ctx, func(txn Transaction) (interface{}, error) {
+				result, err := txn.Execute(query.Query, query.Parameters...)
+				if err != nil {
+					return nil, err
+				}
+				return txn.BufferResult(result)
+			})
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = &QueryError{Index: i, err: err}
+					cancel()
+				})
+				return
+			}
+			results[i] = result.(BufferedResult)
+		}(i, queries[i])
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// isSelect returns whether statement is a SELECT statement.
+func isSelect(statement string) bool {
+	fields := strings.Fields(statement)
+	return len(fields) > 0 && strings.EqualFold(fields[0], "SELECT")
+}
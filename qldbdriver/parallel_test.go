@@ -0,0 +1,113 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amzn/ion-go/ion"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryParallel(t *testing.T) {
+	newDriver := func(sendCommand func(params *qldbsession.SendCommandInput) (*qldbsession.SendCommandOutput, error)) *QLDBDriver {
+		return newMockDriver(t, &qldbsessioniface.MockClientAPI{
+			SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+				return sendCommand(params)
+			},
+		}, func(options *DriverOptions) {
+			options.RetryPolicy = RetryPolicy{MaxRetryLimit: 4}
+		})
+	}
+	// echoParameter returns the first parameter of every statement as its only row.
+	echoParameter := func(params *qldbsession.SendCommandInput) (*qldbsession.SendCommandOutput, error) {
+		output := qldbsessioniface.DefaultSendCommandOutput(params)
+		if params.ExecuteStatement != nil {
+			output.ExecuteStatement.FirstPage.Values = []types.ValueHolder{{IonBinary: params.ExecuteStatement.Parameters[0].IonBinary}}
+		}
+		return output, nil
+	}
+	queries := []Statement{
+		{Query: "SELECT * FROM Person WHERE id = ?", Parameters: []interface{}{1}},
+		{Query: "select * FROM Vehicle WHERE id = ?", Parameters: []interface{}{2}},
+		{Query: "SELECT * FROM Registration WHERE id = ?", Parameters: []interface{}{3}},
+	}
+
+	t.Run("results in order", func(t *testing.T) {
+		results, err := newDriver(echoParameter).QueryParallel(context.Background(), queries, 0)
+		require.NoError(t, err)
+
+		require.Len(t, results, 3)
+		for i, result := range results {
+			require.True(t, result.Next())
+			var value int
+			require.NoError(t, ion.Unmarshal(result.GetCurrentData(), &value))
+			assert.Equal(t, i+1, value)
+		}
+	})
+
+	t.Run("max parallel", func(t *testing.T) {
+		var running, maxRunning int32
+		testDriver := newDriver(func(params *qldbsession.SendCommandInput) (*qldbsession.SendCommandOutput, error) {
+			if params.ExecuteStatement != nil {
+				current := atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+				for {
+					observed := atomic.LoadInt32(&maxRunning)
+					if current <= observed || atomic.CompareAndSwapInt32(&maxRunning, observed, current) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+			return echoParameter(params)
+		})
+
+		_, err := testDriver.QueryParallel(context.Background(), append(queries, queries...), 2)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, atomic.LoadInt32(&maxRunning), int32(2))
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		testDriver := newDriver(func(params *qldbsession.SendCommandInput) (*qldbsession.SendCommandOutput, error) {
+			if params.ExecuteStatement != nil && *params.ExecuteStatement.Statement == queries[1].Query {
+				return nil, errMock
+			}
+			return echoParameter(params)
+		})
+
+		_, err := testDriver.QueryParallel(context.Background(), queries, 0)
+		var queryErr *QueryError
+		require.ErrorAs(t, err, &queryErr)
+		assert.Equal(t, 1, queryErr.Index)
+		assert.ErrorIs(t, err, errMock)
+	})
+
+	t.Run("rejects writes", func(t *testing.T) {
+		testDriver := newDriver(echoParameter)
+
+		_, err := testDriver.QueryParallel(context.Background(), []Statement{queries[0], {Query: "DELETE FROM Person"}}, 0)
+		var queryErr *QueryError
+		require.ErrorAs(t, err, &queryErr)
+		assert.Equal(t, 1, queryErr.Index)
+		assert.Equal(t, 0, testDriver.semaphore.inUse())
+	})
+}
@@ -0,0 +1,90 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+// poolPartition is a named partition of the sessions of a driver, as configured by DriverOptions.PoolPartitions.
+type poolPartition struct {
+	name                      string
+	maxConcurrentTransactions int
+	semaphore                 *semaphore
+	sessionPool               SessionPool
+}
+
+// newPoolPartitions creates the partitions of DriverOptions.PoolPartitions, whose sessions are reused according to
+// DriverOptions.SessionReusePolicy.
+func newPoolPartitions(options *DriverOptions) (map[string]*poolPartition, error) {
+	if len(options.PoolPartitions) == 0 {
+		return nil, nil
+	}
+	partitions := make(map[string]*poolPartition, len(options.PoolPartitions))
+	for name, size := range options.PoolPartitions {
+		if name == "" {
+			return nil, &qldbDriverError{"The name of a pool partition cannot be empty."}
+		}
+		if size < 1 {
+			return nil, &qldbDriverError{"The size of pool partition '" + name + "' must be 1 or greater."}
+		}
+		var sessionPool SessionPool
+		if options.SessionReusePolicy == SessionReuseLIFO {
+			sessionPool = newStackSessionPool(size)
+		} else {
+			sessionPool = newChannelSessionPool(size)
+		}
+		partitions[name] = &poolPartition{
+			name:                      name,
+			maxConcurrentTransactions: size,
+			semaphore:                 makeSemaphore(size),
+			sessionPool:               sessionPool,
+		}
+	}
+	return partitions, nil
+}
+
+// partitionSessions returns the number of sessions of all the partitions of DriverOptions.PoolPartitions.
+func partitionSessions(options *DriverOptions) int {
+	sessions := 0
+	for _, size := range options.PoolPartitions {
+		if size > 0 {
+			sessions += size
+		}
+	}
+	return sessions
+}
+
+// partition returns the partition with the provided name, or nil for the default partition if name is "".
+func (driver *QLDBDriver) partition(name string) (*poolPartition, error) {
+	if name == "" {
+		return nil, nil
+	}
+	partition, ok := driver.partitions[name]
+	if !ok {
+		return nil, &qldbDriverError{"Unknown pool partition: '" + name + "'."}
+	}
+	return partition, nil
+}
+
+// poolOf returns partition, or the default partition made of the MaxConcurrentTransactions sessions of the driver if
+// partition is nil.
+func (driver *QLDBDriver) poolOf(partition *poolPartition) *poolPartition {
+	if partition != nil {
+		return partition
+	}
+	driver.optionsLock.RLock()
+	defer driver.optionsLock.RUnlock()
+	return &poolPartition{
+		maxConcurrentTransactions: driver.maxConcurrentTransactions,
+		semaphore:                 driver.semaphore,
+		sessionPool:               driver.sessionPool,
+	}
+}
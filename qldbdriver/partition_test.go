@@ -0,0 +1,114 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPoolPartitions(t *testing.T) {
+	t.Run("none", func(t *testing.T) {
+		partitions, err := newPoolPartitions(&DriverOptions{})
+		require.NoError(t, err)
+		assert.Nil(t, partitions)
+	})
+
+	t.Run("sizes", func(t *testing.T) {
+		partitions, err := newPoolPartitions(&DriverOptions{PoolPartitions: map[string]int{"batch": 2, "interactive": 4}})
+		require.NoError(t, err)
+		require.Len(t, partitions, 2)
+		assert.Equal(t, 2, partitions["batch"].maxConcurrentTransactions)
+		assert.Equal(t, 4, partitions["interactive"].maxConcurrentTransactions)
+		assert.IsType(t, &channelSessionPool{}, partitions["batch"].sessionPool)
+	})
+
+	t.Run("reuse policy", func(t *testing.T) {
+		partitions, err := newPoolPartitions(&DriverOptions{PoolPartitions: map[string]int{"batch": 2}, SessionReusePolicy: SessionReuseLIFO})
+		require.NoError(t, err)
+		assert.IsType(t, &stackSessionPool{}, partitions["batch"].sessionPool)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := newPoolPartitions(&DriverOptions{PoolPartitions: map[string]int{"batch": 0}})
+		assert.Error(t, err)
+		_, err = newPoolPartitions(&DriverOptions{PoolPartitions: map[string]int{"": 1}})
+		assert.Error(t, err)
+	})
+}
+
+func TestExecuteWithPartition(t *testing.T) {
+	testDriver := newMockDriver(t, &qldbsessioniface.MockClientAPI{
+		SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+			return qldbsessioniface.DefaultSendCommandOutput(params), nil
+		},
+	}, func(options *DriverOptions) {
+		options.MaxConcurrentTransactions = 1
+		options.PoolPartitions = map[string]int{"batch": 1}
+	})
+	partitions := testDriver.partitions
+	batch := func(options *ExecuteOptions) {
+		options.Partition = "batch"
+	}
+
+	t.Run("partitions are isolated", func(t *testing.T) {
+		_, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+			assert.Equal(t, 1, partitions["batch"].semaphore.inUse())
+			assert.Equal(t, 0, testDriver.semaphore.inUse())
+
+			_, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+				return nil, nil
+			}, batch)
+			assert.EqualError(t, err, "MaxConcurrentTransactions limit exceeded.")
+
+			return testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+				return nil, nil
+			})
+		}, batch)
+		require.NoError(t, err)
+	})
+
+	t.Run("sessions return to their partition", func(t *testing.T) {
+		assert.Equal(t, 0, partitions["batch"].semaphore.inUse())
+		assert.Equal(t, 0, testDriver.semaphore.inUse())
+		pooled := partitions["batch"].sessionPool.Get()
+		require.NotNil(t, pooled)
+		assert.Same(t, partitions["batch"], pooled.session.partition)
+		partitions["batch"].sessionPool.Put(pooled)
+
+		pooled = testDriver.sessionPool.Get()
+		require.NotNil(t, pooled)
+		assert.Nil(t, pooled.session.partition)
+		testDriver.sessionPool.Put(pooled)
+	})
+
+	t.Run("unknown partition", func(t *testing.T) {
+		_, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+			return nil, nil
+		}, func(options *ExecuteOptions) {
+			options.Partition = "reporting"
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("shutdown ends the sessions of the partitions", func(t *testing.T) {
+		testDriver.Shutdown(context.Background())
+		assert.Nil(t, partitions["batch"].sessionPool.Get())
+	})
+}
@@ -0,0 +1,277 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"sync"
+	"time"
+)
+
+// SessionPool holds the idle sessions of a QLDBDriver so that they can be reused by later transactions.
+// Implementations must be safe for concurrent use.
+type SessionPool interface {
+	// Get removes an idle session from the pool and returns it, or returns nil if the pool has no idle session.
+	Get() *PooledSession
+	// Put adds a session that is no longer in use to the pool. The driver never has more sessions in use than
+	// DriverOptions.MaxConcurrentTransactions, so the pool must be able to hold that many sessions.
+	Put(session *PooledSession)
+	// Close empties the pool and returns the sessions it held, so that the driver can end them.
+	// Get and Put are not called after Close.
+	Close() []*PooledSession
+}
+
+// SessionReusePolicy represents the order in which the default session pool reuses idle sessions.
+type SessionReusePolicy uint8
+
+const (
+	// SessionReuseFIFO is for reusing the session that has been idle the longest. This is the default policy.
+	SessionReuseFIFO SessionReusePolicy = iota
+	// SessionReuseLIFO is for reusing the session that has been idle the shortest, so that recently used sessions are
+	// preferred and the others age out. Less recently used sessions are more likely to have expired.
+	SessionReuseLIFO
+)
+
+// PoolExhaustionPolicy represents what the driver does when a transaction starts while
+// DriverOptions.MaxConcurrentTransactions transactions are already in progress.
+type PoolExhaustionPolicy uint8
+
+const (
+	// PoolExhaustionFail is for failing immediately with a "MaxConcurrentTransactions limit exceeded" error. This is
+	// the default policy, suited to request handlers that should shed load rather than queue.
+	PoolExhaustionFail PoolExhaustionPolicy = iota
+	// PoolExhaustionBlock is for waiting until a transaction completes, for up to DriverOptions.PoolExhaustionTimeout,
+	// or until the context is done.
+	PoolExhaustionBlock
+	// PoolExhaustionGrow is for starting additional sessions, up to DriverOptions.MaxBurstTransactions transactions in
+	// progress. The additional sessions are ended rather than pooled once their transactions complete, so the pool
+	// shrinks back to MaxConcurrentTransactions sessions. Transactions beyond MaxBurstTransactions fail as with
+	// PoolExhaustionFail.
+	PoolExhaustionGrow
+)
+
+// PooledSession is a QLDB session held by a SessionPool.
+type PooledSession struct {
+	session   *session
+	idleSince time.Time
+}
+
+// resizableSessionPool is implemented by the default session pools, so that their capacity can follow the
+// MaxConcurrentTransactions changed by QLDBDriver.UpdateOptions.
+type resizableSessionPool interface {
+	SessionPool
+	// offer adds a session to the pool, unless the pool is full or closed.
+	offer(session *PooledSession) bool
+	// resize changes the capacity of the pool and returns the idle sessions that no longer fit, so that they can be
+	// ended.
+	resize(capacity int) []*PooledSession
+	// idle returns the number of sessions in the pool.
+	idle() int
+}
+
+// offerSession adds a session to pool, unless pool is a default pool that is full or closed.
+func offerSession(pool SessionPool, session *PooledSession) bool {
+	if resizable, ok := pool.(resizableSessionPool); ok {
+		return resizable.offer(session)
+	}
+	pool.Put(session)
+	return true
+}
+
+// channelSessionPool is the default SessionPool, which hands out idle sessions in the order they were added.
+type channelSessionPool struct {
+	// lock is held for writing while the channel is replaced by resize or closed by Close.
+	lock     sync.RWMutex
+	sessions chan *PooledSession
+	closed   bool
+}
+
+func newChannelSessionPool(capacity int) *channelSessionPool {
+	return &channelSessionPool{sessions: make(chan *PooledSession, capacity)}
+}
+
+func (pool *channelSessionPool) Get() *PooledSession {
+	pool.lock.RLock()
+	defer pool.lock.RUnlock()
+	select {
+	case session, ok := <-pool.sessions:
+		if ok {
+			return session
+		}
+		return nil
+	default:
+		return nil
+	}
+}
+
+func (pool *channelSessionPool) Put(session *PooledSession) {
+	pool.lock.RLock()
+	defer pool.lock.RUnlock()
+	pool.sessions <- session
+}
+
+func (pool *channelSessionPool) offer(session *PooledSession) bool {
+	pool.lock.RLock()
+	defer pool.lock.RUnlock()
+	if pool.closed {
+		return false
+	}
+	select {
+	case pool.sessions <- session:
+		return true
+	default:
+		return false
+	}
+}
+
+func (pool *channelSessionPool) resize(capacity int) []*PooledSession {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+	sessions := make(chan *PooledSession, capacity)
+	var surplus []*PooledSession
+	for len(pool.sessions) > 0 {
+		session := <-pool.sessions
+		if len(sessions) < capacity {
+			sessions <- session
+		} else {
+			surplus = append(surplus, session)
+		}
+	}
+	pool.sessions = sessions
+	return surplus
+}
+
+func (pool *channelSessionPool) idle() int {
+	pool.lock.RLock()
+	defer pool.lock.RUnlock()
+	return len(pool.sessions)
+}
+
+func (pool *channelSessionPool) Close() []*PooledSession {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+	sessions := make([]*PooledSession, 0, len(pool.sessions))
+	for len(pool.sessions) > 0 {
+		sessions = append(sessions, <-pool.sessions)
+	}
+	close(pool.sessions)
+	pool.closed = true
+	return sessions
+}
+
+// stackSessionPool is a SessionPool which hands out the most recently added idle session first.
+type stackSessionPool struct {
+	lock     sync.Mutex
+	capacity int
+	sessions []*PooledSession
+	closed   bool
+}
+
+func newStackSessionPool(capacity int) *stackSessionPool {
+	return &stackSessionPool{capacity: capacity, sessions: make([]*PooledSession, 0, capacity)}
+}
+
+func (pool *stackSessionPool) Get() *PooledSession {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+	if len(pool.sessions) == 0 {
+		return nil
+	}
+	last := len(pool.sessions) - 1
+	session := pool.sessions[last]
+	pool.sessions[last] = nil
+	pool.sessions = pool.sessions[:last]
+	return session
+}
+
+func (pool *stackSessionPool) Put(session *PooledSession) {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+	pool.sessions = append(pool.sessions, session)
+}
+
+func (pool *stackSessionPool) offer(session *PooledSession) bool {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+	if pool.closed || len(pool.sessions) >= pool.capacity {
+		return false
+	}
+	pool.sessions = append(pool.sessions, session)
+	return true
+}
+
+func (pool *stackSessionPool) resize(capacity int) []*PooledSession {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+	pool.capacity = capacity
+	if len(pool.sessions) <= capacity {
+		return nil
+	}
+	// The sessions idle the longest are at the bottom of the stack
+	excess := len(pool.sessions) - capacity
+	surplus := pool.sessions[:excess]
+	pool.sessions = append(make([]*PooledSession, 0, capacity), pool.sessions[excess:]...)
+	return surplus
+}
+
+func (pool *stackSessionPool) idle() int {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+	return len(pool.sessions)
+}
+
+func (pool *stackSessionPool) Close() []*PooledSession {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+	sessions := pool.sessions
+	pool.sessions = nil
+	pool.closed = true
+	return sessions
+}
+
+// sessionRefresher counts the sessions replaced because they became invalid, to detect when the idle sessions of the
+// pool are likely to have become invalid too.
+type sessionRefresher struct {
+	threshold    int
+	window       time.Duration
+	batchSize    int
+	lock         sync.Mutex
+	replacements []time.Time
+	isRefreshing bool
+}
+
+// recordReplacement records a session replacement and returns true if a refresh of the idle sessions should start.
+func (refresher *sessionRefresher) recordReplacement(now time.Time) bool {
+	refresher.lock.Lock()
+	defer refresher.lock.Unlock()
+	windowStart := now.Add(-refresher.window)
+	recent := refresher.replacements[:0]
+	for _, replacement := range refresher.replacements {
+		if replacement.After(windowStart) {
+			recent = append(recent, replacement)
+		}
+	}
+	refresher.replacements = append(recent, now)
+	if refresher.isRefreshing || len(refresher.replacements) < refresher.threshold {
+		return false
+	}
+	refresher.isRefreshing = true
+	refresher.replacements = refresher.replacements[:0]
+	return true
+}
+
+func (refresher *sessionRefresher) done() {
+	refresher.lock.Lock()
+	defer refresher.lock.Unlock()
+	refresher.isRefreshing = false
+}
@@ -0,0 +1,139 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannelSessionPool(t *testing.T) {
+	session1 := &PooledSession{session: &session{logger: mockLogger}}
+	session2 := &PooledSession{session: &session{logger: mockLogger}}
+
+	t.Run("get from empty pool", func(t *testing.T) {
+		pool := newChannelSessionPool(2)
+		assert.Nil(t, pool.Get())
+	})
+
+	t.Run("sessions are reused in the order they were put", func(t *testing.T) {
+		pool := newChannelSessionPool(2)
+		pool.Put(session1)
+		pool.Put(session2)
+		assert.Equal(t, session1, pool.Get())
+		assert.Equal(t, session2, pool.Get())
+		assert.Nil(t, pool.Get())
+	})
+
+	t.Run("close returns idle sessions", func(t *testing.T) {
+		pool := newChannelSessionPool(2)
+		pool.Put(session1)
+		pool.Put(session2)
+		assert.Equal(t, []*PooledSession{session1, session2}, pool.Close())
+		assert.Nil(t, pool.Get())
+	})
+
+	t.Run("offer fails when full", func(t *testing.T) {
+		pool := newChannelSessionPool(1)
+		assert.True(t, pool.offer(session1))
+		assert.False(t, pool.offer(session2))
+		assert.Equal(t, session1, pool.Get())
+	})
+
+	t.Run("resize", func(t *testing.T) {
+		pool := newChannelSessionPool(2)
+		pool.Put(session1)
+		pool.Put(session2)
+		assert.Equal(t, []*PooledSession{session2}, pool.resize(1))
+		assert.False(t, pool.offer(session2))
+		assert.Empty(t, pool.resize(3))
+		assert.True(t, pool.offer(session2))
+		assert.Equal(t, session1, pool.Get())
+		assert.Equal(t, session2, pool.Get())
+	})
+}
+
+func TestStackSessionPool(t *testing.T) {
+	session1 := &PooledSession{session: &session{logger: mockLogger}}
+	session2 := &PooledSession{session: &session{logger: mockLogger}}
+
+	t.Run("get from empty pool", func(t *testing.T) {
+		pool := newStackSessionPool(2)
+		assert.Nil(t, pool.Get())
+	})
+
+	t.Run("most recently put session is reused first", func(t *testing.T) {
+		pool := newStackSessionPool(2)
+		pool.Put(session1)
+		pool.Put(session2)
+		assert.Equal(t, session2, pool.Get())
+		assert.Equal(t, session1, pool.Get())
+		assert.Nil(t, pool.Get())
+	})
+
+	t.Run("close returns idle sessions", func(t *testing.T) {
+		pool := newStackSessionPool(2)
+		pool.Put(session1)
+		pool.Put(session2)
+		assert.Equal(t, []*PooledSession{session1, session2}, pool.Close())
+		assert.Nil(t, pool.Get())
+	})
+
+	t.Run("offer fails when full", func(t *testing.T) {
+		pool := newStackSessionPool(1)
+		assert.True(t, pool.offer(session1))
+		assert.False(t, pool.offer(session2))
+		assert.Equal(t, session1, pool.Get())
+	})
+
+	t.Run("resize ends the sessions idle the longest", func(t *testing.T) {
+		pool := newStackSessionPool(2)
+		pool.Put(session1)
+		pool.Put(session2)
+		assert.Equal(t, []*PooledSession{session1}, pool.resize(1))
+		assert.False(t, pool.offer(session1))
+		assert.Empty(t, pool.resize(3))
+		assert.True(t, pool.offer(session1))
+		assert.Equal(t, session1, pool.Get())
+		assert.Equal(t, session2, pool.Get())
+	})
+}
+
+func TestSessionRefresher(t *testing.T) {
+	now := time.Now()
+
+	t.Run("refresh starts when threshold is reached within window", func(t *testing.T) {
+		refresher := &sessionRefresher{threshold: 3, window: time.Second, batchSize: 1}
+		assert.False(t, refresher.recordReplacement(now))
+		assert.False(t, refresher.recordReplacement(now.Add(100*time.Millisecond)))
+		assert.True(t, refresher.recordReplacement(now.Add(200*time.Millisecond)))
+	})
+
+	t.Run("replacements outside window are not counted", func(t *testing.T) {
+		refresher := &sessionRefresher{threshold: 2, window: time.Second, batchSize: 1}
+		assert.False(t, refresher.recordReplacement(now))
+		assert.False(t, refresher.recordReplacement(now.Add(2*time.Second)))
+		assert.True(t, refresher.recordReplacement(now.Add(2500*time.Millisecond)))
+	})
+
+	t.Run("only one refresh at a time", func(t *testing.T) {
+		refresher := &sessionRefresher{threshold: 1, window: time.Second, batchSize: 1}
+		assert.True(t, refresher.recordReplacement(now))
+		assert.False(t, refresher.recordReplacement(now))
+		refresher.done()
+		assert.True(t, refresher.recordReplacement(now))
+	})
+}
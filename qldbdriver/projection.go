@@ -0,0 +1,203 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/amzn/ion-go/ion"
+)
+
+// Projection extracts the values of a set of field paths, such as "metadata.id" or "data.owner.name", from rows of
+// data in Ion format. Only the fields on the paths are decoded, and the other fields of a row are skipped without
+// being unmarshalled, which is faster than unmarshalling a large document whole to read a few of its fields. A
+// Projection can be used concurrently.
+type Projection struct {
+	paths []string
+	root  *projectionNode
+}
+
+// projectionNode is a field on the path of one or more projected fields.
+type projectionNode struct {
+	children map[string]*projectionNode
+	// The projected path ending at the field, or "" if the field is only on the path of other ones.
+	path string
+}
+
+// NewProjection returns a Projection of the field paths, each made of the names of nested struct fields separated by
+// dots.
+func NewProjection(paths ...string) (*Projection, error) {
+	if len(paths) == 0 {
+		return nil, &qldbDriverError{"At least one field path must be provided."}
+	}
+	root := &projectionNode{}
+	for _, path := range paths {
+		node := root
+		for _, name := range strings.Split(path, ".") {
+			if name == "" {
+				return nil, &qldbDriverError{"Invalid field path: '" + path + "'."}
+			}
+			child, ok := node.children[name]
+			if !ok {
+				if node.children == nil {
+					node.children = make(map[string]*projectionNode)
+				}
+				child = &projectionNode{}
+				node.children[name] = child
+			}
+			node = child
+		}
+		node.path = path
+	}
+	return &Projection{paths: paths, root: root}, nil
+}
+
+// Paths returns the field paths of the projection.
+func (projection *Projection) Paths() []string {
+	return append([]string(nil), projection.paths...)
+}
+
+// Decode returns the values of the field paths found in the row, unmarshalled as ion.Unmarshal does into an
+// interface{}. The paths that are not found in the row, for example because a struct on the path is missing or is
+// not a struct, are absent from the map.
+func (projection *Projection) Decode(ionBinary []byte) (map[string]interface{}, error) {
+	values, err := projection.extract(ionBinary)
+	if err != nil {
+		return nil, err
+	}
+	decoded := make(map[string]interface{}, len(values))
+	for path, value := range values {
+		var v interface{}
+		if err := ion.Unmarshal(value, &v); err != nil {
+			return nil, err
+		}
+		decoded[path] = v
+	}
+	return decoded, nil
+}
+
+// DecodeInto unmarshals the values of the field paths found in the row into the fields of the struct pointed to by v
+// tagged with their path, such as `qldb:"data.owner.name"`. The fields whose path is not found in the row are left
+// unchanged. It returns an error if the path of a tagged field is not a path of the projection.
+func (projection *Projection) DecodeInto(ionBinary []byte, v interface{}) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return &qldbDriverError{"DecodeInto requires a non-nil pointer to a struct."}
+	}
+	value = value.Elem()
+	values, err := projection.extract(ionBinary)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		path, ok := field.Tag.Lookup("qldb")
+		if !ok || field.PkgPath != "" {
+			continue
+		}
+		if !projection.hasPath(path) {
+			return &qldbDriverError{"Field '" + field.Name + "' is tagged with path '" + path + "', which is not a path of the projection."}
+		}
+		fieldValue, found := values[path]
+		if !found {
+			continue
+		}
+		if err := ion.Unmarshal(fieldValue, value.Field(i).Addr().Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (projection *Projection) hasPath(path string) bool {
+	node := projection.root
+	for _, name := range strings.Split(path, ".") {
+		node = node.children[name]
+		if node == nil {
+			return false
+		}
+	}
+	return node.path == path
+}
+
+// extract returns the Ion binary of the values of the field paths found in the row.
+func (projection *Projection) extract(ionBinary []byte) (map[string][]byte, error) {
+	values := make(map[string][]byte)
+	reader := ion.NewReaderBytes(ionBinary)
+	if !reader.Next() {
+		if reader.Err() != nil {
+			return nil, reader.Err()
+		}
+		return nil, &qldbDriverError{"No Ion value to decode."}
+	}
+	err := projection.root.extract(reader, values)
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// extract adds the values of the projected fields below the node to values, reading the struct the reader is
+// positioned on. A field repeated in the struct is taken from its first occurrence.
+func (node *projectionNode) extract(reader ion.Reader, values map[string][]byte) error {
+	if reader.Type() != ion.StructType || reader.IsNull() {
+		return nil
+	}
+	err := reader.StepIn()
+	if err != nil {
+		return err
+	}
+	for reader.Next() {
+		fieldName, err := reader.FieldName()
+		if err != nil {
+			return err
+		}
+		if fieldName == nil || fieldName.Text == nil {
+			continue
+		}
+		child := node.children[*fieldName.Text]
+		if child == nil {
+			continue
+		}
+		if child.path == "" {
+			err = child.extract(reader, values)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if _, found := values[child.path]; found {
+			continue
+		}
+		value, err := ionValueToBinary(reader)
+		if err != nil {
+			return err
+		}
+		values[child.path] = value
+		if len(child.children) > 0 {
+			// The value was consumed by the copy, so the nested paths are read from the copy
+			nested := ion.NewReaderBytes(value)
+			nested.Next()
+			err = child.extract(nested, values)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if reader.Err() != nil {
+		return reader.Err()
+	}
+	return reader.StepOut()
+}
@@ -0,0 +1,93 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjection(t *testing.T) {
+	row := ionTextToBinary(t, `{
+		blockAddress: {strandId: "S", sequenceNo: 3},
+		data: {VIN: "1", owner: {name: "Ana", age: 30}, tags: ["a", "b"]},
+		metadata: {id: "doc1", version: 2}
+	}`)
+
+	t.Run("decode", func(t *testing.T) {
+		projection, err := NewProjection("metadata.id", "data.owner.name", "data.tags", "data.missing", "blockAddress.sequenceNo.x")
+		require.NoError(t, err)
+
+		values, err := projection.Decode(row)
+		require.NoError(t, err)
+		assert.Equal(t, "doc1", values["metadata.id"])
+		assert.Equal(t, "Ana", values["data.owner.name"])
+		assert.Len(t, values["data.tags"], 2)
+		assert.NotContains(t, values, "data.missing")
+		assert.NotContains(t, values, "blockAddress.sequenceNo.x")
+	})
+
+	t.Run("nested paths", func(t *testing.T) {
+		projection, err := NewProjection("data.owner", "data.owner.age")
+		require.NoError(t, err)
+
+		values, err := projection.Decode(row)
+		require.NoError(t, err)
+		assert.Contains(t, values, "data.owner")
+		assert.EqualValues(t, 30, values["data.owner.age"])
+	})
+
+	t.Run("decode into", func(t *testing.T) {
+		projection, err := NewProjection("metadata.id", "metadata.version", "data.owner.name", "data.color")
+		require.NoError(t, err)
+
+		var decoded struct {
+			ID      string `qldb:"metadata.id"`
+			Version int64  `qldb:"metadata.version"`
+			Owner   string `qldb:"data.owner.name"`
+			Color   string `qldb:"data.color"`
+			Other   string
+		}
+		decoded.Color = "unchanged"
+		require.NoError(t, projection.DecodeInto(row, &decoded))
+		assert.Equal(t, "doc1", decoded.ID)
+		assert.Equal(t, int64(2), decoded.Version)
+		assert.Equal(t, "Ana", decoded.Owner)
+		assert.Equal(t, "unchanged", decoded.Color)
+
+		var unknown struct {
+			Hash []byte `qldb:"hash"`
+		}
+		assert.Error(t, projection.DecodeInto(row, &unknown))
+		assert.Error(t, projection.DecodeInto(row, decoded))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := NewProjection()
+		assert.Error(t, err)
+		_, err = NewProjection("data..name")
+		assert.Error(t, err)
+
+		projection, err := NewProjection("data")
+		require.NoError(t, err)
+		_, err = projection.Decode(nil)
+		assert.Error(t, err)
+		values, err := projection.Decode(ionTextToBinary(t, `"not a struct"`))
+		require.NoError(t, err)
+		assert.Empty(t, values)
+		assert.Equal(t, []string{"data"}, projection.Paths())
+	})
+}
@@ -0,0 +1,84 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"github.com/amzn/ion-go/ion"
+)
+
+// MarshalProof returns the Ion text of a proof, a list of SHA-256 hashes, in the format of the proofs returned by the
+// GetRevision and GetBlock APIs, for example [{{hash1}}, {{hash2}}]. It is the format read by the verification
+// samples of the other QLDB drivers, so a proof produced by a Go service can be verified by other tooling.
+func MarshalProof(proof [][]byte) (string, error) {
+	if err := checkProofHashes(proof); err != nil {
+		return "", err
+	}
+	if proof == nil {
+		proof = [][]byte{}
+	}
+	ionText, err := ion.MarshalText(proof)
+	if err != nil {
+		return "", err
+	}
+	return string(ionText), nil
+}
+
+// UnmarshalProof parses a proof in the format returned by the GetRevision and GetBlock APIs and written by
+// MarshalProof, an Ion list of SHA-256 hashes. Ion binary is accepted too.
+func UnmarshalProof(proof string) ([][]byte, error) {
+	var hashes [][]byte
+	err := ion.Unmarshal([]byte(proof), &hashes)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkProofHashes(hashes); err != nil {
+		return nil, err
+	}
+	return hashes, nil
+}
+
+// MarshalBlockAddress returns the Ion text of a block address, in the format of the digest tip addresses returned by the
+// GetDigest API and of the block addresses expected by the GetBlock and GetRevision APIs, for example
+// {strandId:"BlFTjlSXze9BIh1KOszcE3",sequenceNo:14}.
+func MarshalBlockAddress(address BlockAddress) (string, error) {
+	ionText, err := ion.MarshalText(address)
+	if err != nil {
+		return "", err
+	}
+	return string(ionText), nil
+}
+
+// UnmarshalBlockAddress parses a block address or a digest tip address in the format returned by the GetDigest API and
+// written by MarshalBlockAddress. Ion binary is accepted too.
+func UnmarshalBlockAddress(address string) (BlockAddress, error) {
+	blockAddress := BlockAddress{}
+	err := ion.Unmarshal([]byte(address), &blockAddress)
+	if err != nil {
+		return BlockAddress{}, err
+	}
+	if blockAddress.StrandID == "" {
+		return BlockAddress{}, &qldbDriverError{"Block address has no strandId."}
+	}
+	return blockAddress, nil
+}
+
+// checkProofHashes returns an error if a hash of a proof is not a SHA-256 hash.
+func checkProofHashes(proof [][]byte) error {
+	for _, hash := range proof {
+		if len(hash) != hashSize {
+			return &qldbDriverError{"Proof contains a hash that is not a SHA-256 hash."}
+		}
+	}
+	return nil
+}
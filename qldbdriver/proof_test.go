@@ -0,0 +1,73 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProofSerialization(t *testing.T) {
+	t.Run("proof", func(t *testing.T) {
+		proof := [][]byte{testHash("first"), testHash("second")}
+		ionText, err := MarshalProof(proof)
+		require.NoError(t, err)
+		assert.Regexp(t, `^\[\{\{[A-Za-z0-9+/=]+\}\},\{\{[A-Za-z0-9+/=]+\}\}\]$`, ionText)
+
+		parsed, err := UnmarshalProof(ionText)
+		require.NoError(t, err)
+		assert.Equal(t, proof, parsed)
+
+		empty, err := MarshalProof(nil)
+		require.NoError(t, err)
+		assert.Equal(t, "[]", empty)
+	})
+
+	t.Run("proof from another driver", func(t *testing.T) {
+		proof, err := UnmarshalProof("[{{WYLOfZClHkFF7g46ZS6rl1XMD2xtyYHSd9YEeZ7Ex2E=}},\n{{xN9X96atkMvhvF3nEy6jMSVQzKjHJfz1H3bsNeg8GMA=}}]")
+		require.NoError(t, err)
+		assert.Len(t, proof, 2)
+
+		proof, err = UnmarshalProof(string(ionTextToBinary(t, "[{{WYLOfZClHkFF7g46ZS6rl1XMD2xtyYHSd9YEeZ7Ex2E=}}]")))
+		require.NoError(t, err)
+		assert.Len(t, proof, 1)
+	})
+
+	t.Run("invalid proof", func(t *testing.T) {
+		_, err := UnmarshalProof("[{{aGVsbG8=}}]")
+		assert.Error(t, err)
+		_, err = UnmarshalProof("{strandId: \"S\"}")
+		assert.Error(t, err)
+		_, err = MarshalProof([][]byte{[]byte("short")})
+		assert.Error(t, err)
+	})
+
+	t.Run("block address", func(t *testing.T) {
+		address := BlockAddress{StrandID: "BlFTjlSXze9BIh1KOszcE3", SequenceNo: 14}
+		ionText, err := MarshalBlockAddress(address)
+		require.NoError(t, err)
+		assert.Equal(t, `{strandId:"BlFTjlSXze9BIh1KOszcE3",sequenceNo:14}`, ionText)
+
+		parsed, err := UnmarshalBlockAddress("{ strandId: \"BlFTjlSXze9BIh1KOszcE3\", sequenceNo: 14 }")
+		require.NoError(t, err)
+		assert.Equal(t, address, parsed)
+
+		_, err = UnmarshalBlockAddress("{sequenceNo: 14}")
+		assert.Error(t, err)
+		_, err = UnmarshalBlockAddress("not ion {")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,177 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+// Package qldbadmin manages Amazon QLDB ledgers through the QLDB control plane, waiting for ledgers to become active or
+// to be deleted. It can be used by provisioning tooling and by integration tests of applications using the driver.
+package qldbadmin
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldb"
+	"github.com/aws/aws-sdk-go-v2/service/qldb/types"
+)
+
+// ClientAPI is the subset of the qldb.Client methods used by LedgerAdmin.
+type ClientAPI interface {
+	CreateLedger(ctx context.Context, params *qldb.CreateLedgerInput, optFns ...func(*qldb.Options)) (*qldb.CreateLedgerOutput, error)
+	DeleteLedger(ctx context.Context, params *qldb.DeleteLedgerInput, optFns ...func(*qldb.Options)) (*qldb.DeleteLedgerOutput, error)
+	DescribeLedger(ctx context.Context, params *qldb.DescribeLedgerInput, optFns ...func(*qldb.Options)) (*qldb.DescribeLedgerOutput, error)
+	UpdateLedger(ctx context.Context, params *qldb.UpdateLedgerInput, optFns ...func(*qldb.Options)) (*qldb.UpdateLedgerOutput, error)
+}
+
+var _ ClientAPI = (*qldb.Client)(nil)
+
+// Options can be used to configure a LedgerAdmin during construction.
+type Options struct {
+	// The interval between two checks of the state of a ledger while waiting for it. Default: 5s.
+	PollInterval time.Duration
+}
+
+// LedgerAdmin creates, updates and deletes QLDB ledgers. Call constructor qldbadmin.New for a valid LedgerAdmin.
+type LedgerAdmin struct {
+	client       ClientAPI
+	pollInterval time.Duration
+}
+
+// qldbAdminError is returned when a ledger does not reach the expected state.
+type qldbAdminError struct {
+	errorMessage string
+}
+
+// Return the message denoting the cause of the error.
+func (e *qldbAdminError) Error() string {
+	return e.errorMessage
+}
+
+// New creates a LedgerAdmin using the QLDB control plane client and options.
+func New(client ClientAPI, fns ...func(*Options)) (*LedgerAdmin, error) {
+	if client == nil {
+		return nil, &qldbAdminError{"Provided QLDB client is nil."}
+	}
+
+	options := &Options{PollInterval: 5 * time.Second}
+	for _, fn := range fns {
+		fn(options)
+	}
+
+	if options.PollInterval <= 0 {
+		return nil, &qldbAdminError{"PollInterval must be greater than 0."}
+	}
+
+	return &LedgerAdmin{client: client, pollInterval: options.PollInterval}, nil
+}
+
+// CreateLedger creates a ledger and waits for it to become active.
+func (admin *LedgerAdmin) CreateLedger(ctx context.Context, input *qldb.CreateLedgerInput) (*qldb.DescribeLedgerOutput, error) {
+	_, err := admin.client.CreateLedger(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return admin.WaitForActive(ctx, *input.Name)
+}
+
+// DescribeLedger returns the description of a ledger.
+func (admin *LedgerAdmin) DescribeLedger(ctx context.Context, name string) (*qldb.DescribeLedgerOutput, error) {
+	return admin.client.DescribeLedger(ctx, &qldb.DescribeLedgerInput{Name: &name})
+}
+
+// UpdateLedger updates the properties of a ledger, such as its deletion protection.
+func (admin *LedgerAdmin) UpdateLedger(ctx context.Context, input *qldb.UpdateLedgerInput) (*qldb.UpdateLedgerOutput, error) {
+	return admin.client.UpdateLedger(ctx, input)
+}
+
+// DeleteLedger deletes a ledger and waits for the deletion to complete. A ledger that is still being created is
+// deleted once active, and a ledger that does not exist is considered deleted. Deletion protection must be disabled
+// with UpdateLedger beforehand.
+func (admin *LedgerAdmin) DeleteLedger(ctx context.Context, name string) error {
+	_, err := admin.client.DeleteLedger(ctx, &qldb.DeleteLedgerInput{Name: &name})
+	if err != nil {
+		var rnf *types.ResourceNotFoundException
+		if errors.As(err, &rnf) {
+			return nil
+		}
+		var riu *types.ResourceInUseException
+		if !errors.As(err, &riu) {
+			return err
+		}
+		switch {
+		case strings.Contains(riu.ErrorMessage(), "Ledger is being created"):
+			_, err = admin.WaitForActive(ctx, name)
+			if err != nil {
+				return err
+			}
+			_, err = admin.client.DeleteLedger(ctx, &qldb.DeleteLedgerInput{Name: &name})
+			if err != nil {
+				return err
+			}
+		case !strings.Contains(riu.ErrorMessage(), "Ledger is being deleted"):
+			return err
+		}
+	}
+	return admin.WaitForDeletion(ctx, name)
+}
+
+// WaitForActive waits for a ledger to become active and returns its description. Returns an error if the ledger is
+// being deleted or if the context is done first.
+func (admin *LedgerAdmin) WaitForActive(ctx context.Context, name string) (*qldb.DescribeLedgerOutput, error) {
+	for {
+		output, err := admin.DescribeLedger(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		switch output.State {
+		case types.LedgerStateActive:
+			return output, nil
+		case types.LedgerStateDeleting, types.LedgerStateDeleted:
+			return nil, &qldbAdminError{"Ledger " + name + " is " + string(output.State) + " and will not become active."}
+		}
+		err = admin.sleep(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// WaitForDeletion waits for a ledger to be deleted. Returns an error if the context is done first.
+func (admin *LedgerAdmin) WaitForDeletion(ctx context.Context, name string) error {
+	for {
+		output, err := admin.DescribeLedger(ctx, name)
+		if err != nil {
+			var rnf *types.ResourceNotFoundException
+			if errors.As(err, &rnf) {
+				return nil
+			}
+			return err
+		}
+		if output.State == types.LedgerStateDeleted {
+			return nil
+		}
+		err = admin.sleep(ctx)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (admin *LedgerAdmin) sleep(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(admin.pollInterval):
+		return nil
+	}
+}
@@ -0,0 +1,208 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbadmin
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldb"
+	"github.com/aws/aws-sdk-go-v2/service/qldb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const mockLedgerName = "ledgerName"
+
+var errMock = errors.New("mock")
+
+type mockClient struct {
+	mock.Mock
+}
+
+func (m *mockClient) CreateLedger(ctx context.Context, params *qldb.CreateLedgerInput, optFns ...func(*qldb.Options)) (*qldb.CreateLedgerOutput, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(*qldb.CreateLedgerOutput), args.Error(1)
+}
+
+func (m *mockClient) DeleteLedger(ctx context.Context, params *qldb.DeleteLedgerInput, optFns ...func(*qldb.Options)) (*qldb.DeleteLedgerOutput, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(*qldb.DeleteLedgerOutput), args.Error(1)
+}
+
+func (m *mockClient) DescribeLedger(ctx context.Context, params *qldb.DescribeLedgerInput, optFns ...func(*qldb.Options)) (*qldb.DescribeLedgerOutput, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(*qldb.DescribeLedgerOutput), args.Error(1)
+}
+
+func (m *mockClient) UpdateLedger(ctx context.Context, params *qldb.UpdateLedgerInput, optFns ...func(*qldb.Options)) (*qldb.UpdateLedgerOutput, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(*qldb.UpdateLedgerOutput), args.Error(1)
+}
+
+func newTestAdmin(t *testing.T, client ClientAPI) *LedgerAdmin {
+	admin, err := New(client, func(options *Options) {
+		options.PollInterval = time.Millisecond
+	})
+	require.NoError(t, err)
+	return admin
+}
+
+func describeOutput(state types.LedgerState) *qldb.DescribeLedgerOutput {
+	name := mockLedgerName
+	return &qldb.DescribeLedgerOutput{Name: &name, State: state}
+}
+
+func TestNew(t *testing.T) {
+	t.Run("nil client", func(t *testing.T) {
+		_, err := New(nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid poll interval", func(t *testing.T) {
+		_, err := New(new(mockClient), func(options *Options) {
+			options.PollInterval = 0
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("default poll interval", func(t *testing.T) {
+		admin, err := New(new(mockClient))
+		require.NoError(t, err)
+		assert.Equal(t, 5*time.Second, admin.pollInterval)
+	})
+}
+
+func TestCreateLedger(t *testing.T) {
+	name := mockLedgerName
+	input := &qldb.CreateLedgerInput{Name: &name, PermissionsMode: types.PermissionsModeStandard}
+
+	t.Run("waits for active", func(t *testing.T) {
+		client := new(mockClient)
+		client.On("CreateLedger", mock.Anything, input).Return(&qldb.CreateLedgerOutput{}, nil)
+		client.On("DescribeLedger", mock.Anything, mock.Anything).Return(describeOutput(types.LedgerStateCreating), nil).Twice()
+		client.On("DescribeLedger", mock.Anything, mock.Anything).Return(describeOutput(types.LedgerStateActive), nil).Once()
+
+		output, err := newTestAdmin(t, client).CreateLedger(context.Background(), input)
+		require.NoError(t, err)
+		assert.Equal(t, types.LedgerStateActive, output.State)
+		client.AssertNumberOfCalls(t, "DescribeLedger", 3)
+	})
+
+	t.Run("create error", func(t *testing.T) {
+		client := new(mockClient)
+		client.On("CreateLedger", mock.Anything, input).Return(&qldb.CreateLedgerOutput{}, errMock)
+
+		_, err := newTestAdmin(t, client).CreateLedger(context.Background(), input)
+		assert.Equal(t, errMock, err)
+		client.AssertNotCalled(t, "DescribeLedger", mock.Anything, mock.Anything)
+	})
+}
+
+func TestWaitForActive(t *testing.T) {
+	t.Run("ledger being deleted", func(t *testing.T) {
+		client := new(mockClient)
+		client.On("DescribeLedger", mock.Anything, mock.Anything).Return(describeOutput(types.LedgerStateDeleting), nil)
+
+		_, err := newTestAdmin(t, client).WaitForActive(context.Background(), mockLedgerName)
+		assert.Error(t, err)
+	})
+
+	t.Run("describe error", func(t *testing.T) {
+		client := new(mockClient)
+		client.On("DescribeLedger", mock.Anything, mock.Anything).Return(&qldb.DescribeLedgerOutput{}, errMock)
+
+		_, err := newTestAdmin(t, client).WaitForActive(context.Background(), mockLedgerName)
+		assert.Equal(t, errMock, err)
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		client := new(mockClient)
+		client.On("DescribeLedger", mock.Anything, mock.Anything).Return(describeOutput(types.LedgerStateCreating), nil)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := newTestAdmin(t, client).WaitForActive(ctx, mockLedgerName)
+		assert.Equal(t, context.Canceled, err)
+	})
+}
+
+func TestDeleteLedger(t *testing.T) {
+	notFound := &types.ResourceNotFoundException{}
+
+	t.Run("waits for deletion", func(t *testing.T) {
+		client := new(mockClient)
+		client.On("DeleteLedger", mock.Anything, mock.Anything).Return(&qldb.DeleteLedgerOutput{}, nil)
+		client.On("DescribeLedger", mock.Anything, mock.Anything).Return(describeOutput(types.LedgerStateDeleting), nil).Once()
+		client.On("DescribeLedger", mock.Anything, mock.Anything).Return(&qldb.DescribeLedgerOutput{}, notFound).Once()
+
+		err := newTestAdmin(t, client).DeleteLedger(context.Background(), mockLedgerName)
+		assert.NoError(t, err)
+		client.AssertNumberOfCalls(t, "DescribeLedger", 2)
+	})
+
+	t.Run("ledger not found", func(t *testing.T) {
+		client := new(mockClient)
+		client.On("DeleteLedger", mock.Anything, mock.Anything).Return(&qldb.DeleteLedgerOutput{}, notFound)
+
+		err := newTestAdmin(t, client).DeleteLedger(context.Background(), mockLedgerName)
+		assert.NoError(t, err)
+	})
+
+	t.Run("ledger being created", func(t *testing.T) {
+		message := "Ledger is being created"
+		client := new(mockClient)
+		client.On("DeleteLedger", mock.Anything, mock.Anything).Return(&qldb.DeleteLedgerOutput{}, &types.ResourceInUseException{Message: &message}).Once()
+		client.On("DeleteLedger", mock.Anything, mock.Anything).Return(&qldb.DeleteLedgerOutput{}, nil).Once()
+		client.On("DescribeLedger", mock.Anything, mock.Anything).Return(describeOutput(types.LedgerStateActive), nil).Once()
+		client.On("DescribeLedger", mock.Anything, mock.Anything).Return(describeOutput(types.LedgerStateDeleted), nil).Once()
+
+		err := newTestAdmin(t, client).DeleteLedger(context.Background(), mockLedgerName)
+		assert.NoError(t, err)
+		client.AssertNumberOfCalls(t, "DeleteLedger", 2)
+	})
+
+	t.Run("ledger being deleted", func(t *testing.T) {
+		message := "Ledger is being deleted"
+		client := new(mockClient)
+		client.On("DeleteLedger", mock.Anything, mock.Anything).Return(&qldb.DeleteLedgerOutput{}, &types.ResourceInUseException{Message: &message})
+		client.On("DescribeLedger", mock.Anything, mock.Anything).Return(&qldb.DescribeLedgerOutput{}, notFound)
+
+		err := newTestAdmin(t, client).DeleteLedger(context.Background(), mockLedgerName)
+		assert.NoError(t, err)
+	})
+
+	t.Run("delete error", func(t *testing.T) {
+		client := new(mockClient)
+		client.On("DeleteLedger", mock.Anything, mock.Anything).Return(&qldb.DeleteLedgerOutput{}, errMock)
+
+		err := newTestAdmin(t, client).DeleteLedger(context.Background(), mockLedgerName)
+		assert.Equal(t, errMock, err)
+	})
+}
+
+func TestUpdateLedger(t *testing.T) {
+	name := mockLedgerName
+	deletionProtection := false
+	input := &qldb.UpdateLedgerInput{Name: &name, DeletionProtection: &deletionProtection}
+	client := new(mockClient)
+	client.On("UpdateLedger", mock.Anything, input).Return(&qldb.UpdateLedgerOutput{DeletionProtection: &deletionProtection}, nil)
+
+	output, err := newTestAdmin(t, client).UpdateLedger(context.Background(), input)
+	require.NoError(t, err)
+	assert.False(t, *output.DeletionProtection)
+}
@@ -16,11 +16,15 @@ package qldbdriver
 
 import (
 	"context"
+	"errors"
+	"reflect"
 	"sync"
 	"time"
 
 	"github.com/amzn/ion-go/ion"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/errs"
 	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
 )
 
@@ -31,12 +35,236 @@ type DriverOptions struct {
 	RetryPolicy RetryPolicy
 	// The maximum amount of concurrent transactions this driver will permit. Default: 50.
 	MaxConcurrentTransactions int
+	// Named session partitions, each with its maximum number of concurrent transactions, in addition to the default
+	// partition of MaxConcurrentTransactions sessions, for example {"batch": 10}. Default: nil, no partitions.
+	PoolPartitions map[string]int
 	// The logger that the driver will use for any logging messages. Default: "log" package.
 	Logger Logger
 	// The verbosity level of the logs that the logger should receive. Default: qldbdriver.LogInfo.
 	LoggerVerbosity LogLevel
+	// How the commands sent to QLDB are logged at LogDebug level: their session tokens, page tokens and commit digests
+	// are logged as fingerprints, redacted or in full, their parameters redacted or in full, and the literals of their
+	// statements redacted or not. Default: qldbdriver.LogRedactHash.
+	LogRedaction LogRedaction
+	// The duration after which a transaction attempt is considered slow and logged at LogInfo level along with its
+	// transaction ID, attempt number and consumed IOs. Default: 0, which disables slow transaction detection.
+	SlowTransactionThreshold time.Duration
+	// The options used to marshal statement parameters to Ion. Default: ion-go defaults.
+	IonMarshalOptions IonMarshalOptions
+	// The pool holding the idle sessions of the driver. Default: a pool that reuses sessions according to
+	// SessionReusePolicy.
+	SessionPool SessionPool
+	// The order in which the default session pool reuses idle sessions. Default: qldbdriver.SessionReuseFIFO.
+	SessionReusePolicy SessionReusePolicy
+	// The duration after which an idle session is discarded instead of being reused. Zero means that idle sessions are
+	// never discarded. Default: 0.
+	MaxSessionIdleTime time.Duration
+	// The number of invalid sessions replaced within SessionRefreshWindow after which the driver replaces the idle
+	// sessions of the pool in the background, rather than letting later transactions replace them. Default: 0, which
+	// disables background refreshes.
+	SessionRefreshThreshold int
+	// The window in which session replacements are counted towards SessionRefreshThreshold. Default: 10s.
+	SessionRefreshWindow time.Duration
+	// The maximum number of idle sessions a background refresh takes from the pool at a time. Each of them counts
+	// towards MaxConcurrentTransactions until it is replaced. Default: 5.
+	SessionRefreshBatchSize int
+	// The interval at which the driver resizes the pool of idle sessions of the default partition to the load, between
+	// MinIdleSessions and MaxConcurrentTransactions sessions. Default: 0, which disables pool scaling.
+	PoolScalingInterval time.Duration
+	// The number of idle sessions pool scaling keeps in the pool, starting sessions at the end of an interval when
+	// fewer are idle. It cannot exceed MaxConcurrentTransactions. Default: 0.
+	MinIdleSessions int
+	// The mean time to check out a session above which pool scaling grows the pool. Default: 10ms.
+	PoolScalingWaitThreshold time.Duration
+	// The SDK retryer used for the StartSession and FetchPage commands, which are safe to retry at the transport layer,
+	// for example retry.NewStandard() from the aws/retry package. FetchPage commands are only retried by the SDK when
+	// FetchPageRetryLimit is 0. Other commands are never retried by the SDK, since the driver retries whole
+	// transactions according to RetryPolicy. Default: nil, which disables SDK retries.
+	SDKRetryer aws.Retryer
+	// Functions applied to the qldbsession.Options of every command sent to QLDB, after the options set by the driver.
+	// They can be used to set a custom endpoint resolver, HTTP client or middleware. Default: nil.
+	ClientOptions []func(*qldbsession.Options)
+	// The maximum number of times the FetchPage command that reads the next page of a Result is retried within its
+	// transaction, when it failed because QLDB failed internally, was unavailable, exceeded its capacity or throttled it,
+	// before the error is returned by Result.Err and fails the transaction. Fetching a page again returns the same page,
+	// so a large scan does not start over from its first page because of a transient failure. The SDKRetryer does not
+	// also retry the FetchPage commands retried by the driver. Default: 2.
+	FetchPageRetryLimit int
+	// The strategy for delaying the retries of a FetchPage command. Default: ExponentialBackoffStrategy: SleepBase:
+	// 10ms, SleepCap: 1000ms.
+	FetchPageBackoff BackoffStrategy
+	// The maximum number of rows a single statement can return through its Result. Reading a row beyond the limit
+	// fails with a ResultTooLargeError, to protect the application from running out of memory on an accidentally
+	// unbounded query. Default: 0, which does not limit the rows.
+	MaxResultRows int64
+	// The maximum number of bytes of Ion binary a single statement can return through its Result, counted as
+	// MaxResultRows. Default: 0, which does not limit the bytes.
+	MaxResultBytes int64
+	// Sends the commands of the driver to the FIPS endpoint of QLDB in the region of the qldbsession.Client, for
+	// workloads that must use FIPS 140 validated cryptography, such as in the AWS GovCloud (US) regions. It overrides
+	// the EndpointOptions of the client, but not a custom endpoint set with its BaseEndpoint or EndpointResolver.
+	// Default: false, the endpoint of the client, which can also be configured with the AWS_USE_FIPS_ENDPOINT
+	// environment variable or the use_fips_endpoint setting of the shared config file.
+	UseFIPSEndpoint bool
+	// Sends the commands of the driver to the dual-stack endpoint of QLDB, which accepts both IPv4 and IPv6
+	// connections, for deployments on IPv6-only networks. It can be combined with UseFIPSEndpoint, and overrides the
+	// client like it. Default: false, the endpoint of the client, which can also be configured with the
+	// AWS_USE_DUALSTACK_ENDPOINT environment variable or the use_dualstack_endpoint setting of the shared config file.
+	UseDualStackEndpoint bool
+	// The maximum number of statements allowed per transaction. A warning is logged when a transaction reaches 80% of
+	// the limit, and executing more statements returns a StatementLimitError. Default: 0, which disables the limit.
+	StatementLimit int
+	// The size in bytes from which the body of an ExecuteStatement command, which includes its parameters, is
+	// compressed with gzip, for example to reduce the bandwidth of bulk inserts of documents with large blobs. The
+	// SDK does not negotiate request compression, so this should only be enabled for an endpoint that accepts
+	// gzip-encoded requests. Default: 0, which disables compression.
+	RequestCompressionMinBytes int
+	// What the driver does when a transaction starts while MaxConcurrentTransactions transactions are already in
+	// progress. Default: qldbdriver.PoolExhaustionFail.
+	PoolExhaustionPolicy PoolExhaustionPolicy
+	// The maximum time to wait for a transaction to complete with PoolExhaustionBlock. Default: 0, which waits until
+	// the context of the transaction is done.
+	PoolExhaustionTimeout time.Duration
+	// The maximum number of transactions in progress with PoolExhaustionGrow, which must be at least
+	// MaxConcurrentTransactions. Default: 0, which allows twice MaxConcurrentTransactions.
+	MaxBurstTransactions int
+	// The duration for which GetTableNames returns the table names it read last instead of reading them again. Call
+	// InvalidateTableNames after creating or dropping a table to read them again before the duration elapses.
+	// Default: 0, which disables caching.
+	TableNamesCacheTTL time.Duration
+	// Rejects with an UnsafeStatementError, before sending them to QLDB, the statements with a string or Ion literal
+	// containing an interpolation marker such as %s or ${, which suggests that values were interpolated into a
+	// template instead of passed as parameters, and the statements calling the non-deterministic function UTCNOW.
+	// Default: false.
+	StrictStatements bool
+	// The time a transaction can stay idle, from its start to its first statement, between two statements, or from
+	// its last statement to its commit, above which the gap is logged at LogInfo level. QLDB expires the transactions
+	// that stay open for too long, so a gap usually means that the function passed to Execute does slow work that does
+	// not involve QLDB, such as a call to another service, which should be moved outside of the transaction. Default:
+	// 0, which disables idle detection.
+	IdleGapThreshold time.Duration
+	// Fails the statement or commit of a transaction that stayed idle for longer than IdleGapThreshold with a
+	// TransactionIdleError, instead of logging the gap. Default: false.
+	FailOnIdleGap bool
+	// Logs at LogInfo level the findings of LintStatement for the statements executed, once per statement, to find the
+	// full table scans, SELECT * projections and literals that consume more read IOs than needed. Default: false.
+	LintStatements bool
+	// Counts, for each statement, the OCC conflicts of the transactions that executed it, as returned by
+	// QLDBDriver.OCCConflictStats, to find the statements contending for the same documents. Default: false.
+	TrackOCCConflicts bool
+	// Keeps the parameters of the statements, and records in the OCC conflict stats the parameters of the latest
+	// conflicting execution of each statement, to identify the documents contended for. The parameters may contain
+	// sensitive values, and are held in memory until their transaction completes. It requires TrackOCCConflicts.
+	// Default: false.
+	CaptureOCCConflictParameters bool
+	// The name of the top-level field that SoftDelete sets to the time a document is soft deleted, and that Restore
+	// removes, for example "deletedAt". When set, QueryTyped, Count and Exists exclude the documents where the field is
+	// set. Default: "", which disables soft deletes.
+	SoftDeleteField string
+	// Captures the stack of the goroutine taking a session for every transaction, so that the sessions that are never
+	// returned can be traced with SuspectedSessionLeaks, and are logged on Shutdown. Capturing stacks is slow, so this
+	// is meant for debugging. Default: false.
+	DebugSessionLeaks bool
+	// Drops the reference of a Result to each row once Next moves past it, so that the rows already read from a page
+	// can be garbage collected while the rest of the page is iterated. QLDB does not let clients choose the size of
+	// the pages it returns, so this bounds the memory held for wide documents to the rows of a page not yet read and
+	// the rows retained by the caller. Rows served from the document cache of ExecuteOptions.CacheDocumentReads are
+	// not released. Default: false.
+	ReleaseConsumedRows bool
+	// Shares the outcome of Count, Exists and QueryTyped among the concurrent calls with the same table, WHERE clause
+	// and parameters, so that a burst of identical reads results in a single transaction. Default: false.
+	DeduplicateReads bool
+	// The number of recent retries of transactions kept in memory for QLDBDriver.RecentRetries and
+	// QLDBDriver.DumpDiagnostics. A negative value disables the record of retries. Default: 0, which keeps 64 retries.
+	RetryLogSize int
+	// The number of values kept by QLDBDriver.CachedQuery, above which the least recently used value is evicted. A
+	// negative value disables the cache, so that every call executes its function. Default: 0, which keeps 256 values.
+	QueryCacheSize int
+	// A QLDB control plane client, such as qldb.NewFromConfig(cfg), used to verify when the first session is started,
+	// including by Validate, that the ledger is in the region of the qldbsession.Client. A LedgerRegionError is
+	// returned if it is not. Failures to describe the ledger, other than the ledger not being found, are logged and
+	// the ledger is not verified. Default: nil, which disables the verification.
+	LedgerDescriber LedgerDescriber
+	// Retries once a transaction that failed with a CredentialsError, including when starting its session, with a new
+	// session started after forcing the credentials provider of the client to retrieve credentials again, for example
+	// when cached credentials were revoked before they expired. Only a provider with an Invalidate method, such as the
+	// aws.CredentialsCache used by config.LoadDefaultConfig, can be forced to retrieve credentials. Default: false.
+	RetryWithRefreshedCredentials bool
+	// Called on every error before it is returned by QLDBDriver.Execute, including through the methods built on it,
+	// to map the errors of the driver and of the SDK to the error types of an application. Returning nil keeps the
+	// original error. Default: nil, errors are returned as is.
+	TranslateError func(err error) error
+	// Called after a transaction of QLDBDriver.Execute that wrote documents is committed, with the documents it wrote,
+	// for example to invalidate the entries of a cache such as Redis. Default: nil, the written documents are not tracked.
+	AfterCommit func(ctx context.Context, written []WrittenDocument)
+	// Called before every statement executed within a transaction of the driver, including the statements executed by
+	// the methods built on QLDBDriver.Execute, so that policies such as audit logging or an allow-list of statements
+	// can be enforced without wrapping every call site. Returning a non-nil error rejects the statement without sending
+	// it to QLDB: Transaction.Execute returns the error, which fails the transaction unless the function recovers from
+	// it. The statement of the event is redacted like the statements logged by the driver. Default: nil.
+	BeforeStatement func(ctx context.Context, event StatementEvent) error
+	// Called after every statement executed within a transaction of the driver, with the time spent executing it and
+	// its error, if any. It is not called for the statements rejected by BeforeStatement. Default: nil.
+	AfterStatement func(ctx context.Context, event StatementEvent)
+	// The difference between the local clock and the clock of QLDB, measured from the Date header of the responses,
+	// above which a warning is logged at LogInfo level, once until the difference falls back within it. A skewed clock
+	// makes signed requests fail, and makes transactions appear to expire early or late. The header has a resolution of
+	// one second. Default: 1 minute. 0 disables the detection.
+	ClockSkewThreshold time.Duration
 }
 
+// ExecuteOptions can be used to configure a single call to QLDBDriver.Execute.
+type ExecuteOptions struct {
+	// Declares that the provided function is not idempotent. When true, the function is not retried if the outcome of
+	// committing its transaction is unknown, and an AmbiguousCommitError is returned instead. Default: false.
+	NonIdempotent bool
+	// Determines, within a new transaction, whether the transaction with the provided ID was committed. It is called
+	// when the outcome of committing a transaction is unknown, before the function is retried. A committed transaction
+	// makes Execute return the result of the function without retrying it. See VerifyCommitByTable for an
+	// implementation based on the committed view of a table written by the function. Default: nil, no verification.
+	VerifyCommit func(txn Transaction, transactionID string) (bool, error)
+	// An identifier of the business operation performed by the function. It is included in every log message about
+	// the attempts to execute the function, so that the operation can be traced through retries. Default: "".
+	CorrelationID string
+	// Key-value pairs included in every log message about the attempts to execute the function. Default: nil.
+	Tags map[string]string
+	// Called instead of RetryPolicy.OnRetry when the function is about to be retried. See RetryPolicy.OnRetry.
+	// Default: nil, RetryPolicy.OnRetry is called.
+	OnRetry func(attempt int, err error, nextDelay time.Duration) error
+	// Caches, within each transaction, the results of SELECT statements that read documents of a single table by ID,
+	// until a statement of the transaction writes to the table. Default: false.
+	CacheDocumentReads bool
+	// Filled in with a TransactionReport of the statements executed by the attempt whose transaction was committed,
+	// for example to attach the consumed IOs to the response of an API. It is reset when Execute returns an error,
+	// except for its Totals. Default: nil, no report.
+	Report *TransactionReport
+	// Sums the metrics of all the attempts into the TransactionReport.Totals of Report, including the attempts that
+	// failed and were retried. The totals are kept when Execute returns an error, since the IOs of the failed attempts
+	// were consumed all the same. Default: false, only the attempt that committed is reported.
+	AccumulateAttemptMetrics bool
+	// The name of the partition of DriverOptions.PoolPartitions whose sessions are used for the transactions. The
+	// transactions of a partition only use the sessions of their partition, so that a workload such as a batch job
+	// cannot starve the others of sessions. PoolExhaustionPolicy applies to the partitions, except that
+	// PoolExhaustionGrow only lets the default partition grow, and the partitions reuse their sessions according to
+	// SessionReusePolicy, even with a custom SessionPool. Default: "", the default partition.
+	Partition string
+	// Buffers a Result returned by the function into a BufferedResult before its transaction is committed, since a
+	// Result cannot fetch its next pages once the transaction is committed. Default: false, the Result is returned and
+	// reading it beyond its first page fails with a StreamingResultError.
+	BufferResult bool
+	// The maximum number of rows ExecuteFromCursor may skip when it resumes a cursor by executing its statement again,
+	// above which it returns a CursorSkipError. Default: 0, no limit.
+	MaxCursorSkip int64
+}
+
+// Executor executes a function within a QLDB transaction. It is implemented by *QLDBDriver, and accepted by the helpers
+// of the subpackages, such as unitofwork and qldbtestutil, so that they can be tested without a ledger.
+type Executor interface {
+	Execute(ctx context.Context, fn func(txn Transaction) (interface{}, error), optFns ...func(*ExecuteOptions)) (interface{}, error)
+}
+
+var _ Executor = (*QLDBDriver)(nil)
+
 // QLDBDriver is used to execute statements against QLDB. Call constructor qldbdriver.New for a valid QLDBDriver.
 type QLDBDriver struct {
 	ledgerName                string
@@ -45,51 +273,281 @@ type QLDBDriver struct {
 	logger                    *qldbLogger
 	isClosed                  bool
 	semaphore                 *semaphore
-	sessionPool               chan *session
+	sessionPool               SessionPool
+	partitions                map[string]*poolPartition
 	retryPolicy               RetryPolicy
 	lock                      sync.Mutex
+	// optionsLock guards the options that UpdateOptions can change.
+	optionsLock              sync.RWMutex
+	slowTransactionThreshold time.Duration
+	marshalOptions           IonMarshalOptions
+	maxSessionIdleTime       time.Duration
+	sessionRefresher         *sessionRefresher
+	poolScaler               *poolScaler
+	sdkRetryer               aws.Retryer
+	clientOptions            []func(*qldbsession.Options)
+	useFIPSEndpoint          bool
+	useDualStackEndpoint     bool
+	statementLimit           int
+	fetchPageRetry           *fetchPageRetry
+	resultSizeLimit          *resultSizeLimit
+	models                   map[string]reflect.Type
+	acquisitionStats         acquisitionStats
+	poolExhaustionPolicy     PoolExhaustionPolicy
+	poolExhaustionTimeout    time.Duration
+	tableNamesCacheTTL       time.Duration
+	tableNames               []string
+	tableNamesExpiry         time.Time
+	strictStatements         bool
+	idleGapThreshold         time.Duration
+	failOnIdleGap            bool
+	linter                   *statementLinter
+	occConflicts             *occConflictTracker
+	softDeleteField          string
+	releaseConsumedRows      bool
+	readFlights              *readFlights
+	translateError           func(err error) error
+	afterCommit              func(ctx context.Context, written []WrittenDocument)
+	statementHooks           *statementHooks
+	capabilities             *serverCapabilities
+	clockSkew                *clockSkewDetector
+	refreshCredentials       bool
+	ledgerRegionCheck        *ledgerRegionCheck
+	sessionCheckouts         sessionCheckouts
+	retryLog                 retryLog
+	queryCache               queryCache
 }
 
+// semaphore bounds the number of transactions in progress. Its size can be changed while permits are acquired.
 type semaphore struct {
-	values chan struct{}
+	lock sync.Mutex
+	size int
+	used int
+	// peak is the highest number of permits acquired since the last call to resetPeak.
+	peak int
+	// released is closed, and replaced, when a permit may have become available.
+	released chan struct{}
+}
+
+// defaultDriverOptions returns the DriverOptions of a driver before the options passed to New are applied.
+func defaultDriverOptions() *DriverOptions {
+	retryPolicy := RetryPolicy{
+		MaxRetryLimit:   4,
+		Backoff:         ExponentialBackoffStrategy{SleepBase: time.Duration(10) * time.Millisecond, SleepCap: time.Duration(5000) * time.Millisecond},
+		ThrottleBackoff: ExponentialBackoffStrategy{SleepBase: time.Duration(100) * time.Millisecond, SleepCap: time.Duration(10000) * time.Millisecond}}
+	return &DriverOptions{RetryPolicy: retryPolicy, MaxConcurrentTransactions: 50, Logger: defaultLogger{}, LoggerVerbosity: LogInfo,
+		SessionRefreshWindow: 10 * time.Second, SessionRefreshBatchSize: 5, PoolScalingWaitThreshold: 10 * time.Millisecond,
+		FetchPageRetryLimit: 2, ClockSkewThreshold: time.Minute}
 }
 
 // New creates a QLBDDriver using the parameters and options, and verifies the configuration.
 //
-// Note that qldbSession will disable all SDK retry attempts when calling service operations.
+// Note that qldbSession will disable all SDK retry attempts when calling service operations, unless DriverOptions.SDKRetryer is set.
 // DriverOptions.RetryLimit is unrelated to SDK retries, but should be used if it is desired to modify the amount of retires for statement executions.
 func New(ledgerName string, qldbSession *qldbsession.Client, fns ...func(*DriverOptions)) (*QLDBDriver, error) {
 	if qldbSession == nil {
 		return nil, &qldbDriverError{"Provided QLDBSession is nil."}
 	}
 
-	retryPolicy := RetryPolicy{
-		MaxRetryLimit: 4,
-		Backoff:       ExponentialBackoffStrategy{SleepBase: time.Duration(10) * time.Millisecond, SleepCap: time.Duration(5000) * time.Millisecond}}
-	options := &DriverOptions{RetryPolicy: retryPolicy, MaxConcurrentTransactions: 50, Logger: defaultLogger{}, LoggerVerbosity: LogInfo}
-
+	options := defaultDriverOptions()
 	for _, fn := range fns {
 		fn(options)
 	}
+	driverQldbSession := *qldbSession
+	return newDriver(ledgerName, &driverQldbSession, options)
+}
 
+// newDriver creates a QLDBDriver with options to which the functions passed to New were already applied.
+func newDriver(ledgerName string, qldbSession qldbsessioniface.ClientAPI, options *DriverOptions) (*QLDBDriver, error) {
 	if options.MaxConcurrentTransactions < 1 {
 		return nil, &qldbDriverError{"MaxConcurrentTransactions must be 1 or greater."}
 	}
 
-	logger := &qldbLogger{options.Logger, options.LoggerVerbosity}
+	if options.SlowTransactionThreshold < 0 {
+		return nil, &qldbDriverError{"SlowTransactionThreshold must be 0 or greater."}
+	}
 
-	driverQldbSession := *qldbSession
+	if options.SessionReusePolicy > SessionReuseLIFO {
+		return nil, &qldbDriverError{"SessionReusePolicy is invalid."}
+	}
+
+	if options.PoolExhaustionPolicy > PoolExhaustionGrow {
+		return nil, &qldbDriverError{"PoolExhaustionPolicy is invalid."}
+	}
+	if options.LogRedaction > LogRedactNone {
+		return nil, &qldbDriverError{"LogRedaction is invalid."}
+	}
+
+	if options.TableNamesCacheTTL < 0 {
+		return nil, &qldbDriverError{"TableNamesCacheTTL must be 0 or greater."}
+	}
+
+	if options.PoolExhaustionTimeout < 0 {
+		return nil, &qldbDriverError{"PoolExhaustionTimeout must be 0 or greater."}
+	}
+
+	permits := options.MaxConcurrentTransactions
+	if options.PoolExhaustionPolicy == PoolExhaustionGrow {
+		if options.MaxBurstTransactions == 0 {
+			options.MaxBurstTransactions = 2 * options.MaxConcurrentTransactions
+		}
+		if options.MaxBurstTransactions < options.MaxConcurrentTransactions {
+			return nil, &qldbDriverError{"MaxBurstTransactions must be 0 or at least MaxConcurrentTransactions."}
+		}
+		permits = options.MaxBurstTransactions
+	}
+
+	if options.MaxSessionIdleTime < 0 {
+		return nil, &qldbDriverError{"MaxSessionIdleTime must be 0 or greater."}
+	}
+
+	if options.FetchPageRetryLimit < 0 {
+		return nil, &qldbDriverError{"FetchPageRetryLimit must be 0 or greater."}
+	}
+	if options.MaxResultRows < 0 {
+		return nil, &qldbDriverError{"MaxResultRows must be 0 or greater."}
+	}
+	if options.MaxResultBytes < 0 {
+		return nil, &qldbDriverError{"MaxResultBytes must be 0 or greater."}
+	}
+	if options.StatementLimit < 0 {
+		return nil, &qldbDriverError{"StatementLimit must be 0 or greater."}
+	}
+
+	if options.RequestCompressionMinBytes < 0 {
+		return nil, &qldbDriverError{"RequestCompressionMinBytes must be 0 or greater."}
+	}
+
+	if options.ClockSkewThreshold < 0 {
+		return nil, &qldbDriverError{"ClockSkewThreshold must be 0 or greater."}
+	}
+
+	if options.SoftDeleteField != "" && !tableNameRegex.MatchString(options.SoftDeleteField) {
+		return nil, &qldbDriverError{"Invalid SoftDeleteField: '" + options.SoftDeleteField + "'."}
+	}
+
+	clientOptions := options.ClientOptions
+	if options.RequestCompressionMinBytes > 0 || options.UseFIPSEndpoint || options.UseDualStackEndpoint {
+		clientOptions = make([]func(*qldbsession.Options), 0, len(options.ClientOptions)+2)
+		if options.RequestCompressionMinBytes > 0 {
+			clientOptions = append(clientOptions, withRequestCompression(options.RequestCompressionMinBytes))
+		}
+		if options.UseFIPSEndpoint || options.UseDualStackEndpoint {
+			clientOptions = append(clientOptions, withEndpointOptions(options.UseFIPSEndpoint, options.UseDualStackEndpoint))
+		}
+		clientOptions = append(clientOptions, options.ClientOptions...)
+	}
+
+	if options.SessionRefreshThreshold < 0 {
+		return nil, &qldbDriverError{"SessionRefreshThreshold must be 0 or greater."}
+	}
+
+	var refresher *sessionRefresher
+	if options.SessionRefreshThreshold > 0 {
+		if options.SessionRefreshWindow <= 0 {
+			return nil, &qldbDriverError{"SessionRefreshWindow must be greater than 0."}
+		}
+		if options.SessionRefreshBatchSize < 1 {
+			return nil, &qldbDriverError{"SessionRefreshBatchSize must be 1 or greater."}
+		}
+		refresher = &sessionRefresher{
+			threshold: options.SessionRefreshThreshold,
+			window:    options.SessionRefreshWindow,
+			batchSize: options.SessionRefreshBatchSize,
+		}
+	}
+
+	scaler, err := newPoolScaler(options)
+	if err != nil {
+		return nil, err
+	}
 
-	semaphore := makeSemaphore(options.MaxConcurrentTransactions)
-	sessionPool := make(chan *session, options.MaxConcurrentTransactions)
+	logger := &qldbLogger{logger: options.Logger, redaction: options.LogRedaction}
+	logger.setLevel(options.LoggerVerbosity)
+	if permits > DefaultMaxActiveSessionsPerLedger {
+		logger.logf(LogInfo, "The driver can hold up to %d sessions, more than the default QLDB quota of %d active sessions "+
+			"per ledger. Starting sessions fails once the quota of the ledger is reached.", permits, DefaultMaxActiveSessionsPerLedger)
+	}
+
+	semaphore := makeSemaphore(permits)
+	sessionPool := options.SessionPool
+	if sessionPool == nil {
+		if options.SessionReusePolicy == SessionReuseLIFO {
+			sessionPool = newStackSessionPool(options.MaxConcurrentTransactions)
+		} else {
+			sessionPool = newChannelSessionPool(options.MaxConcurrentTransactions)
+		}
+	}
+	partitions, err := newPoolPartitions(options)
+	if err != nil {
+		return nil, err
+	}
+	var regionCheck *ledgerRegionCheck
+	if options.LedgerDescriber != nil {
+		regionCheck = &ledgerRegionCheck{describer: options.LedgerDescriber}
+	}
 	isClosed := false
 
-	return &QLDBDriver{ledgerName, &driverQldbSession, options.MaxConcurrentTransactions, logger, isClosed,
-		semaphore, sessionPool, options.RetryPolicy, sync.Mutex{}}, nil
+	driver := &QLDBDriver{
+		ledgerName:                ledgerName,
+		qldbSession:               qldbSession,
+		maxConcurrentTransactions: options.MaxConcurrentTransactions,
+		logger:                    logger,
+		isClosed:                  isClosed,
+		semaphore:                 semaphore,
+		sessionPool:               sessionPool,
+		partitions:                partitions,
+		retryPolicy:               options.RetryPolicy,
+		slowTransactionThreshold:  options.SlowTransactionThreshold,
+		marshalOptions:            options.IonMarshalOptions,
+		maxSessionIdleTime:        options.MaxSessionIdleTime,
+		sessionRefresher:          refresher,
+		poolScaler:                scaler,
+		sdkRetryer:                options.SDKRetryer,
+		clientOptions:             clientOptions,
+		useFIPSEndpoint:           options.UseFIPSEndpoint,
+		useDualStackEndpoint:      options.UseDualStackEndpoint,
+		statementLimit:            options.StatementLimit,
+		fetchPageRetry:            fetchPageRetryFromOptions(options),
+		resultSizeLimit:           resultSizeLimitFromOptions(options),
+		poolExhaustionPolicy:      options.PoolExhaustionPolicy,
+		poolExhaustionTimeout:     options.PoolExhaustionTimeout,
+		tableNamesCacheTTL:        options.TableNamesCacheTTL,
+		strictStatements:          options.StrictStatements,
+		idleGapThreshold:          options.IdleGapThreshold,
+		failOnIdleGap:             options.FailOnIdleGap,
+		releaseConsumedRows:       options.ReleaseConsumedRows,
+		translateError:            options.TranslateError,
+		afterCommit:               options.AfterCommit,
+		statementHooks:            newStatementHooks(options.BeforeStatement, options.AfterStatement),
+		capabilities:              &serverCapabilities{},
+		clockSkew:                 newClockSkewDetector(options.ClockSkewThreshold),
+		refreshCredentials:        options.RetryWithRefreshedCredentials,
+		ledgerRegionCheck:         regionCheck,
+		softDeleteField:           options.SoftDeleteField,
+		sessionCheckouts:          sessionCheckouts{captureStacks: options.DebugSessionLeaks},
+		retryLog:                  retryLog{size: options.RetryLogSize},
+		queryCache:                queryCache{size: options.QueryCacheSize},
+	}
+	if options.LintStatements {
+		driver.linter = newStatementLinter()
+	}
+	if options.DeduplicateReads {
+		driver.readFlights = newReadFlights()
+	}
+	if options.TrackOCCConflicts {
+		driver.occConflicts = newOCCConflictTracker(options.CaptureOCCConflictParameters)
+	}
+	if scaler != nil {
+		go driver.scalePool(scaler)
+	}
+	return driver, nil
 }
 
 // SetRetryPolicy sets the driver's retry policy for Execute.
 func (driver *QLDBDriver) SetRetryPolicy(rp RetryPolicy) {
+	driver.optionsLock.Lock()
+	defer driver.optionsLock.Unlock()
 	driver.retryPolicy = rp
 }
 
@@ -97,75 +555,341 @@ func (driver *QLDBDriver) SetRetryPolicy(rp RetryPolicy) {
 //
 // The provided function might be executed more than once and is not expected to run concurrently.
 // It is recommended for it to be idempotent, so that it doesn't have unintended side effects in the case of retries.
-func (driver *QLDBDriver) Execute(ctx context.Context, fn func(txn Transaction) (interface{}, error)) (interface{}, error) {
+// Functions that are not idempotent should be declared with ExecuteOptions.NonIdempotent.
+func (driver *QLDBDriver) Execute(ctx context.Context, fn func(txn Transaction) (interface{}, error), optFns ...func(*ExecuteOptions)) (interface{}, error) {
+	result, err := driver.execute(ctx, fn, optFns...)
+	return result, driver.translate(err)
+}
+
+// translate returns the error of DriverOptions.TranslateError for err, or err if there is none.
+func (driver *QLDBDriver) translate(err error) error {
+	if err != nil && driver.translateError != nil {
+		if translated := driver.translateError(err); translated != nil {
+			return translated
+		}
+	}
+	return err
+}
+
+func (driver *QLDBDriver) execute(ctx context.Context, fn func(txn Transaction) (interface{}, error), optFns ...func(*ExecuteOptions)) (interface{}, error) {
 	if driver.isClosed {
 		return nil, &qldbDriverError{"Cannot invoke methods on a closed QLDBDriver."}
 	}
 
+	options := &ExecuteOptions{}
+	for _, optFn := range optFns {
+		optFn(options)
+	}
+
+	partition, err := driver.partition(options.Partition)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := driver.logger.forContext(ctx).withTags(options.CorrelationID, options.Tags)
+	retryPolicy := driver.currentRetryPolicy()
 	retryAttempt := 0
+	onRetry := retryPolicy.OnRetry
+	if options.OnRetry != nil {
+		onRetry = options.OnRetry
+	}
 
-	session, err := driver.getSession(ctx)
+	var deadline time.Time
+	if retryPolicy.MaxElapsedTime > 0 {
+		deadline = time.Now().Add(retryPolicy.MaxElapsedTime)
+	}
+
+	session, err := driver.getSession(ctx, partition)
 	if err != nil {
 		return nil, err
 	}
 
 	var result interface{}
 	var txnErr *txnError
+	var ambiguousErr *AmbiguousCommitError
+	credentialsRefreshed := false
+	var written *[]WrittenDocument
+	if driver.afterCommit != nil {
+		written = new([]WrittenDocument)
+	}
+	var totals *ExecutionTotals
+	if options.Report != nil && options.AccumulateAttemptMetrics {
+		totals = &ExecutionTotals{}
+	}
+	// fail returns err, wrapped in ambiguousErr if a previous attempt may have been committed
+	fail := func(err error) (interface{}, error) {
+		if options.Report != nil {
+			*options.Report = TransactionReport{}
+			if totals != nil {
+				options.Report.Totals = *totals
+			}
+		}
+		if ambiguousErr != nil {
+			ambiguousErr.err = err
+			return nil, ambiguousErr
+		}
+		return nil, err
+	}
 	for {
-		result, txnErr = session.execute(ctx, fn)
+		attemptCtx, cancel := driver.attemptContext(ctx, deadline, retryAttempt, retryPolicy.MaxRetryLimit)
+		result, txnErr = driver.executeAttempt(attemptCtx, session.withExecuteOptions(logger, options), fn, retryAttempt+1, options.Report, totals, written)
+		if totals != nil {
+			options.Report.Totals = *totals
+		}
+		attemptExpired := attemptCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil
+		cancel()
+		if txnErr != nil && txnErr.isThrottle {
+			driver.acquisitionStats.recordThrottle()
+		}
+		if txnErr != nil && attemptExpired && time.Now().Before(deadline) {
+			logger.logf(LogInfo, "Attempt #%d exceeded its deadline.", retryAttempt+1)
+			// The session may be stalled, so the retry uses another one
+			txnErr.canRetry = true
+			txnErr.abortSuccess = false
+		}
 		if txnErr != nil {
 			// If initial session is invalid, always retry once
 			if txnErr.canRetry && txnErr.isISE && retryAttempt == 0 {
-				driver.logger.log(LogDebug, "Initial session received from pool invalid. Retrying...")
-				session, err = driver.createSession(ctx)
+				logger.log(LogDebug, "Initial session received from pool invalid. Retrying...")
+				if onRetry != nil {
+					if err = onRetry(retryAttempt+1, txnErr.unwrap(), 0); err != nil {
+						driver.discardSession(session)
+						return fail(err)
+					}
+				}
+				driver.recordRetry(txnErr, retryAttempt+1, attemptExpired, 0)
+				driver.recordSessionReplacement()
+				driver.sessionCheckouts.checkin(session)
+				session, err = driver.createSession(ctx, partition)
 				if err != nil {
-					return nil, err
+					return fail(err)
 				}
 				retryAttempt++
 				continue
 			}
+			// If the credentials were rejected, retry once with refreshed credentials
+			if txnErr.isCredentials && driver.refreshCredentials && !credentialsRefreshed {
+				logger.log(LogInfo, "Failed to authenticate. Refreshing the credentials and retrying with a new session.")
+				if onRetry != nil {
+					if err = onRetry(retryAttempt+1, txnErr.unwrap(), 0); err != nil {
+						driver.discardSession(session)
+						return fail(err)
+					}
+				}
+				driver.recordRetry(txnErr, retryAttempt+1, attemptExpired, 0)
+				credentialsRefreshed = true
+				driver.sessionCheckouts.checkin(session)
+				// The rejected session may still be open, so it is ended before its permit is reused
+				driver.endSessions(logger, []*PooledSession{{session: session}})
+				session, err = driver.startSession(ctx, partition, true)
+				if err != nil {
+					return fail(err)
+				}
+				retryAttempt++
+				continue
+			}
+			if txnErr.ambiguousCommit {
+				logger.logf(LogInfo, "Outcome of committing transaction %s is unknown.", txnErr.transactionID)
+				committedMaybe := true
+				if options.VerifyCommit != nil {
+					committed, verifyErr := driver.verifyCommit(ctx, session.withExecuteOptions(logger, options), options.VerifyCommit, txnErr.transactionID)
+					if verifyErr == nil && committed {
+						driver.releaseSession(ctx, session)
+						if written != nil && len(*written) > 0 {
+							driver.afterCommit(ctx, *written)
+						}
+						return result, nil
+					}
+					committedMaybe = verifyErr != nil
+					if verifyErr != nil && !verifyErr.abortSuccess {
+						txnErr.abortSuccess = false
+					}
+				}
+				// An unverified commit takes precedence over a verified one
+				if ambiguousErr == nil || !ambiguousErr.CommittedMaybe {
+					ambiguousErr = &AmbiguousCommitError{TransactionID: txnErr.transactionID, CommittedMaybe: committedMaybe}
+				}
+			}
+			stopAmbiguous := options.NonIdempotent && ambiguousErr != nil && ambiguousErr.CommittedMaybe
 			// Do not retry
-			if !txnErr.canRetry || retryAttempt >= driver.retryPolicy.MaxRetryLimit {
+			budgetElapsed := !deadline.IsZero() && !time.Now().Before(deadline)
+			if !txnErr.canRetry || stopAmbiguous || budgetElapsed || retryAttempt >= retryPolicy.MaxRetryLimit {
 				if txnErr.abortSuccess {
-					driver.releaseSession(session)
+					driver.releaseSession(ctx, session)
 				} else {
-					driver.semaphore.release()
+					driver.discardSession(session)
+				}
+				if stopAmbiguous {
+					logger.log(LogInfo, "Not retrying the non-idempotent function.")
 				}
-				return nil, txnErr.unwrap()
+				if ambiguousErr != nil && !ambiguousErr.CommittedMaybe && !txnErr.ambiguousCommit {
+					// The verified transaction is unrelated to this failure
+					ambiguousErr = nil
+				}
+				return fail(txnErr.unwrap())
 			}
 			// Retry
 			retryAttempt++
-			driver.logger.logf(LogInfo, "A recoverable error has occurred. Attempting retry #%d.", retryAttempt)
-			driver.logger.logf(LogDebug, "Errored Transaction ID: %s. Error cause: '%v'", txnErr.transactionID, txnErr)
+			delay, remaining, fits := retryPolicy.fitDelay(ctx, deadline, retryPolicy.backoffFor(txnErr).Delay(retryAttempt))
+			if !fits {
+				logger.logf(LogInfo, "Not retrying: a delay of %v exceeds the %v remaining before the deadline.", delay, remaining)
+				if txnErr.abortSuccess {
+					driver.releaseSession(ctx, session)
+				} else {
+					driver.discardSession(session)
+				}
+				return fail(&DeadlineWouldExceedError{Delay: delay, Remaining: remaining, err: txnErr.unwrap()})
+			}
+			if onRetry != nil {
+				if err = onRetry(retryAttempt, txnErr.unwrap(), delay); err != nil {
+					logger.logf(LogInfo, "Retry #%d was cancelled by the OnRetry callback.", retryAttempt)
+					if txnErr.abortSuccess {
+						driver.releaseSession(ctx, session)
+					} else {
+						driver.discardSession(session)
+					}
+					return fail(err)
+				}
+			}
+			driver.recordRetry(txnErr, retryAttempt, attemptExpired, delay)
+			logger.logf(LogInfo, "A recoverable error has occurred. Attempting retry #%d.", retryAttempt)
+			logger.logf(LogDebug, "Errored Transaction ID: %s. Error cause: '%v'", txnErr.transactionID, txnErr)
 			if txnErr.isISE {
-				driver.logger.log(LogDebug, "Replacing expired session...")
-				session, err = driver.createSession(ctx)
+				logger.log(LogDebug, "Replacing expired session...")
+				driver.recordSessionReplacement()
+				driver.sessionCheckouts.checkin(session)
+				session, err = driver.createSession(ctx, partition)
 				if err != nil {
-					return nil, err
+					return fail(err)
 				}
 			} else {
 				if !txnErr.abortSuccess {
-					driver.logger.log(LogDebug, "Retrying with a different session...")
-					driver.semaphore.release()
-					session, err = driver.getSession(ctx)
+					logger.log(LogDebug, "Retrying with a different session...")
+					driver.discardSession(session)
+					session, err = driver.getSession(ctx, partition)
 					if err != nil {
-						return nil, err
+						return fail(err)
 					}
 				}
 			}
 
-			delay := driver.retryPolicy.Backoff.Delay(retryAttempt)
 			sleepWithContext(ctx, delay)
 			continue
 		}
-		driver.releaseSession(session)
+		driver.releaseSession(ctx, session)
 		break
 	}
+	if written != nil && len(*written) > 0 {
+		driver.afterCommit(ctx, *written)
+	}
 	return result, nil
 }
 
-// GetTableNames returns a list of the names of active tables in the ledger.
+// attemptContext returns the context of an attempt of Execute. When Execute has a deadline derived from
+// RetryPolicy.MaxElapsedTime, the attempt is given an equal share of the time remaining for it and the retries that
+// may follow.
+func (driver *QLDBDriver) attemptContext(ctx context.Context, deadline time.Time, retryAttempt int, maxRetryLimit int) (context.Context, context.CancelFunc) {
+	if deadline.IsZero() {
+		return ctx, func() {}
+	}
+	attempts := maxRetryLimit - retryAttempt + 1
+	if attempts < 1 {
+		attempts = 1
+	}
+	return context.WithTimeout(ctx, time.Until(deadline)/time.Duration(attempts))
+}
+
+// verifyCommit calls verify in a new transaction on the session to determine whether the transaction was committed.
+func (driver *QLDBDriver) verifyCommit(ctx context.Context, session *session, verify func(txn Transaction, transactionID string) (bool, error), transactionID string) (bool, *txnError) {
+	committed, txnErr := session.execute(ctx, func(txn Transaction) (interface{}, error) {
+		return verify(txn, transactionID)
+	})
+	if txnErr != nil {
+		session.logger.logf(LogInfo, "Failed to verify whether transaction %s was committed.", transactionID)
+		session.logger.logf(LogDebug, "Verification error cause: '%v'", txnErr.unwrap())
+		return false, txnErr
+	}
+	session.logger.logf(LogInfo, "Verified whether transaction %s was committed: %v.", transactionID, committed)
+	return committed.(bool), nil
+}
+
+// executeAttempt executes fn once on the session. If the outcome of committing the transaction is unknown, the result
+// of fn is returned along with the error in case the transaction turns out to be committed.
+// When report and written are not nil, they are filled in for a transaction that may have been committed. When totals
+// is not nil, the metrics of the attempt are added to it whatever its outcome.
+func (driver *QLDBDriver) executeAttempt(ctx context.Context, session *session, fn func(txn Transaction) (interface{}, error), attempt int, report *TransactionReport, totals *ExecutionTotals, written *[]WrittenDocument) (interface{}, *txnError) {
+	var txn *transaction
+	var fnResult interface{}
+	start := time.Now()
+	result, txnErr := session.execute(ctx, func(t Transaction) (interface{}, error) {
+		if executor, ok := t.(*transactionExecutor); ok {
+			txn = executor.txn
+		}
+		var err error
+		fnResult, err = fn(t)
+		return fnResult, err
+	})
+	elapsed := time.Since(start)
+	if driver.slowTransactionThreshold > 0 && elapsed > driver.slowTransactionThreshold {
+		transactionID := ""
+		ioUsage := newIOUsage(0, 0)
+		if txn != nil {
+			transactionID = *txn.id
+			ioUsage = txn.consumedIOs()
+		}
+		session.logger.logf(LogInfo, "Slow transaction detected. Transaction ID: %s, attempt #%d took %v, exceeding threshold of %v. Consumed read IOs: %d, write IOs: %d.",
+			transactionID, attempt, elapsed, driver.slowTransactionThreshold, ioUsage.ReadIOs(), ioUsage.WriteIOs())
+	}
+	if driver.occConflicts != nil && txn != nil && txnErr != nil && errs.IsOCCConflict(txnErr.unwrap()) {
+		driver.occConflicts.record(txn)
+	}
+	if report != nil && txn != nil && (txnErr == nil || txnErr.ambiguousCommit) {
+		*report = newTransactionReport(txn, attempt)
+	}
+	if totals != nil {
+		totals.add(txn, attempt)
+	}
+	if written != nil && txn != nil && txn.writes != nil && (txnErr == nil || txnErr.ambiguousCommit) {
+		*written = txn.writes.documents
+	}
+	if txnErr != nil && txnErr.ambiguousCommit {
+		return fnResult, txnErr
+	}
+	return result, txnErr
+}
+
+// Validate verifies that the ledger exists and is active by starting a session on it, which is then kept in the pool.
+// A LedgerUnavailableError is returned if QLDB refused to start the session, so that a misconfigured driver can be
+// detected on construction rather than on its first transaction.
+func (driver *QLDBDriver) Validate(ctx context.Context) error {
+	if driver.isClosed {
+		return &qldbDriverError{"Cannot invoke methods on a closed QLDBDriver."}
+	}
+
+	session, err := driver.getSession(ctx, nil)
+	if err != nil {
+		var driverErr *qldbDriverError
+		if errors.As(err, &driverErr) {
+			return err
+		}
+		return &LedgerUnavailableError{LedgerName: driver.ledgerName, err: err}
+	}
+	driver.releaseSession(ctx, session)
+	return nil
+}
+
+// GetTableNames returns a list of the names of active tables in the ledger. The names are read in a transaction, or
+// returned from the cache when DriverOptions.TableNamesCacheTTL is set.
 func (driver *QLDBDriver) GetTableNames(ctx context.Context) ([]string, error) {
+	driver.lock.Lock()
+	if driver.tableNames != nil && time.Now().Before(driver.tableNamesExpiry) {
+		tableNames := make([]string, len(driver.tableNames))
+		copy(tableNames, driver.tableNames)
+		driver.lock.Unlock()
+		return tableNames, nil
+	}
+	driver.lock.Unlock()
+
 	const tableNameQuery string = "SELECT name FROM information_schema.user_tables WHERE status = 'ACTIVE'"
 	type tableName struct {
 		Name string `ion:"name"`
@@ -194,7 +918,22 @@ func (driver *QLDBDriver) GetTableNames(ctx context.Context) ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
-	return executeResult.([]string), nil
+	tableNames := executeResult.([]string)
+	if driver.tableNamesCacheTTL > 0 {
+		driver.lock.Lock()
+		driver.tableNames = make([]string, len(tableNames))
+		copy(driver.tableNames, tableNames)
+		driver.tableNamesExpiry = time.Now().Add(driver.tableNamesCacheTTL)
+		driver.lock.Unlock()
+	}
+	return tableNames, nil
+}
+
+// InvalidateTableNames discards the table names cached by GetTableNames, so that the next call reads them again.
+func (driver *QLDBDriver) InvalidateTableNames() {
+	driver.lock.Lock()
+	defer driver.lock.Unlock()
+	driver.tableNames = nil
 }
 
 // Shutdown the driver, cleaning up allocated resources.
@@ -203,45 +942,230 @@ func (driver *QLDBDriver) Shutdown(ctx context.Context) {
 	defer driver.lock.Unlock()
 	if !driver.isClosed {
 		driver.isClosed = true
-		for len(driver.sessionPool) > 0 {
-			session := <-driver.sessionPool
-			err := session.endSession(ctx)
+		if driver.poolScaler != nil {
+			close(driver.poolScaler.stop)
+		}
+		driver.logSessionLeaks()
+		pooledSessions := driver.sessionPool.Close()
+		for _, partition := range driver.partitions {
+			pooledSessions = append(pooledSessions, partition.sessionPool.Close()...)
+		}
+		for _, pooledSession := range pooledSessions {
+			err := pooledSession.session.endSession(ctx)
 			if err != nil {
 				driver.logger.logf(LogDebug, "Encountered error trying to end session: '%v'", err.Error())
 			}
 		}
-		close(driver.sessionPool)
 	}
 }
 
-func (driver *QLDBDriver) getSession(ctx context.Context) (*session, error) {
-	driver.logger.logf(LogDebug, "Getting session. Existing sessions available: %v", len(driver.sessionPool))
-	isPermitAcquired := driver.semaphore.tryAcquire()
+// getSession takes a session of partition, or of the default partition if partition is nil.
+func (driver *QLDBDriver) getSession(ctx context.Context, partition *poolPartition) (*session, error) {
+	pool := driver.poolOf(partition)
+	logger := driver.logger.forContext(ctx)
+	logger.log(LogDebug, "Getting session.")
+	start := time.Now()
+	isPermitAcquired := pool.semaphore.tryAcquire()
+	if !isPermitAcquired && driver.poolExhaustionPolicy == PoolExhaustionBlock {
+		logger.log(LogDebug, "No permit available. Waiting for a transaction to complete.")
+		var err error
+		driver.optionsLock.RLock()
+		timeout := driver.poolExhaustionTimeout
+		driver.optionsLock.RUnlock()
+		isPermitAcquired, err = pool.semaphore.acquire(ctx, timeout)
+		if err != nil {
+			driver.acquisitionStats.recordPermit(time.Since(start), false)
+			return nil, err
+		}
+	}
+	permitWait := time.Since(start)
+	driver.acquisitionStats.recordPermit(permitWait, isPermitAcquired)
 	if isPermitAcquired {
-		if len(driver.sessionPool) > 0 {
-			session := <-driver.sessionPool
-			driver.logger.log(LogDebug, "Reusing session from pool.")
-			return session, nil
+		var expired []*PooledSession
+		defer func() { driver.endSessions(logger, expired) }()
+		for pooledSession := pool.sessionPool.Get(); pooledSession != nil; pooledSession = pool.sessionPool.Get() {
+			if driver.maxSessionIdleTime > 0 && time.Since(pooledSession.idleSince) > driver.maxSessionIdleTime {
+				logger.log(LogDebug, "Discarding session that exceeded the maximum idle time.")
+				expired = append(expired, pooledSession)
+				continue
+			}
+			driver.acquisitionStats.recordReuse()
+			driver.sessionCheckouts.checkout(pooledSession.session)
+			logger.logf(LogDebug, "Reusing session from pool. Permit acquired in %v.", permitWait)
+			return pooledSession.session, nil
 		}
-		return driver.createSession(ctx)
+		logger.logf(LogDebug, "No idle session in pool. Permit acquired in %v.", permitWait)
+		return driver.createSession(ctx, partition)
 	}
+	logger.logf(LogDebug, "No permit available after %v: %d transactions in progress.",
+		permitWait, pool.semaphore.inUse())
 	return nil, &qldbDriverError{"MaxConcurrentTransactions limit exceeded."}
 }
 
-func (driver *QLDBDriver) createSession(ctx context.Context) (*session, error) {
-	driver.logger.log(LogDebug, "Creating a new session")
-	communicator, err := startSession(ctx, driver.ledgerName, driver.qldbSession, driver.logger)
+// createSession starts a session of partition, or of the default partition if partition is nil, for which a permit
+// was acquired.
+func (driver *QLDBDriver) createSession(ctx context.Context, partition *poolPartition) (*session, error) {
+	return driver.startSession(ctx, partition, false)
+}
+
+// startSession starts a session like createSession. With withRefreshedCredentials, the credentials provider of the
+// client is forced to retrieve credentials first. Otherwise, a session that cannot be started because of its
+// credentials is started again with refreshed credentials if DriverOptions.RetryWithRefreshedCredentials is set.
+func (driver *QLDBDriver) startSession(ctx context.Context, partition *poolPartition, withRefreshedCredentials bool) (*session, error) {
+	logger := driver.logger.forContext(ctx)
+	logger.log(LogDebug, "Creating a new session")
+	var startOptions []func(*qldbsession.Options)
+	if withRefreshedCredentials {
+		startOptions = append(startOptions, refreshCredentials)
+	}
+	checkRegion := false
+	if driver.ledgerRegionCheck != nil {
+		verified, regionErr := driver.ledgerRegionCheck.status()
+		if regionErr != nil {
+			driver.poolOf(partition).semaphore.release()
+			return nil, regionErr
+		}
+		checkRegion = !verified
+	}
+	var clientRegion string
+	if checkRegion {
+		startOptions = append(startOptions, func(options *qldbsession.Options) {
+			clientRegion = options.Region
+		})
+	}
+	start := time.Now()
+	communicator, err := startSession(ctx, driver.ledgerName, driver.qldbSession, driver.logger, driver.sdkRetryer, driver.clientOptions, startOptions...)
+	latency := time.Since(start)
+	driver.acquisitionStats.recordStartSession(latency, err)
+	if errs.IsThrottling(err) {
+		driver.acquisitionStats.recordThrottle()
+	}
+	if checkRegion {
+		// A session may even start in the wrong region, on another ledger with the same name
+		if regionErr := driver.ledgerRegionCheck.verify(ctx, logger, driver.ledgerName, clientRegion); regionErr != nil {
+			logger.log(LogInfo, regionErr.Error())
+			if err == nil {
+				driver.endSessions(logger, []*PooledSession{{session: &session{communicator: communicator, logger: driver.logger}}})
+			}
+			driver.poolOf(partition).semaphore.release()
+			return nil, regionErr
+		}
+	}
 	if err != nil {
-		driver.semaphore.release()
+		logger.logf(LogDebug, "Failed to start a session after %v.", latency)
+		if errs.IsCredentialsError(err) {
+			if driver.refreshCredentials && !withRefreshedCredentials {
+				logger.log(LogInfo, "Failed to authenticate. Refreshing the credentials and starting the session again.")
+				return driver.startSession(ctx, partition, true)
+			}
+			err = &CredentialsError{err: err}
+		}
+		driver.poolOf(partition).semaphore.release()
 		return nil, err
 	}
-	return &session{communicator, driver.logger}, nil
+	logger.logf(LogDebug, "Started a session in %v.", latency)
+	communicator.clockSkew = driver.clockSkew
+	communicator.retriesFetchPage = driver.fetchPageRetry != nil
+	session := &session{
+		communicator:        communicator,
+		logger:              driver.logger,
+		marshalOptions:      driver.marshalOptions,
+		statementLimit:      driver.statementLimit,
+		fetchPageRetry:      driver.fetchPageRetry,
+		resultSizeLimit:     driver.resultSizeLimit,
+		strictStatements:    driver.strictStatements,
+		idleGapThreshold:    driver.idleGapThreshold,
+		failOnIdleGap:       driver.failOnIdleGap,
+		linter:              driver.linter,
+		occConflicts:        driver.occConflicts,
+		releaseConsumedRows: driver.releaseConsumedRows,
+		trackWrites:         driver.afterCommit != nil,
+		hooks:               driver.statementHooks,
+		capabilities:        driver.capabilities,
+		partition:           partition,
+	}
+	driver.sessionCheckouts.checkout(session)
+	return session, nil
 }
 
-func (driver *QLDBDriver) releaseSession(session *session) {
-	driver.sessionPool <- session
-	driver.semaphore.release()
-	driver.logger.logf(LogDebug, "Session returned to pool; size of pool is now %v", len(driver.sessionPool))
+func (driver *QLDBDriver) releaseSession(ctx context.Context, session *session) {
+	logger := driver.logger.forContext(ctx)
+	pool := driver.poolOf(session.partition)
+	driver.sessionCheckouts.checkin(session)
+	// Sessions started beyond MaxConcurrentTransactions, by PoolExhaustionGrow or before UpdateOptions lowered it, and
+	// sessions beyond the capacity of a pool shrunk by pool scaling, are ended rather than pooled
+	pooledSession := &PooledSession{session: session, idleSince: time.Now()}
+	if pool.semaphore.inUse() > pool.maxConcurrentTransactions || !offerSession(pool.sessionPool, pooledSession) {
+		pool.semaphore.release()
+		logger.log(LogDebug, "Ending session started beyond MaxConcurrentTransactions or the capacity of the pool.")
+		driver.endSessions(logger, []*PooledSession{pooledSession})
+		return
+	}
+	pool.semaphore.release()
+	logger.log(LogDebug, "Session returned to pool.")
+}
+
+// discardSession releases the permit of a session that is not returned to the pool.
+func (driver *QLDBDriver) discardSession(session *session) {
+	driver.sessionCheckouts.checkin(session)
+	driver.poolOf(session.partition).semaphore.release()
+}
+
+// recordSessionReplacement starts a background refresh of the idle sessions if many sessions were replaced recently.
+func (driver *QLDBDriver) recordSessionReplacement() {
+	if driver.sessionRefresher != nil && driver.sessionRefresher.recordReplacement(time.Now()) {
+		driver.logger.log(LogDebug, "Detected a burst of invalid sessions. Refreshing idle sessions in the background.")
+		go driver.refreshIdleSessions(context.Background())
+	}
+}
+
+// refreshIdleSessions replaces the sessions that were idle in the pool when the refresh started, in batches. A permit
+// is held for each session taken from the pool until it is replaced.
+func (driver *QLDBDriver) refreshIdleSessions(ctx context.Context) {
+	defer driver.sessionRefresher.done()
+	start := time.Now()
+	refreshed := 0
+	for isDone := false; !isDone; {
+		batch := 0
+		var replaced []*PooledSession
+		for batch < driver.sessionRefresher.batchSize {
+			if !driver.semaphore.tryAcquire() {
+				isDone = true
+				break
+			}
+			pooledSession := driver.sessionPool.Get()
+			if pooledSession == nil || !pooledSession.idleSince.Before(start) {
+				// The pool may have filled up or shrunk since the session was taken, so it is offered rather than put back
+				if pooledSession != nil && !offerSession(driver.sessionPool, pooledSession) {
+					driver.endSessions(driver.logger, []*PooledSession{pooledSession})
+				}
+				driver.semaphore.release()
+				isDone = true
+				break
+			}
+			replaced = append(replaced, pooledSession)
+			batch++
+		}
+		driver.endSessions(driver.logger, replaced)
+		for ; batch > 0; batch-- {
+			session, err := driver.createSession(ctx, nil)
+			if err != nil {
+				driver.logger.logf(LogDebug, "Failed to refresh an idle session: '%v'", err)
+				continue
+			}
+			driver.lock.Lock()
+			isClosed := driver.isClosed
+			driver.lock.Unlock()
+			if isClosed {
+				driver.discardSession(session)
+				_ = session.endSession(ctx)
+				return
+			}
+			driver.releaseSession(ctx, session)
+			refreshed++
+		}
+	}
+	driver.logger.logf(LogDebug, "Refreshed %d idle sessions.", refreshed)
 }
 
 func sleepWithContext(ctx context.Context, delay time.Duration) {
@@ -252,22 +1176,100 @@ func sleepWithContext(ctx context.Context, delay time.Duration) {
 }
 
 func makeSemaphore(size int) *semaphore {
-	smphr := &semaphore{make(chan struct{}, size)}
-	for counter := 0; counter < size; counter++ {
-		smphr.values <- struct{}{}
-	}
-	return smphr
+	return &semaphore{size: size, released: make(chan struct{})}
 }
 
 func (smphr *semaphore) tryAcquire() bool {
-	select {
-	case _, ok := <-smphr.values:
-		return ok
-	default:
+	smphr.lock.Lock()
+	defer smphr.lock.Unlock()
+	if smphr.used >= smphr.size {
 		return false
 	}
+	smphr.acquired()
+	return true
+}
+
+// acquire waits for a permit for up to timeout, or until ctx is done if timeout is 0. It returns false when no permit
+// became available in time, and the error of ctx when it is done first.
+func (smphr *semaphore) acquire(ctx context.Context, timeout time.Duration) (bool, error) {
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	for {
+		smphr.lock.Lock()
+		if smphr.used < smphr.size {
+			smphr.acquired()
+			smphr.lock.Unlock()
+			return true, nil
+		}
+		released := smphr.released
+		smphr.lock.Unlock()
+		select {
+		case <-released:
+		case <-expired:
+			return false, nil
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
 }
 
 func (smphr *semaphore) release() {
-	smphr.values <- struct{}{}
+	smphr.lock.Lock()
+	defer smphr.lock.Unlock()
+	smphr.used--
+	smphr.notify()
+}
+
+// resize changes the number of permits. When it shrinks below the number of permits acquired, no permit is available
+// until enough of them are released.
+func (smphr *semaphore) resize(size int) {
+	smphr.lock.Lock()
+	defer smphr.lock.Unlock()
+	smphr.size = size
+	smphr.notify()
+}
+
+// acquired counts an acquired permit. The lock must be held.
+func (smphr *semaphore) acquired() {
+	smphr.used++
+	if smphr.used > smphr.peak {
+		smphr.peak = smphr.used
+	}
+}
+
+// resetPeak returns the highest number of permits acquired since the previous call, and starts tracking it again from
+// the number of permits currently acquired.
+func (smphr *semaphore) resetPeak() int {
+	smphr.lock.Lock()
+	defer smphr.lock.Unlock()
+	peak := smphr.peak
+	smphr.peak = smphr.used
+	return peak
+}
+
+// notify wakes up the goroutines waiting for a permit. The lock must be held.
+func (smphr *semaphore) notify() {
+	close(smphr.released)
+	smphr.released = make(chan struct{})
+}
+
+// inUse returns the number of permits currently acquired.
+func (smphr *semaphore) inUse() int {
+	smphr.lock.Lock()
+	defer smphr.lock.Unlock()
+	return smphr.used
+}
+
+// available returns the number of permits that can currently be acquired.
+func (smphr *semaphore) available() int {
+	smphr.lock.Lock()
+	defer smphr.lock.Unlock()
+	if smphr.used >= smphr.size {
+		return 0
+	}
+	return smphr.size - smphr.used
 }
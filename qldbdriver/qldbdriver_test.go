@@ -15,13 +15,19 @@ package qldbdriver
 import (
 	"context"
 	"errors"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/amzn/ion-go/ion"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
 	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/aws/smithy-go"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/errs"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -67,7 +73,7 @@ func TestNew(t *testing.T) {
 		assert.Equal(t, createdDriver.maxConcurrentTransactions, defaultMaxConcurrentTransactions)
 		assert.Equal(t, createdDriver.retryPolicy.MaxRetryLimit, defaultRetry)
 		assert.Equal(t, createdDriver.isClosed, false)
-		assert.Equal(t, cap(createdDriver.sessionPool), defaultMaxConcurrentTransactions)
+		assert.Equal(t, cap(createdDriver.sessionPool.(*channelSessionPool).sessions), defaultMaxConcurrentTransactions)
 
 		driverQldbSession := createdDriver.qldbSession
 
@@ -106,6 +112,183 @@ func TestNew(t *testing.T) {
 		qldbSession = nil
 		assert.NotNil(t, driverQldbSession)
 	})
+
+	t.Run("Negative slow transaction threshold error", func(t *testing.T) {
+		cfg, err := config.LoadDefaultConfig(context.TODO())
+		require.NoError(t, err)
+		qldbSession := qldbsession.NewFromConfig(cfg)
+
+		_, err = New(mockLedgerName,
+			qldbSession,
+			func(options *DriverOptions) {
+				options.LoggerVerbosity = LogOff
+				options.SlowTransactionThreshold = -time.Second
+			})
+		assert.Error(t, err)
+	})
+
+	t.Run("LIFO session reuse policy", func(t *testing.T) {
+		cfg, err := config.LoadDefaultConfig(context.TODO())
+		require.NoError(t, err)
+		qldbSession := qldbsession.NewFromConfig(cfg)
+
+		createdDriver, err := New(mockLedgerName,
+			qldbSession,
+			func(options *DriverOptions) {
+				options.LoggerVerbosity = LogOff
+				options.SessionReusePolicy = SessionReuseLIFO
+				options.MaxSessionIdleTime = time.Minute
+			})
+		require.NoError(t, err)
+		assert.IsType(t, &stackSessionPool{}, createdDriver.sessionPool)
+		assert.Equal(t, time.Minute, createdDriver.maxSessionIdleTime)
+	})
+
+	t.Run("Request compression", func(t *testing.T) {
+		cfg, err := config.LoadDefaultConfig(context.TODO())
+		require.NoError(t, err)
+		qldbSession := qldbsession.NewFromConfig(cfg)
+
+		clientOption := func(*qldbsession.Options) {}
+		createdDriver, err := New(mockLedgerName,
+			qldbSession,
+			func(options *DriverOptions) {
+				options.LoggerVerbosity = LogOff
+				options.ClientOptions = []func(*qldbsession.Options){clientOption}
+				options.RequestCompressionMinBytes = 1024
+			})
+		require.NoError(t, err)
+		assert.Len(t, createdDriver.clientOptions, 2)
+
+		_, err = New(mockLedgerName,
+			qldbSession,
+			func(options *DriverOptions) {
+				options.LoggerVerbosity = LogOff
+				options.RequestCompressionMinBytes = -1
+			})
+		assert.Error(t, err)
+	})
+
+	t.Run("Pool exhaustion policy", func(t *testing.T) {
+		cfg, err := config.LoadDefaultConfig(context.TODO())
+		require.NoError(t, err)
+		qldbSession := qldbsession.NewFromConfig(cfg)
+
+		createdDriver, err := New(mockLedgerName,
+			qldbSession,
+			func(options *DriverOptions) {
+				options.LoggerVerbosity = LogOff
+				options.MaxConcurrentTransactions = 5
+				options.PoolExhaustionPolicy = PoolExhaustionGrow
+			})
+		require.NoError(t, err)
+		assert.Equal(t, 10, createdDriver.semaphore.size)
+
+		invalidOptions := []func(*DriverOptions){
+			func(options *DriverOptions) { options.PoolExhaustionPolicy = PoolExhaustionGrow + 1 },
+			func(options *DriverOptions) { options.PoolExhaustionTimeout = -time.Second },
+			func(options *DriverOptions) {
+				options.PoolExhaustionPolicy = PoolExhaustionGrow
+				options.MaxConcurrentTransactions = 5
+				options.MaxBurstTransactions = 4
+			},
+		}
+		for _, invalidOption := range invalidOptions {
+			_, err = New(mockLedgerName, qldbSession, func(options *DriverOptions) {
+				options.LoggerVerbosity = LogOff
+			}, invalidOption)
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("Invalid session reuse policy error", func(t *testing.T) {
+		cfg, err := config.LoadDefaultConfig(context.TODO())
+		require.NoError(t, err)
+		qldbSession := qldbsession.NewFromConfig(cfg)
+
+		_, err = New(mockLedgerName,
+			qldbSession,
+			func(options *DriverOptions) {
+				options.LoggerVerbosity = LogOff
+				options.SessionReusePolicy = SessionReuseLIFO + 1
+			})
+		assert.Error(t, err)
+	})
+
+	t.Run("Negative max session idle time error", func(t *testing.T) {
+		cfg, err := config.LoadDefaultConfig(context.TODO())
+		require.NoError(t, err)
+		qldbSession := qldbsession.NewFromConfig(cfg)
+
+		_, err = New(mockLedgerName,
+			qldbSession,
+			func(options *DriverOptions) {
+				options.LoggerVerbosity = LogOff
+				options.MaxSessionIdleTime = -time.Second
+			})
+		assert.Error(t, err)
+	})
+
+	t.Run("Invalid session refresh options error", func(t *testing.T) {
+		cfg, err := config.LoadDefaultConfig(context.TODO())
+		require.NoError(t, err)
+		qldbSession := qldbsession.NewFromConfig(cfg)
+
+		invalidOptions := []func(*DriverOptions){
+			func(options *DriverOptions) { options.SessionRefreshThreshold = -1 },
+			func(options *DriverOptions) {
+				options.SessionRefreshThreshold = 3
+				options.SessionRefreshWindow = 0
+			},
+			func(options *DriverOptions) {
+				options.SessionRefreshThreshold = 3
+				options.SessionRefreshBatchSize = 0
+			},
+		}
+		for _, invalidOption := range invalidOptions {
+			_, err = New(mockLedgerName, qldbSession, invalidOption)
+			assert.Error(t, err)
+		}
+
+		createdDriver, err := New(mockLedgerName, qldbSession, func(options *DriverOptions) {
+			options.LoggerVerbosity = LogOff
+			options.SessionRefreshThreshold = 3
+		})
+		require.NoError(t, err)
+		require.NotNil(t, createdDriver.sessionRefresher)
+		assert.Equal(t, 10*time.Second, createdDriver.sessionRefresher.window)
+		assert.Equal(t, 5, createdDriver.sessionRefresher.batchSize)
+	})
+
+	t.Run("Negative statement limit error", func(t *testing.T) {
+		cfg, err := config.LoadDefaultConfig(context.TODO())
+		require.NoError(t, err)
+		qldbSession := qldbsession.NewFromConfig(cfg)
+
+		_, err = New(mockLedgerName,
+			qldbSession,
+			func(options *DriverOptions) {
+				options.LoggerVerbosity = LogOff
+				options.StatementLimit = -1
+			})
+		assert.Error(t, err)
+	})
+
+	t.Run("Custom session pool", func(t *testing.T) {
+		cfg, err := config.LoadDefaultConfig(context.TODO())
+		require.NoError(t, err)
+		qldbSession := qldbsession.NewFromConfig(cfg)
+		pool := newChannelSessionPool(1)
+
+		createdDriver, err := New(mockLedgerName,
+			qldbSession,
+			func(options *DriverOptions) {
+				options.LoggerVerbosity = LogOff
+				options.SessionPool = pool
+			})
+		require.NoError(t, err)
+		assert.Equal(t, pool, createdDriver.sessionPool)
+	})
 }
 
 func TestExecute(t *testing.T) {
@@ -116,7 +299,7 @@ func TestExecute(t *testing.T) {
 		logger:                    mockLogger,
 		isClosed:                  false,
 		semaphore:                 makeSemaphore(10),
-		sessionPool:               make(chan *session, 10),
+		sessionPool:               newChannelSessionPool(10),
 		retryPolicy: RetryPolicy{
 			MaxRetryLimit: 4,
 			Backoff: ExponentialBackoffStrategy{
@@ -171,11 +354,80 @@ func TestExecute(t *testing.T) {
 		assert.Nil(t, err)
 	})
 
+	t.Run("slow transaction is logged", func(t *testing.T) {
+		mockSession := new(mockQLDBSession)
+		mockSession.On("SendCommand", mock.Anything, mock.Anything, mock.Anything).Return(&mockSendCommandWithTxID, nil)
+		testDriver.qldbSession = mockSession
+		testLogger := &recordingLogger{}
+		testDriver.logger = &qldbLogger{logger: testLogger, verbosity: LogInfo}
+		testDriver.slowTransactionThreshold = time.Millisecond
+		defer func() {
+			testDriver.logger = mockLogger
+			testDriver.slowTransactionThreshold = 0
+		}()
+
+		_, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+			time.Sleep(2 * time.Millisecond)
+			return nil, nil
+		})
+		require.NoError(t, err)
+		require.Len(t, testLogger.messages, 1)
+		assert.Contains(t, testLogger.messages[0], "Slow transaction detected. Transaction ID: "+mockTxnID+", attempt #1")
+		assert.Contains(t, testLogger.messages[0], "Consumed read IOs: 0, write IOs: 0.")
+	})
+
+	t.Run("fast transaction is not logged", func(t *testing.T) {
+		mockSession := new(mockQLDBSession)
+		mockSession.On("SendCommand", mock.Anything, mock.Anything, mock.Anything).Return(&mockSendCommandWithTxID, nil)
+		testDriver.qldbSession = mockSession
+		testLogger := &recordingLogger{}
+		testDriver.logger = &qldbLogger{logger: testLogger, verbosity: LogInfo}
+		testDriver.slowTransactionThreshold = time.Hour
+		defer func() {
+			testDriver.logger = mockLogger
+			testDriver.slowTransactionThreshold = 0
+		}()
+
+		_, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+			return nil, nil
+		})
+		require.NoError(t, err)
+		assert.Empty(t, testLogger.messages)
+	})
+
+	t.Run("correlation ID and tags are logged", func(t *testing.T) {
+		mockSendCommandWithTxID.CommitTransaction.CommitDigest = []byte{167, 123, 231, 255, 170, 172, 35, 142, 73, 31, 239, 199, 252, 120, 175, 217, 235, 220, 184, 200, 85, 203, 140, 230, 151, 221, 131, 255, 163, 151, 170, 210}
+		isCommit := mock.MatchedBy(func(input *qldbsession.SendCommandInput) bool { return input.CommitTransaction != nil })
+		mockSession := new(mockQLDBSession)
+		mockSession.On("SendCommand", mock.Anything, isCommit, mock.Anything).Return(&mockSendCommandWithTxID, testOCC).Once()
+		mockSession.On("SendCommand", mock.Anything, mock.Anything, mock.Anything).Return(&mockSendCommandWithTxID, nil)
+		testDriver.qldbSession = mockSession
+		testDriver.sessionPool = newChannelSessionPool(10)
+		testDriver.semaphore = makeSemaphore(10)
+		testLogger := &recordingLogger{}
+		testDriver.logger = &qldbLogger{logger: testLogger, verbosity: LogInfo}
+		defer func() {
+			testDriver.logger = mockLogger
+		}()
+
+		_, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+			return nil, nil
+		}, func(options *ExecuteOptions) {
+			options.CorrelationID = "order-42"
+			options.Tags = map[string]string{"tenant": "acme", "env": "test"}
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, testLogger.messages)
+		for _, message := range testLogger.messages {
+			assert.Contains(t, message, "[correlationId=order-42 env=test tenant=acme] ")
+		}
+	})
+
 	t.Run("error get session", func(t *testing.T) {
 		mockSession := new(mockQLDBSession)
 		mockSession.On("SendCommand", mock.Anything, mock.Anything, mock.Anything).Return(&mockDriverSendCommand, errMock)
 		testDriver.qldbSession = mockSession
-		testDriver.sessionPool = make(chan *session, 10)
+		testDriver.sessionPool = newChannelSessionPool(10)
 
 		result, err := testDriver.Execute(context.Background(), nil)
 
@@ -213,7 +465,7 @@ func TestExecute(t *testing.T) {
 		mockSession.On("SendCommand", mock.Anything, abortTransactionRequest, mock.Anything).Return(&mockSendCommandForSession, nil)
 		testDriver.qldbSession = mockSession
 
-		testDriver.sessionPool = make(chan *session, 10)
+		testDriver.sessionPool = newChannelSessionPool(10)
 		testDriver.semaphore = makeSemaphore(10)
 
 		result, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
@@ -237,7 +489,7 @@ func TestExecute(t *testing.T) {
 		mockSession.On("SendCommand", mock.Anything, mock.Anything, mock.Anything).Return(&mockSendCommandWithTxID, nil)
 		testDriver.qldbSession = mockSession
 
-		testDriver.sessionPool = make(chan *session, 10)
+		testDriver.sessionPool = newChannelSessionPool(10)
 		testDriver.semaphore = makeSemaphore(10)
 
 		result, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
@@ -280,7 +532,7 @@ func TestExecute(t *testing.T) {
 
 		testDriver.qldbSession = mockSession
 
-		testDriver.sessionPool = make(chan *session, 10)
+		testDriver.sessionPool = newChannelSessionPool(10)
 		testDriver.semaphore = makeSemaphore(10)
 
 		result, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
@@ -320,7 +572,7 @@ func TestExecute(t *testing.T) {
 
 		testDriver.qldbSession = mockSession
 
-		testDriver.sessionPool = make(chan *session, 10)
+		testDriver.sessionPool = newChannelSessionPool(10)
 		testDriver.semaphore = makeSemaphore(10)
 
 		result, err := testDriver.Execute(context.Background(),
@@ -402,7 +654,7 @@ func TestExecute(t *testing.T) {
 
 		testDriver.qldbSession = mockSession
 
-		testDriver.sessionPool = make(chan *session, 10)
+		testDriver.sessionPool = newChannelSessionPool(10)
 		testDriver.semaphore = makeSemaphore(10)
 
 		result, err := testDriver.Execute(context.Background(),
@@ -416,7 +668,12 @@ func TestExecute(t *testing.T) {
 
 		var ise *types.InvalidSessionException
 		assert.True(t, errors.As(err, &ise))
-		assert.Equal(t, testTxnExpire, err)
+		assert.Equal(t, testTxnExpire, ise)
+
+		var expired *TransactionExpiredError
+		require.True(t, errors.As(err, &expired))
+		assert.Equal(t, mockTxnID, expired.TransactionID)
+		assert.True(t, errs.IsTransactionExpired(err))
 	})
 
 	t.Run("abort transaction on customer error", func(t *testing.T) {
@@ -447,7 +704,7 @@ func TestExecute(t *testing.T) {
 
 		testDriver.qldbSession = mockSession
 
-		testDriver.sessionPool = make(chan *session, 10)
+		testDriver.sessionPool = newChannelSessionPool(10)
 		testDriver.semaphore = makeSemaphore(10)
 
 		result, err := testDriver.Execute(context.Background(),
@@ -492,7 +749,7 @@ func TestExecute(t *testing.T) {
 
 		testDriver.qldbSession = mockSession
 
-		testDriver.sessionPool = make(chan *session, 10)
+		testDriver.sessionPool = newChannelSessionPool(10)
 		testDriver.semaphore = makeSemaphore(10)
 
 		result, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
@@ -507,6 +764,288 @@ func TestExecute(t *testing.T) {
 		assert.Equal(t, expectedTables, result.([]string))
 		assert.NoError(t, err)
 	})
+
+	t.Run("ambiguous commit", func(t *testing.T) {
+		mockSendCommandWithTxID.CommitTransaction.CommitDigest = []byte{167, 123, 231, 255, 170, 172, 35, 142, 73, 31, 239, 199, 252, 120, 175, 217, 235, 220, 184, 200, 85, 203, 140, 230, 151, 221, 131, 255, 163, 151, 170, 210}
+		isCommit := mock.MatchedBy(func(input *qldbsession.SendCommandInput) bool { return input.CommitTransaction != nil })
+		test500error := &InternalFailure{Code: &ErrCodeInternalFailure, Message: &ErrMessageInternalFailure}
+		fn := func(txn Transaction) (interface{}, error) {
+			return nil, nil
+		}
+
+		t.Run("non-idempotent function is not retried", func(t *testing.T) {
+			mockSession := new(mockQLDBSession)
+			mockSession.On("SendCommand", mock.Anything, isCommit, mock.Anything).Return(&mockSendCommandWithTxID, test500error)
+			mockSession.On("SendCommand", mock.Anything, mock.Anything, mock.Anything).Return(&mockSendCommandWithTxID, nil)
+			testDriver.qldbSession = mockSession
+			testDriver.sessionPool = newChannelSessionPool(10)
+			testDriver.semaphore = makeSemaphore(10)
+
+			result, err := testDriver.Execute(context.Background(), fn, func(options *ExecuteOptions) {
+				options.NonIdempotent = true
+			})
+			assert.Nil(t, result)
+			var ambiguousErr *AmbiguousCommitError
+			require.True(t, errors.As(err, &ambiguousErr))
+			assert.Equal(t, mockTxnID, ambiguousErr.TransactionID)
+			assert.True(t, ambiguousErr.CommittedMaybe)
+			assert.Equal(t, test500error, errors.Unwrap(err))
+			mockSession.AssertNumberOfCalls(t, "SendCommand", 4)
+			// Session was returned to the pool and the permit released
+			assert.Equal(t, 1, len(testDriver.sessionPool.(*channelSessionPool).sessions))
+			assert.Equal(t, 10, testDriver.semaphore.available())
+		})
+
+		t.Run("idempotent function is retried", func(t *testing.T) {
+			mockSession := new(mockQLDBSession)
+			mockSession.On("SendCommand", mock.Anything, isCommit, mock.Anything).Return(&mockSendCommandWithTxID, test500error).Once()
+			mockSession.On("SendCommand", mock.Anything, mock.Anything, mock.Anything).Return(&mockSendCommandWithTxID, nil)
+			testDriver.qldbSession = mockSession
+			testDriver.sessionPool = newChannelSessionPool(10)
+			testDriver.semaphore = makeSemaphore(10)
+
+			_, err := testDriver.Execute(context.Background(), fn)
+			assert.NoError(t, err)
+		})
+
+		t.Run("committed transaction is not retried", func(t *testing.T) {
+			mockSession := new(mockQLDBSession)
+			mockSession.On("SendCommand", mock.Anything, isCommit, mock.Anything).Return(&mockSendCommandWithTxID, test500error).Once()
+			mockSession.On("SendCommand", mock.Anything, mock.Anything, mock.Anything).Return(&mockSendCommandWithTxID, nil)
+			testDriver.qldbSession = mockSession
+			testDriver.sessionPool = newChannelSessionPool(10)
+			testDriver.semaphore = makeSemaphore(10)
+
+			calls := 0
+			var verifiedID string
+			result, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+				calls++
+				return "result", nil
+			}, func(options *ExecuteOptions) {
+				options.NonIdempotent = true
+				options.VerifyCommit = func(txn Transaction, transactionID string) (bool, error) {
+					verifiedID = transactionID
+					return true, nil
+				}
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, "result", result)
+			assert.Equal(t, 1, calls)
+			assert.Equal(t, mockTxnID, verifiedID)
+			assert.Equal(t, 1, len(testDriver.sessionPool.(*channelSessionPool).sessions))
+			assert.Equal(t, 10, testDriver.semaphore.available())
+		})
+
+		t.Run("transaction verified as not committed is retried", func(t *testing.T) {
+			mockSession := new(mockQLDBSession)
+			mockSession.On("SendCommand", mock.Anything, isCommit, mock.Anything).Return(&mockSendCommandWithTxID, test500error).Once()
+			mockSession.On("SendCommand", mock.Anything, mock.Anything, mock.Anything).Return(&mockSendCommandWithTxID, nil)
+			testDriver.qldbSession = mockSession
+			testDriver.sessionPool = newChannelSessionPool(10)
+			testDriver.semaphore = makeSemaphore(10)
+
+			calls := 0
+			_, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+				calls++
+				return nil, nil
+			}, func(options *ExecuteOptions) {
+				options.NonIdempotent = true
+				options.VerifyCommit = func(txn Transaction, transactionID string) (bool, error) {
+					return false, nil
+				}
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, 2, calls)
+		})
+
+		t.Run("failed verification stops non-idempotent function", func(t *testing.T) {
+			mockSession := new(mockQLDBSession)
+			mockSession.On("SendCommand", mock.Anything, isCommit, mock.Anything).Return(&mockSendCommandWithTxID, test500error).Once()
+			mockSession.On("SendCommand", mock.Anything, mock.Anything, mock.Anything).Return(&mockSendCommandWithTxID, nil)
+			testDriver.qldbSession = mockSession
+			testDriver.sessionPool = newChannelSessionPool(10)
+			testDriver.semaphore = makeSemaphore(10)
+
+			_, err := testDriver.Execute(context.Background(), fn, func(options *ExecuteOptions) {
+				options.NonIdempotent = true
+				options.VerifyCommit = func(txn Transaction, transactionID string) (bool, error) {
+					return false, errors.New("verification failed")
+				}
+			})
+			var ambiguousErr *AmbiguousCommitError
+			require.True(t, errors.As(err, &ambiguousErr))
+			assert.True(t, ambiguousErr.CommittedMaybe)
+			assert.Equal(t, test500error, errors.Unwrap(err))
+		})
+
+		t.Run("exhausted retries", func(t *testing.T) {
+			defer func(limit int) { testDriver.retryPolicy.MaxRetryLimit = limit }(testDriver.retryPolicy.MaxRetryLimit)
+			testDriver.retryPolicy.MaxRetryLimit = 1
+
+			t.Run("unverified commit", func(t *testing.T) {
+				mockSession := new(mockQLDBSession)
+				mockSession.On("SendCommand", mock.Anything, isCommit, mock.Anything).Return(&mockSendCommandWithTxID, test500error)
+				mockSession.On("SendCommand", mock.Anything, mock.Anything, mock.Anything).Return(&mockSendCommandWithTxID, nil)
+				testDriver.qldbSession = mockSession
+				testDriver.sessionPool = newChannelSessionPool(10)
+				testDriver.semaphore = makeSemaphore(10)
+
+				_, err := testDriver.Execute(context.Background(), fn)
+				var ambiguousErr *AmbiguousCommitError
+				require.True(t, errors.As(err, &ambiguousErr))
+				assert.Equal(t, mockTxnID, ambiguousErr.TransactionID)
+				assert.True(t, ambiguousErr.CommittedMaybe)
+			})
+
+			t.Run("commit verified as not committed", func(t *testing.T) {
+				mockSession := new(mockQLDBSession)
+				mockSession.On("SendCommand", mock.Anything, isCommit, mock.Anything).Return(&mockSendCommandWithTxID, test500error).Once()
+				mockSession.On("SendCommand", mock.Anything, isCommit, mock.Anything).Return(&mockSendCommandWithTxID, nil).Once()
+				mockSession.On("SendCommand", mock.Anything, isCommit, mock.Anything).Return(&mockSendCommandWithTxID, test500error).Once()
+				mockSession.On("SendCommand", mock.Anything, mock.Anything, mock.Anything).Return(&mockSendCommandWithTxID, nil)
+				testDriver.qldbSession = mockSession
+				testDriver.sessionPool = newChannelSessionPool(10)
+				testDriver.semaphore = makeSemaphore(10)
+
+				_, err := testDriver.Execute(context.Background(), fn, func(options *ExecuteOptions) {
+					options.VerifyCommit = func(txn Transaction, transactionID string) (bool, error) {
+						return false, nil
+					}
+				})
+				var ambiguousErr *AmbiguousCommitError
+				require.True(t, errors.As(err, &ambiguousErr))
+				assert.False(t, ambiguousErr.CommittedMaybe)
+				assert.Equal(t, test500error, errors.Unwrap(err))
+			})
+		})
+	})
+}
+
+func TestExecuteOnRetry(t *testing.T) {
+	newTestDriver := func() (*QLDBDriver, *mockQLDBSession) {
+		isStartTransaction := mock.MatchedBy(func(input *qldbsession.SendCommandInput) bool { return input.StartTransaction != nil })
+		mockSession := new(mockQLDBSession)
+		mockSession.On("SendCommand", mock.Anything, isStartTransaction, mock.Anything).Return(&mockDriverSendCommand, testOCC)
+		mockSession.On("SendCommand", mock.Anything, mock.Anything, mock.Anything).Return(&mockDriverSendCommand, nil)
+		return newMockDriver(t, mockSession, func(options *DriverOptions) {
+			options.RetryPolicy = RetryPolicy{
+				MaxRetryLimit: 3,
+				Backoff:       ExponentialBackoffStrategy{SleepBase: time.Millisecond, SleepCap: time.Millisecond}}
+		}), mockSession
+	}
+	noop := func(txn Transaction) (interface{}, error) { return nil, nil }
+
+	t.Run("called before each retry", func(t *testing.T) {
+		testDriver, _ := newTestDriver()
+		var attempts []int
+		testDriver.retryPolicy.OnRetry = func(attempt int, err error, nextDelay time.Duration) error {
+			attempts = append(attempts, attempt)
+			assert.Equal(t, testOCC, err)
+			assert.True(t, nextDelay <= time.Millisecond)
+			return nil
+		}
+
+		_, err := testDriver.Execute(context.Background(), noop)
+		assert.Equal(t, testOCC, err)
+		assert.Equal(t, []int{1, 2, 3}, attempts)
+		assert.Equal(t, 10, testDriver.semaphore.available())
+	})
+
+	t.Run("give up", func(t *testing.T) {
+		testDriver, mockSession := newTestDriver()
+		errGiveUp := errors.New("give up")
+		testDriver.retryPolicy.OnRetry = func(attempt int, err error, nextDelay time.Duration) error {
+			if attempt == 2 {
+				return errGiveUp
+			}
+			return nil
+		}
+
+		_, err := testDriver.Execute(context.Background(), noop)
+		assert.Equal(t, errGiveUp, err)
+		startTransactions := 0
+		for _, call := range mockSession.Calls {
+			if call.Arguments.Get(1).(*qldbsession.SendCommandInput).StartTransaction != nil {
+				startTransactions++
+			}
+		}
+		assert.Equal(t, 2, startTransactions)
+		assert.Equal(t, 10, testDriver.semaphore.available())
+	})
+
+	t.Run("execute options take precedence", func(t *testing.T) {
+		testDriver, _ := newTestDriver()
+		testDriver.retryPolicy.OnRetry = func(attempt int, err error, nextDelay time.Duration) error {
+			t.Error("RetryPolicy.OnRetry should not be called")
+			return nil
+		}
+		errGiveUp := errors.New("give up")
+
+		_, err := testDriver.Execute(context.Background(), noop, func(options *ExecuteOptions) {
+			options.OnRetry = func(attempt int, err error, nextDelay time.Duration) error {
+				return errGiveUp
+			}
+		})
+		assert.Equal(t, errGiveUp, err)
+	})
+
+	t.Run("give up after invalid initial session clears report", func(t *testing.T) {
+		testISE := &types.InvalidSessionException{Code: &ErrCodeInvalidSessionException, Message: &ErrMessageInvalidSessionException}
+		isStartTransaction := mock.MatchedBy(func(input *qldbsession.SendCommandInput) bool { return input.StartTransaction != nil })
+		mockSession := new(mockQLDBSession)
+		mockSession.On("SendCommand", mock.Anything, isStartTransaction, mock.Anything).Return(&mockDriverSendCommand, testISE)
+		mockSession.On("SendCommand", mock.Anything, mock.Anything, mock.Anything).Return(&mockDriverSendCommand, nil)
+		testDriver, _ := newTestDriver()
+		testDriver.qldbSession = mockSession
+		errGiveUp := errors.New("give up")
+		report := TransactionReport{TransactionID: "previous"}
+
+		_, err := testDriver.Execute(context.Background(), noop, func(options *ExecuteOptions) {
+			options.Report = &report
+			options.OnRetry = func(attempt int, err error, nextDelay time.Duration) error {
+				return errGiveUp
+			}
+		})
+		assert.Equal(t, errGiveUp, err)
+		assert.Equal(t, TransactionReport{}, report)
+		assert.Equal(t, 10, testDriver.semaphore.available())
+	})
+}
+
+func TestValidate(t *testing.T) {
+	newTestDriver := func(mockSession *mockQLDBSession) *QLDBDriver {
+		return newMockDriver(t, mockSession)
+	}
+
+	t.Run("success keeps session in pool", func(t *testing.T) {
+		mockSession := new(mockQLDBSession)
+		mockSession.On("SendCommand", mock.Anything, mock.Anything, mock.Anything).Return(&mockDriverSendCommand, nil)
+		testDriver := newTestDriver(mockSession)
+
+		assert.NoError(t, testDriver.Validate(context.Background()))
+		assert.Equal(t, 1, len(testDriver.sessionPool.(*channelSessionPool).sessions))
+		assert.Equal(t, 10, testDriver.semaphore.available())
+	})
+
+	t.Run("ledger unavailable", func(t *testing.T) {
+		mockSession := new(mockQLDBSession)
+		mockSession.On("SendCommand", mock.Anything, mock.Anything, mock.Anything).Return(&mockDriverSendCommand, testBadReq)
+		testDriver := newTestDriver(mockSession)
+
+		err := testDriver.Validate(context.Background())
+		var lue *LedgerUnavailableError
+		require.True(t, errors.As(err, &lue))
+		assert.Equal(t, mockLedgerName, lue.LedgerName)
+		assert.Equal(t, testBadReq, errors.Unwrap(err))
+		assert.Equal(t, 10, testDriver.semaphore.available())
+	})
+
+	t.Run("closed driver", func(t *testing.T) {
+		testDriver := newTestDriver(new(mockQLDBSession))
+		testDriver.isClosed = true
+
+		assert.Error(t, testDriver.Validate(context.Background()))
+	})
 }
 
 func TestGetTableNames(t *testing.T) {
@@ -517,7 +1056,7 @@ func TestGetTableNames(t *testing.T) {
 		logger:                    mockLogger,
 		isClosed:                  false,
 		semaphore:                 makeSemaphore(10),
-		sessionPool:               make(chan *session, 10),
+		sessionPool:               newChannelSessionPool(10),
 		retryPolicy: RetryPolicy{
 			MaxRetryLimit: 10,
 			Backoff: ExponentialBackoffStrategy{
@@ -574,6 +1113,55 @@ func TestGetTableNames(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, expectedTables, result)
 	})
+
+	t.Run("cached", func(t *testing.T) {
+		tableBinary, _ := ion.MarshalBinary(map[string]string{"name": "table1"})
+		mockClient := &qldbsessioniface.MockClientAPI{
+			SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+				if params.ExecuteStatement != nil {
+					return &qldbsession.SendCommandOutput{ExecuteStatement: &types.ExecuteStatementResult{
+						FirstPage: &types.Page{Values: []types.ValueHolder{{IonBinary: tableBinary}}},
+					}}, nil
+				}
+				return qldbsessioniface.DefaultSendCommandOutput(params), nil
+			},
+		}
+		cachingDriver := newMockDriver(t, mockClient, func(options *DriverOptions) {
+			options.TableNamesCacheTTL = time.Minute
+		})
+		statementCount := func() int {
+			count := 0
+			for _, input := range mockClient.Inputs() {
+				if input.ExecuteStatement != nil {
+					count++
+				}
+			}
+			return count
+		}
+
+		for i := 0; i < 2; i++ {
+			result, err := cachingDriver.GetTableNames(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, []string{"table1"}, result)
+		}
+		assert.Equal(t, 1, statementCount())
+
+		result, _ := cachingDriver.GetTableNames(context.Background())
+		result[0] = "modified"
+		result, _ = cachingDriver.GetTableNames(context.Background())
+		assert.Equal(t, []string{"table1"}, result)
+		assert.Equal(t, 1, statementCount())
+
+		cachingDriver.InvalidateTableNames()
+		_, err := cachingDriver.GetTableNames(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 2, statementCount())
+
+		cachingDriver.tableNamesExpiry = time.Now().Add(-time.Second)
+		_, err = cachingDriver.GetTableNames(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 3, statementCount())
+	})
 }
 
 func TestShutdownDriver(t *testing.T) {
@@ -584,7 +1172,7 @@ func TestShutdownDriver(t *testing.T) {
 		logger:                    mockLogger,
 		isClosed:                  false,
 		semaphore:                 nil,
-		sessionPool:               make(chan *session, 10),
+		sessionPool:               newChannelSessionPool(10),
 		retryPolicy: RetryPolicy{
 			MaxRetryLimit: 10,
 			Backoff: ExponentialBackoffStrategy{
@@ -595,7 +1183,7 @@ func TestShutdownDriver(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		testDriver.Shutdown(context.Background())
 		assert.Equal(t, testDriver.isClosed, true)
-		_, ok := <-testDriver.sessionPool
+		_, ok := <-testDriver.sessionPool.(*channelSessionPool).sessions
 		assert.Equal(t, ok, false)
 	})
 
@@ -609,7 +1197,7 @@ func TestGetSession(t *testing.T) {
 		logger:                    mockLogger,
 		isClosed:                  false,
 		semaphore:                 makeSemaphore(10),
-		sessionPool:               make(chan *session, 10),
+		sessionPool:               newChannelSessionPool(10),
 		retryPolicy: RetryPolicy{
 			MaxRetryLimit: 10,
 			Backoff: ExponentialBackoffStrategy{
@@ -623,7 +1211,7 @@ func TestGetSession(t *testing.T) {
 		mockSession.On("SendCommand", mock.Anything, mock.Anything, mock.Anything).Return(&mockDriverSendCommand, errMock)
 		testDriver.qldbSession = mockSession
 
-		session, err := testDriver.getSession(context.Background())
+		session, err := testDriver.getSession(context.Background(), nil)
 
 		assert.Equal(t, err, errMock)
 		assert.Nil(t, session)
@@ -634,7 +1222,7 @@ func TestGetSession(t *testing.T) {
 		mockSession.On("SendCommand", mock.Anything, mock.Anything, mock.Anything).Return(&mockDriverSendCommand, nil)
 		testDriver.qldbSession = mockSession
 
-		session, err := testDriver.getSession(context.Background())
+		session, err := testDriver.getSession(context.Background(), nil)
 
 		assert.NoError(t, err)
 		assert.Equal(t, &mockSessionToken, session.communicator.(*communicator).sessionToken)
@@ -649,20 +1237,160 @@ func TestGetSession(t *testing.T) {
 			logger:       mockLogger,
 		}
 
-		session1 := &session{&testCommunicator, mockLogger}
-		session2 := &session{&testCommunicator, mockLogger}
+		session1 := &session{communicator: &testCommunicator, logger: mockLogger}
+		session2 := &session{communicator: &testCommunicator, logger: mockLogger}
 
-		testDriver.sessionPool <- session1
-		testDriver.sessionPool <- session2
+		testDriver.sessionPool.Put(&PooledSession{session: session1})
+		testDriver.sessionPool.Put(&PooledSession{session: session2})
 
 		mockSession.On("SendCommand", mock.Anything, mock.Anything, mock.Anything).Return(&mockDriverSendCommand, errMock)
 
 		testDriver.qldbSession = mockSession
 
-		session, err := testDriver.getSession(context.Background())
+		session, err := testDriver.getSession(context.Background(), nil)
 		assert.NoError(t, err)
 		assert.Equal(t, &mockSessionToken, session.communicator.(*communicator).sessionToken)
 	})
+
+	t.Run("idle session exceeding max idle time is discarded", func(t *testing.T) {
+		testDriver.sessionPool = newChannelSessionPool(10)
+		testDriver.semaphore = makeSemaphore(10)
+		testDriver.maxSessionIdleTime = time.Minute
+		defer func() { testDriver.maxSessionIdleTime = 0 }()
+
+		staleService := new(mockTransactionService)
+		ended := make(chan struct{})
+		staleService.On("endSession", mock.Anything).Return(&types.EndSessionResult{}, nil).Run(func(args mock.Arguments) { close(ended) })
+		staleSession := &session{communicator: staleService, logger: mockLogger}
+		freshSession := &session{logger: mockLogger}
+		testDriver.sessionPool.Put(&PooledSession{session: staleSession, idleSince: time.Now().Add(-time.Hour)})
+		testDriver.sessionPool.Put(&PooledSession{session: freshSession, idleSince: time.Now()})
+
+		session, err := testDriver.getSession(context.Background(), nil)
+		assert.NoError(t, err)
+		assert.Same(t, freshSession, session)
+		// The discarded session is ended, so that it does not count against the sessions of the ledger
+		select {
+		case <-ended:
+		case <-time.After(time.Second):
+			assert.Fail(t, "The discarded session was not ended.")
+		}
+	})
+}
+
+func TestRefreshIdleSessions(t *testing.T) {
+	newTestDriver := func() *QLDBDriver {
+		mockSession := new(mockQLDBSession)
+		mockSession.On("SendCommand", mock.Anything, mock.Anything, mock.Anything).Return(&mockDriverSendCommand, nil)
+		testDriver := newMockDriver(t, mockSession, func(options *DriverOptions) {
+			options.MaxConcurrentTransactions = 3
+			options.SessionRefreshThreshold = 1
+			options.SessionRefreshWindow = time.Second
+			options.SessionRefreshBatchSize = 2
+		})
+		// The tests start the refresh themselves
+		testDriver.sessionRefresher.isRefreshing = true
+		return testDriver
+	}
+	var ended int32
+	staleService := new(mockTransactionService)
+	staleService.On("endSession", mock.Anything).Return(&types.EndSessionResult{}, nil).Run(func(args mock.Arguments) {
+		atomic.AddInt32(&ended, 1)
+	})
+	staleSessions := []*session{
+		{communicator: staleService, logger: mockLogger},
+		{communicator: staleService, logger: mockLogger},
+		{communicator: staleService, logger: mockLogger},
+	}
+
+	t.Run("idle sessions are replaced", func(t *testing.T) {
+		testDriver := newTestDriver()
+		for _, staleSession := range staleSessions {
+			testDriver.sessionPool.Put(&PooledSession{session: staleSession, idleSince: time.Now().Add(-time.Minute)})
+		}
+
+		testDriver.refreshIdleSessions(context.Background())
+		// The replaced sessions are ended
+		assert.Eventually(t, func() bool { return atomic.LoadInt32(&ended) == 3 }, time.Second, time.Millisecond)
+
+		sessions := testDriver.sessionPool.Close()
+		require.Len(t, sessions, 3)
+		for _, pooledSession := range sessions {
+			assert.NotContains(t, staleSessions, pooledSession.session)
+		}
+		assert.Equal(t, 3, testDriver.semaphore.available())
+		assert.False(t, testDriver.sessionRefresher.isRefreshing)
+	})
+
+	t.Run("sessions returned after the refresh started are kept", func(t *testing.T) {
+		testDriver := newTestDriver()
+		freshSession := &session{logger: mockLogger}
+		testDriver.sessionPool.Put(&PooledSession{session: freshSession, idleSince: time.Now().Add(time.Minute)})
+
+		testDriver.refreshIdleSessions(context.Background())
+
+		sessions := testDriver.sessionPool.Close()
+		require.Len(t, sessions, 1)
+		assert.Same(t, freshSession, sessions[0].session)
+		assert.Equal(t, 3, testDriver.semaphore.available())
+	})
+
+	t.Run("session that no longer fits the pool is ended", func(t *testing.T) {
+		testDriver := newTestDriver()
+		freshService := new(mockTransactionService)
+		freshEnded := make(chan struct{})
+		freshService.On("endSession", mock.Anything).Return(&types.EndSessionResult{}, nil).Run(func(args mock.Arguments) {
+			close(freshEnded)
+		})
+		freshSession := &session{communicator: freshService, logger: mockLogger}
+		// The pool is shrunk, as by pool scaling, after the refresh has taken the session
+		pool := &shrinkingSessionPool{channelSessionPool: newChannelSessionPool(3)}
+		pool.Put(&PooledSession{session: freshSession, idleSince: time.Now().Add(time.Minute)})
+		testDriver.sessionPool = pool
+
+		refreshed := make(chan struct{})
+		go func() {
+			testDriver.refreshIdleSessions(context.Background())
+			close(refreshed)
+		}()
+		select {
+		case <-refreshed:
+		case <-time.After(time.Second):
+			require.Fail(t, "The refresh blocked on the shrunk pool.")
+		}
+		select {
+		case <-freshEnded:
+		case <-time.After(time.Second):
+			assert.Fail(t, "The session that no longer fits the pool was not ended.")
+		}
+		assert.Nil(t, testDriver.sessionPool.Get())
+		assert.Equal(t, 3, testDriver.semaphore.available())
+		// Shutdown is not blocked by the refresh
+		testDriver.Shutdown(context.Background())
+	})
+
+	t.Run("refresh stops when no permit is available", func(t *testing.T) {
+		testDriver := newTestDriver()
+		testDriver.semaphore = makeSemaphore(0)
+		testDriver.sessionPool.Put(&PooledSession{session: staleSessions[0], idleSince: time.Now().Add(-time.Minute)})
+
+		testDriver.refreshIdleSessions(context.Background())
+
+		sessions := testDriver.sessionPool.Close()
+		require.Len(t, sessions, 1)
+		assert.Same(t, staleSessions[0], sessions[0].session)
+	})
+}
+
+// shrinkingSessionPool is a channelSessionPool which is resized to 0 whenever a session is taken from it.
+type shrinkingSessionPool struct {
+	*channelSessionPool
+}
+
+func (pool *shrinkingSessionPool) Get() *PooledSession {
+	session := pool.channelSessionPool.Get()
+	pool.resize(0)
+	return session
 }
 
 func TestSessionPoolCapacity(t *testing.T) {
@@ -674,7 +1402,7 @@ func TestSessionPoolCapacity(t *testing.T) {
 			logger:                    mockLogger,
 			isClosed:                  false,
 			semaphore:                 makeSemaphore(2),
-			sessionPool:               make(chan *session, 2),
+			sessionPool:               newChannelSessionPool(2),
 			retryPolicy: RetryPolicy{
 				MaxRetryLimit: 10,
 				Backoff: ExponentialBackoffStrategy{
@@ -687,23 +1415,23 @@ func TestSessionPoolCapacity(t *testing.T) {
 		mockSession.On("SendCommand", mock.Anything, mock.Anything, mock.Anything).Return(&mockDriverSendCommand, nil)
 		testDriver.qldbSession = mockSession
 
-		session1, err := testDriver.getSession(context.Background())
+		session1, err := testDriver.getSession(context.Background(), nil)
 		assert.NoError(t, err)
 		assert.NotNil(t, session1)
 
-		session2, err := testDriver.getSession(context.Background())
+		session2, err := testDriver.getSession(context.Background(), nil)
 		assert.NoError(t, err)
 		assert.NotNil(t, session2)
 
-		session3, err := testDriver.getSession(context.Background())
+		session3, err := testDriver.getSession(context.Background(), nil)
 		assert.Error(t, err)
 		assert.Nil(t, session3)
 		qldbErr := err.(*qldbDriverError)
 		assert.Error(t, qldbErr)
 
-		testDriver.releaseSession(session1)
+		testDriver.releaseSession(context.Background(), session1)
 
-		session4, err := testDriver.getSession(context.Background())
+		session4, err := testDriver.getSession(context.Background(), nil)
 		assert.NoError(t, err)
 		assert.NotNil(t, session4)
 	})
@@ -718,7 +1446,7 @@ func TestCreateSession(t *testing.T) {
 		logger:                    mockLogger,
 		isClosed:                  false,
 		semaphore:                 makeSemaphore(10),
-		sessionPool:               make(chan *session, 10),
+		sessionPool:               newChannelSessionPool(10),
 		retryPolicy: RetryPolicy{
 			MaxRetryLimit: 10,
 			Backoff: ExponentialBackoffStrategy{
@@ -732,7 +1460,7 @@ func TestCreateSession(t *testing.T) {
 		testDriver.qldbSession = mockSession
 
 		testDriver.semaphore.tryAcquire()
-		session, err := testDriver.createSession(context.Background())
+		session, err := testDriver.createSession(context.Background(), nil)
 
 		assert.Nil(t, session)
 		assert.Equal(t, errMock, err)
@@ -743,7 +1471,7 @@ func TestCreateSession(t *testing.T) {
 		mockSession.On("SendCommand", mock.Anything, mock.Anything, mock.Anything).Return(&mockDriverSendCommand, nil)
 		testDriver.qldbSession = mockSession
 
-		session, err := testDriver.createSession(context.Background())
+		session, err := testDriver.createSession(context.Background(), nil)
 
 		assert.NoError(t, err)
 		assert.Equal(t, &mockSessionToken, session.communicator.(*communicator).sessionToken)
@@ -783,3 +1511,479 @@ var mockDriverSendCommand = qldbsession.SendCommandOutput{
 	StartSession:      &mockDriverStartSession,
 	StartTransaction:  &mockDriverStartTransaction,
 }
+
+func TestPoolExhaustionPolicy(t *testing.T) {
+	newTestDriver := func(policy PoolExhaustionPolicy, permits int) *QLDBDriver {
+		return newMockDriver(t, &qldbsessioniface.MockClientAPI{}, func(options *DriverOptions) {
+			options.MaxConcurrentTransactions = 1
+			options.PoolExhaustionPolicy = policy
+			options.MaxBurstTransactions = permits
+		})
+	}
+	noop := func(txn Transaction) (interface{}, error) { return nil, nil }
+	nested := func(testDriver *QLDBDriver, ctx context.Context) func(txn Transaction) (interface{}, error) {
+		return func(txn Transaction) (interface{}, error) {
+			return testDriver.Execute(ctx, noop)
+		}
+	}
+
+	t.Run("fail", func(t *testing.T) {
+		testDriver := newTestDriver(PoolExhaustionFail, 1)
+		_, err := testDriver.Execute(context.Background(), nested(testDriver, context.Background()))
+		var driverErr *qldbDriverError
+		require.True(t, errors.As(err, &driverErr))
+		assert.Equal(t, 1, testDriver.semaphore.available())
+	})
+
+	t.Run("block until timeout", func(t *testing.T) {
+		testDriver := newTestDriver(PoolExhaustionBlock, 1)
+		testDriver.poolExhaustionTimeout = 10 * time.Millisecond
+		_, err := testDriver.Execute(context.Background(), nested(testDriver, context.Background()))
+		var driverErr *qldbDriverError
+		require.True(t, errors.As(err, &driverErr))
+		assert.Equal(t, 1, testDriver.semaphore.available())
+	})
+
+	t.Run("block until context is done", func(t *testing.T) {
+		testDriver := newTestDriver(PoolExhaustionBlock, 1)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := testDriver.Execute(context.Background(), nested(testDriver, ctx))
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.Equal(t, 1, testDriver.semaphore.available())
+	})
+
+	t.Run("block until transaction completes", func(t *testing.T) {
+		testDriver := newTestDriver(PoolExhaustionBlock, 1)
+		testDriver.poolExhaustionTimeout = 5 * time.Second
+		started := make(chan struct{})
+		done := make(chan error)
+		go func() {
+			_, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+				close(started)
+				time.Sleep(20 * time.Millisecond)
+				return nil, nil
+			})
+			done <- err
+		}()
+		<-started
+
+		_, err := testDriver.Execute(context.Background(), noop)
+		require.NoError(t, err)
+		require.NoError(t, <-done)
+		assert.Equal(t, 1, testDriver.semaphore.available())
+	})
+
+	t.Run("grow", func(t *testing.T) {
+		testDriver := newTestDriver(PoolExhaustionGrow, 2)
+		_, err := testDriver.Execute(context.Background(), nested(testDriver, context.Background()))
+		require.NoError(t, err)
+		assert.Equal(t, 2, testDriver.semaphore.available())
+		assert.Equal(t, 1, len(testDriver.sessionPool.(*channelSessionPool).sessions))
+		assert.Equal(t, int64(2), testDriver.SessionAcquisitionStats().Created)
+
+		_, err = testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+			return testDriver.Execute(context.Background(), nested(testDriver, context.Background()))
+		})
+		var driverErr *qldbDriverError
+		require.True(t, errors.As(err, &driverErr))
+		assert.Equal(t, 2, testDriver.semaphore.available())
+	})
+}
+
+func TestExecuteMaxElapsedTime(t *testing.T) {
+	newTestDriver := func(stalled func(params *qldbsession.SendCommandInput) bool) (*QLDBDriver, *qldbsessioniface.MockClientAPI) {
+		mockClient := &qldbsessioniface.MockClientAPI{
+			SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+				if stalled(params) {
+					<-ctx.Done()
+					return nil, ctx.Err()
+				}
+				return qldbsessioniface.DefaultSendCommandOutput(params), nil
+			},
+		}
+		return newMockDriver(t, mockClient, func(options *DriverOptions) {
+			options.RetryPolicy = RetryPolicy{
+				MaxRetryLimit:  3,
+				Backoff:        ExponentialBackoffStrategy{SleepBase: time.Millisecond, SleepCap: time.Millisecond},
+				MaxElapsedTime: 400 * time.Millisecond,
+			}
+		}), mockClient
+	}
+	execute := func(txn Transaction) (interface{}, error) {
+		_, err := txn.Execute("SELECT * FROM Person")
+		return nil, err
+	}
+	commandCount := func(mockClient *qldbsessioniface.MockClientAPI, isCommand func(params *qldbsession.SendCommandInput) bool) int {
+		count := 0
+		for _, input := range mockClient.Inputs() {
+			if isCommand(input) {
+				count++
+			}
+		}
+		return count
+	}
+	isExecuteStatement := func(params *qldbsession.SendCommandInput) bool { return params.ExecuteStatement != nil }
+	isCommitTransaction := func(params *qldbsession.SendCommandInput) bool { return params.CommitTransaction != nil }
+
+	t.Run("stalled attempt is retried", func(t *testing.T) {
+		var stalledOnce int32
+		testDriver, mockClient := newTestDriver(func(params *qldbsession.SendCommandInput) bool {
+			return params.ExecuteStatement != nil && atomic.CompareAndSwapInt32(&stalledOnce, 0, 1)
+		})
+
+		start := time.Now()
+		_, err := testDriver.Execute(context.Background(), execute)
+		require.NoError(t, err)
+		assert.True(t, time.Since(start) < 400*time.Millisecond)
+		assert.Equal(t, 2, commandCount(mockClient, isExecuteStatement))
+		assert.Equal(t, 10, testDriver.semaphore.available())
+	})
+
+	t.Run("retries stop when time has elapsed", func(t *testing.T) {
+		testDriver, _ := newTestDriver(isExecuteStatement)
+
+		start := time.Now()
+		_, err := testDriver.Execute(context.Background(), execute)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.True(t, time.Since(start) >= 400*time.Millisecond)
+		assert.Equal(t, 10, testDriver.semaphore.available())
+	})
+
+	t.Run("stalled commit is ambiguous", func(t *testing.T) {
+		var stalledOnce int32
+		testDriver, mockClient := newTestDriver(func(params *qldbsession.SendCommandInput) bool {
+			return params.CommitTransaction != nil && atomic.CompareAndSwapInt32(&stalledOnce, 0, 1)
+		})
+
+		_, err := testDriver.Execute(context.Background(), execute, func(options *ExecuteOptions) {
+			options.NonIdempotent = true
+		})
+		var ambiguousErr *AmbiguousCommitError
+		require.True(t, errors.As(err, &ambiguousErr))
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.Equal(t, 1, commandCount(mockClient, isCommitTransaction))
+	})
+
+	t.Run("no deadline without MaxElapsedTime", func(t *testing.T) {
+		testDriver, _ := newTestDriver(func(params *qldbsession.SendCommandInput) bool { return false })
+		testDriver.retryPolicy.MaxElapsedTime = 0
+
+		_, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+			executor := txn.(*transactionExecutor)
+			_, hasDeadline := executor.ctx.Deadline()
+			assert.False(t, hasDeadline)
+			return nil, nil
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestExecuteReturningResult(t *testing.T) {
+	newDriver := func() *QLDBDriver {
+		pageToken := "token"
+		return newMockDriver(t, &qldbsessioniface.MockClientAPI{
+			SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+				output := qldbsessioniface.DefaultSendCommandOutput(params)
+				switch {
+				case params.ExecuteStatement != nil:
+					output.ExecuteStatement.FirstPage = &types.Page{
+						Values:        []types.ValueHolder{{IonBinary: ionTextToBinary(t, "1")}},
+						NextPageToken: &pageToken,
+					}
+				case params.FetchPage != nil:
+					output.FetchPage.Page = &types.Page{Values: []types.ValueHolder{{IonBinary: ionTextToBinary(t, "2")}}}
+				}
+				return output, nil
+			},
+		}, func(options *DriverOptions) {
+			options.RetryPolicy = RetryPolicy{MaxRetryLimit: 4}
+		})
+	}
+	executeSelect := func(txn Transaction) (interface{}, error) {
+		return txn.Execute("SELECT * FROM T")
+	}
+
+	t.Run("read after commit", func(t *testing.T) {
+		result, err := newDriver().Execute(context.Background(), executeSelect)
+		require.NoError(t, err)
+
+		streaming, ok := result.(Result)
+		require.True(t, ok)
+		assert.True(t, streaming.Next(nil))
+		assert.False(t, streaming.Next(nil))
+		var streamingErr *StreamingResultError
+		require.ErrorAs(t, streaming.Err(), &streamingErr)
+		assert.Equal(t, qldbsessioniface.MockTransactionID, streamingErr.TransactionID)
+	})
+
+	t.Run("buffer result", func(t *testing.T) {
+		result, err := newDriver().Execute(context.Background(), executeSelect, func(options *ExecuteOptions) {
+			options.BufferResult = true
+		})
+		require.NoError(t, err)
+
+		buffered, ok := result.(BufferedResult)
+		require.True(t, ok)
+		rows := 0
+		for buffered.Next() {
+			rows++
+		}
+		assert.Equal(t, 2, rows)
+	})
+}
+
+func TestFitDelay(t *testing.T) {
+	t.Run("no deadline", func(t *testing.T) {
+		delay, _, fits := RetryPolicy{}.fitDelay(context.Background(), time.Time{}, time.Second)
+		assert.True(t, fits)
+		assert.Equal(t, time.Second, delay)
+	})
+
+	t.Run("before deadline", func(t *testing.T) {
+		delay, _, fits := RetryPolicy{}.fitDelay(context.Background(), time.Now().Add(time.Minute), time.Second)
+		assert.True(t, fits)
+		assert.Equal(t, time.Second, delay)
+	})
+
+	t.Run("context deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		_, remaining, fits := RetryPolicy{}.fitDelay(ctx, time.Now().Add(time.Minute), time.Second)
+		assert.False(t, fits)
+		assert.True(t, remaining <= 100*time.Millisecond)
+	})
+
+	t.Run("elapsed time deadline", func(t *testing.T) {
+		_, _, fits := RetryPolicy{}.fitDelay(context.Background(), time.Now().Add(100*time.Millisecond), time.Second)
+		assert.False(t, fits)
+	})
+
+	t.Run("truncate", func(t *testing.T) {
+		delay, remaining, fits := RetryPolicy{TruncateBackoff: true}.fitDelay(context.Background(), time.Now().Add(100*time.Millisecond), time.Second)
+		assert.True(t, fits)
+		assert.Equal(t, remaining/2, delay)
+	})
+
+	t.Run("truncate past deadline", func(t *testing.T) {
+		_, _, fits := RetryPolicy{TruncateBackoff: true}.fitDelay(context.Background(), time.Now().Add(-time.Millisecond), time.Second)
+		assert.False(t, fits)
+	})
+}
+
+func TestExecuteDeadlineWouldExceed(t *testing.T) {
+	newTestDriver := func(truncate bool) *QLDBDriver {
+		return newMockDriver(t, &qldbsessioniface.MockClientAPI{
+			SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+				if params.CommitTransaction != nil {
+					return nil, testOCC
+				}
+				return qldbsessioniface.DefaultSendCommandOutput(params), nil
+			},
+		}, func(options *DriverOptions) {
+			options.RetryPolicy = RetryPolicy{
+				MaxRetryLimit:   4,
+				Backoff:         ExponentialBackoffStrategy{SleepBase: time.Second, SleepCap: time.Second},
+				TruncateBackoff: truncate,
+			}
+		})
+	}
+	execute := func(txn Transaction) (interface{}, error) {
+		return nil, nil
+	}
+
+	t.Run("fail fast", func(t *testing.T) {
+		testDriver := newTestDriver(false)
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := testDriver.Execute(ctx, execute)
+		assert.True(t, time.Since(start) < 200*time.Millisecond)
+		var deadlineErr *DeadlineWouldExceedError
+		require.ErrorAs(t, err, &deadlineErr)
+		assert.True(t, deadlineErr.Delay > deadlineErr.Remaining)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.True(t, errs.IsOCCConflict(errors.Unwrap(err)))
+		assert.Equal(t, 0, testDriver.semaphore.inUse())
+	})
+
+	t.Run("truncate", func(t *testing.T) {
+		testDriver := newTestDriver(true)
+		var retries int32
+		testDriver.retryPolicy.OnRetry = func(attempt int, err error, nextDelay time.Duration) error {
+			atomic.AddInt32(&retries, 1)
+			assert.True(t, nextDelay < 200*time.Millisecond)
+			return nil
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+
+		_, err := testDriver.Execute(ctx, execute)
+		assert.Error(t, err)
+		assert.True(t, atomic.LoadInt32(&retries) > 0)
+	})
+}
+
+func TestExecuteTranslateError(t *testing.T) {
+	errDomain := errors.New("domain")
+	testDriver := newMockDriver(t, &qldbsessioniface.MockClientAPI{
+		SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+			return qldbsessioniface.DefaultSendCommandOutput(params), nil
+		},
+	}, func(options *DriverOptions) {
+		options.TranslateError = func(err error) error {
+			if errors.Is(err, errMock) {
+				return errDomain
+			}
+			return nil
+		}
+	})
+
+	t.Run("translated", func(t *testing.T) {
+		_, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+			return nil, errMock
+		})
+		assert.Equal(t, errDomain, err)
+	})
+
+	t.Run("kept", func(t *testing.T) {
+		errOther := errors.New("other")
+		_, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+			return nil, errOther
+		})
+		assert.Equal(t, errOther, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		result, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+			return 1, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, result)
+	})
+
+	t.Run("methods built on Execute", func(t *testing.T) {
+		_, err := testDriver.ExecuteWorkflow(context.Background(), []WorkflowStep{{Name: "fail", Run: func(txn Transaction, previous interface{}) (interface{}, error) {
+			return nil, errMock
+		}}})
+		assert.Equal(t, errDomain, err)
+	})
+}
+
+// cachedCredentials is a credentials provider that counts the forced refreshes of its credentials.
+type cachedCredentials struct {
+	invalidations int
+}
+
+func (provider *cachedCredentials) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	return aws.Credentials{AccessKeyID: "id", SecretAccessKey: "secret"}, nil
+}
+
+func (provider *cachedCredentials) Invalidate() {
+	provider.invalidations++
+}
+
+func TestExecuteCredentialsError(t *testing.T) {
+	errExpired := &smithy.GenericAPIError{Code: "ExpiredTokenException", Message: "The security token included in the request is expired"}
+	newDriver := func(startFailures int, executeFailures int, refresh bool) (*QLDBDriver, *qldbsessioniface.MockClientAPI, *cachedCredentials) {
+		credentials := &cachedCredentials{}
+		mockClient := &qldbsessioniface.MockClientAPI{
+			SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+				options := qldbsession.Options{Credentials: credentials}
+				for _, optFn := range optFns {
+					optFn(&options)
+				}
+				if params.StartSession != nil && startFailures > 0 {
+					startFailures--
+					return nil, &smithy.OperationError{Err: &v4.SigningError{Err: errors.New("failed to retrieve credentials: throttled")}}
+				}
+				if params.ExecuteStatement != nil && executeFailures > 0 {
+					executeFailures--
+					return nil, errExpired
+				}
+				return qldbsessioniface.DefaultSendCommandOutput(params), nil
+			},
+		}
+		return newMockDriver(t, mockClient, func(options *DriverOptions) {
+			options.RetryPolicy = RetryPolicy{MaxRetryLimit: 4, Backoff: ExponentialBackoffStrategy{SleepBase: time.Millisecond, SleepCap: time.Millisecond}}
+			options.RetryWithRefreshedCredentials = refresh
+		}), mockClient, credentials
+	}
+	execute := func(testDriver *QLDBDriver) (interface{}, error) {
+		return testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+			_, err := txn.Execute("SELECT * FROM T")
+			return 1, err
+		})
+	}
+	count := func(mockClient *qldbsessioniface.MockClientAPI, command func(*qldbsession.SendCommandInput) bool) int {
+		total := 0
+		for _, input := range mockClient.Inputs() {
+			if command(input) {
+				total++
+			}
+		}
+		return total
+	}
+	isStartSession := func(input *qldbsession.SendCommandInput) bool { return input.StartSession != nil }
+	isStartTransaction := func(input *qldbsession.SendCommandInput) bool { return input.StartTransaction != nil }
+	isEndSession := func(input *qldbsession.SendCommandInput) bool { return input.EndSession != nil }
+
+	t.Run("not retried by default", func(t *testing.T) {
+		testDriver, mockClient, credentials := newDriver(0, 1, false)
+		_, err := execute(testDriver)
+		var credentialsErr *CredentialsError
+		require.True(t, errors.As(err, &credentialsErr))
+		assert.True(t, errs.IsCredentialsError(err))
+		assert.Equal(t, errExpired, errors.Unwrap(err))
+		assert.Contains(t, err.Error(), "Check that the credentials provider")
+		assert.Equal(t, 1, count(mockClient, isStartTransaction))
+		assert.Equal(t, 0, credentials.invalidations)
+	})
+
+	t.Run("session not started", func(t *testing.T) {
+		testDriver, _, credentials := newDriver(1, 0, false)
+		_, err := execute(testDriver)
+		var credentialsErr *CredentialsError
+		require.True(t, errors.As(err, &credentialsErr))
+		assert.Equal(t, 10, testDriver.semaphore.available())
+		assert.Equal(t, 0, credentials.invalidations)
+	})
+
+	t.Run("retried with refreshed credentials", func(t *testing.T) {
+		testDriver, mockClient, credentials := newDriver(0, 1, true)
+		var retries []RetryErrorClass
+		testDriver.retryPolicy.OnRetry = func(attempt int, err error, nextDelay time.Duration) error {
+			retries = append(retries, retryErrorClass(err, false))
+			return nil
+		}
+		result, err := execute(testDriver)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result)
+		assert.Equal(t, 1, credentials.invalidations)
+		assert.Equal(t, 2, count(mockClient, isStartSession))
+		assert.Equal(t, []RetryErrorClass{RetryCredentials}, retries)
+		// The session that received the rejected credentials is ended
+		assert.Eventually(t, func() bool { return count(mockClient, isEndSession) == 1 }, time.Second, time.Millisecond)
+		assert.Equal(t, 10, testDriver.semaphore.available())
+	})
+
+	t.Run("session started with refreshed credentials", func(t *testing.T) {
+		testDriver, mockClient, credentials := newDriver(1, 0, true)
+		result, err := execute(testDriver)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result)
+		assert.Equal(t, 1, credentials.invalidations)
+		assert.Equal(t, 2, count(mockClient, isStartSession))
+	})
+
+	t.Run("refreshed once", func(t *testing.T) {
+		testDriver, mockClient, credentials := newDriver(0, 3, true)
+		_, err := execute(testDriver)
+		var credentialsErr *CredentialsError
+		require.True(t, errors.As(err, &credentialsErr))
+		assert.Equal(t, 1, credentials.invalidations)
+		assert.Equal(t, 2, count(mockClient, isStartTransaction))
+		assert.Equal(t, 10, testDriver.semaphore.available())
+	})
+}
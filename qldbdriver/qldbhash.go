@@ -31,6 +31,12 @@ func toQLDBHash(value interface{}) (*qldbHash, error) {
 	if err != nil {
 		return nil, err
 	}
+	return ionBinaryToQLDBHash(ionValue)
+}
+
+// ionBinaryToQLDBHash returns the Ion hash of a value in Ion binary format. Equivalent values have the same hash,
+// regardless of the order of their struct fields.
+func ionBinaryToQLDBHash(ionValue []byte) (*qldbHash, error) {
 	ionReader := ion.NewReaderBytes(ionValue)
 	hashReader, err := ionhash.NewHashReader(ionReader, ionhash.NewCryptoHasherProvider(ionhash.SHA256))
 	if err != nil {
@@ -46,6 +52,24 @@ func toQLDBHash(value interface{}) (*qldbHash, error) {
 	return &qldbHash{hash}, nil
 }
 
+func toStatementHash(statement string, parameters []interface{}) (*qldbHash, error) {
+	statementHash, err := toQLDBHash(statement)
+	if err != nil {
+		return nil, err
+	}
+	for _, parameter := range parameters {
+		parameterHash, err := toQLDBHash(parameter)
+		if err != nil {
+			return nil, err
+		}
+		statementHash, err = statementHash.dot(parameterHash)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return statementHash, nil
+}
+
 func (thisHash *qldbHash) dot(thatHash *qldbHash) (*qldbHash, error) {
 	concatenated, err := joinHashesPairwise(thisHash.hash, thatHash.hash)
 	if err != nil {
@@ -0,0 +1,103 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbsessioniface
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+)
+
+const (
+	// MockSessionToken is the session token returned by DefaultSendCommandOutput.
+	MockSessionToken = "mockSessionToken"
+	// MockTransactionID is the transaction ID returned by DefaultSendCommandOutput.
+	MockTransactionID = "mockTransactionId"
+)
+
+// MockClientAPI is a ClientAPI for unit tests, which records the commands it receives and responds to them with
+// SendCommandFunc.
+//
+//	mockSvc := &qldbsessioniface.MockClientAPI{
+//	    SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+//	        if params.ExecuteStatement != nil {
+//	            return nil, errors.New("mock error")
+//	        }
+//	        return qldbsessioniface.DefaultSendCommandOutput(params), nil
+//	    },
+//	}
+//
+// MockClientAPI is safe for concurrent use.
+type MockClientAPI struct {
+	// The function responding to every command. Default: DefaultSendCommandOutput.
+	SendCommandFunc func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error)
+
+	lock   sync.Mutex
+	inputs []*qldbsession.SendCommandInput
+}
+
+// SendCommand records the command and responds to it with SendCommandFunc.
+func (m *MockClientAPI) SendCommand(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+	m.lock.Lock()
+	m.inputs = append(m.inputs, params)
+	sendCommandFunc := m.SendCommandFunc
+	m.lock.Unlock()
+
+	if sendCommandFunc == nil {
+		return DefaultSendCommandOutput(params), nil
+	}
+	return sendCommandFunc(ctx, params, optFns...)
+}
+
+// Inputs returns the commands received so far, in the order they were received.
+func (m *MockClientAPI) Inputs() []*qldbsession.SendCommandInput {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	inputs := make([]*qldbsession.SendCommandInput, len(m.inputs))
+	copy(inputs, m.inputs)
+	return inputs
+}
+
+// DefaultSendCommandOutput returns a successful output for the command. Sessions are started with MockSessionToken,
+// transactions are started with MockTransactionID, statements return an empty page and commits return the commit
+// digest of the request.
+func DefaultSendCommandOutput(params *qldbsession.SendCommandInput) *qldbsession.SendCommandOutput {
+	output := &qldbsession.SendCommandOutput{}
+	switch {
+	case params.StartSession != nil:
+		sessionToken := MockSessionToken
+		output.StartSession = &types.StartSessionResult{SessionToken: &sessionToken}
+	case params.StartTransaction != nil:
+		transactionID := MockTransactionID
+		output.StartTransaction = &types.StartTransactionResult{TransactionId: &transactionID}
+	case params.ExecuteStatement != nil:
+		output.ExecuteStatement = &types.ExecuteStatementResult{FirstPage: &types.Page{}}
+	case params.FetchPage != nil:
+		output.FetchPage = &types.FetchPageResult{Page: &types.Page{}}
+	case params.CommitTransaction != nil:
+		output.CommitTransaction = &types.CommitTransactionResult{
+			TransactionId: params.CommitTransaction.TransactionId,
+			CommitDigest:  params.CommitTransaction.CommitDigest,
+		}
+	case params.AbortTransaction != nil:
+		output.AbortTransaction = &types.AbortTransactionResult{}
+	case params.EndSession != nil:
+		output.EndSession = &types.EndSessionResult{}
+	}
+	return output
+}
+
+var _ ClientAPI = (*MockClientAPI)(nil)
@@ -0,0 +1,69 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbsessioniface
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockClientAPI(t *testing.T) {
+	t.Run("default outputs", func(t *testing.T) {
+		mockSvc := &MockClientAPI{}
+
+		output, err := mockSvc.SendCommand(context.Background(), &qldbsession.SendCommandInput{StartSession: &types.StartSessionRequest{}})
+		require.NoError(t, err)
+		assert.Equal(t, MockSessionToken, *output.StartSession.SessionToken)
+
+		output, err = mockSvc.SendCommand(context.Background(), &qldbsession.SendCommandInput{StartTransaction: &types.StartTransactionRequest{}})
+		require.NoError(t, err)
+		assert.Equal(t, MockTransactionID, *output.StartTransaction.TransactionId)
+
+		output, err = mockSvc.SendCommand(context.Background(), &qldbsession.SendCommandInput{ExecuteStatement: &types.ExecuteStatementRequest{}})
+		require.NoError(t, err)
+		assert.Empty(t, output.ExecuteStatement.FirstPage.Values)
+
+		digest := []byte{1, 2, 3}
+		output, err = mockSvc.SendCommand(context.Background(), &qldbsession.SendCommandInput{CommitTransaction: &types.CommitTransactionRequest{CommitDigest: digest}})
+		require.NoError(t, err)
+		assert.Equal(t, digest, output.CommitTransaction.CommitDigest)
+
+		assert.Len(t, mockSvc.Inputs(), 4)
+	})
+
+	t.Run("custom function", func(t *testing.T) {
+		errMock := errors.New("mock")
+		mockSvc := &MockClientAPI{
+			SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+				if params.ExecuteStatement != nil {
+					return nil, errMock
+				}
+				return DefaultSendCommandOutput(params), nil
+			},
+		}
+
+		_, err := mockSvc.SendCommand(context.Background(), &qldbsession.SendCommandInput{StartSession: &types.StartSessionRequest{}})
+		assert.NoError(t, err)
+		input := &qldbsession.SendCommandInput{ExecuteStatement: &types.ExecuteStatementRequest{}}
+		_, err = mockSvc.SendCommand(context.Background(), input)
+		assert.Equal(t, errMock, err)
+		assert.Equal(t, input, mockSvc.Inputs()[1])
+	})
+}
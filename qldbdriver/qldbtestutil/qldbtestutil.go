@@ -0,0 +1,176 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+// Package qldbtestutil helps the integration tests of applications using the driver to run against ephemeral QLDB
+// ledgers: it creates a ledger for a test, deletes it when the test completes, and sets up and clears tables. The
+// helpers fail the test with t.Fatalf rather than returning errors, so that they can be called from any test or
+// subtest.
+package qldbtestutil
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/qldb"
+	"github.com/aws/aws-sdk-go-v2/service/qldb/types"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbadmin"
+)
+
+// maxLedgerNameLength is the maximum length of the name of a QLDB ledger.
+const maxLedgerNameLength = 32
+
+// Options can be used to configure a Ledger during construction.
+type Options struct {
+	// The interval between two checks of the state of the ledger while waiting for it. Default: 5s.
+	PollInterval time.Duration
+	// The maximum time to wait for the ledger to be created, and then to be deleted. Default: 10m.
+	Timeout time.Duration
+	// The permissions mode of the ledger. Default: types.PermissionsModeStandard.
+	PermissionsMode types.PermissionsMode
+	// Keeps the ledger once the test completes, for example to inspect it after a failure. Default: false.
+	KeepLedger bool
+}
+
+// Ledger is an ephemeral ledger created for a test by NewLedger.
+type Ledger struct {
+	// The name of the ledger.
+	Name string
+	// The LedgerAdmin that created the ledger.
+	Admin   *qldbadmin.LedgerAdmin
+	timeout time.Duration
+}
+
+// UniqueLedgerName returns prefix followed by a random suffix, so that concurrent runs of a test suite use distinct
+// ledgers. prefix is truncated so that the name does not exceed the 32 characters allowed by QLDB.
+func UniqueLedgerName(prefix string) string {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		panic(err)
+	}
+	maxPrefixLength := maxLedgerNameLength - 1 - 2*len(suffix)
+	if len(prefix) > maxPrefixLength {
+		prefix = prefix[:maxPrefixLength]
+	}
+	return prefix + "-" + hex.EncodeToString(suffix)
+}
+
+// NewLedger creates a ledger named name with the QLDB control plane client, such as qldb.NewFromConfig(cfg), and
+// waits for it to become active. A ledger with the same name, left by a previous run, is deleted first. The ledger is
+// deleted once the test and its subtests complete, unless Options.KeepLedger is set.
+func NewLedger(t testing.TB, client qldbadmin.ClientAPI, name string, fns ...func(*Options)) *Ledger {
+	t.Helper()
+	options := &Options{PollInterval: 5 * time.Second, Timeout: 10 * time.Minute, PermissionsMode: types.PermissionsModeStandard}
+	for _, fn := range fns {
+		fn(options)
+	}
+	admin, err := qldbadmin.New(client, func(adminOptions *qldbadmin.Options) {
+		adminOptions.PollInterval = options.PollInterval
+	})
+	if err != nil {
+		t.Fatalf("qldbtestutil: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), options.Timeout)
+	defer cancel()
+	if err = admin.DeleteLedger(ctx, name); err != nil {
+		t.Fatalf("qldbtestutil: failed to delete ledger %s left by a previous run: %v", name, err)
+	}
+	t.Logf("qldbtestutil: creating ledger %s", name)
+	_, err = admin.CreateLedger(ctx, &qldb.CreateLedgerInput{
+		Name:               &name,
+		DeletionProtection: aws.Bool(false),
+		PermissionsMode:    options.PermissionsMode,
+	})
+	if err != nil {
+		t.Fatalf("qldbtestutil: failed to create ledger %s: %v", name, err)
+	}
+
+	ledger := &Ledger{Name: name, Admin: admin, timeout: options.Timeout}
+	if !options.KeepLedger {
+		t.Cleanup(func() {
+			ledger.delete(t)
+		})
+	}
+	return ledger
+}
+
+// delete deletes the ledger and waits for the deletion to complete.
+func (ledger *Ledger) delete(t testing.TB) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), ledger.timeout)
+	defer cancel()
+	t.Logf("qldbtestutil: deleting ledger %s", ledger.Name)
+	if err := ledger.Admin.DeleteLedger(ctx, ledger.Name); err != nil {
+		t.Errorf("qldbtestutil: failed to delete ledger %s: %v", ledger.Name, err)
+	}
+}
+
+// NewDriver creates a driver for the ledger with the QLDB Session client, such as qldbsession.NewFromConfig(cfg), and
+// the options. The driver is shut down once the test and its subtests complete, before the ledger is deleted.
+func (ledger *Ledger) NewDriver(t testing.TB, qldbSession *qldbsession.Client, fns ...func(*qldbdriver.DriverOptions)) *qldbdriver.QLDBDriver {
+	t.Helper()
+	driver, err := qldbdriver.New(ledger.Name, qldbSession, fns...)
+	if err != nil {
+		t.Fatalf("qldbtestutil: failed to create a driver for ledger %s: %v", ledger.Name, err)
+	}
+	t.Cleanup(func() {
+		driver.Shutdown(context.Background())
+	})
+	return driver
+}
+
+// CreateTable creates a table with an index on each of indexedFields. Indexes can only be created on empty tables,
+// so CreateTable should be called before inserting documents.
+func CreateTable(t testing.TB, executor qldbdriver.Executor, table string, indexedFields ...string) {
+	t.Helper()
+	verifyIdentifier(t, table)
+	for _, field := range indexedFields {
+		verifyIdentifier(t, field)
+	}
+	execute(t, executor, "CREATE TABLE "+table)
+	for _, field := range indexedFields {
+		execute(t, executor, "CREATE INDEX ON "+table+" ("+field+")")
+	}
+}
+
+// DeleteAll deletes the documents of a table, for example to isolate the tests sharing a ledger.
+func DeleteAll(t testing.TB, executor qldbdriver.Executor, table string) {
+	t.Helper()
+	verifyIdentifier(t, table)
+	execute(t, executor, "DELETE FROM "+table)
+}
+
+// verifyIdentifier fails the test if name cannot be used as a table or field name without quoting.
+func verifyIdentifier(t testing.TB, name string) {
+	t.Helper()
+	if !qldbdriver.IsValidIdentifier(name) {
+		t.Fatalf("qldbtestutil: %q is not a valid table or field name", name)
+	}
+}
+
+// execute executes a statement in its own transaction.
+func execute(t testing.TB, executor qldbdriver.Executor, statement string) {
+	t.Helper()
+	_, err := executor.Execute(context.Background(), func(txn qldbdriver.Transaction) (interface{}, error) {
+		return txn.Execute(statement)
+	})
+	if err != nil {
+		t.Fatalf("qldbtestutil: failed to execute %q: %v", statement, err)
+	}
+}
@@ -0,0 +1,293 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbtestutil
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/qldb"
+	"github.com/aws/aws-sdk-go-v2/service/qldb/types"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient is a QLDB control plane holding ledgers that become active or deleted immediately.
+type fakeClient struct {
+	ledgers   map[string]types.LedgerState
+	createErr error
+	created   []*qldb.CreateLedgerInput
+	deleted   []string
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{ledgers: map[string]types.LedgerState{}}
+}
+
+func (client *fakeClient) CreateLedger(ctx context.Context, params *qldb.CreateLedgerInput, optFns ...func(*qldb.Options)) (*qldb.CreateLedgerOutput, error) {
+	if client.createErr != nil {
+		return nil, client.createErr
+	}
+	client.created = append(client.created, params)
+	client.ledgers[*params.Name] = types.LedgerStateActive
+	return &qldb.CreateLedgerOutput{Name: params.Name, State: types.LedgerStateCreating}, nil
+}
+
+func (client *fakeClient) DeleteLedger(ctx context.Context, params *qldb.DeleteLedgerInput, optFns ...func(*qldb.Options)) (*qldb.DeleteLedgerOutput, error) {
+	if _, ok := client.ledgers[*params.Name]; !ok {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("Ledger not found")}
+	}
+	client.deleted = append(client.deleted, *params.Name)
+	delete(client.ledgers, *params.Name)
+	return &qldb.DeleteLedgerOutput{}, nil
+}
+
+func (client *fakeClient) DescribeLedger(ctx context.Context, params *qldb.DescribeLedgerInput, optFns ...func(*qldb.Options)) (*qldb.DescribeLedgerOutput, error) {
+	state, ok := client.ledgers[*params.Name]
+	if !ok {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("Ledger not found")}
+	}
+	return &qldb.DescribeLedgerOutput{Name: params.Name, State: state}, nil
+}
+
+func (client *fakeClient) UpdateLedger(ctx context.Context, params *qldb.UpdateLedgerInput, optFns ...func(*qldb.Options)) (*qldb.UpdateLedgerOutput, error) {
+	return &qldb.UpdateLedgerOutput{}, nil
+}
+
+// fakeTB records the failures and the cleanup functions of a test.
+type fakeTB struct {
+	testing.TB
+	failures []string
+	cleanups []func()
+}
+
+func (tb *fakeTB) Helper() {}
+
+func (tb *fakeTB) Logf(format string, args ...interface{}) {}
+
+func (tb *fakeTB) Errorf(format string, args ...interface{}) {
+	tb.failures = append(tb.failures, fmt.Sprintf(format, args...))
+}
+
+func (tb *fakeTB) Fatalf(format string, args ...interface{}) {
+	tb.Errorf(format, args...)
+	panic(tb)
+}
+
+func (tb *fakeTB) Cleanup(fn func()) {
+	tb.cleanups = append(tb.cleanups, fn)
+}
+
+// run calls fn, which stops at the first call to Fatalf, and then the cleanup functions.
+func (tb *fakeTB) run(fn func()) {
+	func() {
+		defer func() {
+			if recovered := recover(); recovered != nil && recovered != tb {
+				panic(recovered)
+			}
+		}()
+		fn()
+	}()
+	for i := len(tb.cleanups) - 1; i >= 0; i-- {
+		tb.cleanups[i]()
+	}
+}
+
+// fakeTransaction records the statements it executes.
+type fakeTransaction struct {
+	qldbdriver.Transaction
+	statements []string
+	err        error
+}
+
+func (txn *fakeTransaction) Execute(statement string, parameters ...interface{}) (qldbdriver.Result, error) {
+	if txn.err != nil {
+		return nil, txn.err
+	}
+	txn.statements = append(txn.statements, statement)
+	return nil, nil
+}
+
+// fakeExecutor executes functions with a fakeTransaction.
+type fakeExecutor struct {
+	lock         sync.Mutex
+	txn          *fakeTransaction
+	transactions int
+}
+
+func (executor *fakeExecutor) Execute(ctx context.Context, fn func(txn qldbdriver.Transaction) (interface{}, error), optFns ...func(*qldbdriver.ExecuteOptions)) (interface{}, error) {
+	executor.lock.Lock()
+	defer executor.lock.Unlock()
+	executor.transactions++
+	return fn(executor.txn)
+}
+
+func TestUniqueLedgerName(t *testing.T) {
+	name := UniqueLedgerName("Golang-Integration")
+	assert.True(t, strings.HasPrefix(name, "Golang-Integration-"))
+	assert.Len(t, name, len("Golang-Integration-")+8)
+	assert.NotEqual(t, name, UniqueLedgerName("Golang-Integration"))
+
+	long := UniqueLedgerName(strings.Repeat("a", 40))
+	assert.Len(t, long, maxLedgerNameLength)
+	assert.True(t, strings.HasPrefix(long, strings.Repeat("a", 23)+"-"))
+}
+
+func TestNewLedger(t *testing.T) {
+	pollFast := func(options *Options) {
+		options.PollInterval = time.Millisecond
+	}
+
+	t.Run("created and deleted", func(t *testing.T) {
+		client := newFakeClient()
+		tb := &fakeTB{}
+		tb.run(func() {
+			ledger := NewLedger(tb, client, "ledger", pollFast)
+			assert.Equal(t, "ledger", ledger.Name)
+			assert.Equal(t, types.LedgerStateActive, client.ledgers["ledger"])
+			require.Len(t, client.created, 1)
+			assert.False(t, *client.created[0].DeletionProtection)
+			assert.Equal(t, types.PermissionsModeStandard, client.created[0].PermissionsMode)
+		})
+		assert.Empty(t, tb.failures)
+		assert.Equal(t, []string{"ledger"}, client.deleted)
+		assert.Empty(t, client.ledgers)
+	})
+
+	t.Run("leftover ledger deleted first", func(t *testing.T) {
+		client := newFakeClient()
+		client.ledgers["ledger"] = types.LedgerStateActive
+		tb := &fakeTB{}
+		tb.run(func() {
+			NewLedger(tb, client, "ledger", pollFast)
+			assert.Equal(t, []string{"ledger"}, client.deleted)
+		})
+		assert.Empty(t, tb.failures)
+		assert.Equal(t, []string{"ledger", "ledger"}, client.deleted)
+	})
+
+	t.Run("kept", func(t *testing.T) {
+		client := newFakeClient()
+		tb := &fakeTB{}
+		tb.run(func() {
+			NewLedger(tb, client, "ledger", pollFast, func(options *Options) {
+				options.KeepLedger = true
+			})
+		})
+		assert.Empty(t, tb.failures)
+		assert.Empty(t, client.deleted)
+		assert.Contains(t, client.ledgers, "ledger")
+	})
+
+	t.Run("creation failure", func(t *testing.T) {
+		client := newFakeClient()
+		client.createErr = &types.LimitExceededException{Message: aws.String("too many ledgers")}
+		tb := &fakeTB{}
+		reached := false
+		tb.run(func() {
+			NewLedger(tb, client, "ledger", pollFast)
+			reached = true
+		})
+		assert.False(t, reached)
+		require.Len(t, tb.failures, 1)
+		assert.Contains(t, tb.failures[0], "failed to create ledger ledger")
+		assert.Empty(t, tb.cleanups)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		tb := &fakeTB{}
+		tb.run(func() {
+			NewLedger(tb, newFakeClient(), "ledger", func(options *Options) {
+				options.PollInterval = 0
+			})
+		})
+		require.Len(t, tb.failures, 1)
+		assert.Contains(t, tb.failures[0], "PollInterval")
+	})
+}
+
+func TestNewDriver(t *testing.T) {
+	client := newFakeClient()
+	tb := &fakeTB{}
+	var driver *qldbdriver.QLDBDriver
+	tb.run(func() {
+		ledger := NewLedger(tb, client, "ledger", func(options *Options) {
+			options.PollInterval = time.Millisecond
+		})
+		driver = ledger.NewDriver(tb, qldbsession.New(qldbsession.Options{Region: "us-east-1"}), func(options *qldbdriver.DriverOptions) {
+			options.MaxConcurrentTransactions = 2
+		})
+	})
+	assert.Empty(t, tb.failures)
+	require.NotNil(t, driver)
+	_, err := driver.Execute(context.Background(), func(txn qldbdriver.Transaction) (interface{}, error) {
+		return nil, nil
+	})
+	assert.EqualError(t, err, "Cannot invoke methods on a closed QLDBDriver.")
+	assert.Equal(t, []string{"ledger"}, client.deleted)
+}
+
+func TestCreateTable(t *testing.T) {
+	t.Run("with indexes", func(t *testing.T) {
+		executor := &fakeExecutor{txn: &fakeTransaction{}}
+		tb := &fakeTB{}
+		tb.run(func() {
+			CreateTable(tb, executor, "Vehicle", "VIN", "Owner")
+		})
+		assert.Empty(t, tb.failures)
+		assert.Equal(t, []string{"CREATE TABLE Vehicle", "CREATE INDEX ON Vehicle (VIN)", "CREATE INDEX ON Vehicle (Owner)"},
+			executor.txn.statements)
+		assert.Equal(t, 3, executor.transactions)
+	})
+
+	t.Run("invalid names", func(t *testing.T) {
+		executor := &fakeExecutor{txn: &fakeTransaction{}}
+		for _, names := range [][]string{{"Vehicle; DROP TABLE Person"}, {"Vehicle", "VIN)"}} {
+			tb := &fakeTB{}
+			tb.run(func() {
+				CreateTable(tb, executor, names[0], names[1:]...)
+			})
+			assert.Len(t, tb.failures, 1)
+		}
+		assert.Empty(t, executor.txn.statements)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		executor := &fakeExecutor{txn: &fakeTransaction{err: fmt.Errorf("table exists")}}
+		tb := &fakeTB{}
+		tb.run(func() {
+			CreateTable(tb, executor, "Vehicle", "VIN")
+		})
+		require.Len(t, tb.failures, 1)
+		assert.Contains(t, tb.failures[0], `failed to execute "CREATE TABLE Vehicle": table exists`)
+		assert.Equal(t, 1, executor.transactions)
+	})
+}
+
+func TestDeleteAll(t *testing.T) {
+	executor := &fakeExecutor{txn: &fakeTransaction{}}
+	tb := &fakeTB{}
+	tb.run(func() {
+		DeleteAll(tb, executor, "Vehicle")
+	})
+	assert.Empty(t, tb.failures)
+	assert.Equal(t, []string{"DELETE FROM Vehicle"}, executor.txn.statements)
+}
@@ -0,0 +1,213 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultQueryCacheSize is the number of values kept when DriverOptions.QueryCacheSize is 0.
+const defaultQueryCacheSize = 256
+
+// queryCacheFlightKind identifies the reads of CachedQuery among the reads in flight.
+const queryCacheFlightKind = "CachedQuery"
+
+// queryCache holds the values returned by the functions of CachedQuery, evicting the least recently used value once
+// it is full. The zero value keeps defaultQueryCacheSize values, and a negative size disables it.
+type queryCache struct {
+	lock    sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	// order holds the *queryCacheEntry values, the most recently used first.
+	order   *list.List
+	flights *readFlights
+	// reads holds the reads in flight of each key, so that a read started before a removal of its key does not cache
+	// its value. The reads of a key are dropped once none is in flight, so that removing keys never read does not grow
+	// the cache.
+	reads map[string]*queryCacheReads
+}
+
+type queryCacheEntry struct {
+	key     string
+	value   interface{}
+	expires time.Time
+}
+
+type queryCacheReads struct {
+	// count is the number of reads in flight.
+	count int
+	// generation counts the removals of the key since the first read in flight started.
+	generation uint64
+}
+
+// capacity returns the number of values kept, or a negative number if the cache is disabled.
+func (cache *queryCache) capacity() int {
+	if cache.size == 0 {
+		return defaultQueryCacheSize
+	}
+	return cache.size
+}
+
+// inFlight returns the reads in flight of the cache, shared by the concurrent misses of a key.
+func (cache *queryCache) inFlight() *readFlights {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	if cache.flights == nil {
+		cache.flights = newReadFlights()
+	}
+	return cache.flights
+}
+
+// get returns the value cached under key, unless it expired.
+func (cache *queryCache) get(key string) (interface{}, bool) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	element, ok := cache.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*queryCacheEntry)
+	if !time.Now().Before(entry.expires) {
+		cache.order.Remove(element)
+		delete(cache.entries, key)
+		return nil, false
+	}
+	cache.order.MoveToFront(element)
+	return entry.value, true
+}
+
+// beginRead records a read of key in flight, and returns the generation of key to be passed to put by the read. The
+// read must be ended with endRead.
+func (cache *queryCache) beginRead(key string) uint64 {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	if cache.reads == nil {
+		cache.reads = make(map[string]*queryCacheReads)
+	}
+	reads, ok := cache.reads[key]
+	if !ok {
+		reads = &queryCacheReads{}
+		cache.reads[key] = reads
+	}
+	reads.count++
+	return reads.generation
+}
+
+// endRead records the end of a read of key started with beginRead.
+func (cache *queryCache) endRead(key string) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	reads, ok := cache.reads[key]
+	if !ok {
+		return
+	}
+	reads.count--
+	if reads.count <= 0 {
+		delete(cache.reads, key)
+	}
+}
+
+// put caches value under key for ttl, evicting the least recently used value if the cache is full. The value is not
+// cached if key was removed since generation was returned by beginRead, since it may have been read before the
+// removal.
+func (cache *queryCache) put(key string, value interface{}, ttl time.Duration, generation uint64) {
+	size := cache.capacity()
+	if size < 0 {
+		return
+	}
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	if reads, ok := cache.reads[key]; ok && reads.generation != generation {
+		return
+	}
+	if cache.entries == nil {
+		cache.entries = make(map[string]*list.Element)
+		cache.order = list.New()
+	}
+	expires := time.Now().Add(ttl)
+	if element, ok := cache.entries[key]; ok {
+		entry := element.Value.(*queryCacheEntry)
+		entry.value = value
+		entry.expires = expires
+		cache.order.MoveToFront(element)
+		return
+	}
+	cache.entries[key] = cache.order.PushFront(&queryCacheEntry{key: key, value: value, expires: expires})
+	if cache.order.Len() > size {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*queryCacheEntry).key)
+	}
+}
+
+// remove drops the value cached under key, keeps the reads in flight for key from caching their value, and detaches
+// them so that the calls missing key afterwards read it again rather than share a value read before the removal.
+func (cache *queryCache) remove(key string) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	cache.flights.forget(queryCacheFlightKind, key, nil)
+	if reads, ok := cache.reads[key]; ok {
+		reads.generation++
+	}
+	if element, ok := cache.entries[key]; ok {
+		cache.order.Remove(element)
+		delete(cache.entries, key)
+	}
+}
+
+// CachedQuery returns the value cached under key, or executes fn like Execute and caches the value it returns under
+// key for ttl. It is meant for the reads of slowly changing reference data, to save the read IOs of executing them
+// again: fn should only read, and the value it returns should be decoded, since a Result cannot be read once its
+// transaction is committed. The callers of the same key share the same value, which must not be modified.
+//
+// The concurrent calls missing the same key share a single execution of fn, with the context of the call that executes
+// it. A call whose context is done stops waiting, and the calls waiting for an execution that failed because the
+// context of its own call was done execute fn again. The calls started after InvalidateCachedQuery for key do not share
+// the executions started before it, whose values are not cached. Errors are not cached either. The cache keeps DriverOptions.QueryCacheSize values,
+// evicting the least recently used one when full, and is only held in memory: use InvalidateCachedQuery to drop a value
+// once the data it was read from is written. When the cache is disabled, every call executes fn, like Execute.
+func (driver *QLDBDriver) CachedQuery(ctx context.Context, key string, ttl time.Duration, fn func(txn Transaction) (interface{}, error), optFns ...func(*ExecuteOptions)) (interface{}, error) {
+	if ttl <= 0 {
+		return nil, &qldbDriverError{"ttl must be greater than 0."}
+	}
+	if driver.queryCache.capacity() < 0 {
+		return driver.Execute(ctx, fn, optFns...)
+	}
+	if value, ok := driver.queryCache.get(key); ok {
+		return value, nil
+	}
+	return driver.queryCache.inFlight().do(ctx, queryCacheFlightKind, key, nil, func(ctx context.Context) (interface{}, error) {
+		// A call that missed the key may start after another one cached it
+		if value, ok := driver.queryCache.get(key); ok {
+			return value, nil
+		}
+		generation := driver.queryCache.beginRead(key)
+		defer driver.queryCache.endRead(key)
+		value, err := driver.Execute(ctx, fn, optFns...)
+		if err != nil {
+			return nil, err
+		}
+		driver.queryCache.put(key, value, ttl, generation)
+		return value, nil
+	})
+}
+
+// InvalidateCachedQuery drops the value cached under key by CachedQuery, so that the next call executes its function
+// again.
+func (driver *QLDBDriver) InvalidateCachedQuery(key string) {
+	driver.queryCache.remove(key)
+}
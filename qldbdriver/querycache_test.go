@@ -0,0 +1,296 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryCache(t *testing.T) {
+	t.Run("get and put", func(t *testing.T) {
+		cache := &queryCache{}
+		_, ok := cache.get("colors")
+		assert.False(t, ok)
+
+		cache.put("colors", []string{"red"}, time.Minute, 0)
+		value, ok := cache.get("colors")
+		require.True(t, ok)
+		assert.Equal(t, []string{"red"}, value)
+
+		cache.put("colors", []string{"blue"}, time.Minute, 0)
+		value, _ = cache.get("colors")
+		assert.Equal(t, []string{"blue"}, value)
+
+		cache.remove("colors")
+		_, ok = cache.get("colors")
+		assert.False(t, ok)
+	})
+
+	t.Run("put after remove", func(t *testing.T) {
+		cache := &queryCache{}
+		generation := cache.beginRead("colors")
+		cache.remove("colors")
+		cache.put("colors", "red", time.Minute, generation)
+		cache.endRead("colors")
+		_, ok := cache.get("colors")
+		assert.False(t, ok)
+
+		cache.put("colors", "blue", time.Minute, cache.beginRead("colors"))
+		cache.endRead("colors")
+		value, ok := cache.get("colors")
+		require.True(t, ok)
+		assert.Equal(t, "blue", value)
+	})
+
+	t.Run("reads are dropped once none is in flight", func(t *testing.T) {
+		cache := &queryCache{}
+		for i := 0; i < 10; i++ {
+			cache.remove(strconv.Itoa(i))
+		}
+		assert.Empty(t, cache.reads)
+
+		cache.beginRead("colors")
+		generation := cache.beginRead("colors")
+		cache.remove("colors")
+		cache.endRead("colors")
+		assert.Len(t, cache.reads, 1)
+		cache.put("colors", "red", time.Minute, generation)
+		_, ok := cache.get("colors")
+		assert.False(t, ok)
+		cache.endRead("colors")
+		assert.Empty(t, cache.reads)
+	})
+
+	t.Run("expiry", func(t *testing.T) {
+		cache := &queryCache{}
+		cache.put("colors", "red", time.Minute, 0)
+		cache.entries["colors"].Value.(*queryCacheEntry).expires = time.Now().Add(-time.Second)
+		_, ok := cache.get("colors")
+		assert.False(t, ok)
+		assert.Empty(t, cache.entries)
+		assert.Equal(t, 0, cache.order.Len())
+	})
+
+	t.Run("evicts least recently used", func(t *testing.T) {
+		cache := &queryCache{size: 2}
+		cache.put("a", 1, time.Minute, 0)
+		cache.put("b", 2, time.Minute, 0)
+		_, ok := cache.get("a")
+		require.True(t, ok)
+		cache.put("c", 3, time.Minute, 0)
+
+		_, ok = cache.get("b")
+		assert.False(t, ok)
+		_, ok = cache.get("a")
+		assert.True(t, ok)
+		_, ok = cache.get("c")
+		assert.True(t, ok)
+	})
+
+	t.Run("default and disabled sizes", func(t *testing.T) {
+		cache := &queryCache{}
+		for i := 0; i < defaultQueryCacheSize+1; i++ {
+			cache.put(strconv.Itoa(i), i, time.Minute, 0)
+		}
+		assert.Len(t, cache.entries, defaultQueryCacheSize)
+		_, ok := cache.get("0")
+		assert.False(t, ok)
+
+		disabled := &queryCache{size: -1}
+		disabled.put("a", 1, time.Minute, 0)
+		_, ok = disabled.get("a")
+		assert.False(t, ok)
+	})
+}
+
+func TestCachedQuery(t *testing.T) {
+	var transactions int32
+	newDriver := func(cacheSize int) *QLDBDriver {
+		atomic.StoreInt32(&transactions, 0)
+		return newMockDriver(t, &qldbsessioniface.MockClientAPI{
+			SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+				if params.StartTransaction != nil {
+					atomic.AddInt32(&transactions, 1)
+				}
+				return qldbsessioniface.DefaultSendCommandOutput(params), nil
+			},
+		}, func(options *DriverOptions) {
+			options.QueryCacheSize = cacheSize
+		})
+	}
+	var reads int
+	readColors := func(txn Transaction) (interface{}, error) {
+		reads++
+		return []string{"red", "blue"}, nil
+	}
+
+	t.Run("caches values", func(t *testing.T) {
+		driver := newDriver(0)
+		reads = 0
+		for i := 0; i < 3; i++ {
+			value, err := driver.CachedQuery(context.Background(), "colors", time.Minute, readColors)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"red", "blue"}, value)
+		}
+		assert.Equal(t, 1, reads)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&transactions))
+
+		driver.InvalidateCachedQuery("colors")
+		_, err := driver.CachedQuery(context.Background(), "colors", time.Minute, readColors)
+		require.NoError(t, err)
+		assert.Equal(t, 2, reads)
+	})
+
+	t.Run("does not cache errors", func(t *testing.T) {
+		driver := newDriver(0)
+		calls := 0
+		failing := func(txn Transaction) (interface{}, error) {
+			calls++
+			return nil, errMock
+		}
+		for i := 0; i < 2; i++ {
+			_, err := driver.CachedQuery(context.Background(), "colors", time.Minute, failing)
+			assert.True(t, errors.Is(err, errMock))
+		}
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		driver := newDriver(-1)
+		reads = 0
+		for i := 0; i < 2; i++ {
+			_, err := driver.CachedQuery(context.Background(), "colors", time.Minute, readColors)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 2, reads)
+	})
+
+	t.Run("disabled cache does not share executions", func(t *testing.T) {
+		driver := newDriver(-1)
+		started := make(chan struct{})
+		release := make(chan struct{})
+		var calls int32
+		blocking := func(txn Transaction) (interface{}, error) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				close(started)
+				<-release
+			}
+			return []string{"red"}, nil
+		}
+		done := make(chan struct{})
+		go func() {
+			_, err := driver.CachedQuery(context.Background(), "colors", time.Minute, blocking)
+			assert.NoError(t, err)
+			close(done)
+		}()
+		<-started
+
+		_, err := driver.CachedQuery(context.Background(), "colors", time.Minute, blocking)
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+		close(release)
+		<-done
+	})
+
+	t.Run("cancelled miss does not fail concurrent callers", func(t *testing.T) {
+		driver := newDriver(0)
+		started := make(chan struct{})
+		leaderCtx, cancelLeader := context.WithCancel(context.Background())
+		leaderErr := make(chan error)
+		go func() {
+			_, err := driver.CachedQuery(leaderCtx, "colors", time.Minute, func(txn Transaction) (interface{}, error) {
+				close(started)
+				<-leaderCtx.Done()
+				return nil, leaderCtx.Err()
+			})
+			leaderErr <- err
+		}()
+		<-started
+
+		value := make(chan interface{})
+		go func() {
+			colors, err := driver.CachedQuery(context.Background(), "colors", time.Minute, func(txn Transaction) (interface{}, error) {
+				return []string{"red"}, nil
+			})
+			assert.NoError(t, err)
+			value <- colors
+		}()
+		time.Sleep(20 * time.Millisecond)
+		cancelLeader()
+		assert.True(t, errors.Is(<-leaderErr, context.Canceled))
+		assert.Equal(t, []string{"red"}, <-value)
+	})
+
+	t.Run("invalidation during miss", func(t *testing.T) {
+		driver := newDriver(0)
+		_, err := driver.CachedQuery(context.Background(), "colors", time.Minute, func(txn Transaction) (interface{}, error) {
+			driver.InvalidateCachedQuery("colors")
+			return []string{"red"}, nil
+		})
+		require.NoError(t, err)
+
+		reads = 0
+		value, err := driver.CachedQuery(context.Background(), "colors", time.Minute, readColors)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"red", "blue"}, value)
+		assert.Equal(t, 1, reads)
+		assert.Empty(t, driver.queryCache.reads)
+	})
+
+	t.Run("call after invalidation does not share the read in flight", func(t *testing.T) {
+		driver := newDriver(0)
+		started := make(chan struct{})
+		release := make(chan struct{})
+		done := make(chan interface{})
+		go func() {
+			value, err := driver.CachedQuery(context.Background(), "colors", time.Minute, func(txn Transaction) (interface{}, error) {
+				close(started)
+				<-release
+				return []string{"red"}, nil
+			})
+			assert.NoError(t, err)
+			done <- value
+		}()
+		<-started
+
+		driver.InvalidateCachedQuery("colors")
+		value, err := driver.CachedQuery(context.Background(), "colors", time.Minute, func(txn Transaction) (interface{}, error) {
+			return []string{"blue"}, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"blue"}, value)
+		close(release)
+		assert.Equal(t, []string{"red"}, <-done)
+
+		value, err = driver.CachedQuery(context.Background(), "colors", time.Minute, readColors)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"blue"}, value)
+	})
+
+	t.Run("invalid ttl", func(t *testing.T) {
+		driver := newDriver(0)
+		_, err := driver.CachedQuery(context.Background(), "colors", 0, readColors)
+		assert.Error(t, err)
+	})
+}
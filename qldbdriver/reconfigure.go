@@ -0,0 +1,107 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+)
+
+// UpdateOptions changes the options of a driver while it is in use, for example to tune it without restarting a
+// service. fn is called with the current options, and the changes it makes to the following options are applied
+// atomically: RetryPolicy, LoggerVerbosity, MaxConcurrentTransactions, MaxBurstTransactions and
+// PoolExhaustionTimeout. Changes to the other options are ignored. The options are verified like by New, and none of
+// them is applied if they are invalid.
+//
+// Transactions in progress keep the retry policy they started with. When MaxConcurrentTransactions is lowered, the
+// idle sessions beyond it are ended, and the transactions in progress beyond it end their sessions when they complete.
+// MaxConcurrentTransactions cannot be raised for a driver with a custom DriverOptions.SessionPool, which may not hold
+// the additional sessions. With DriverOptions.PoolScalingInterval, the pool keeps its scaled capacity, lowered to
+// MaxConcurrentTransactions if needed, and pool scaling grows it up to the new MaxConcurrentTransactions. The
+// partitions of DriverOptions.PoolPartitions are not changed.
+func (driver *QLDBDriver) UpdateOptions(fn func(*DriverOptions)) error {
+	driver.optionsLock.Lock()
+	defer driver.optionsLock.Unlock()
+
+	options := &DriverOptions{
+		RetryPolicy:               driver.retryPolicy,
+		MaxConcurrentTransactions: driver.maxConcurrentTransactions,
+		LoggerVerbosity:           driver.logger.level(),
+		PoolExhaustionPolicy:      driver.poolExhaustionPolicy,
+		PoolExhaustionTimeout:     driver.poolExhaustionTimeout,
+	}
+	if driver.poolExhaustionPolicy == PoolExhaustionGrow {
+		options.MaxBurstTransactions = driver.semaphore.size
+	}
+	fn(options)
+
+	if options.MaxConcurrentTransactions < 1 {
+		return &qldbDriverError{"MaxConcurrentTransactions must be 1 or greater."}
+	}
+	if options.PoolExhaustionTimeout < 0 {
+		return &qldbDriverError{"PoolExhaustionTimeout must be 0 or greater."}
+	}
+	permits := options.MaxConcurrentTransactions
+	if driver.poolExhaustionPolicy == PoolExhaustionGrow {
+		if options.MaxBurstTransactions == 0 {
+			options.MaxBurstTransactions = 2 * options.MaxConcurrentTransactions
+		}
+		if options.MaxBurstTransactions < options.MaxConcurrentTransactions {
+			return &qldbDriverError{"MaxBurstTransactions must be 0 or at least MaxConcurrentTransactions."}
+		}
+		permits = options.MaxBurstTransactions
+	}
+	resizable, isResizable := driver.sessionPool.(resizableSessionPool)
+	if !isResizable && options.MaxConcurrentTransactions > driver.maxConcurrentTransactions {
+		return &qldbDriverError{"MaxConcurrentTransactions cannot be raised for a driver with a custom SessionPool."}
+	}
+
+	driver.retryPolicy = options.RetryPolicy
+	driver.logger.setLevel(options.LoggerVerbosity)
+	driver.poolExhaustionTimeout = options.PoolExhaustionTimeout
+	if options.MaxConcurrentTransactions != driver.maxConcurrentTransactions {
+		driver.logger.logf(LogInfo, "Changing MaxConcurrentTransactions from %d to %d.",
+			driver.maxConcurrentTransactions, options.MaxConcurrentTransactions)
+		driver.maxConcurrentTransactions = options.MaxConcurrentTransactions
+		if isResizable {
+			capacity := options.MaxConcurrentTransactions
+			if driver.poolScaler != nil {
+				capacity = driver.poolScaler.limit(driver.poolScaler.capacity, capacity)
+			}
+			driver.endSessions(driver.logger, resizable.resize(capacity))
+		}
+	}
+	driver.semaphore.resize(permits)
+	return nil
+}
+
+// endSessions ends sessions in the background.
+func (driver *QLDBDriver) endSessions(logger *qldbLogger, sessions []*PooledSession) {
+	if len(sessions) == 0 {
+		return
+	}
+	go func() {
+		for _, pooledSession := range sessions {
+			if err := pooledSession.session.endSession(context.Background()); err != nil {
+				logger.logf(LogDebug, "Encountered error trying to end session: '%v'", err.Error())
+			}
+		}
+	}()
+}
+
+// currentRetryPolicy returns the retry policy set by New, SetRetryPolicy or UpdateOptions.
+func (driver *QLDBDriver) currentRetryPolicy() RetryPolicy {
+	driver.optionsLock.RLock()
+	defer driver.optionsLock.RUnlock()
+	return driver.retryPolicy
+}
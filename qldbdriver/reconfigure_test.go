@@ -0,0 +1,179 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateOptions(t *testing.T) {
+	newDriver := func(maxConcurrentTransactions int) (*QLDBDriver, *qldbsessioniface.MockClientAPI) {
+		mockClient := &qldbsessioniface.MockClientAPI{
+			SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+				return qldbsessioniface.DefaultSendCommandOutput(params), nil
+			},
+		}
+		return newMockDriver(t, mockClient, func(options *DriverOptions) {
+			options.MaxConcurrentTransactions = maxConcurrentTransactions
+			options.RetryPolicy = RetryPolicy{MaxRetryLimit: 4}
+		}), mockClient
+	}
+	noop := func(txn Transaction) (interface{}, error) {
+		return nil, nil
+	}
+	endSessionCount := func(mockClient *qldbsessioniface.MockClientAPI) int {
+		count := 0
+		for _, input := range mockClient.Inputs() {
+			if input.EndSession != nil {
+				count++
+			}
+		}
+		return count
+	}
+
+	t.Run("retry policy, verbosity and timeout", func(t *testing.T) {
+		testDriver, _ := newDriver(2)
+
+		require.NoError(t, testDriver.UpdateOptions(func(options *DriverOptions) {
+			assert.Equal(t, 2, options.MaxConcurrentTransactions)
+			assert.Equal(t, LogOff, options.LoggerVerbosity)
+			options.RetryPolicy.MaxRetryLimit = 7
+			options.LoggerVerbosity = LogDebug
+			options.PoolExhaustionTimeout = time.Second
+			options.StatementLimit = 3
+		}))
+
+		assert.Equal(t, 7, testDriver.currentRetryPolicy().MaxRetryLimit)
+		assert.Equal(t, LogDebug, testDriver.logger.level())
+		assert.Equal(t, time.Second, testDriver.poolExhaustionTimeout)
+		assert.Equal(t, 0, testDriver.statementLimit)
+	})
+
+	t.Run("invalid options are not applied", func(t *testing.T) {
+		testDriver, _ := newDriver(2)
+
+		err := testDriver.UpdateOptions(func(options *DriverOptions) {
+			options.RetryPolicy.MaxRetryLimit = 7
+			options.MaxConcurrentTransactions = 0
+		})
+		assert.Error(t, err)
+		assert.Equal(t, 4, testDriver.currentRetryPolicy().MaxRetryLimit)
+		assert.Equal(t, 2, testDriver.maxConcurrentTransactions)
+	})
+
+	t.Run("grow", func(t *testing.T) {
+		testDriver, _ := newDriver(1)
+
+		_, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+			_, err := testDriver.Execute(context.Background(), noop)
+			assert.Error(t, err)
+
+			require.NoError(t, testDriver.UpdateOptions(func(options *DriverOptions) {
+				options.MaxConcurrentTransactions = 2
+			}))
+			return testDriver.Execute(context.Background(), noop)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, testDriver.semaphore.available())
+	})
+
+	t.Run("grow wakes up waiting transactions", func(t *testing.T) {
+		testDriver, _ := newDriver(1)
+		testDriver.poolExhaustionPolicy = PoolExhaustionBlock
+		require.True(t, testDriver.semaphore.tryAcquire())
+
+		done := make(chan error)
+		go func() {
+			_, err := testDriver.Execute(context.Background(), noop)
+			done <- err
+		}()
+		time.Sleep(10 * time.Millisecond)
+		require.NoError(t, testDriver.UpdateOptions(func(options *DriverOptions) {
+			options.MaxConcurrentTransactions = 2
+		}))
+
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("the waiting transaction was not woken up")
+		}
+	})
+
+	t.Run("shrink", func(t *testing.T) {
+		testDriver, mockClient := newDriver(3)
+		sessions := make([]*session, 0, 3)
+		for i := 0; i < 3; i++ {
+			session, err := testDriver.getSession(context.Background(), nil)
+			require.NoError(t, err)
+			sessions = append(sessions, session)
+		}
+		testDriver.releaseSession(context.Background(), sessions[0])
+		testDriver.releaseSession(context.Background(), sessions[1])
+
+		require.NoError(t, testDriver.UpdateOptions(func(options *DriverOptions) {
+			options.MaxConcurrentTransactions = 1
+		}))
+		// One of the idle sessions no longer fits in the pool
+		assert.Eventually(t, func() bool { return endSessionCount(mockClient) == 1 }, time.Second, time.Millisecond)
+		assert.Equal(t, 0, testDriver.semaphore.available())
+
+		// The session in use no longer fits either
+		testDriver.releaseSession(context.Background(), sessions[2])
+		assert.Eventually(t, func() bool { return endSessionCount(mockClient) == 2 }, time.Second, time.Millisecond)
+		assert.Equal(t, 1, testDriver.semaphore.available())
+		assert.NotNil(t, testDriver.sessionPool.Get())
+	})
+
+	t.Run("custom pool cannot grow", func(t *testing.T) {
+		testDriver, _ := newDriver(2)
+		testDriver.sessionPool = &customSessionPool{newChannelSessionPool(2)}
+
+		err := testDriver.UpdateOptions(func(options *DriverOptions) {
+			options.MaxConcurrentTransactions = 3
+		})
+		assert.Error(t, err)
+		require.NoError(t, testDriver.UpdateOptions(func(options *DriverOptions) {
+			options.MaxConcurrentTransactions = 1
+		}))
+	})
+}
+
+// customSessionPool is a SessionPool that cannot be resized.
+type customSessionPool struct {
+	SessionPool
+}
+
+func TestSemaphoreResize(t *testing.T) {
+	smphr := makeSemaphore(2)
+	require.True(t, smphr.tryAcquire())
+	require.True(t, smphr.tryAcquire())
+
+	smphr.resize(1)
+	assert.Equal(t, 0, smphr.available())
+	smphr.release()
+	assert.False(t, smphr.tryAcquire())
+	smphr.release()
+	assert.True(t, smphr.tryAcquire())
+
+	smphr.resize(3)
+	assert.Equal(t, 2, smphr.available())
+}
@@ -0,0 +1,177 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"github.com/amzn/ion-go/ion"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+)
+
+// LogRedaction determines how the commands sent to QLDB are logged at LogDebug level: their session tokens, page
+// tokens, commit digests, statement literals and statement parameters could otherwise give access to a session or
+// expose the data of the ledger in the logs.
+type LogRedaction uint8
+
+const (
+	// LogRedactHash logs the tokens and digests of commands as fingerprints, the first 8 hexadecimal digits of their
+	// HMAC-SHA256 with a key drawn at random when the process starts, so that the log lines about the same session can
+	// be correlated without exposing it. The parameters of commands are logged as "<redacted>", since the fingerprints
+	// of values with few possible values, such as booleans or identification numbers, could be reversed by trying them
+	// all, and the literals of statements are redacted. This is the default redaction.
+	LogRedactHash LogRedaction = iota
+	// LogRedactAll logs "<redacted>" in place of the tokens, digests and parameters of commands, and redacts the
+	// literals of statements.
+	LogRedactAll
+	// LogRedactNone logs the tokens, digests, statements and parameters of commands in full, parameters as Ion text.
+	// It is meant for local debugging only.
+	LogRedactNone
+)
+
+const redactedValue = "<redacted>"
+
+// fingerprintKey is the key of the fingerprints of LogRedactHash. It only lives in the memory of the process, so that
+// the fingerprints cannot be reversed from the logs, nor correlated across processes.
+var fingerprintKey = newFingerprintKey()
+
+func newFingerprintKey() []byte {
+	key := make([]byte, sha256.Size)
+	if _, err := rand.Read(key); err != nil {
+		return nil
+	}
+	return key
+}
+
+// redact returns how value is logged with the redaction: its fingerprint, a placeholder, or value itself.
+func (redaction LogRedaction) redact(value []byte, text func() string) string {
+	switch redaction {
+	case LogRedactAll:
+		return redactedValue
+	case LogRedactNone:
+		return text()
+	default:
+		if fingerprintKey == nil {
+			return redactedValue
+		}
+		mac := hmac.New(sha256.New, fingerprintKey)
+		mac.Write(value)
+		return "#" + hex.EncodeToString(mac.Sum(nil)[:4])
+	}
+}
+
+// redactString returns how a string value such as a token is logged with the redaction.
+func (redaction LogRedaction) redactString(value *string) string {
+	if value == nil {
+		return "<nil>"
+	}
+	return redaction.redact([]byte(*value), func() string {
+		return *value
+	})
+}
+
+// redactStatement returns how a statement is logged with the redaction.
+func (redaction LogRedaction) redactStatement(statement *string) string {
+	if statement == nil {
+		return "<nil>"
+	}
+	if redaction == LogRedactNone {
+		return strconv.Quote(*statement)
+	}
+	return strconv.Quote(redactStatement(*statement))
+}
+
+// describeCommand returns the description of a command logged before it is sent to QLDB, with the sensitive values of
+// the command redacted.
+func describeCommand(command *qldbsession.SendCommandInput, redaction LogRedaction) string {
+	var description strings.Builder
+	description.WriteString("SendCommand ")
+	switch {
+	case command.StartSession != nil:
+		description.WriteString("StartSession{LedgerName: " + stringOrNil(command.StartSession.LedgerName) + "}")
+	case command.StartTransaction != nil:
+		description.WriteString("StartTransaction{}")
+	case command.ExecuteStatement != nil:
+		request := command.ExecuteStatement
+		parameters := make([]string, len(request.Parameters))
+		for i, parameter := range request.Parameters {
+			parameters[i] = redaction.redactParameter(parameter)
+		}
+		description.WriteString("ExecuteStatement{TransactionId: " + stringOrNil(request.TransactionId) +
+			", Statement: " + redaction.redactStatement(request.Statement) +
+			", Parameters: [" + strings.Join(parameters, ", ") + "]}")
+	case command.FetchPage != nil:
+		description.WriteString("FetchPage{TransactionId: " + stringOrNil(command.FetchPage.TransactionId) +
+			", NextPageToken: " + redaction.redactString(command.FetchPage.NextPageToken) + "}")
+	case command.CommitTransaction != nil:
+		digest := command.CommitTransaction.CommitDigest
+		description.WriteString("CommitTransaction{TransactionId: " + stringOrNil(command.CommitTransaction.TransactionId) +
+			", CommitDigest: " + redaction.redact(digest, func() string {
+			return base64.StdEncoding.EncodeToString(digest)
+		}) + "}")
+	case command.AbortTransaction != nil:
+		description.WriteString("AbortTransaction{}")
+	case command.EndSession != nil:
+		description.WriteString("EndSession{}")
+	}
+	if command.SessionToken != nil {
+		description.WriteString(" SessionToken: " + redaction.redactString(command.SessionToken))
+	}
+	return description.String()
+}
+
+// redactParameter returns how a statement parameter is logged with the redaction. Parameters are never fingerprinted.
+func (redaction LogRedaction) redactParameter(parameter types.ValueHolder) string {
+	if redaction != LogRedactNone {
+		return redactedValue
+	}
+	if parameter.IonText != nil {
+		return redaction.redactString(parameter.IonText)
+	}
+	return redaction.redact(parameter.IonBinary, func() string {
+		return ionBinaryToText(parameter.IonBinary)
+	})
+}
+
+// ionBinaryToText returns the Ion text of a value in Ion binary, or a placeholder if it is not valid Ion.
+func ionBinaryToText(ionBinary []byte) string {
+	reader := ion.NewReaderBytes(ionBinary)
+	if !reader.Next() {
+		return "<invalid Ion>"
+	}
+	var text bytes.Buffer
+	writer := ion.NewTextWriter(&text)
+	if err := copyIonValue(reader, writer); err != nil {
+		return "<invalid Ion>"
+	}
+	if err := writer.Finish(); err != nil {
+		return "<invalid Ion>"
+	}
+	return strings.TrimSpace(text.String())
+}
+
+func stringOrNil(value *string) string {
+	if value == nil {
+		return "<nil>"
+	}
+	return *value
+}
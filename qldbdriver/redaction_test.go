@@ -0,0 +1,114 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribeCommand(t *testing.T) {
+	token := "secret-session-token"
+	executeStatement := &qldbsession.SendCommandInput{
+		SessionToken: &token,
+		ExecuteStatement: &types.ExecuteStatementRequest{
+			TransactionId: aws.String(mockTxnID),
+			Statement:     aws.String("SELECT * FROM Person WHERE Name = 'Ana' AND Age = ?"),
+			Parameters:    []types.ValueHolder{{IonBinary: ionTextToBinary(t, `{ssn: "123-45-6789"}`)}},
+		},
+	}
+	commit := &qldbsession.SendCommandInput{
+		SessionToken:      &token,
+		CommitTransaction: &types.CommitTransactionRequest{TransactionId: aws.String(mockTxnID), CommitDigest: []byte{1, 2, 3}},
+	}
+	fetchPage := &qldbsession.SendCommandInput{
+		SessionToken: &token,
+		FetchPage:    &types.FetchPageRequest{TransactionId: aws.String(mockTxnID), NextPageToken: aws.String("page-token")},
+	}
+
+	t.Run("hash", func(t *testing.T) {
+		description := describeCommand(executeStatement, LogRedactHash)
+		assert.NotContains(t, description, token)
+		assert.NotContains(t, description, "123-45-6789")
+		assert.NotContains(t, description, "Ana")
+		assert.Contains(t, description, `Statement: "SELECT * FROM Person WHERE Name = '?' AND Age = ?"`)
+		assert.Contains(t, description, "TransactionId: "+mockTxnID)
+		assert.Contains(t, description, "Parameters: [<redacted>]")
+		assert.Regexp(t, `SessionToken: #[0-9a-f]{8}$`, description)
+		// The same token has the same fingerprint, so that the commands of a session can be correlated.
+		fingerprint := description[strings.LastIndex(description, "#"):]
+		assert.True(t, strings.HasSuffix(describeCommand(commit, LogRedactHash), "SessionToken: "+fingerprint))
+		// The fingerprint is keyed, so that it cannot be computed from a guessed value.
+		hash := sha256.Sum256([]byte(token))
+		assert.NotEqual(t, "#"+hex.EncodeToString(hash[:4]), fingerprint)
+
+		assert.NotContains(t, describeCommand(fetchPage, LogRedactHash), "page-token")
+		assert.Regexp(t, `CommitDigest: #[0-9a-f]{8}`, describeCommand(commit, LogRedactHash))
+	})
+
+	t.Run("all", func(t *testing.T) {
+		description := describeCommand(executeStatement, LogRedactAll)
+		assert.Equal(t, "SendCommand ExecuteStatement{TransactionId: "+mockTxnID+
+			`, Statement: "SELECT * FROM Person WHERE Name = '?' AND Age = ?", Parameters: [<redacted>]} SessionToken: <redacted>`, description)
+		assert.Equal(t, "SendCommand CommitTransaction{TransactionId: "+mockTxnID+", CommitDigest: <redacted>} SessionToken: <redacted>",
+			describeCommand(commit, LogRedactAll))
+		assert.Equal(t, "SendCommand FetchPage{TransactionId: "+mockTxnID+", NextPageToken: <redacted>} SessionToken: <redacted>",
+			describeCommand(fetchPage, LogRedactAll))
+	})
+
+	t.Run("none", func(t *testing.T) {
+		description := describeCommand(executeStatement, LogRedactNone)
+		assert.Contains(t, description, `Statement: "SELECT * FROM Person WHERE Name = 'Ana' AND Age = ?"`)
+		assert.Contains(t, description, `Parameters: [{ssn:"123-45-6789"}]`)
+		assert.Contains(t, description, "SessionToken: "+token)
+		assert.Contains(t, describeCommand(commit, LogRedactNone), "CommitDigest: AQID")
+	})
+
+	t.Run("other commands", func(t *testing.T) {
+		assert.Equal(t, "SendCommand StartSession{LedgerName: "+mockLedgerName+"}",
+			describeCommand(&qldbsession.SendCommandInput{StartSession: &types.StartSessionRequest{LedgerName: aws.String(mockLedgerName)}}, LogRedactHash))
+		assert.Equal(t, "SendCommand StartTransaction{} SessionToken: <redacted>",
+			describeCommand(&qldbsession.SendCommandInput{SessionToken: &token, StartTransaction: &types.StartTransactionRequest{}}, LogRedactAll))
+		assert.Equal(t, "SendCommand AbortTransaction{}", describeCommand(&qldbsession.SendCommandInput{AbortTransaction: &types.AbortTransactionRequest{}}, LogRedactAll))
+		assert.Equal(t, "SendCommand EndSession{}", describeCommand(&qldbsession.SendCommandInput{EndSession: &types.EndSessionRequest{}}, LogRedactAll))
+	})
+}
+
+func TestCommunicatorLogRedaction(t *testing.T) {
+	testLogger := &recordingLogger{}
+	token := "secret-session-token"
+	mockSession := new(mockQLDBSession)
+	mockSession.On("SendCommand", mock.Anything, mock.Anything, mock.Anything).Return(&mockSendCommand, nil)
+	testCommunicator := communicator{
+		service:      mockSession,
+		sessionToken: &token,
+		logger:       &qldbLogger{logger: testLogger, verbosity: LogDebug},
+	}
+
+	_, err := testCommunicator.commitTransaction(context.Background(), aws.String(mockTxnID), []byte{1, 2, 3})
+	require.NoError(t, err)
+	require.Len(t, testLogger.messages, 1)
+	assert.NotContains(t, testLogger.messages[0], token)
+	assert.Contains(t, testLogger.messages[0], "[DEBUG] SendCommand CommitTransaction{TransactionId: "+mockTxnID)
+}
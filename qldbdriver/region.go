@@ -0,0 +1,81 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/qldb"
+	"github.com/aws/aws-sdk-go-v2/service/qldb/types"
+)
+
+// LedgerDescriber is the subset of the qldb.Client methods used to verify the region of the ledger, see
+// DriverOptions.LedgerDescriber.
+type LedgerDescriber interface {
+	DescribeLedger(ctx context.Context, params *qldb.DescribeLedgerInput, optFns ...func(*qldb.Options)) (*qldb.DescribeLedgerOutput, error)
+}
+
+var _ LedgerDescriber = (*qldb.Client)(nil)
+
+// ledgerRegionCheck verifies once that the ledger is in the region of the qldbsession.Client.
+type ledgerRegionCheck struct {
+	describer LedgerDescriber
+	lock      sync.Mutex
+	done      bool
+	err       error
+}
+
+// status returns whether the region of the ledger was verified, and the LedgerRegionError if it is not the region of
+// the qldbsession.Client.
+func (check *ledgerRegionCheck) status() (bool, error) {
+	check.lock.Lock()
+	defer check.lock.Unlock()
+	return check.done, check.err
+}
+
+// verify describes the ledger and returns a LedgerRegionError if it is not in clientRegion, the region of the
+// qldbsession.Client. The ledger is only described once, and the same error is returned by later calls. Failures to
+// describe the ledger, other than the ledger not being found, are logged rather than returned, so that the check cannot
+// make a working driver fail.
+func (check *ledgerRegionCheck) verify(ctx context.Context, logger *qldbLogger, ledgerName string, clientRegion string) error {
+	check.lock.Lock()
+	defer check.lock.Unlock()
+	if check.done {
+		return check.err
+	}
+	var describerRegion string
+	output, err := check.describer.DescribeLedger(ctx, &qldb.DescribeLedgerInput{Name: &ledgerName}, func(options *qldb.Options) {
+		describerRegion = options.Region
+	})
+	if err != nil {
+		var rnf *types.ResourceNotFoundException
+		if !errors.As(err, &rnf) {
+			if ctx.Err() != nil {
+				return nil
+			}
+			logger.logf(LogInfo, "Could not verify the region of the ledger: '%v'", err)
+			check.done = true
+			return nil
+		}
+		check.err = &LedgerRegionError{LedgerName: ledgerName, ClientRegion: clientRegion, DescriberRegion: describerRegion, err: err}
+	} else if parsed, parseErr := arn.Parse(aws.ToString(output.Arn)); parseErr == nil && parsed.Region != clientRegion {
+		check.err = &LedgerRegionError{LedgerName: ledgerName, ClientRegion: clientRegion, DescriberRegion: describerRegion, LedgerRegion: parsed.Region}
+	}
+	check.done = true
+	return check.err
+}
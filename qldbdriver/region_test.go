@@ -0,0 +1,144 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/qldb"
+	"github.com/aws/aws-sdk-go-v2/service/qldb/types"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockLedgerDescriber describes ledgers from the region it is configured for.
+type mockLedgerDescriber struct {
+	region string
+	arn    string
+	err    error
+	calls  int
+}
+
+func (describer *mockLedgerDescriber) DescribeLedger(ctx context.Context, params *qldb.DescribeLedgerInput, optFns ...func(*qldb.Options)) (*qldb.DescribeLedgerOutput, error) {
+	describer.calls++
+	options := qldb.Options{Region: describer.region}
+	for _, optFn := range optFns {
+		optFn(&options)
+	}
+	if describer.err != nil {
+		return nil, describer.err
+	}
+	return &qldb.DescribeLedgerOutput{Name: params.Name, Arn: aws.String(describer.arn)}, nil
+}
+
+func TestLedgerRegionCheck(t *testing.T) {
+	newDriver := func(describer *mockLedgerDescriber) (*QLDBDriver, *qldbsessioniface.MockClientAPI) {
+		mockClient := &qldbsessioniface.MockClientAPI{
+			SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+				options := qldbsession.Options{Region: "us-east-1"}
+				for _, optFn := range optFns {
+					optFn(&options)
+				}
+				return qldbsessioniface.DefaultSendCommandOutput(params), nil
+			},
+		}
+		return newMockDriver(t, mockClient, func(options *DriverOptions) {
+			options.LedgerDescriber = describer
+		}), mockClient
+	}
+	ledgerArn := func(region string) string {
+		return "arn:aws:qldb:" + region + ":123456789012:ledger/" + mockLedgerName
+	}
+	noop := func(txn Transaction) (interface{}, error) {
+		return nil, nil
+	}
+
+	t.Run("same region", func(t *testing.T) {
+		describer := &mockLedgerDescriber{region: "us-east-1", arn: ledgerArn("us-east-1")}
+		testDriver, _ := newDriver(describer)
+		require.NoError(t, testDriver.Validate(context.Background()))
+		_, err := testDriver.Execute(context.Background(), noop)
+		require.NoError(t, err)
+		assert.Equal(t, 1, describer.calls)
+	})
+
+	t.Run("ledger in another region", func(t *testing.T) {
+		describer := &mockLedgerDescriber{region: "eu-west-1", arn: ledgerArn("eu-west-1")}
+		testDriver, mockClient := newDriver(describer)
+		_, err := testDriver.Execute(context.Background(), noop)
+		var regionErr *LedgerRegionError
+		require.True(t, errors.As(err, &regionErr))
+		assert.Equal(t, "us-east-1", regionErr.ClientRegion)
+		assert.Equal(t, "eu-west-1", regionErr.LedgerRegion)
+		assert.Equal(t, "Ledger "+mockLedgerName+" is in region eu-west-1, but the qldbsession.Client is configured for region us-east-1.", err.Error())
+		assert.Equal(t, 10, testDriver.semaphore.available())
+		assert.Eventually(t, func() bool {
+			for _, input := range mockClient.Inputs() {
+				if input.EndSession != nil {
+					return true
+				}
+			}
+			return false
+		}, time.Second, time.Millisecond, "the session started in the wrong region is ended")
+
+		sessionsStarted := len(mockClient.Inputs())
+		_, err = testDriver.Execute(context.Background(), noop)
+		assert.Equal(t, regionErr, err)
+		assert.Equal(t, 1, describer.calls)
+		assert.Equal(t, sessionsStarted, len(mockClient.Inputs()), "no session is started once the region is known")
+	})
+
+	t.Run("ledger not found", func(t *testing.T) {
+		notFound := &types.ResourceNotFoundException{Message: aws.String("Ledger not found")}
+		describer := &mockLedgerDescriber{region: "us-west-2", err: notFound}
+		testDriver, _ := newDriver(describer)
+		err := testDriver.Validate(context.Background())
+		var regionErr *LedgerRegionError
+		require.True(t, errors.As(err, &regionErr))
+		assert.Equal(t, "us-west-2", regionErr.DescriberRegion)
+		assert.Equal(t, "", regionErr.LedgerRegion)
+		assert.Contains(t, err.Error(), "was not found in region us-west-2")
+		assert.True(t, errors.Is(err, notFound))
+	})
+
+	t.Run("describe failure is ignored", func(t *testing.T) {
+		describer := &mockLedgerDescriber{region: "us-east-1", err: errMock}
+		testDriver, _ := newDriver(describer)
+		_, err := testDriver.Execute(context.Background(), noop)
+		require.NoError(t, err)
+		_, err = testDriver.Execute(context.Background(), noop)
+		require.NoError(t, err)
+		assert.Equal(t, 1, describer.calls)
+	})
+
+	t.Run("retried when the context is done", func(t *testing.T) {
+		describer := &mockLedgerDescriber{region: "us-east-1", arn: ledgerArn("us-east-1"), err: errMock}
+		check := &ledgerRegionCheck{describer: describer}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.NoError(t, check.verify(ctx, mockLogger, mockLedgerName, "us-east-1"))
+		verified, _ := check.status()
+		assert.False(t, verified)
+		describer.err = nil
+		assert.NoError(t, check.verify(context.Background(), mockLogger, mockLedgerName, "us-east-1"))
+		verified, _ = check.status()
+		assert.True(t, verified)
+	})
+}
@@ -0,0 +1,241 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// recordingVersion is the version of the format written by CommandRecorder.
+const recordingVersion = 1
+
+// recording is the format written by CommandRecorder and read by CommandReplayer.
+type recording struct {
+	Version  int               `json:"version"`
+	Commands []recordedCommand `json:"commands"`
+}
+
+// recordedCommand is a command sent to QLDB, with either its output or its error.
+type recordedCommand struct {
+	Input  json.RawMessage                `json:"input"`
+	Output *qldbsession.SendCommandOutput `json:"output,omitempty"`
+	Error  *recordedError                 `json:"error,omitempty"`
+}
+
+// recordedError is an error returned by the SDK. The exceptions of QLDB Session are replayed with their type, the other
+// API errors as smithy.GenericAPIError, and the other errors with their message only.
+type recordedError struct {
+	Code       string `json:"code,omitempty"`
+	Message    string `json:"message"`
+	StatusCode int    `json:"statusCode,omitempty"`
+}
+
+func newRecordedError(err error) *recordedError {
+	recorded := &recordedError{Message: err.Error()}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		recorded.Code = apiErr.ErrorCode()
+		recorded.Message = apiErr.ErrorMessage()
+	}
+	var responseErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &responseErr) {
+		recorded.StatusCode = responseErr.HTTPStatusCode()
+	}
+	return recorded
+}
+
+// err returns an error like the one that was recorded.
+func (recorded *recordedError) err() error {
+	message := recorded.Message
+	var err error
+	switch recorded.Code {
+	case "":
+		err = errors.New(message)
+	case "BadRequestException":
+		err = &types.BadRequestException{Message: &message}
+	case "CapacityExceededException":
+		err = &types.CapacityExceededException{Message: &message}
+	case "InvalidSessionException":
+		err = &types.InvalidSessionException{Message: &message}
+	case "LimitExceededException":
+		err = &types.LimitExceededException{Message: &message}
+	case "OccConflictException":
+		err = &types.OccConflictException{Message: &message}
+	case "RateExceededException":
+		err = &types.RateExceededException{Message: &message}
+	default:
+		err = &smithy.GenericAPIError{Code: recorded.Code, Message: message}
+	}
+	if recorded.StatusCode != 0 {
+		err = &awshttp.ResponseError{ResponseError: &smithyhttp.ResponseError{
+			Response: &smithyhttp.Response{Response: &http.Response{StatusCode: recorded.StatusCode}},
+			Err:      err,
+		}}
+	}
+	return err
+}
+
+// CommandRecorder records the commands that drivers send to QLDB and their responses, so that the run of a test
+// against QLDB can be replayed by a CommandReplayer, without AWS access. Add ClientOption to
+// DriverOptions.ClientOptions, and call WriteTo once the test completes to save the commands, for example to a file
+// under testdata. The commands include the statements and their parameters, and the documents returned by QLDB.
+// CommandRecorder is safe for concurrent use.
+type CommandRecorder struct {
+	lock     sync.Mutex
+	commands []recordedCommand
+}
+
+// NewCommandRecorder creates a CommandRecorder that has not recorded any command.
+func NewCommandRecorder() *CommandRecorder {
+	return &CommandRecorder{}
+}
+
+// ClientOption returns the client option recording the commands sent by a driver.
+func (recorder *CommandRecorder) ClientOption() func(*qldbsession.Options) {
+	return withInitializeMiddleware(middleware.InitializeMiddlewareFunc("QLDBCommandRecorder", recorder.handleInitialize))
+}
+
+func (recorder *CommandRecorder) handleInitialize(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+	input, ok := in.Parameters.(*qldbsession.SendCommandInput)
+	if !ok {
+		return next.HandleInitialize(ctx, in)
+	}
+	encodedInput, err := json.Marshal(input)
+	if err != nil {
+		return middleware.InitializeOutput{}, middleware.Metadata{}, err
+	}
+	out, metadata, err := next.HandleInitialize(ctx, in)
+	command := recordedCommand{Input: encodedInput}
+	if err != nil {
+		command.Error = newRecordedError(err)
+	} else {
+		command.Output, _ = out.Result.(*qldbsession.SendCommandOutput)
+	}
+	recorder.lock.Lock()
+	recorder.commands = append(recorder.commands, command)
+	recorder.lock.Unlock()
+	return out, metadata, err
+}
+
+// WriteTo writes the commands recorded so far to w, as indented JSON.
+func (recorder *CommandRecorder) WriteTo(w io.Writer) (int64, error) {
+	recorder.lock.Lock()
+	data, err := json.MarshalIndent(recording{Version: recordingVersion, Commands: recorder.commands}, "", "  ")
+	recorder.lock.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// CommandReplayer responds to the commands of drivers with the responses recorded by a CommandRecorder, instead of
+// sending them to QLDB, so that tests of transactional flows are deterministic and run without AWS access. Add
+// ClientOption to DriverOptions.ClientOptions of a driver created with a client that is never called, such as
+// qldbsession.New(qldbsession.Options{Region: "us-east-1"}), which needs no credentials.
+//
+// Each recorded command responds once, to the first command sent with the same input, so the test must send the
+// same statements with the same parameters as the recorded run. A command that was not recorded fails with an error.
+// CommandReplayer is safe for concurrent use.
+type CommandReplayer struct {
+	lock     sync.Mutex
+	commands []recordedCommand
+	replayed []bool
+}
+
+// NewCommandReplayer creates a CommandReplayer for the commands written by CommandRecorder.WriteTo to r.
+func NewCommandReplayer(r io.Reader) (*CommandReplayer, error) {
+	var recorded recording
+	if err := json.NewDecoder(r).Decode(&recorded); err != nil {
+		return nil, err
+	}
+	if recorded.Version != recordingVersion {
+		return nil, &qldbDriverError{"Unsupported version of recorded commands."}
+	}
+	// The inputs are indented by WriteTo, and compared with the compact inputs of the commands sent
+	for i, command := range recorded.Commands {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, command.Input); err != nil {
+			return nil, err
+		}
+		recorded.Commands[i].Input = compact.Bytes()
+	}
+	return &CommandReplayer{commands: recorded.Commands, replayed: make([]bool, len(recorded.Commands))}, nil
+}
+
+// ClientOption returns the client option replaying the commands sent by a driver.
+func (replayer *CommandReplayer) ClientOption() func(*qldbsession.Options) {
+	return withInitializeMiddleware(middleware.InitializeMiddlewareFunc("QLDBCommandReplayer", replayer.handleInitialize))
+}
+
+func (replayer *CommandReplayer) handleInitialize(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+	input, ok := in.Parameters.(*qldbsession.SendCommandInput)
+	if !ok {
+		return next.HandleInitialize(ctx, in)
+	}
+	encodedInput, err := json.Marshal(input)
+	if err != nil {
+		return middleware.InitializeOutput{}, middleware.Metadata{}, err
+	}
+	replayer.lock.Lock()
+	defer replayer.lock.Unlock()
+	for i, command := range replayer.commands {
+		if replayer.replayed[i] || !bytes.Equal(command.Input, encodedInput) {
+			continue
+		}
+		replayer.replayed[i] = true
+		if command.Error != nil {
+			return middleware.InitializeOutput{}, middleware.Metadata{}, command.Error.err()
+		}
+		return middleware.InitializeOutput{Result: command.Output}, middleware.Metadata{}, nil
+	}
+	return middleware.InitializeOutput{}, middleware.Metadata{}, &qldbDriverError{"No recorded command matches the command " + string(encodedInput) + "."}
+}
+
+// Remaining returns the number of recorded commands that were not replayed yet, for example to verify that a test
+// replayed the whole recorded run.
+func (replayer *CommandReplayer) Remaining() int {
+	replayer.lock.Lock()
+	defer replayer.lock.Unlock()
+	remaining := 0
+	for _, replayed := range replayer.replayed {
+		if !replayed {
+			remaining++
+		}
+	}
+	return remaining
+}
+
+// withInitializeMiddleware returns a client option adding m first to the initialize step of every command, so that it
+// sees the commands as sent by the driver and their final outcome, after the SDK retries.
+func withInitializeMiddleware(m middleware.InitializeMiddleware) func(*qldbsession.Options) {
+	return func(options *qldbsession.Options) {
+		options.APIOptions = append(options.APIOptions, func(stack *middleware.Stack) error {
+			return stack.Initialize.Add(m, middleware.Before)
+		})
+	}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amzn/ion-go/ion"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/errs"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withFakeQLDB returns a client option responding to every command with respond, instead of sending it to QLDB.
+func withFakeQLDB(respond func(params *qldbsession.SendCommandInput) (*qldbsession.SendCommandOutput, error)) func(*qldbsession.Options) {
+	return func(options *qldbsession.Options) {
+		options.APIOptions = append(options.APIOptions, func(stack *middleware.Stack) error {
+			return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("FakeQLDB", func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+				output, err := respond(in.Parameters.(*qldbsession.SendCommandInput))
+				return middleware.InitializeOutput{Result: output}, middleware.Metadata{}, err
+			}), middleware.After)
+		})
+	}
+}
+
+func TestCommandRecordAndReplay(t *testing.T) {
+	document := ionTextToBinary(t, "{name: \"Alice\"}")
+	occ := &types.OccConflictException{Message: aws.String("Transaction aborted by OCC")}
+	occFailures := 1
+	respond := func(params *qldbsession.SendCommandInput) (*qldbsession.SendCommandOutput, error) {
+		if params.CommitTransaction != nil && occFailures > 0 {
+			occFailures--
+			return nil, occ
+		}
+		output := qldbsessioniface.DefaultSendCommandOutput(params)
+		if params.ExecuteStatement != nil && strings.HasPrefix(*params.ExecuteStatement.Statement, "SELECT") {
+			output.ExecuteStatement.FirstPage.Values = []types.ValueHolder{{IonBinary: document}}
+		}
+		return output, nil
+	}
+	newDriver := func(t *testing.T, clientOption func(*qldbsession.Options)) *QLDBDriver {
+		driver, err := New(mockLedgerName, qldbsession.New(qldbsession.Options{Region: "us-east-1"}), func(options *DriverOptions) {
+			options.LoggerVerbosity = LogOff
+			options.RetryPolicy.Backoff = ExponentialBackoffStrategy{}
+			options.ClientOptions = append(options.ClientOptions, clientOption)
+		})
+		require.NoError(t, err)
+		return driver
+	}
+	flow := func(driver *QLDBDriver, name string) (interface{}, error) {
+		return driver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+			result, err := txn.Execute("SELECT name FROM People WHERE name = ?", name)
+			if err != nil {
+				return nil, err
+			}
+			var names []string
+			for result.Next(txn) {
+				var person struct {
+					Name string `ion:"name"`
+				}
+				if err := ion.Unmarshal(result.GetCurrentData(), &person); err != nil {
+					return nil, err
+				}
+				names = append(names, person.Name)
+			}
+			return names, result.Err()
+		})
+	}
+
+	recorder := NewCommandRecorder()
+	recordingDriver := newDriver(t, func(options *qldbsession.Options) {
+		withFakeQLDB(respond)(options)
+		recorder.ClientOption()(options)
+	})
+	recorded, err := flow(recordingDriver, "Alice")
+	require.NoError(t, err)
+	recordingDriver.Shutdown(context.Background())
+	assert.Equal(t, []string{"Alice"}, recorded)
+
+	var buf bytes.Buffer
+	_, err = recorder.WriteTo(&buf)
+	require.NoError(t, err)
+	data := buf.Bytes()
+	assert.Contains(t, string(data), "OccConflictException")
+
+	t.Run("replayed", func(t *testing.T) {
+		replayer, err := NewCommandReplayer(bytes.NewReader(data))
+		require.NoError(t, err)
+		replayed := 0
+		driver := newDriver(t, replayer.ClientOption())
+		driver.retryPolicy.OnRetry = func(attempt int, err error, nextDelay time.Duration) error {
+			assert.True(t, errs.IsOCCConflict(err))
+			replayed++
+			return nil
+		}
+		result, err := flow(driver, "Alice")
+		require.NoError(t, err)
+		driver.Shutdown(context.Background())
+		assert.Equal(t, recorded, result)
+		assert.Equal(t, 1, replayed, "the recorded OCC conflict is replayed")
+		assert.Equal(t, 0, replayer.Remaining())
+	})
+
+	t.Run("command not recorded", func(t *testing.T) {
+		replayer, err := NewCommandReplayer(bytes.NewReader(data))
+		require.NoError(t, err)
+		driver := newDriver(t, replayer.ClientOption())
+		defer driver.Shutdown(context.Background())
+		_, err = flow(driver, "Bob")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "No recorded command matches the command")
+		assert.NotZero(t, replayer.Remaining())
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		_, err := NewCommandReplayer(strings.NewReader(`{"version": 2, "commands": []}`))
+		assert.EqualError(t, err, "Unsupported version of recorded commands.")
+	})
+}
+
+func TestRecordedError(t *testing.T) {
+	testCases := []struct {
+		name  string
+		err   error
+		check func(err error) bool
+	}{
+		{"OCC conflict", &types.OccConflictException{Message: aws.String("occ")}, errs.IsOCCConflict},
+		{"transaction expired", &types.InvalidSessionException{Message: aws.String("Transaction 324weqr2 has expired")}, errs.IsTransactionExpired},
+		{"bad request", &types.BadRequestException{Message: aws.String("bad request")}, errs.IsBadRequest},
+		{"capacity exceeded", &types.CapacityExceededException{Message: aws.String("capacity")}, errs.IsCapacityExceeded},
+		{"server error", (&recordedError{Code: "InternalFailure", Message: "failure", StatusCode: 500}).err(), errs.IsServerError},
+		{"forbidden", (&recordedError{Code: "AccessDeniedException", Message: "denied", StatusCode: 403}).err(), errs.IsCredentialsError},
+		{"other", errors.New("connection reset"), func(err error) bool { return err.Error() == "connection reset" }},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.True(t, tc.check(tc.err))
+			recorded := newRecordedError(tc.err)
+			replayed := recorded.err()
+			assert.True(t, tc.check(replayed))
+			assert.Equal(t, recorded, newRecordedError(replayed))
+		})
+	}
+}
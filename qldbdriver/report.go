@@ -0,0 +1,112 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"time"
+)
+
+// TransactionReport summarizes the statements executed by the attempt of QLDBDriver.Execute whose transaction was
+// committed. See ExecuteOptions.Report.
+type TransactionReport struct {
+	// The ID of the committed transaction.
+	TransactionID string
+	// The number of the attempt that committed the transaction, starting at 1.
+	Attempt int
+	// The statements executed within the transaction, in the order they were executed.
+	Statements []StatementReport
+	// The total number of read IOs consumed by the statements.
+	ReadIOs int64
+	// The total number of write IOs consumed by the statements.
+	WriteIOs int64
+	// The total server-side processing time of the statements.
+	ProcessingTime time.Duration
+	// The total time spent waiting for QLDB to execute the statements and return their pages.
+	Latency time.Duration
+	// The metrics of all the attempts of Execute, including the attempts that failed and were retried, when
+	// ExecuteOptions.AccumulateAttemptMetrics is set. The fields above only cover the attempt that committed.
+	Totals ExecutionTotals
+}
+
+// ExecutionTotals sums the metrics of all the attempts of a QLDBDriver.Execute call, for accurate cost accounting:
+// the IOs consumed by an attempt that failed, for example because of an OCC conflict, are billed even though its
+// transaction was not committed. Like a TransactionReport, they only cover the pages fetched by the function passed to
+// Execute.
+type ExecutionTotals struct {
+	// The number of attempts, including the ones whose transaction failed to start.
+	Attempts int
+	// The total number of read IOs consumed by the statements of all the attempts.
+	ReadIOs int64
+	// The total number of write IOs consumed by the statements of all the attempts.
+	WriteIOs int64
+	// The total server-side processing time of the statements of all the attempts.
+	ProcessingTime time.Duration
+	// The total time spent waiting for QLDB to execute the statements of all the attempts and return their pages.
+	Latency time.Duration
+}
+
+// add adds the metrics of an attempt, whose transaction is nil if it failed to start.
+func (totals *ExecutionTotals) add(txn *transaction, attempt int) {
+	totals.Attempts++
+	if txn == nil {
+		return
+	}
+	report := newTransactionReport(txn, attempt)
+	totals.ReadIOs += report.ReadIOs
+	totals.WriteIOs += report.WriteIOs
+	totals.ProcessingTime += report.ProcessingTime
+	totals.Latency += report.Latency
+}
+
+// StatementReport summarizes the execution of a statement within a transaction. The rows, IOs and timings only cover
+// the pages fetched by the function passed to Execute, so rows of a result that was not fully read are not included.
+type StatementReport struct {
+	// The PartiQL statement, without its parameters.
+	Statement string
+	// The number of rows read from the result of the statement.
+	Rows int64
+	// The number of read IOs consumed by the statement.
+	ReadIOs int64
+	// The number of write IOs consumed by the statement.
+	WriteIOs int64
+	// The server-side processing time of the statement.
+	ProcessingTime time.Duration
+	// The time spent waiting for QLDB to execute the statement and return its pages.
+	Latency time.Duration
+}
+
+// newTransactionReport summarizes the statements executed so far within txn, which was started by the provided attempt.
+func newTransactionReport(txn *transaction, attempt int) TransactionReport {
+	report := TransactionReport{
+		TransactionID: *txn.id,
+		Attempt:       attempt,
+		Statements:    make([]StatementReport, len(txn.results)),
+	}
+	for i, res := range txn.results {
+		statement := StatementReport{
+			Statement:      res.statement,
+			Rows:           res.rows,
+			ReadIOs:        res.ioUsage.ReadIOs(),
+			WriteIOs:       res.ioUsage.WriteIOs(),
+			ProcessingTime: res.timingInfo.ProcessingTime(),
+			Latency:        res.latency,
+		}
+		report.Statements[i] = statement
+		report.ReadIOs += statement.ReadIOs
+		report.WriteIOs += statement.WriteIOs
+		report.ProcessingTime += statement.ProcessingTime
+		report.Latency += statement.Latency
+	}
+	return report
+}
@@ -0,0 +1,148 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTransactionReport(t *testing.T) {
+	txnID := "txnID"
+	txn := &transaction{
+		id: &txnID,
+		results: []*result{
+			{statement: "SELECT * FROM Person", rows: 2, ioUsage: newIOUsage(3, 0), timingInfo: newTimingInformation(5), latency: 20 * time.Millisecond},
+			{statement: "INSERT INTO Person ?", rows: 1, ioUsage: newIOUsage(1, 2), timingInfo: newTimingInformation(7), latency: 30 * time.Millisecond},
+		},
+	}
+
+	report := newTransactionReport(txn, 2)
+	assert.Equal(t, TransactionReport{
+		TransactionID: txnID,
+		Attempt:       2,
+		Statements: []StatementReport{
+			{Statement: "SELECT * FROM Person", Rows: 2, ReadIOs: 3, WriteIOs: 0, ProcessingTime: 5 * time.Millisecond, Latency: 20 * time.Millisecond},
+			{Statement: "INSERT INTO Person ?", Rows: 1, ReadIOs: 1, WriteIOs: 2, ProcessingTime: 7 * time.Millisecond, Latency: 30 * time.Millisecond},
+		},
+		ReadIOs:        4,
+		WriteIOs:       2,
+		ProcessingTime: 12 * time.Millisecond,
+		Latency:        50 * time.Millisecond,
+	}, report)
+}
+
+func TestExecuteReport(t *testing.T) {
+	row := ionTextToBinary(t, "{name: \"Alice\"}")
+	newTestDriver := func(commitErrors int32) *QLDBDriver {
+		mockClient := &qldbsessioniface.MockClientAPI{
+			SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+				switch {
+				case params.ExecuteStatement != nil:
+					return &qldbsession.SendCommandOutput{ExecuteStatement: &types.ExecuteStatementResult{
+						FirstPage:         &types.Page{Values: []types.ValueHolder{{IonBinary: row}, {IonBinary: row}}},
+						ConsumedIOs:       &types.IOUsage{ReadIOs: 4, WriteIOs: 1},
+						TimingInformation: &types.TimingInformation{ProcessingTimeMilliseconds: 3},
+					}}, nil
+				case params.CommitTransaction != nil && atomic.AddInt32(&commitErrors, -1) >= 0:
+					return nil, testOCC
+				}
+				return qldbsessioniface.DefaultSendCommandOutput(params), nil
+			},
+		}
+		return newMockDriver(t, mockClient, func(options *DriverOptions) {
+			options.RetryPolicy = RetryPolicy{
+				MaxRetryLimit: 2,
+				Backoff:       ExponentialBackoffStrategy{SleepBase: time.Millisecond, SleepCap: time.Millisecond}}
+		})
+	}
+	readAll := func(txn Transaction) (interface{}, error) {
+		res, err := txn.Execute("SELECT * FROM Person WHERE name = ?", "Alice")
+		if err != nil {
+			return nil, err
+		}
+		for res.Next(txn) {
+		}
+		return nil, res.Err()
+	}
+
+	t.Run("committed attempt", func(t *testing.T) {
+		var report TransactionReport
+		_, err := newTestDriver(1).Execute(context.Background(), readAll, func(options *ExecuteOptions) {
+			options.Report = &report
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, qldbsessioniface.MockTransactionID, report.TransactionID)
+		assert.Equal(t, 2, report.Attempt)
+		require.Len(t, report.Statements, 1)
+		statement := report.Statements[0]
+		assert.Equal(t, "SELECT * FROM Person WHERE name = ?", statement.Statement)
+		assert.Equal(t, int64(2), statement.Rows)
+		assert.Equal(t, int64(4), statement.ReadIOs)
+		assert.Equal(t, int64(1), statement.WriteIOs)
+		assert.Equal(t, 3*time.Millisecond, statement.ProcessingTime)
+		assert.Equal(t, int64(4), report.ReadIOs)
+		assert.Equal(t, int64(1), report.WriteIOs)
+		assert.Equal(t, 3*time.Millisecond, report.ProcessingTime)
+		assert.Equal(t, statement.Latency, report.Latency)
+	})
+
+	t.Run("reset on error", func(t *testing.T) {
+		report := TransactionReport{TransactionID: "previous"}
+		_, err := newTestDriver(3).Execute(context.Background(), readAll, func(options *ExecuteOptions) {
+			options.Report = &report
+		})
+		assert.True(t, errors.Is(err, testOCC))
+		assert.Equal(t, TransactionReport{}, report)
+	})
+
+	t.Run("accumulated attempts", func(t *testing.T) {
+		var report TransactionReport
+		_, err := newTestDriver(1).Execute(context.Background(), readAll, func(options *ExecuteOptions) {
+			options.Report = &report
+			options.AccumulateAttemptMetrics = true
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(4), report.ReadIOs)
+		assert.Equal(t, 2, report.Totals.Attempts)
+		assert.Equal(t, int64(8), report.Totals.ReadIOs)
+		assert.Equal(t, int64(2), report.Totals.WriteIOs)
+		assert.Equal(t, 6*time.Millisecond, report.Totals.ProcessingTime)
+		assert.True(t, report.Totals.Latency >= report.Latency)
+	})
+
+	t.Run("accumulated attempts on error", func(t *testing.T) {
+		var report TransactionReport
+		_, err := newTestDriver(3).Execute(context.Background(), readAll, func(options *ExecuteOptions) {
+			options.Report = &report
+			options.AccumulateAttemptMetrics = true
+		})
+		assert.True(t, errors.Is(err, testOCC))
+
+		assert.Empty(t, report.TransactionID)
+		assert.Equal(t, 3, report.Totals.Attempts)
+		assert.Equal(t, int64(12), report.Totals.ReadIOs)
+	})
+}
@@ -14,7 +14,9 @@ and limitations under the License.
 package qldbdriver
 
 import (
+	"bytes"
 	"context"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
 )
@@ -29,17 +31,33 @@ type Result interface {
 }
 
 type result struct {
-	ctx          context.Context
-	communicator qldbService
-	txnID        *string
-	pageValues   []types.ValueHolder
-	pageToken    *string
-	index        int
-	logger       *qldbLogger
-	ionBinary    []byte
-	ioUsage      *IOUsage
-	timingInfo   *TimingInformation
-	err          error
+	ctx           context.Context
+	communicator  qldbService
+	txnID         *string
+	pageValues    []types.ValueHolder
+	pageToken     *string
+	index         int
+	logger        *qldbLogger
+	ionBinary     []byte
+	ioUsage       *IOUsage
+	timingInfo    *TimingInformation
+	err           error
+	statementHash []byte
+	position      int64
+	statement     string
+	paramCount    int
+	pagesFetched  int
+	logged        bool
+	rows          int64
+	latency       time.Duration
+	committed     bool
+	releaseRows   bool
+	fetchRetry    *fetchPageRetry
+	sizeLimit     *resultSizeLimit
+	bytes         int64
+	capabilities  *serverCapabilities
+	// parameters are kept for the OCC conflict stats when DriverOptions.CaptureOCCConflictParameters is set.
+	parameters []interface{}
 }
 
 // Next advances to the next row of data in the current result set.
@@ -53,23 +71,40 @@ func (result *result) Next(txn Transaction) bool {
 	if result.index >= len(result.pageValues) {
 		if result.pageToken == nil {
 			// No more data left
+			result.logDiagnostics()
 			return false
 		}
 		result.err = result.getNextPage()
 		if result.err != nil {
+			result.logDiagnostics()
 			return false
 		}
 		return result.Next(txn)
 	}
 
+	if result.err = result.sizeLimit.check(result, len(result.pageValues[result.index].IonBinary)); result.err != nil {
+		result.logDiagnostics()
+		return false
+	}
 	result.ionBinary = result.pageValues[result.index].IonBinary
+	if result.releaseRows {
+		result.pageValues[result.index] = types.ValueHolder{}
+	}
 	result.index++
+	result.position++
+	result.rows++
+	result.bytes += int64(len(result.ionBinary))
 
 	return true
 }
 
 func (result *result) getNextPage() error {
-	nextPage, err := result.communicator.fetchPage(result.ctx, result.pageToken, result.txnID)
+	if result.committed {
+		return &StreamingResultError{TransactionID: *result.txnID}
+	}
+	start := time.Now()
+	nextPage, err := result.fetchRetry.fetchPage(result.ctx, result.communicator, result.logger, result.pageToken, result.txnID)
+	result.latency += time.Since(start)
 	if err != nil {
 		return err
 	}
@@ -77,11 +112,25 @@ func (result *result) getNextPage() error {
 	result.pageValues = nextPage.Page.Values
 	result.pageToken = nextPage.Page.NextPageToken
 	result.index = 0
+	result.pagesFetched++
 	result.updateMetrics(nextPage)
 	return nil
 }
 
+// logDiagnostics logs a single line summarizing the statement execution once the result set has been consumed, or once
+// the transaction of a result that was not consumed ends.
+func (result *result) logDiagnostics() {
+	if result.logged || result.logger == nil || result.logger.level() < LogDebug {
+		return
+	}
+	result.logged = true
+	result.logger.logf(LogDebug, "Statement diagnostics: statement=%q parameters=%d pages=%d readIOs=%d writeIOs=%d processingTimeMs=%d",
+		result.logger.statement(result.statement), result.paramCount, result.pagesFetched,
+		*result.ioUsage.readIOs, *result.ioUsage.writeIOs, *result.timingInfo.processingTimeMilliseconds)
+}
+
 func (result *result) updateMetrics(fetchPageResult *types.FetchPageResult) {
+	result.capabilities.observe(fetchPageResult.ConsumedIOs != nil, fetchPageResult.TimingInformation != nil)
 	if fetchPageResult.ConsumedIOs != nil {
 		*result.ioUsage.readIOs += fetchPageResult.ConsumedIOs.ReadIOs
 		*result.ioUsage.writeIOs += fetchPageResult.ConsumedIOs.WriteIOs
@@ -93,27 +142,115 @@ func (result *result) updateMetrics(fetchPageResult *types.FetchPageResult) {
 }
 
 // GetConsumedIOs returns the statement statistics for the current number of read IO requests that were consumed. The statistics are stateful.
+// The counts of the returned IOUsage are 0, and IOUsage.Reported returns false, if QLDB does not report consumed IOs,
+// see ServerCapabilities.
 func (result *result) GetConsumedIOs() *IOUsage {
 	if result.ioUsage == nil {
 		return nil
 	}
+	if result.capabilities.snapshot().ConsumedIOs == CapabilityUnsupported {
+		return unreportedIOUsage()
+	}
 	return newIOUsage(*result.ioUsage.readIOs, *result.ioUsage.writeIOs)
 }
 
 // GetTimingInformation returns the statement statistics for the current server-side processing time. The statistics are stateful.
+// The processing time of the returned TimingInformation is 0, and TimingInformation.Reported returns false, if QLDB
+// does not report it, see ServerCapabilities.
 func (result *result) GetTimingInformation() *TimingInformation {
 	if result.timingInfo == nil {
 		return nil
 	}
+	if result.capabilities.snapshot().TimingInformation == CapabilityUnsupported {
+		return unreportedTimingInformation()
+	}
 	return newTimingInformation(*result.timingInfo.processingTimeMilliseconds)
 }
 
+// GetCursor returns a Cursor marking the current position in the result set of res, or nil if res is not a Result
+// returned by a Transaction of the driver.
+// The Cursor can be passed to ExecuteFromCursor to resume iteration, including from a later transaction.
+func GetCursor(res Result) *Cursor {
+	result, ok := res.(*result)
+	if !ok {
+		return nil
+	}
+	return result.cursor()
+}
+
+func (result *result) cursor() *Cursor {
+	cursor := &Cursor{statementHash: result.statementHash, position: result.position}
+	if result.txnID != nil {
+		cursor.transactionID = *result.txnID
+	}
+	// The page token can only be reused directly when the current page has been fully consumed
+	if result.index >= len(result.pageValues) && result.pageToken != nil {
+		pageToken := *result.pageToken
+		cursor.pageToken = &pageToken
+	}
+	return cursor
+}
+
 // GetCurrentData returns the current row of data in Ion format. Use ion.Unmarshal or other Ion library methods to handle parsing.
 // See https://github.com/amzn/ion-go for more information.
 func (result *result) GetCurrentData() []byte {
 	return result.ionBinary
 }
 
+// GetCurrentDataJSON returns the current row of data of res, a Result or BufferedResult returned by the driver,
+// converted from Ion to JSON. See JSONOptions for how Ion types without a JSON equivalent are converted.
+func GetCurrentDataJSON(res interface{}, fns ...func(*JSONOptions)) ([]byte, error) {
+	ionBinary, err := currentRow(res, "GetCurrentDataJSON")
+	if err != nil {
+		return nil, err
+	}
+	return ionToJSON(ionBinary, fns...)
+}
+
+// BufferedResultToJSON returns all the rows of data of res, a BufferedResult returned by the driver, converted from Ion
+// to JSON, as a JSON array. It does not affect the position of res. See JSONOptions for how Ion types without a JSON
+// equivalent are converted.
+func BufferedResultToJSON(res BufferedResult, fns ...func(*JSONOptions)) ([]byte, error) {
+	buffered, ok := res.(*bufferedResult)
+	if !ok {
+		return nil, &qldbDriverError{"BufferedResultToJSON requires a BufferedResult returned by the driver."}
+	}
+	return buffered.toJSON(fns...)
+}
+
+// currentRow returns the current row of data of res, a Result or BufferedResult returned by the driver. caller is the
+// name of the function reported in the errors.
+func currentRow(res interface{}, caller string) ([]byte, error) {
+	var ionBinary []byte
+	switch res := res.(type) {
+	case *result:
+		ionBinary = res.ionBinary
+	case *bufferedResult:
+		ionBinary = res.ionBinary
+	default:
+		return nil, &qldbDriverError{caller + " requires a Result or BufferedResult returned by the driver."}
+	}
+	if ionBinary == nil {
+		return nil, &qldbDriverError{"No current row of data. Call Next before " + caller + "."}
+	}
+	return ionBinary, nil
+}
+
+// GetCurrentColumns returns the names and Ion types of the fields of the current row of data of res, a Result or
+// BufferedResult returned by the driver, to discover the shape of the rows of a statement without unmarshalling them
+// into a predefined struct. Before Next is called, the columns of the first row of a BufferedResult are returned.
+// Rows of the same result may have different fields, since QLDB documents have no schema.
+func GetCurrentColumns(res interface{}) ([]Column, error) {
+	if buffered, ok := res.(*bufferedResult); ok {
+		return buffered.currentColumns()
+	}
+	ionBinary, err := currentRow(res, "GetCurrentColumns")
+	if err != nil {
+		return nil, err
+	}
+	return columns(ionBinary)
+}
+
 // Err returns an error if a previous call to Next has failed.
 // The returned error will be nil if the previous call to Next succeeded.
 func (result *result) Err() error {
@@ -157,12 +294,41 @@ func (result *bufferedResult) GetCurrentData() []byte {
 	return result.ionBinary
 }
 
+// currentColumns returns the columns of the current row of data, or of the first row before Next is called.
+func (result *bufferedResult) currentColumns() ([]Column, error) {
+	ionBinary := result.ionBinary
+	if ionBinary == nil && result.index == 0 && len(result.values) > 0 {
+		ionBinary = result.values[0]
+	}
+	if ionBinary == nil {
+		return nil, &qldbDriverError{"No current row of data."}
+	}
+	return columns(ionBinary)
+}
+
+func (result *bufferedResult) toJSON(fns ...func(*JSONOptions)) ([]byte, error) {
+	buf := bytes.Buffer{}
+	buf.WriteByte('[')
+	for i, value := range result.values {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		converted, err := ionToJSON(value, fns...)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(converted)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
 // GetConsumedIOs returns the statement statistics for the total number of read IO requests that were consumed.
 func (result *bufferedResult) GetConsumedIOs() *IOUsage {
 	if result.ioUsage == nil {
 		return nil
 	}
-	return newIOUsage(*result.ioUsage.readIOs, *result.ioUsage.writeIOs)
+	return result.ioUsage.copy()
 }
 
 // GetTimingInformation returns the statement statistics for the total server-side processing time.
@@ -170,21 +336,65 @@ func (result *bufferedResult) GetTimingInformation() *TimingInformation {
 	if result.timingInfo == nil {
 		return nil
 	}
-	return newTimingInformation(*result.timingInfo.processingTimeMilliseconds)
+	return result.timingInfo.copy()
 }
 
 // IOUsage contains metrics for the amount of IO requests that were consumed.
+//
+// The usage of a Result is the sum of the IOs consumed by the ExecuteStatement command of its statement and by the
+// FetchPage commands of the pages fetched so far, so it grows as the result is iterated. A FetchPage command retried
+// within the transaction (see DriverOptions.FetchPageRetryLimit) adds only the IOs of its successful response. When
+// QLDBDriver.Execute retries a transaction, the statements of each attempt have their own results, and the IOs consumed
+// by the failed attempts are not included. A statement served from the document cache of a transaction consumes no
+// IOs, and a Result resumed from a Cursor only counts the pages fetched after it was resumed. A BufferedResult has the
+// usage of its Result when it was buffered.
 type IOUsage struct {
 	readIOs  *int64
 	writeIOs *int64
+	// unreported is set when QLDB does not report consumed IOs, in which case the counts are 0.
+	unreported bool
 }
 
 // newIOUsage creates a new instance of IOUsage.
 func newIOUsage(readIOs int64, writeIOs int64) *IOUsage {
-	return &IOUsage{&readIOs, &writeIOs}
+	return &IOUsage{readIOs: &readIOs, writeIOs: &writeIOs}
+}
+
+// unreportedIOUsage creates an IOUsage for statements whose consumed IOs QLDB does not report.
+func unreportedIOUsage() *IOUsage {
+	ioUsage := newIOUsage(0, 0)
+	ioUsage.unreported = true
+	return ioUsage
+}
+
+// ReadIOs returns the number of read IO requests that were consumed for a statement execution, or 0 if QLDB does not
+// report consumed IOs, see Reported.
+func (ioUsage *IOUsage) ReadIOs() int64 {
+	if ioUsage.readIOs == nil {
+		return 0
+	}
+	return *ioUsage.readIOs
 }
 
-// GetReadIOs returns the number of read IO requests that were consumed for a statement execution.
+// WriteIOs returns the number of write IO requests that were consumed for a statement execution, or 0 if QLDB does not
+// report consumed IOs, see Reported.
+func (ioUsage *IOUsage) WriteIOs() int64 {
+	if ioUsage.writeIOs == nil {
+		return 0
+	}
+	return *ioUsage.writeIOs
+}
+
+// Reported returns false if QLDB does not report the IOs consumed by statements, as detected by
+// QLDBDriver.ServerCapabilities, in which case ReadIOs and WriteIOs return 0.
+func (ioUsage *IOUsage) Reported() bool {
+	return !ioUsage.unreported && ioUsage.readIOs != nil && ioUsage.writeIOs != nil
+}
+
+// GetReadIOs returns the number of read IO requests that were consumed for a statement execution, or a pointer to 0 if
+// QLDB does not report consumed IOs.
+//
+// Deprecated: Use ReadIOs and Reported instead.
 func (ioUsage *IOUsage) GetReadIOs() *int64 {
 	return ioUsage.readIOs
 }
@@ -194,17 +404,65 @@ func (ioUsage *IOUsage) getWriteIOs() *int64 {
 	return ioUsage.writeIOs
 }
 
+// copy returns a copy of the usage, which is not reported if the usage is not.
+func (ioUsage *IOUsage) copy() *IOUsage {
+	if !ioUsage.Reported() {
+		return unreportedIOUsage()
+	}
+	return newIOUsage(*ioUsage.readIOs, *ioUsage.writeIOs)
+}
+
 // TimingInformation contains metrics for server-side processing time.
+//
+// The processing time of a Result is aggregated like its IOUsage: it is the sum of the processing times of the
+// ExecuteStatement command and of the FetchPage commands of the pages fetched so far, excluding failed commands and
+// the attempts of a transaction retried by QLDBDriver.Execute. It does not include the network latency between the
+// driver and QLDB, which is reported by TransactionReport.
 type TimingInformation struct {
 	processingTimeMilliseconds *int64
+	// unreported is set when QLDB does not report the processing time, in which case it is 0.
+	unreported bool
 }
 
 // newTimingInformation creates a new instance of TimingInformation.
 func newTimingInformation(processingTimeMilliseconds int64) *TimingInformation {
-	return &TimingInformation{&processingTimeMilliseconds}
+	return &TimingInformation{processingTimeMilliseconds: &processingTimeMilliseconds}
+}
+
+// unreportedTimingInformation creates a TimingInformation for statements whose processing time QLDB does not report.
+func unreportedTimingInformation() *TimingInformation {
+	timingInfo := newTimingInformation(0)
+	timingInfo.unreported = true
+	return timingInfo
 }
 
-// GetProcessingTimeMilliseconds returns the server-side processing time in milliseconds for a statement execution.
+// ProcessingTime returns the server-side processing time for a statement execution, or 0 if QLDB does not report it,
+// see Reported.
+func (timingInfo *TimingInformation) ProcessingTime() time.Duration {
+	if timingInfo.processingTimeMilliseconds == nil {
+		return 0
+	}
+	return time.Duration(*timingInfo.processingTimeMilliseconds) * time.Millisecond
+}
+
+// Reported returns false if QLDB does not report the processing time of statements, as detected by
+// QLDBDriver.ServerCapabilities, in which case ProcessingTime returns 0.
+func (timingInfo *TimingInformation) Reported() bool {
+	return !timingInfo.unreported && timingInfo.processingTimeMilliseconds != nil
+}
+
+// GetProcessingTimeMilliseconds returns the server-side processing time in milliseconds for a statement execution, or
+// a pointer to 0 if QLDB does not report it.
+//
+// Deprecated: Use ProcessingTime and Reported instead.
 func (timingInfo *TimingInformation) GetProcessingTimeMilliseconds() *int64 {
 	return timingInfo.processingTimeMilliseconds
 }
+
+// copy returns a copy of the timing information, which is not reported if the timing information is not.
+func (timingInfo *TimingInformation) copy() *TimingInformation {
+	if !timingInfo.Reported() {
+		return unreportedTimingInformation()
+	}
+	return newTimingInformation(*timingInfo.processingTimeMilliseconds)
+}
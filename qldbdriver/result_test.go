@@ -16,10 +16,12 @@ package qldbdriver
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestResult(t *testing.T) {
@@ -138,6 +140,60 @@ func TestResult(t *testing.T) {
 		})
 	})
 
+	t.Run("GetCursor", func(t *testing.T) {
+		mockToken := "mockToken"
+		mockTxnID := "mockTxnID"
+		mockHash, _ := toQLDBHash("mockStatement")
+		cursorResult := &result{
+			txnID:         &mockTxnID,
+			pageValues:    mockPageValues,
+			pageToken:     &mockToken,
+			ioUsage:       newIOUsage(0, 0),
+			timingInfo:    newTimingInformation(0),
+			statementHash: mockHash.hash,
+		}
+
+		cursor := GetCursor(cursorResult)
+		assert.Equal(t, int64(0), cursor.GetPosition())
+		assert.Equal(t, mockTxnID, cursor.GetTransactionID())
+		assert.Equal(t, mockHash.hash, cursor.statementHash)
+		// Current page has not been consumed yet
+		assert.Nil(t, cursor.pageToken)
+
+		assert.True(t, cursorResult.Next(&transactionExecutor{nil, nil}))
+		cursor = GetCursor(cursorResult)
+		assert.Equal(t, int64(1), cursor.GetPosition())
+		assert.Equal(t, &mockToken, cursor.pageToken)
+	})
+
+	t.Run("logDiagnostics", func(t *testing.T) {
+		mockToken := "mockToken"
+		mockService := new(mockResultService)
+		mockService.On("fetchPage", mock.Anything, mock.Anything, mock.Anything).Return(&fetchPageResultWithStats, nil)
+		testLogger := &recordingLogger{}
+		diagnosticsResult := &result{
+			communicator: mockService,
+			pageValues:   mockPageValues,
+			pageToken:    &mockToken,
+			logger:       &qldbLogger{logger: testLogger, verbosity: LogDebug},
+			ioUsage:      newIOUsage(0, 0),
+			timingInfo:   newTimingInformation(0),
+			statement:    "SELECT * FROM people WHERE name = 'Jane' AND age > 30",
+			paramCount:   2,
+			pagesFetched: 1,
+		}
+
+		for diagnosticsResult.Next(&transactionExecutor{nil, nil}) {
+		}
+		// Further calls do not log again
+		assert.False(t, diagnosticsResult.Next(&transactionExecutor{nil, nil}))
+
+		require.Len(t, testLogger.messages, 1)
+		assert.Equal(t,
+			`[DEBUG] Statement diagnostics: statement="SELECT * FROM people WHERE name = '?' AND age > ?" parameters=2 pages=2 readIOs=1 writeIOs=2 processingTimeMs=3`,
+			testLogger.messages[0])
+	})
+
 	t.Run("updateMetrics", func(t *testing.T) {
 		t.Run("res does not have metrics and fetch page does not have metrics", func(t *testing.T) {
 			res := result{ioUsage: newIOUsage(0, 0), timingInfo: newTimingInformation(0)}
@@ -186,6 +242,42 @@ func TestResult(t *testing.T) {
 	})
 }
 
+func TestResultReleaseRows(t *testing.T) {
+	newPage := func() []types.ValueHolder {
+		return []types.ValueHolder{{IonBinary: []byte{1}}, {IonBinary: []byte{2}}}
+	}
+
+	t.Run("release", func(t *testing.T) {
+		page := newPage()
+		res := &result{pageValues: page, releaseRows: true, ioUsage: newIOUsage(0, 0), timingInfo: newTimingInformation(0)}
+
+		require.True(t, res.Next(nil))
+		assert.Equal(t, []byte{1}, res.GetCurrentData())
+		assert.Nil(t, page[0].IonBinary)
+		assert.Equal(t, []byte{2}, page[1].IonBinary)
+
+		require.True(t, res.Next(nil))
+		assert.Equal(t, []byte{2}, res.GetCurrentData())
+		assert.Nil(t, page[1].IonBinary)
+		assert.False(t, res.Next(nil))
+	})
+
+	t.Run("keep", func(t *testing.T) {
+		page := newPage()
+		res := &result{pageValues: page, ioUsage: newIOUsage(0, 0), timingInfo: newTimingInformation(0)}
+
+		for res.Next(nil) {
+		}
+		assert.Equal(t, newPage(), page)
+	})
+}
+
+func TestRedactStatement(t *testing.T) {
+	assert.Equal(t, "SELECT * FROM t WHERE a = ?", redactStatement("SELECT * FROM t WHERE a = ?"))
+	assert.Equal(t, "SELECT * FROM t1 WHERE a = '?' AND b = ?", redactStatement("SELECT * FROM t1 WHERE a = 'it''s' AND b = 1.5e3"))
+	assert.Equal(t, "INSERT INTO t VALUE `?`", redactStatement("INSERT INTO t VALUE `{a: 1}`"))
+}
+
 func TestBufferedResult(t *testing.T) {
 	byteSlice1 := make([]byte, 1)
 	byteSlice1[0] = 1
@@ -252,6 +344,48 @@ func (m *mockResultService) startTransaction(ctx context.Context) (*types.StartT
 	panic("not used")
 }
 
+func TestStatementMetrics(t *testing.T) {
+	t.Run("reported", func(t *testing.T) {
+		ioUsage := newIOUsage(3, 2)
+		assert.True(t, ioUsage.Reported())
+		assert.Equal(t, int64(3), ioUsage.ReadIOs())
+		assert.Equal(t, int64(2), ioUsage.WriteIOs())
+
+		timingInfo := newTimingInformation(15)
+		assert.True(t, timingInfo.Reported())
+		assert.Equal(t, 15*time.Millisecond, timingInfo.ProcessingTime())
+	})
+
+	t.Run("not reported", func(t *testing.T) {
+		ioUsage := &IOUsage{}
+		assert.False(t, ioUsage.Reported())
+		assert.Equal(t, int64(0), ioUsage.ReadIOs())
+		assert.Equal(t, int64(0), ioUsage.WriteIOs())
+		assert.False(t, ioUsage.copy().Reported())
+		require.NotNil(t, ioUsage.copy().GetReadIOs())
+		assert.Equal(t, int64(0), *unreportedIOUsage().GetReadIOs())
+		assert.False(t, unreportedIOUsage().copy().Reported())
+
+		timingInfo := &TimingInformation{}
+		assert.False(t, timingInfo.Reported())
+		assert.Equal(t, time.Duration(0), timingInfo.ProcessingTime())
+		assert.False(t, timingInfo.copy().Reported())
+		require.NotNil(t, timingInfo.copy().GetProcessingTimeMilliseconds())
+		assert.Equal(t, int64(0), *unreportedTimingInformation().GetProcessingTimeMilliseconds())
+		assert.False(t, unreportedTimingInformation().copy().Reported())
+	})
+
+	t.Run("aggregated across pages", func(t *testing.T) {
+		res := &result{ioUsage: newIOUsage(1, 0), timingInfo: newTimingInformation(2)}
+		res.updateMetrics(&types.FetchPageResult{ConsumedIOs: generateQldbsessionIOUsage(4, 1), TimingInformation: generateQldbsessionTimingInformation(3)})
+		res.updateMetrics(&types.FetchPageResult{})
+
+		assert.Equal(t, int64(5), res.GetConsumedIOs().ReadIOs())
+		assert.Equal(t, int64(1), res.GetConsumedIOs().WriteIOs())
+		assert.Equal(t, 5*time.Millisecond, res.GetTimingInformation().ProcessingTime())
+	})
+}
+
 func generateQldbsessionIOUsage(readIOs int64, writeIOs int64) *types.IOUsage {
 	return &types.IOUsage{
 		ReadIOs:  readIOs,
@@ -264,3 +398,11 @@ func generateQldbsessionTimingInformation(processingTimeMilliseconds int64) *typ
 		ProcessingTimeMilliseconds: processingTimeMilliseconds,
 	}
 }
+
+type recordingLogger struct {
+	messages []string
+}
+
+func (logger *recordingLogger) Log(message string, verbosity LogLevel) {
+	logger.messages = append(logger.messages, message)
+}
@@ -0,0 +1,48 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+// resultSizeLimit caps the rows and bytes a single statement can return through its Result, as configured with
+// DriverOptions.MaxResultRows and DriverOptions.MaxResultBytes.
+type resultSizeLimit struct {
+	rows  int64
+	bytes int64
+}
+
+// check returns a ResultTooLargeError if reading a row of rowSize bytes would make the result exceed the limit.
+func (limit *resultSizeLimit) check(result *result, rowSize int) error {
+	if limit == nil {
+		return nil
+	}
+	rows, bytes := result.rows+1, result.bytes+int64(rowSize)
+	if (limit.rows > 0 && rows > limit.rows) || (limit.bytes > 0 && bytes > limit.bytes) {
+		return &ResultTooLargeError{
+			TransactionID: *result.txnID,
+			Statement:     redactStatement(result.statement),
+			Rows:          rows,
+			Bytes:         bytes,
+			MaxRows:       limit.rows,
+			MaxBytes:      limit.bytes,
+		}
+	}
+	return nil
+}
+
+// resultSizeLimitFromOptions returns the limit on the size of results configured by options, or nil if there is none.
+func resultSizeLimitFromOptions(options *DriverOptions) *resultSizeLimit {
+	if options.MaxResultRows <= 0 && options.MaxResultBytes <= 0 {
+		return nil
+	}
+	return &resultSizeLimit{rows: options.MaxResultRows, bytes: options.MaxResultBytes}
+}
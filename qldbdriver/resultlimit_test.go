@@ -0,0 +1,84 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultSizeLimit(t *testing.T) {
+	newTestResult := func(limit *resultSizeLimit) *result {
+		return &result{
+			txnID:      &mockTxnID,
+			pageValues: []types.ValueHolder{{IonBinary: []byte{1, 2}}, {IonBinary: []byte{3, 4}}, {IonBinary: []byte{5, 6}}},
+			logger:     mockLogger,
+			ioUsage:    newIOUsage(0, 0),
+			timingInfo: newTimingInformation(0),
+			statement:  "SELECT * FROM Person WHERE Name = 'Ana'",
+			sizeLimit:  limit,
+		}
+	}
+
+	t.Run("rows", func(t *testing.T) {
+		res := newTestResult(&resultSizeLimit{rows: 2})
+		assert.True(t, res.Next(nil))
+		assert.True(t, res.Next(nil))
+		assert.False(t, res.Next(nil))
+		var tooLarge *ResultTooLargeError
+		require.True(t, errors.As(res.Err(), &tooLarge))
+		assert.Equal(t, int64(3), tooLarge.Rows)
+		assert.Equal(t, int64(2), tooLarge.MaxRows)
+		assert.Equal(t, "SELECT * FROM Person WHERE Name = '?'", tooLarge.Statement)
+		assert.Equal(t, "The result of statement 'SELECT * FROM Person WHERE Name = '?'' in transaction "+mockTxnID+
+			" exceeded the limit of 2 rows.", tooLarge.Error())
+		assert.Nil(t, res.GetCurrentData())
+	})
+
+	t.Run("bytes", func(t *testing.T) {
+		res := newTestResult(&resultSizeLimit{bytes: 5})
+		assert.True(t, res.Next(nil))
+		assert.True(t, res.Next(nil))
+		assert.False(t, res.Next(nil))
+		var tooLarge *ResultTooLargeError
+		require.True(t, errors.As(res.Err(), &tooLarge))
+		assert.Equal(t, int64(6), tooLarge.Bytes)
+		assert.Contains(t, tooLarge.Error(), "exceeded the limit of 5 bytes")
+	})
+
+	t.Run("within limit", func(t *testing.T) {
+		res := newTestResult(&resultSizeLimit{rows: 3, bytes: 6})
+		for res.Next(nil) {
+		}
+		assert.NoError(t, res.Err())
+		assert.Equal(t, int64(3), res.rows)
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		res := newTestResult(nil)
+		for res.Next(nil) {
+		}
+		assert.NoError(t, res.Err())
+	})
+}
+
+func TestResultSizeLimitFromOptions(t *testing.T) {
+	assert.Nil(t, resultSizeLimitFromOptions(&DriverOptions{}))
+	assert.Equal(t, &resultSizeLimit{rows: 10}, resultSizeLimitFromOptions(&DriverOptions{MaxResultRows: 10}))
+	assert.Equal(t, &resultSizeLimit{bytes: 1024}, resultSizeLimitFromOptions(&DriverOptions{MaxResultBytes: 1024}))
+}
@@ -0,0 +1,62 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"io"
+
+	"github.com/amzn/ion-go/ion"
+)
+
+// resultStream is an io.Reader over the Ion binary of the remaining rows of a Result, advancing the result as the rows
+// are read so that pages are fetched as needed.
+type resultStream struct {
+	res     Result
+	pending []byte
+}
+
+func (stream *resultStream) Read(p []byte) (int, error) {
+	for len(stream.pending) == 0 {
+		if !stream.res.Next(nil) {
+			if err := stream.res.Err(); err != nil {
+				return 0, err
+			}
+			return 0, io.EOF
+		}
+		stream.pending = stream.res.GetCurrentData()
+	}
+	n := copy(p, stream.pending)
+	stream.pending = stream.pending[n:]
+	return n, nil
+}
+
+// GetCurrentReader returns an ion.Reader over the current row of data of res, a Result or BufferedResult returned by
+// the driver, positioned before the row, to parse only the fields needed from a large document instead of unmarshalling
+// it whole. See NewResultReader to read all the rows of a Result.
+func GetCurrentReader(res interface{}) (ion.Reader, error) {
+	ionBinary, err := currentRow(res, "GetCurrentReader")
+	if err != nil {
+		return nil, err
+	}
+	return ion.NewReaderBytes(ionBinary), nil
+}
+
+// NewResultReader returns an ion.Reader over the remaining rows of res, each row a top-level value, to parse the rows
+// of a large result as a single stream. The reader advances res as it reads, fetching the next pages of the result as
+// needed, so res must not be used while the reader is in use, and the reader must be used before the transaction of
+// res is committed. A failure of the result, such as a failure to fetch a page, stops the reader with an error returned
+// by its Err method, and the failure itself is returned by res.Err.
+func NewResultReader(res Result) ion.Reader {
+	return ion.NewReader(&resultStream{res: res})
+}
@@ -0,0 +1,124 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/amzn/ion-go/ion"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCurrentReader(t *testing.T) {
+	row := ionTextToBinary(t, `{name: "Ana", address: {city: "Lisbon"}}`)
+
+	readCity := func(t *testing.T, reader ion.Reader) string {
+		require.True(t, reader.Next())
+		require.NoError(t, reader.StepIn())
+		for reader.Next() {
+			field, err := reader.FieldName()
+			require.NoError(t, err)
+			if *field.Text != "address" {
+				continue
+			}
+			require.NoError(t, reader.StepIn())
+			require.True(t, reader.Next())
+			city, err := reader.StringValue()
+			require.NoError(t, err)
+			return *city
+		}
+		return ""
+	}
+
+	t.Run("result", func(t *testing.T) {
+		res := &result{pageValues: []types.ValueHolder{{IonBinary: row}}, ioUsage: newIOUsage(0, 0), timingInfo: newTimingInformation(0)}
+		_, err := GetCurrentReader(res)
+		assert.Error(t, err)
+
+		require.True(t, res.Next(nil))
+		reader, err := GetCurrentReader(res)
+		require.NoError(t, err)
+		assert.Equal(t, "Lisbon", readCity(t, reader))
+	})
+
+	t.Run("buffered result", func(t *testing.T) {
+		res := &bufferedResult{values: [][]byte{row}}
+		_, err := GetCurrentReader(res)
+		assert.Error(t, err)
+
+		require.True(t, res.Next())
+		reader, err := GetCurrentReader(res)
+		require.NoError(t, err)
+		assert.Equal(t, "Lisbon", readCity(t, reader))
+	})
+
+	t.Run("result not returned by the driver", func(t *testing.T) {
+		_, err := GetCurrentReader(struct{ Result }{})
+		assert.Error(t, err)
+	})
+}
+
+func TestNewResultReader(t *testing.T) {
+	token := "token"
+	newTestResult := func(mockService *mockTransactionService) *result {
+		return &result{
+			ctx:          context.Background(),
+			communicator: mockService,
+			txnID:        &mockTxnID,
+			pageValues:   []types.ValueHolder{{IonBinary: ionTextToBinary(t, `{id: 1}`)}, {IonBinary: ionTextToBinary(t, `{id: 2}`)}},
+			pageToken:    &token,
+			logger:       mockLogger,
+			ioUsage:      newIOUsage(0, 0),
+			timingInfo:   newTimingInformation(0),
+		}
+	}
+	readIDs := func(reader ion.Reader) []int {
+		var ids []int
+		for reader.Next() {
+			var row struct {
+				ID int `ion:"id"`
+			}
+			if err := unmarshalCurrentValue(reader, &row); err != nil {
+				break
+			}
+			ids = append(ids, row.ID)
+		}
+		return ids
+	}
+
+	t.Run("all pages", func(t *testing.T) {
+		mockService := new(mockTransactionService)
+		nextPage := &types.FetchPageResult{Page: &types.Page{Values: []types.ValueHolder{{IonBinary: ionTextToBinary(t, `{id: 3, extra: "field"}`)}}}}
+		mockService.On("fetchPage", mock.Anything, mock.Anything, mock.Anything).Return(nextPage, nil).Once()
+
+		reader := NewResultReader(newTestResult(mockService))
+		assert.Equal(t, []int{1, 2, 3}, readIDs(reader))
+		assert.NoError(t, reader.Err())
+	})
+
+	t.Run("fetch failure", func(t *testing.T) {
+		mockService := new(mockTransactionService)
+		mockService.On("fetchPage", mock.Anything, mock.Anything, mock.Anything).Return(&types.FetchPageResult{}, errMock).Once()
+
+		res := newTestResult(mockService)
+		reader := NewResultReader(res)
+		assert.Equal(t, []int{1, 2}, readIDs(reader))
+		assert.Error(t, reader.Err())
+		assert.Equal(t, errMock, res.Err())
+	})
+}
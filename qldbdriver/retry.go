@@ -14,6 +14,7 @@ and limitations under the License.
 package qldbdriver
 
 import (
+	"context"
 	"math"
 	"math/rand"
 	"time"
@@ -31,6 +32,24 @@ type RetryPolicy struct {
 	MaxRetryLimit int
 	// The strategy to use for delaying before the retry attempt.
 	Backoff BackoffStrategy
+	// The strategy to use instead of Backoff for delaying before the retry of a transaction that QLDB throttled, so that
+	// a burst of transactions backs off long enough for the rate of requests to drop below the limits of the account.
+	// Default: ExponentialBackoffStrategy: SleepBase: 100ms, SleepCap: 10000ms. When nil, Backoff is used.
+	ThrottleBackoff BackoffStrategy
+	// Called when the driver decided to retry the provided function after a recoverable error, before waiting for
+	// nextDelay. attempt is the number of the retry attempt, starting at 1, and err is the error that caused the retry.
+	// Returning a non-nil error gives up instead of retrying, and QLDBDriver.Execute returns that error. It can be
+	// overridden per call with ExecuteOptions.OnRetry. Default: nil.
+	OnRetry func(attempt int, err error, nextDelay time.Duration) error
+	// The maximum time QLDBDriver.Execute spends executing and retrying the provided function. Each attempt is given a
+	// deadline of an equal share of the time remaining for it and the retries that may follow, so that a stalled
+	// command fails its attempt, which is retried on another session, instead of consuming the whole time. Retries
+	// stop once the time has elapsed. Default: 0, which only bounds Execute by its context.
+	MaxElapsedTime time.Duration
+	// Shortens the delay before a retry that would start after the deadline of the context of QLDBDriver.Execute or
+	// of MaxElapsedTime, leaving half of the remaining time to the retry. Default: false, Execute fails immediately with
+	// a DeadlineWouldExceedError instead of waiting past the deadline.
+	TruncateBackoff bool
 }
 
 // ExponentialBackoffStrategy exponentially increases the delay per retry attempt given a base and a cap.
@@ -50,3 +69,31 @@ func (s ExponentialBackoffStrategy) Delay(retryAttempt int) time.Duration {
 
 	return time.Duration(jitter*math.Min(float64(s.SleepCap.Milliseconds()), float64(s.SleepBase.Milliseconds())*math.Pow(2, float64(retryAttempt)))) * time.Millisecond
 }
+
+// backoffFor returns the strategy for delaying the retry of the failed transaction.
+func (policy RetryPolicy) backoffFor(txnErr *txnError) BackoffStrategy {
+	if txnErr.isThrottle && policy.ThrottleBackoff != nil {
+		return policy.ThrottleBackoff
+	}
+	return policy.Backoff
+}
+
+// fitDelay adjusts the delay before a retry to the earliest of the deadline of ctx and deadline, if any. It returns
+// the delay to wait, the time remaining before the deadline, and false if the retry would start after the deadline
+// and the delay is not truncated.
+func (policy RetryPolicy) fitDelay(ctx context.Context, deadline time.Time, delay time.Duration) (time.Duration, time.Duration, bool) {
+	if ctxDeadline, ok := ctx.Deadline(); ok && (deadline.IsZero() || ctxDeadline.Before(deadline)) {
+		deadline = ctxDeadline
+	}
+	if deadline.IsZero() {
+		return delay, 0, true
+	}
+	remaining := time.Until(deadline)
+	if delay < remaining {
+		return delay, remaining, true
+	}
+	if policy.TruncateBackoff && remaining > 0 {
+		return remaining / 2, remaining, true
+	}
+	return delay, remaining, false
+}
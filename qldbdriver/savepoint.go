@@ -0,0 +1,180 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+)
+
+// maxSavepointRollbacks is the number of times QLDBDriver.ExecuteWithSavepoints restarts its function after a rollback
+// to a savepoint, which bounds a function that always rolls back.
+const maxSavepointRollbacks = 10
+
+// SavepointTransaction is the Transaction passed to the function of QLDBDriver.ExecuteWithSavepoints. QLDB does not
+// support savepoints, so they are emulated: the statements executed by the function are recorded, and rolling back to
+// a savepoint starts a new transaction, executes the statements recorded before the savepoint again, and calls the
+// function again.
+type SavepointTransaction interface {
+	Transaction
+	// Savepoint marks the statements executed so far with name, replacing a previous savepoint with the same name.
+	Savepoint(name string)
+	// RollbackTo returns a SavepointRollbackError which, returned by the function, rolls the transaction back to the
+	// savepoint, with cause as the reason of the rollback. It returns an error that does not roll back if there is no
+	// savepoint with the name.
+	RollbackTo(name string, cause error) error
+	// RolledBackTo returns the savepoint and the cause of the rollback that restarted the function, or "" and nil if
+	// the function was not restarted by a rollback.
+	RolledBackTo() (string, error)
+}
+
+// savepointStatement is a statement executed by the function of QLDBDriver.ExecuteWithSavepoints.
+type savepointStatement struct {
+	statement  string
+	parameters []interface{}
+}
+
+type savepointTransaction struct {
+	Transaction
+	executed   []savepointStatement
+	savepoints map[string]int
+	replay     []savepointStatement
+	replayed   []Result
+	rollback   *SavepointRollbackError
+	// mismatched is set when the function executed a statement that does not match the one replayed at its position.
+	mismatched bool
+}
+
+// newSavepointTransaction executes the statements to replay within txn, and returns the SavepointTransaction passed to
+// the function restarted by rollback, or to the function called for the first time if rollback is nil.
+func newSavepointTransaction(txn Transaction, rollback *SavepointRollbackError) (*savepointTransaction, error) {
+	savepointTxn := &savepointTransaction{Transaction: txn, savepoints: make(map[string]int), rollback: rollback}
+	if rollback == nil {
+		return savepointTxn, nil
+	}
+	savepointTxn.replay = rollback.replay
+	for _, replayed := range rollback.replay {
+		result, err := txn.Execute(replayed.statement, replayed.parameters...)
+		if err != nil {
+			return nil, err
+		}
+		savepointTxn.replayed = append(savepointTxn.replayed, result)
+	}
+	return savepointTxn, nil
+}
+
+// Execute a statement with any parameters within this transaction. While the function executes again the statements
+// replayed up to the savepoint it was rolled back to, the results of the replayed statements are returned instead.
+func (txn *savepointTransaction) Execute(statement string, parameters ...interface{}) (Result, error) {
+	if index := len(txn.executed); index < len(txn.replayed) {
+		replayed := txn.replay[index]
+		if !txn.matches(replayed, statement, parameters) {
+			txn.mismatched = true
+			return nil, &qldbDriverError{"Statement does not match the statement executed at the same position before the rollback to the savepoint."}
+		}
+		txn.executed = append(txn.executed, replayed)
+		return txn.replayed[index], nil
+	}
+	result, err := txn.Transaction.Execute(statement, parameters...)
+	if err != nil {
+		return nil, err
+	}
+	txn.executed = append(txn.executed, savepointStatement{statement: statement, parameters: parameters})
+	return result, nil
+}
+
+// matches returns whether statement and parameters are the replayed statement, comparing the hashes of the parameters
+// as they are sent to QLDB.
+func (txn *savepointTransaction) matches(replayed savepointStatement, statement string, parameters []interface{}) bool {
+	if replayed.statement != statement || len(replayed.parameters) != len(parameters) {
+		return false
+	}
+	marshalOptions := IonMarshalOptions{}
+	if executor, ok := executorOf(txn.Transaction); ok {
+		marshalOptions = executor.txn.marshalOptions
+	}
+	replayedHash, err := toStatementHash(replayed.statement, wrapParameters(replayed.parameters, marshalOptions))
+	if err != nil {
+		return false
+	}
+	statementHash, err := toStatementHash(statement, wrapParameters(parameters, marshalOptions))
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(replayedHash.hash, statementHash.hash)
+}
+
+// Savepoint marks the statements executed so far with name.
+func (txn *savepointTransaction) Savepoint(name string) {
+	txn.savepoints[name] = len(txn.executed)
+}
+
+// RollbackTo returns the error rolling the transaction back to the savepoint.
+func (txn *savepointTransaction) RollbackTo(name string, cause error) error {
+	count, ok := txn.savepoints[name]
+	if !ok {
+		return &qldbDriverError{"Savepoint '" + name + "' does not exist."}
+	}
+	replay := make([]savepointStatement, count)
+	copy(replay, txn.executed[:count])
+	return &SavepointRollbackError{Savepoint: name, err: cause, replay: replay}
+}
+
+// RolledBackTo returns the savepoint and the cause of the rollback that restarted the function.
+func (txn *savepointTransaction) RolledBackTo() (string, error) {
+	if txn.rollback == nil {
+		return "", nil
+	}
+	return txn.rollback.Savepoint, txn.rollback.err
+}
+
+// ExecuteWithSavepoints executes fn in a transaction like Execute, with savepoints that the function can roll back to
+// when it hits a recoverable business error, to take another path through a multi-step flow. When fn returns the error
+// of SavepointTransaction.RollbackTo, the transaction is aborted, and a new transaction executes again every statement
+// recorded before the savepoint and calls fn again, with SavepointTransaction.RolledBackTo returning the savepoint.
+// A rollback therefore costs the reads and writes of all the replayed statements, and the time to execute them again
+// counts towards the duration of the new transaction. The function must execute the same statements, with the same
+// parameters, up to the savepoint, for which the results of the replayed statements are returned, before it can take
+// another path: otherwise the statement that differs returns an error, and the transaction is retried from the start,
+// without replaying any statement. Like the function passed to Execute, fn is also called again from the start when
+// the transaction is retried.
+func (driver *QLDBDriver) ExecuteWithSavepoints(ctx context.Context, fn func(txn SavepointTransaction) (interface{}, error), optFns ...func(*ExecuteOptions)) (interface{}, error) {
+	var rollback *SavepointRollbackError
+	for rollbacks := 0; ; rollbacks++ {
+		var savepointTxn *savepointTransaction
+		// The errors are translated once the rollbacks are done, since a translated error may hide the rollback
+		result, err := driver.execute(ctx, func(txn Transaction) (interface{}, error) {
+			var err error
+			savepointTxn, err = newSavepointTransaction(txn, rollback)
+			if err != nil {
+				return nil, err
+			}
+			return fn(savepointTxn)
+		}, optFns...)
+
+		if err != nil && savepointTxn != nil && savepointTxn.mismatched && rollbacks < maxSavepointRollbacks {
+			driver.logger.logf(LogDebug, "Statement does not match the replayed statement, retrying the transaction from the start.")
+			rollback = nil
+			continue
+		}
+		var rollbackErr *SavepointRollbackError
+		var ambiguousErr *AmbiguousCommitError
+		if !errors.As(err, &rollbackErr) || errors.As(err, &ambiguousErr) || rollbacks >= maxSavepointRollbacks {
+			return result, driver.translate(err)
+		}
+		driver.logger.logf(LogDebug, "Rolling back to savepoint '%s'.", rollbackErr.Savepoint)
+		rollback = rollbackErr
+	}
+}
@@ -0,0 +1,180 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteWithSavepoints(t *testing.T) {
+	var sent []string
+	aborts := 0
+	testDriver := newMockDriver(t, &qldbsessioniface.MockClientAPI{
+		SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+			if params.ExecuteStatement != nil {
+				sent = append(sent, *params.ExecuteStatement.Statement)
+			}
+			if params.AbortTransaction != nil {
+				aborts++
+			}
+			return qldbsessioniface.DefaultSendCommandOutput(params), nil
+		},
+	})
+	errOutOfStock := errors.New("out of stock")
+	reset := func() {
+		sent, aborts = nil, 0
+	}
+
+	t.Run("rollback to savepoint", func(t *testing.T) {
+		reset()
+		var rolledBack []string
+		var causes []error
+		result, err := testDriver.ExecuteWithSavepoints(context.Background(), func(txn SavepointTransaction) (interface{}, error) {
+			savepoint, cause := txn.RolledBackTo()
+			rolledBack = append(rolledBack, savepoint)
+			causes = append(causes, cause)
+			if _, err := txn.Execute("INSERT INTO Orders ?", map[string]string{"id": "1"}); err != nil {
+				return nil, err
+			}
+			txn.Savepoint("order")
+			if savepoint == "order" {
+				return txn.Execute("INSERT INTO Backorders ?", map[string]string{"id": "1"})
+			}
+			if _, err := txn.Execute("UPDATE Stock SET count = count - 1"); err != nil {
+				return nil, err
+			}
+			return nil, txn.RollbackTo("order", errOutOfStock)
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, result)
+		assert.Equal(t, []string{"", "order"}, rolledBack)
+		assert.Equal(t, []error{nil, errOutOfStock}, causes)
+		assert.Equal(t, []string{"INSERT INTO Orders ?", "UPDATE Stock SET count = count - 1", "INSERT INTO Orders ?", "INSERT INTO Backorders ?"}, sent)
+		assert.Equal(t, 1, aborts)
+	})
+
+	t.Run("replay mismatch", func(t *testing.T) {
+		reset()
+		calls := 0
+		_, err := testDriver.ExecuteWithSavepoints(context.Background(), func(txn SavepointTransaction) (interface{}, error) {
+			calls++
+			if savepoint, _ := txn.RolledBackTo(); savepoint != "" {
+				return txn.Execute("SELECT * FROM Orders")
+			}
+			if _, err := txn.Execute("INSERT INTO Orders ?", 1); err != nil {
+				return nil, err
+			}
+			if calls > 1 {
+				return nil, nil
+			}
+			txn.Savepoint("order")
+			return nil, txn.RollbackTo("order", nil)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, []string{"INSERT INTO Orders ?", "INSERT INTO Orders ?", "INSERT INTO Orders ?"}, sent)
+		assert.Equal(t, 2, aborts)
+	})
+
+	t.Run("replay parameter mismatch", func(t *testing.T) {
+		reset()
+		var rolledBack []string
+		_, err := testDriver.ExecuteWithSavepoints(context.Background(), func(txn SavepointTransaction) (interface{}, error) {
+			savepoint, _ := txn.RolledBackTo()
+			rolledBack = append(rolledBack, savepoint)
+			if _, err := txn.Execute("INSERT INTO Orders ?", map[string]int{"id": len(rolledBack)}); err != nil {
+				return nil, err
+			}
+			if len(rolledBack) > 1 {
+				return nil, nil
+			}
+			txn.Savepoint("order")
+			return nil, txn.RollbackTo("order", nil)
+		})
+		require.NoError(t, err)
+		// The replay of the first call does not match the second call, which is retried from the start
+		assert.Equal(t, []string{"", "order", ""}, rolledBack)
+		assert.Equal(t, 2, aborts)
+	})
+
+	t.Run("rollback limit", func(t *testing.T) {
+		reset()
+		calls := 0
+		_, err := testDriver.ExecuteWithSavepoints(context.Background(), func(txn SavepointTransaction) (interface{}, error) {
+			calls++
+			txn.Savepoint("start")
+			return nil, txn.RollbackTo("start", errOutOfStock)
+		})
+		var rollbackErr *SavepointRollbackError
+		require.True(t, errors.As(err, &rollbackErr))
+		assert.Equal(t, "start", rollbackErr.Savepoint)
+		assert.True(t, errors.Is(err, errOutOfStock))
+		assert.Equal(t, maxSavepointRollbacks+1, calls)
+	})
+
+	t.Run("rollback with TranslateError", func(t *testing.T) {
+		reset()
+		errTranslated := errors.New("translated")
+		testDriver.translateError = func(err error) error { return errTranslated }
+		defer func() { testDriver.translateError = nil }()
+		var rolledBack []string
+		_, err := testDriver.ExecuteWithSavepoints(context.Background(), func(txn SavepointTransaction) (interface{}, error) {
+			savepoint, _ := txn.RolledBackTo()
+			rolledBack = append(rolledBack, savepoint)
+			txn.Savepoint("start")
+			if savepoint == "" {
+				return nil, txn.RollbackTo("start", errOutOfStock)
+			}
+			return nil, errOutOfStock
+		})
+		// The rollback is not hidden by the translation, which only applies to the error returned
+		assert.Equal(t, []string{"", "start"}, rolledBack)
+		assert.Equal(t, errTranslated, err)
+	})
+
+	t.Run("unknown savepoint", func(t *testing.T) {
+		reset()
+		calls := 0
+		_, err := testDriver.ExecuteWithSavepoints(context.Background(), func(txn SavepointTransaction) (interface{}, error) {
+			calls++
+			return nil, txn.RollbackTo("missing", errOutOfStock)
+		})
+		assert.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("cursor not supported", func(t *testing.T) {
+		_, err := testDriver.ExecuteWithSavepoints(context.Background(), func(txn SavepointTransaction) (interface{}, error) {
+			return ExecuteFromCursor(txn, &Cursor{}, "SELECT * FROM Orders")
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("helpers unwrap the transaction", func(t *testing.T) {
+		_, err := testDriver.ExecuteWithSavepoints(context.Background(), func(txn SavepointTransaction) (interface{}, error) {
+			executor, ok := executorOf(txn)
+			require.True(t, ok)
+			assert.Equal(t, txn.ID(), executor.ID())
+			return nil, nil
+		})
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,183 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"time"
+)
+
+// poolScaler resizes the session pool of the default partition to the load, according to
+// DriverOptions.PoolScalingInterval.
+type poolScaler struct {
+	interval        time.Duration
+	waitThreshold   time.Duration
+	minIdleSessions int
+	// capacity is the number of idle sessions the pool currently holds at most.
+	capacity int
+	// last is the SessionAcquisitionStats at the end of the previous interval.
+	last SessionAcquisitionStats
+	// warmed and warmLatency count the sessions started by warmPool since the end of the previous interval, which are
+	// not checkouts.
+	warmed      int64
+	warmLatency time.Duration
+	stop        chan struct{}
+}
+
+// newPoolScaler verifies the pool scaling options, and returns nil if pool scaling is disabled.
+func newPoolScaler(options *DriverOptions) (*poolScaler, error) {
+	if options.PoolScalingInterval < 0 {
+		return nil, &qldbDriverError{"PoolScalingInterval must be 0 or greater."}
+	}
+	if options.MinIdleSessions < 0 {
+		return nil, &qldbDriverError{"MinIdleSessions must be 0 or greater."}
+	}
+	if options.MinIdleSessions > options.MaxConcurrentTransactions {
+		return nil, &qldbDriverError{"MinIdleSessions cannot exceed MaxConcurrentTransactions."}
+	}
+	if options.PoolScalingWaitThreshold < 0 {
+		return nil, &qldbDriverError{"PoolScalingWaitThreshold must be 0 or greater."}
+	}
+	if options.PoolScalingInterval == 0 {
+		return nil, nil
+	}
+	if options.SessionPool != nil {
+		return nil, &qldbDriverError{"PoolScalingInterval cannot be set with a custom SessionPool."}
+	}
+	return &poolScaler{
+		interval:        options.PoolScalingInterval,
+		waitThreshold:   options.PoolScalingWaitThreshold,
+		minIdleSessions: options.MinIdleSessions,
+		capacity:        options.MaxConcurrentTransactions,
+		stop:            make(chan struct{}),
+	}, nil
+}
+
+// next returns the capacity of the pool for the next interval, given the stats at the end of the current interval,
+// the highest number of transactions in progress during it, and MaxConcurrentTransactions. The pool doubles when the
+// mean time to check out a session, waiting for a permit and starting a session, exceeds PoolScalingWaitThreshold.
+// Otherwise, it shrinks halfway towards the highest number of transactions in progress, and the idle sessions that no
+// longer fit are ended, to reduce session-hours during quiet periods.
+func (scaler *poolScaler) next(stats SessionAcquisitionStats, peak int, maxSessions int) int {
+	checkouts := stats.Reused - scaler.last.Reused + stats.Created - scaler.last.Created - scaler.warmed
+	wait := stats.PermitWait - scaler.last.PermitWait + stats.StartSessionLatency - scaler.last.StartSessionLatency -
+		scaler.warmLatency
+	scaler.last = stats
+	scaler.warmed = 0
+	scaler.warmLatency = 0
+
+	capacity := scaler.capacity
+	if checkouts > 0 && wait/time.Duration(checkouts) > scaler.waitThreshold {
+		capacity *= 2
+		if capacity < peak {
+			capacity = peak
+		}
+		if capacity == 0 {
+			capacity = 1
+		}
+	} else if peak < capacity {
+		// Shrinking halfway leaves room for the load to pick up again without starting sessions
+		capacity = (capacity + peak) / 2
+	}
+	return scaler.limit(capacity, maxSessions)
+}
+
+// limit bounds capacity between MinIdleSessions and maxSessions, and makes it the capacity of the pool.
+func (scaler *poolScaler) limit(capacity int, maxSessions int) int {
+	if capacity < scaler.minIdleSessions {
+		capacity = scaler.minIdleSessions
+	}
+	if capacity > maxSessions {
+		capacity = maxSessions
+	}
+	scaler.capacity = capacity
+	return capacity
+}
+
+// scalePool resizes the session pool at every interval of scaler, until the driver is shut down.
+func (driver *QLDBDriver) scalePool(scaler *poolScaler) {
+	ticker := time.NewTicker(scaler.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-scaler.stop:
+			return
+		case <-ticker.C:
+			driver.resizePool(scaler)
+		}
+	}
+}
+
+// resizePool resizes the session pool to the load of the interval that just ended, ends the idle sessions that no
+// longer fit, and then starts sessions until the pool holds MinIdleSessions idle sessions.
+func (driver *QLDBDriver) resizePool(scaler *poolScaler) {
+	if driver.resizePoolCapacity(scaler) {
+		driver.warmPool(scaler)
+	}
+}
+
+// resizePoolCapacity resizes the session pool like resizePool, and returns false if the driver is shut down.
+func (driver *QLDBDriver) resizePoolCapacity(scaler *poolScaler) bool {
+	driver.lock.Lock()
+	defer driver.lock.Unlock()
+	if driver.isClosed {
+		return false
+	}
+	stats := driver.acquisitionStats.snapshot()
+	peak := driver.semaphore.resetPeak()
+
+	driver.optionsLock.Lock()
+	defer driver.optionsLock.Unlock()
+	previous := scaler.capacity
+	capacity := scaler.next(stats, peak, driver.maxConcurrentTransactions)
+	if capacity != previous {
+		driver.logger.logf(LogDebug, "Resizing the session pool from %d to %d sessions.", previous, capacity)
+		driver.endSessions(driver.logger, driver.sessionPool.(resizableSessionPool).resize(capacity))
+	}
+	return true
+}
+
+// warmPool starts sessions until the pool holds MinIdleSessions idle sessions, as long as permits are available. The
+// sessions are started without holding the lock of the driver.
+func (driver *QLDBDriver) warmPool(scaler *poolScaler) {
+	ctx := context.Background()
+	pool := driver.sessionPool.(resizableSessionPool)
+	warmed := 0
+	for missing := scaler.minIdleSessions - pool.idle(); missing > 0; missing-- {
+		if !driver.semaphore.tryAcquire() {
+			break
+		}
+		start := time.Now()
+		session, err := driver.createSession(ctx, nil)
+		scaler.warmed++
+		scaler.warmLatency += time.Since(start)
+		if err != nil {
+			driver.logger.logf(LogDebug, "Failed to start an idle session: '%v'", err)
+			break
+		}
+		driver.lock.Lock()
+		isClosed := driver.isClosed
+		driver.lock.Unlock()
+		if isClosed {
+			driver.discardSession(session)
+			_ = session.endSession(ctx)
+			return
+		}
+		driver.releaseSession(ctx, session)
+		warmed++
+	}
+	if warmed > 0 {
+		driver.logger.logf(LogDebug, "Started %d idle sessions to keep MinIdleSessions.", warmed)
+	}
+}
@@ -0,0 +1,234 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPoolScaler(t *testing.T) {
+	testCases := []struct {
+		name    string
+		fn      func(*DriverOptions)
+		message string
+	}{
+		{"negative interval", func(options *DriverOptions) { options.PoolScalingInterval = -time.Second },
+			"PoolScalingInterval must be 0 or greater."},
+		{"negative minimum", func(options *DriverOptions) { options.MinIdleSessions = -1 },
+			"MinIdleSessions must be 0 or greater."},
+		{"minimum above maximum", func(options *DriverOptions) { options.MinIdleSessions = 51 },
+			"MinIdleSessions cannot exceed MaxConcurrentTransactions."},
+		{"negative threshold", func(options *DriverOptions) { options.PoolScalingWaitThreshold = -time.Second },
+			"PoolScalingWaitThreshold must be 0 or greater."},
+		{"custom pool", func(options *DriverOptions) {
+			options.PoolScalingInterval = time.Second
+			options.SessionPool = newChannelSessionPool(50)
+		}, "PoolScalingInterval cannot be set with a custom SessionPool."},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			options := defaultDriverOptions()
+			testCase.fn(options)
+			scaler, err := newPoolScaler(options)
+			assert.Nil(t, scaler)
+			require.Error(t, err)
+			assert.Equal(t, testCase.message, err.Error())
+		})
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		scaler, err := newPoolScaler(defaultDriverOptions())
+		assert.NoError(t, err)
+		assert.Nil(t, scaler)
+	})
+
+	t.Run("starts at MaxConcurrentTransactions", func(t *testing.T) {
+		options := defaultDriverOptions()
+		options.PoolScalingInterval = time.Minute
+		options.MinIdleSessions = 5
+		scaler, err := newPoolScaler(options)
+		require.NoError(t, err)
+		assert.Equal(t, 50, scaler.capacity)
+		assert.Equal(t, 5, scaler.minIdleSessions)
+		assert.Equal(t, 10*time.Millisecond, scaler.waitThreshold)
+	})
+}
+
+func TestPoolScalerNext(t *testing.T) {
+	newScaler := func(capacity int) *poolScaler {
+		return &poolScaler{waitThreshold: 10 * time.Millisecond, minIdleSessions: 2, capacity: capacity}
+	}
+	slow := SessionAcquisitionStats{Created: 4, StartSessionLatency: 200 * time.Millisecond}
+	fast := SessionAcquisitionStats{Reused: 4, PermitWait: time.Millisecond}
+
+	t.Run("grows when checkouts wait", func(t *testing.T) {
+		scaler := newScaler(4)
+		assert.Equal(t, 8, scaler.next(slow, 4, 20))
+		assert.Equal(t, slow, scaler.last)
+	})
+
+	t.Run("grows to the peak", func(t *testing.T) {
+		assert.Equal(t, 12, newScaler(4).next(slow, 12, 20))
+	})
+
+	t.Run("grows up to MaxConcurrentTransactions", func(t *testing.T) {
+		assert.Equal(t, 20, newScaler(16).next(slow, 16, 20))
+	})
+
+	t.Run("grows from zero", func(t *testing.T) {
+		scaler := &poolScaler{waitThreshold: 10 * time.Millisecond}
+		assert.Equal(t, 1, scaler.next(slow, 0, 20))
+	})
+
+	t.Run("only counts the last interval", func(t *testing.T) {
+		scaler := newScaler(4)
+		scaler.last = slow
+		assert.Equal(t, 4, scaler.next(slow, 4, 20))
+	})
+
+	t.Run("shrinks halfway to the peak when checkouts do not wait", func(t *testing.T) {
+		scaler := newScaler(16)
+		assert.Equal(t, 10, scaler.next(fast, 4, 20))
+		assert.Equal(t, 10, scaler.capacity)
+	})
+
+	t.Run("shrinks when idle", func(t *testing.T) {
+		assert.Equal(t, 8, newScaler(16).next(SessionAcquisitionStats{}, 0, 20))
+	})
+
+	t.Run("does not shrink below MinIdleSessions", func(t *testing.T) {
+		assert.Equal(t, 2, newScaler(3).next(SessionAcquisitionStats{}, 0, 20))
+	})
+
+	t.Run("keeps its capacity when busy", func(t *testing.T) {
+		assert.Equal(t, 4, newScaler(4).next(fast, 4, 20))
+	})
+
+	t.Run("limited by a lower MaxConcurrentTransactions", func(t *testing.T) {
+		assert.Equal(t, 6, newScaler(8).next(fast, 8, 6))
+	})
+}
+
+func TestResizePool(t *testing.T) {
+	mockClient := &qldbsessioniface.MockClientAPI{
+		SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+			return qldbsessioniface.DefaultSendCommandOutput(params), nil
+		},
+	}
+	scaler := &poolScaler{waitThreshold: 10 * time.Millisecond, minIdleSessions: 1, capacity: 4}
+	testDriver := newMockDriver(t, mockClient, func(options *DriverOptions) {
+		options.MaxConcurrentTransactions = 4
+	})
+	// The scaler is set without the background scaling started by New, so that the test resizes the pool itself
+	testDriver.poolScaler = scaler
+	ctx := context.Background()
+	sessions := make([]*session, 4)
+	for i := range sessions {
+		var err error
+		sessions[i], err = testDriver.getSession(ctx, nil)
+		require.NoError(t, err)
+	}
+	for _, session := range sessions {
+		testDriver.releaseSession(ctx, session)
+	}
+	assert.Equal(t, 4, testDriver.semaphore.resetPeak())
+	endSessionCount := func() int {
+		count := 0
+		for _, input := range mockClient.Inputs() {
+			if input.EndSession != nil {
+				count++
+			}
+		}
+		return count
+	}
+
+	// The sessions were started by the previous interval, which had no peak since
+	scaler.last = testDriver.SessionAcquisitionStats()
+	testDriver.resizePool(scaler)
+	assert.Equal(t, 2, scaler.capacity)
+	assert.Eventually(t, func() bool { return endSessionCount() == 2 }, time.Second, time.Millisecond)
+
+	session, err := testDriver.getSession(ctx, nil)
+	require.NoError(t, err)
+	other, err := testDriver.getSession(ctx, nil)
+	require.NoError(t, err)
+	third, err := testDriver.getSession(ctx, nil)
+	require.NoError(t, err)
+	testDriver.releaseSession(ctx, session)
+	testDriver.releaseSession(ctx, other)
+	testDriver.releaseSession(ctx, third)
+	assert.Eventually(t, func() bool { return endSessionCount() == 3 }, time.Second, time.Millisecond,
+		"a session beyond the capacity of the pool is ended when released")
+
+	testDriver.isClosed = true
+	scaler.last = SessionAcquisitionStats{}
+	testDriver.resizePool(scaler)
+	assert.Equal(t, 2, scaler.capacity, "a closed driver is not resized")
+}
+
+func TestWarmPool(t *testing.T) {
+	mockClient := &qldbsessioniface.MockClientAPI{
+		SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+			return qldbsessioniface.DefaultSendCommandOutput(params), nil
+		},
+	}
+	scaler := &poolScaler{minIdleSessions: 2, capacity: 4}
+	testDriver := newMockDriver(t, mockClient, func(options *DriverOptions) {
+		options.MaxConcurrentTransactions = 4
+	})
+	testDriver.poolScaler = scaler
+	startSessionCount := func() int {
+		count := 0
+		for _, input := range mockClient.Inputs() {
+			if input.StartSession != nil {
+				count++
+			}
+		}
+		return count
+	}
+
+	testDriver.resizePool(scaler)
+	assert.Equal(t, 2, scaler.capacity)
+	assert.Equal(t, 2, startSessionCount(), "sessions are started up to MinIdleSessions")
+	assert.Equal(t, 2, testDriver.sessionPool.(resizableSessionPool).idle())
+	assert.Equal(t, 0, testDriver.semaphore.inUse())
+
+	testDriver.resizePool(scaler)
+	assert.Equal(t, 2, scaler.capacity, "the sessions started for MinIdleSessions are not checkouts")
+	assert.Equal(t, 2, startSessionCount(), "no session is started while MinIdleSessions sessions are idle")
+
+	testDriver.isClosed = true
+	_ = testDriver.sessionPool.(resizableSessionPool).resize(0)
+	testDriver.resizePool(scaler)
+	assert.Equal(t, 2, startSessionCount(), "no session is started for a closed driver")
+}
+
+func TestSemaphorePeak(t *testing.T) {
+	smphr := makeSemaphore(3)
+	assert.True(t, smphr.tryAcquire())
+	assert.True(t, smphr.tryAcquire())
+	smphr.release()
+	assert.Equal(t, 2, smphr.resetPeak())
+	assert.Equal(t, 1, smphr.resetPeak())
+	smphr.release()
+	assert.Equal(t, 1, smphr.resetPeak())
+	assert.Equal(t, 0, smphr.resetPeak())
+}
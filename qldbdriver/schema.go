@@ -0,0 +1,65 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"github.com/amzn/ion-go/ion"
+)
+
+// Column describes a field of a row of data, as returned by GetCurrentColumns.
+type Column struct {
+	// The name of the field, or "" for a row that is not a struct, such as a row of SELECT VALUE.
+	Name string
+	// The Ion type of the field. An untyped null has the type ion.NullType.
+	Type ion.Type
+	// Whether the value of the field is a null, typed or not.
+	IsNull bool
+}
+
+// columns returns the columns of a row of data in Ion binary, in the order of its fields. When a struct has several
+// fields with the same name, the first one is returned. Fields named by a symbol without text are skipped.
+func columns(ionBinary []byte) ([]Column, error) {
+	reader := ion.NewReaderBytes(ionBinary)
+	if !reader.Next() {
+		if reader.Err() != nil {
+			return nil, reader.Err()
+		}
+		return nil, &qldbDriverError{"The row of data is empty."}
+	}
+	if reader.Type() != ion.StructType || reader.IsNull() {
+		return []Column{{Type: reader.Type(), IsNull: reader.IsNull()}}, nil
+	}
+
+	err := reader.StepIn()
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]Column, 0)
+	seen := map[string]bool{}
+	for reader.Next() {
+		fieldName, err := reader.FieldName()
+		if err != nil {
+			return nil, err
+		}
+		if fieldName == nil || fieldName.Text == nil || seen[*fieldName.Text] {
+			continue
+		}
+		seen[*fieldName.Text] = true
+		columns = append(columns, Column{Name: *fieldName.Text, Type: reader.Type(), IsNull: reader.IsNull()})
+	}
+	if reader.Err() != nil {
+		return nil, reader.Err()
+	}
+	return columns, nil
+}
@@ -0,0 +1,113 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"testing"
+
+	"github.com/amzn/ion-go/ion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumns(t *testing.T) {
+	testCases := []struct {
+		name     string
+		row      string
+		expected []Column
+	}{
+		{
+			name: "struct",
+			row:  `{id: 1, name: "Alice", balance: 1.5, tags: [a], joined: 2021-01-01T, photo: null.blob, extra: null}`,
+			expected: []Column{
+				{Name: "id", Type: ion.IntType},
+				{Name: "name", Type: ion.StringType},
+				{Name: "balance", Type: ion.DecimalType},
+				{Name: "tags", Type: ion.ListType},
+				{Name: "joined", Type: ion.TimestampType},
+				{Name: "photo", Type: ion.BlobType, IsNull: true},
+				{Name: "extra", Type: ion.NullType, IsNull: true},
+			},
+		},
+		{
+			name:     "repeated field",
+			row:      `{a: 1, a: "two"}`,
+			expected: []Column{{Name: "a", Type: ion.IntType}},
+		},
+		{
+			name:     "empty struct",
+			row:      `{}`,
+			expected: []Column{},
+		},
+		{
+			name:     "scalar",
+			row:      `"VIN-1"`,
+			expected: []Column{{Type: ion.StringType}},
+		},
+		{
+			name:     "null struct",
+			row:      `null.struct`,
+			expected: []Column{{Type: ion.StructType, IsNull: true}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := columns(ionTextToBinary(t, tc.row))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+	t.Run("empty row", func(t *testing.T) {
+		_, err := columns([]byte{})
+		assert.Error(t, err)
+	})
+}
+
+func TestGetCurrentColumns(t *testing.T) {
+	first := ionTextToBinary(t, `{id: 1}`)
+	second := ionTextToBinary(t, `{id: 2, name: "Bob"}`)
+
+	t.Run("result", func(t *testing.T) {
+		res := &result{ionBinary: second}
+		actual, err := GetCurrentColumns(res)
+		require.NoError(t, err)
+		assert.Equal(t, []Column{{Name: "id", Type: ion.IntType}, {Name: "name", Type: ion.StringType}}, actual)
+
+		_, err = GetCurrentColumns(&result{})
+		assert.Error(t, err)
+	})
+
+	t.Run("buffered result", func(t *testing.T) {
+		res := &bufferedResult{values: [][]byte{first, second}}
+		actual, err := GetCurrentColumns(res)
+		require.NoError(t, err)
+		assert.Equal(t, []Column{{Name: "id", Type: ion.IntType}}, actual)
+
+		require.True(t, res.Next())
+		require.True(t, res.Next())
+		actual, err = GetCurrentColumns(res)
+		require.NoError(t, err)
+		assert.Len(t, actual, 2)
+
+		require.False(t, res.Next())
+		_, err = GetCurrentColumns(res)
+		assert.Error(t, err)
+	})
+
+	t.Run("result not returned by the driver", func(t *testing.T) {
+		_, err := GetCurrentColumns(struct{ Result }{})
+		assert.Error(t, err)
+	})
+}
@@ -16,17 +16,43 @@ package qldbdriver
 import (
 	"context"
 	"errors"
-	"regexp"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
-	"github.com/aws/smithy-go"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/errs"
 )
 
-var regex = regexp.MustCompile(`Transaction\s.*\shas\sexpired`)
-
 type session struct {
-	communicator qldbService
-	logger       *qldbLogger
+	communicator        qldbService
+	logger              *qldbLogger
+	marshalOptions      IonMarshalOptions
+	statementLimit      int
+	fetchPageRetry      *fetchPageRetry
+	resultSizeLimit     *resultSizeLimit
+	cacheReads          bool
+	bufferResults       bool
+	maxCursorSkip       int64
+	strictStatements    bool
+	idleGapThreshold    time.Duration
+	failOnIdleGap       bool
+	linter              *statementLinter
+	hooks               *statementHooks
+	capabilities        *serverCapabilities
+	occConflicts        *occConflictTracker
+	releaseConsumedRows bool
+	trackWrites         bool
+	partition           *poolPartition
+}
+
+// withExecuteOptions returns a copy of the session that logs with the provided logger and applies the options of an
+// Execute call to its transactions.
+func (session *session) withExecuteOptions(logger *qldbLogger, options *ExecuteOptions) *session {
+	copied := *session
+	copied.logger = logger
+	copied.cacheReads = options.CacheDocumentReads
+	copied.bufferResults = options.BufferResult
+	copied.maxCursorSkip = options.MaxCursorSkip
+	return &copied
 }
 
 func (session *session) endSession(ctx context.Context) error {
@@ -40,14 +66,28 @@ func (session *session) execute(ctx context.Context, fn func(txn Transaction) (i
 		return nil, session.wrapError(ctx, err, "")
 	}
 
-	result, err := fn(&transactionExecutor{ctx, txn})
+	// Results that were not consumed to the end are logged once the transaction ends
+	defer txn.logDiagnostics()
+
+	executor := &transactionExecutor{ctx, txn}
+	result, err := fn(executor)
 	if err != nil {
 		return nil, session.wrapError(ctx, err, *txn.id)
 	}
 
+	// A Result cannot fetch its next pages once the transaction is committed.
+	if streaming, ok := result.(Result); ok && session.bufferResults {
+		result, err = executor.BufferResult(streaming)
+		if err != nil {
+			return nil, session.wrapError(ctx, err, *txn.id)
+		}
+	}
+
 	err = txn.commit(ctx)
 	if err != nil {
-		return nil, session.wrapError(ctx, err, *txn.id)
+		txnErr := session.wrapError(ctx, err, *txn.id)
+		txnErr.ambiguousCommit = errs.IsServerError(err) || errors.Is(err, context.DeadlineExceeded)
+		return nil, txnErr
 	}
 
 	return result, nil
@@ -56,15 +96,17 @@ func (session *session) execute(ctx context.Context, fn func(txn Transaction) (i
 func (session *session) wrapError(ctx context.Context, err error, transID string) *txnError {
 	var ise *types.InvalidSessionException
 	var occ *types.OccConflictException
-	var apiErr smithy.APIError
 	switch {
 	case errors.As(err, &ise):
-		match := regex.MatchString(ise.ErrorMessage())
+		expired := errs.IsTransactionExpired(err)
+		if expired {
+			err = &TransactionExpiredError{TransactionID: transID, err: err}
+		}
 		return &txnError{
 			transactionID: transID,
 			message:       "Invalid Session Exception.",
 			err:           err,
-			canRetry:      !match,
+			canRetry:      !expired,
 			abortSuccess:  false,
 			isISE:         true,
 		}
@@ -77,17 +119,34 @@ func (session *session) wrapError(ctx context.Context, err error, transID string
 			abortSuccess:  true,
 			isISE:         false,
 		}
-	case errors.As(err, &apiErr):
-		code := apiErr.ErrorCode()
-		if code == "InternalFailure" || code == "ServiceUnavailable" {
-			return &txnError{
-				transactionID: transID,
-				message:       "Service unavailable or internal error.",
-				err:           err,
-				canRetry:      true,
-				abortSuccess:  session.tryAbort(ctx),
-				isISE:         false,
-			}
+	case errs.IsCredentialsError(err):
+		return &txnError{
+			transactionID: transID,
+			message:       "Credentials error.",
+			err:           &CredentialsError{err: err},
+			canRetry:      false,
+			abortSuccess:  session.tryAbort(ctx),
+			isISE:         false,
+			isCredentials: true,
+		}
+	case errs.IsThrottling(err):
+		return &txnError{
+			transactionID: transID,
+			message:       "Request throttled.",
+			err:           err,
+			canRetry:      true,
+			abortSuccess:  session.tryAbort(ctx),
+			isISE:         false,
+			isThrottle:    true,
+		}
+	case errs.IsServerError(err):
+		return &txnError{
+			transactionID: transID,
+			message:       "Service unavailable or internal error.",
+			err:           err,
+			canRetry:      true,
+			abortSuccess:  session.tryAbort(ctx),
+			isISE:         false,
 		}
 	}
 	return &txnError{
@@ -111,7 +170,37 @@ func (session *session) startTransaction(ctx context.Context) (*transaction, err
 		return nil, err
 	}
 
-	return &transaction{session.communicator, result.TransactionId, session.logger, txnHash}, nil
+	var cache *documentCache
+	if session.cacheReads {
+		cache = newDocumentCache()
+	}
+	var writes *writeSet
+	if session.trackWrites {
+		writes = newWriteSet()
+	}
+
+	return &transaction{
+		communicator:        session.communicator,
+		id:                  result.TransactionId,
+		logger:              session.logger,
+		commitHash:          txnHash,
+		marshalOptions:      session.marshalOptions,
+		statementLimit:      session.statementLimit,
+		fetchPageRetry:      session.fetchPageRetry,
+		resultSizeLimit:     session.resultSizeLimit,
+		documentCache:       cache,
+		maxCursorSkip:       session.maxCursorSkip,
+		strictStatements:    session.strictStatements,
+		idleGapThreshold:    session.idleGapThreshold,
+		failOnIdleGap:       session.failOnIdleGap,
+		lastActivity:        time.Now(),
+		linter:              session.linter,
+		occConflicts:        session.occConflicts,
+		releaseConsumedRows: session.releaseConsumedRows,
+		writes:              writes,
+		hooks:               session.hooks,
+		capabilities:        session.capabilities,
+	}, nil
 }
 
 func (session *session) tryAbort(ctx context.Context) bool {
@@ -55,6 +55,29 @@ func TestSessionManagementIntegration(t *testing.T) {
 		assert.True(t, errors.As(err, &bre))
 	})
 
+	t.Run("Validate fails for non existent ledger", func(t *testing.T) {
+		driver, err := testBase.getDriver(&testDriverOptions{
+			ledgerName: "NoSuchLedger",
+			maxConcTx:  10,
+			retryLimit: 4,
+		})
+		require.NoError(t, err)
+		defer driver.Shutdown(context.Background())
+
+		err = driver.Validate(context.Background())
+		var lue *LedgerUnavailableError
+		require.True(t, errors.As(err, &lue))
+		assert.Equal(t, "NoSuchLedger", lue.LedgerName)
+	})
+
+	t.Run("Validate succeeds for active ledger", func(t *testing.T) {
+		driver, err := testBase.getDefaultDriver()
+		require.NoError(t, err)
+		defer driver.Shutdown(context.Background())
+
+		assert.NoError(t, driver.Validate(context.Background()))
+	})
+
 	t.Run("Get session when pool doesnt have session and has not hit limit", func(t *testing.T) {
 		driver, err := testBase.getDefaultDriver()
 		require.NoError(t, err)
@@ -16,6 +16,7 @@ package qldbdriver
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
@@ -27,7 +28,7 @@ func TestSessionStartTransaction(t *testing.T) {
 	t.Run("error", func(t *testing.T) {
 		mockSessionService := new(mockSessionService)
 		mockSessionService.On("startTransaction", mock.Anything).Return(&mockStartTransactionResult, errMock)
-		session := session{mockSessionService, mockLogger}
+		session := session{communicator: mockSessionService, logger: mockLogger}
 
 		result, err := session.startTransaction(context.Background())
 
@@ -38,12 +39,38 @@ func TestSessionStartTransaction(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockSessionService := new(mockSessionService)
 		mockSessionService.On("startTransaction", mock.Anything).Return(&mockStartTransactionResult, nil)
-		session := session{mockSessionService, mockLogger}
+		session := session{communicator: mockSessionService, logger: mockLogger}
 
 		result, err := session.startTransaction(context.Background())
 
 		assert.NoError(t, err)
 		assert.Equal(t, mockTransactionID, *result.id)
+		assert.Nil(t, result.documentCache)
+	})
+
+	t.Run("cache document reads", func(t *testing.T) {
+		mockSessionService := new(mockSessionService)
+		mockSessionService.On("startTransaction", mock.Anything).Return(&mockStartTransactionResult, nil)
+		baseSession := &session{communicator: mockSessionService, logger: mockLogger}
+		session := baseSession.withExecuteOptions(mockLogger, &ExecuteOptions{CacheDocumentReads: true})
+
+		result, err := session.startTransaction(context.Background())
+
+		assert.NoError(t, err)
+		assert.NotNil(t, result.documentCache)
+		assert.False(t, baseSession.cacheReads)
+	})
+
+	t.Run("max cursor skip", func(t *testing.T) {
+		mockSessionService := new(mockSessionService)
+		mockSessionService.On("startTransaction", mock.Anything).Return(&mockStartTransactionResult, nil)
+		baseSession := &session{communicator: mockSessionService, logger: mockLogger}
+		session := baseSession.withExecuteOptions(mockLogger, &ExecuteOptions{MaxCursorSkip: 100})
+
+		result, err := session.startTransaction(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(100), result.maxCursorSkip)
 	})
 }
 
@@ -51,7 +78,7 @@ func TestSessionEndSession(t *testing.T) {
 	t.Run("error", func(t *testing.T) {
 		mockSessionService := new(mockSessionService)
 		mockSessionService.On("endSession", mock.Anything).Return(&mockEndSessionResult, errMock)
-		session := session{mockSessionService, mockLogger}
+		session := session{communicator: mockSessionService, logger: mockLogger}
 
 		err := session.endSession(context.Background())
 
@@ -61,7 +88,7 @@ func TestSessionEndSession(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockSessionService := new(mockSessionService)
 		mockSessionService.On("endSession", mock.Anything).Return(&mockEndSessionResult, nil)
-		session := session{mockSessionService, mockLogger}
+		session := session{communicator: mockSessionService, logger: mockLogger}
 
 		err := session.endSession(context.Background())
 		assert.NoError(t, err)
@@ -76,7 +103,7 @@ func TestSessionExecute(t *testing.T) {
 			Return(&mockExecuteResult, nil)
 		mockSessionService.On("commitTransaction", mock.Anything, mock.Anything, mock.Anything).
 			Return(&mockCommitTransactionResult, nil)
-		session := session{mockSessionService, mockLogger}
+		session := session{communicator: mockSessionService, logger: mockLogger}
 
 		result, err := session.execute(context.Background(), func(txn Transaction) (interface{}, error) {
 			_, err := txn.Execute("SELECT v FROM table")
@@ -89,11 +116,34 @@ func TestSessionExecute(t *testing.T) {
 		assert.Equal(t, 3, result)
 	})
 
+	t.Run("diagnostics of unconsumed results", func(t *testing.T) {
+		mockSessionService := new(mockSessionService)
+		mockSessionService.On("startTransaction", mock.Anything).Return(&mockStartTransactionResult, nil)
+		mockSessionService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(&mockExecuteResult, nil)
+		mockSessionService.On("commitTransaction", mock.Anything, mock.Anything, mock.Anything).
+			Return(&mockCommitTransactionResult, nil)
+		testLogger := &recordingLogger{}
+		session := session{communicator: mockSessionService, logger: &qldbLogger{logger: testLogger, verbosity: LogDebug}}
+
+		_, err := session.execute(context.Background(), func(txn Transaction) (interface{}, error) {
+			return txn.Execute("SELECT v FROM table")
+		})
+		assert.Nil(t, err)
+		diagnostics := 0
+		for _, message := range testLogger.messages {
+			if strings.HasPrefix(message, "[DEBUG] Statement diagnostics: ") {
+				diagnostics++
+			}
+		}
+		assert.Equal(t, 1, diagnostics)
+	})
+
 	t.Run("startTxnUnknownErrorAbortSuccess", func(t *testing.T) {
 		mockSessionService := new(mockSessionService)
 		mockSessionService.On("startTransaction", mock.Anything).Return(&mockStartTransactionResult, errMock)
 		mockSessionService.On("abortTransaction", mock.Anything).Return(&mockAbortTransactionResult, nil)
-		session := session{mockSessionService, mockLogger}
+		session := session{communicator: mockSessionService, logger: mockLogger}
 
 		result, err := session.execute(context.Background(), func(txn Transaction) (interface{}, error) {
 			_, err := txn.Execute("SELECT v FROM table")
@@ -114,7 +164,7 @@ func TestSessionExecute(t *testing.T) {
 		mockSessionService := new(mockSessionService)
 		mockSessionService.On("startTransaction", mock.Anything).Return(&mockStartTransactionResult, errMock)
 		mockSessionService.On("abortTransaction", mock.Anything).Return(&mockAbortTransactionResult, errMock)
-		session := session{mockSessionService, mockLogger}
+		session := session{communicator: mockSessionService, logger: mockLogger}
 
 		result, err := session.execute(context.Background(), func(txn Transaction) (interface{}, error) {
 			_, err := txn.Execute("SELECT v FROM table")
@@ -134,7 +184,7 @@ func TestSessionExecute(t *testing.T) {
 	t.Run("startTxnISE", func(t *testing.T) {
 		mockSessionService := new(mockSessionService)
 		mockSessionService.On("startTransaction", mock.Anything).Return(&mockStartTransactionResult, testISE)
-		session := session{mockSessionService, mockLogger}
+		session := session{communicator: mockSessionService, logger: mockLogger}
 
 		result, err := session.execute(context.Background(), func(txn Transaction) (interface{}, error) {
 			_, err := txn.Execute("SELECT * FROM table")
@@ -155,7 +205,7 @@ func TestSessionExecute(t *testing.T) {
 		mockSessionService := new(mockSessionService)
 		mockSessionService.On("startTransaction", mock.Anything).Return(&mockStartTransactionResult, test500)
 		mockSessionService.On("abortTransaction", mock.Anything).Return(&mockAbortTransactionResult, nil)
-		session := session{mockSessionService, mockLogger}
+		session := session{communicator: mockSessionService, logger: mockLogger}
 
 		result, err := session.execute(context.Background(), func(txn Transaction) (interface{}, error) {
 			_, err := txn.Execute("SELECT v FROM table")
@@ -177,7 +227,7 @@ func TestSessionExecute(t *testing.T) {
 		mockSessionService := new(mockSessionService)
 		mockSessionService.On("startTransaction", mock.Anything).Return(&mockStartTransactionResult, test500)
 		mockSessionService.On("abortTransaction", mock.Anything).Return(&mockAbortTransactionResult, errMock)
-		session := session{mockSessionService, mockLogger}
+		session := session{communicator: mockSessionService, logger: mockLogger}
 
 		result, err := session.execute(context.Background(), func(txn Transaction) (interface{}, error) {
 			_, err := txn.Execute("SELECT v FROM table")
@@ -201,7 +251,7 @@ func TestSessionExecute(t *testing.T) {
 		mockSessionService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 			Return(&mockExecuteResult, errMock)
 		mockSessionService.On("abortTransaction", mock.Anything).Return(&mockAbortTransactionResult, nil)
-		session := session{mockSessionService, mockLogger}
+		session := session{communicator: mockSessionService, logger: mockLogger}
 
 		result, err := session.execute(context.Background(), func(txn Transaction) (interface{}, error) {
 			_, err := txn.Execute("SELECT v FROM table")
@@ -224,7 +274,7 @@ func TestSessionExecute(t *testing.T) {
 		mockSessionService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 			Return(&mockExecuteResult, errMock)
 		mockSessionService.On("abortTransaction", mock.Anything).Return(&mockAbortTransactionResult, errMock)
-		session := session{mockSessionService, mockLogger}
+		session := session{communicator: mockSessionService, logger: mockLogger}
 
 		result, err := session.execute(context.Background(), func(txn Transaction) (interface{}, error) {
 			_, err := txn.Execute("SELECT v FROM table")
@@ -246,7 +296,7 @@ func TestSessionExecute(t *testing.T) {
 		mockSessionService.On("startTransaction", mock.Anything).Return(&mockStartTransactionResult, nil)
 		mockSessionService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 			Return(&mockExecuteResult, testISE)
-		session := session{mockSessionService, mockLogger}
+		session := session{communicator: mockSessionService, logger: mockLogger}
 
 		result, err := session.execute(context.Background(), func(txn Transaction) (interface{}, error) {
 			_, err := txn.Execute("SELECT v FROM table")
@@ -269,7 +319,7 @@ func TestSessionExecute(t *testing.T) {
 		mockSessionService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 			Return(&mockExecuteResult, test500)
 		mockSessionService.On("abortTransaction", mock.Anything).Return(&mockAbortTransactionResult, nil)
-		session := session{mockSessionService, mockLogger}
+		session := session{communicator: mockSessionService, logger: mockLogger}
 
 		result, err := session.execute(context.Background(), func(txn Transaction) (interface{}, error) {
 			_, err := txn.Execute("SELECT v FROM table")
@@ -286,6 +336,7 @@ func TestSessionExecute(t *testing.T) {
 		assert.False(t, err.isISE)
 		assert.True(t, err.canRetry)
 		assert.True(t, err.abortSuccess)
+		assert.False(t, err.ambiguousCommit)
 	})
 
 	t.Run("execute500AbortError", func(t *testing.T) {
@@ -294,7 +345,7 @@ func TestSessionExecute(t *testing.T) {
 		mockSessionService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 			Return(&mockExecuteResult, test500)
 		mockSessionService.On("abortTransaction", mock.Anything).Return(&mockAbortTransactionResult, errMock)
-		session := session{mockSessionService, mockLogger}
+		session := session{communicator: mockSessionService, logger: mockLogger}
 
 		result, err := session.execute(context.Background(), func(txn Transaction) (interface{}, error) {
 			_, err := txn.Execute("SELECT v FROM table")
@@ -319,7 +370,7 @@ func TestSessionExecute(t *testing.T) {
 		mockSessionService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 			Return(&mockExecuteResult, testBadReq)
 		mockSessionService.On("abortTransaction", mock.Anything).Return(&mockAbortTransactionResult, nil)
-		session := session{mockSessionService, mockLogger}
+		session := session{communicator: mockSessionService, logger: mockLogger}
 
 		result, err := session.execute(context.Background(), func(txn Transaction) (interface{}, error) {
 			_, err := txn.Execute("SELECT v FROM table")
@@ -342,7 +393,7 @@ func TestSessionExecute(t *testing.T) {
 		mockSessionService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 			Return(&mockExecuteResult, testBadReq)
 		mockSessionService.On("abortTransaction", mock.Anything).Return(&mockAbortTransactionResult, errMock)
-		session := session{mockSessionService, mockLogger}
+		session := session{communicator: mockSessionService, logger: mockLogger}
 
 		result, err := session.execute(context.Background(), func(txn Transaction) (interface{}, error) {
 			_, err := txn.Execute("SELECT v FROM table")
@@ -367,7 +418,7 @@ func TestSessionExecute(t *testing.T) {
 		mockSessionService.On("commitTransaction", mock.Anything, mock.Anything, mock.Anything).
 			Return(&mockCommitTransactionResult, errMock)
 		mockSessionService.On("abortTransaction", mock.Anything).Return(&mockAbortTransactionResult, nil)
-		session := session{mockSessionService, mockLogger}
+		session := session{communicator: mockSessionService, logger: mockLogger}
 
 		result, err := session.execute(context.Background(), func(txn Transaction) (interface{}, error) {
 			_, err := txn.Execute("SELECT v FROM table")
@@ -392,7 +443,7 @@ func TestSessionExecute(t *testing.T) {
 		mockSessionService.On("commitTransaction", mock.Anything, mock.Anything, mock.Anything).
 			Return(&mockCommitTransactionResult, errMock)
 		mockSessionService.On("abortTransaction", mock.Anything).Return(&mockAbortTransactionResult, errMock)
-		session := session{mockSessionService, mockLogger}
+		session := session{communicator: mockSessionService, logger: mockLogger}
 
 		result, err := session.execute(context.Background(), func(txn Transaction) (interface{}, error) {
 			_, err := txn.Execute("SELECT v FROM table")
@@ -417,7 +468,7 @@ func TestSessionExecute(t *testing.T) {
 		mockSessionService.On("commitTransaction", mock.Anything, mock.Anything, mock.Anything).
 			Return(&mockCommitTransactionResult, test500)
 		mockSessionService.On("abortTransaction", mock.Anything).Return(&mockAbortTransactionResult, nil)
-		session := session{mockSessionService, mockLogger}
+		session := session{communicator: mockSessionService, logger: mockLogger}
 
 		result, err := session.execute(context.Background(), func(txn Transaction) (interface{}, error) {
 			_, err := txn.Execute("SELECT v FROM table")
@@ -433,6 +484,7 @@ func TestSessionExecute(t *testing.T) {
 		assert.False(t, err.isISE)
 		assert.True(t, err.canRetry)
 		assert.True(t, err.abortSuccess)
+		assert.True(t, err.ambiguousCommit)
 	})
 
 	t.Run("commit500AbortError", func(t *testing.T) {
@@ -443,7 +495,7 @@ func TestSessionExecute(t *testing.T) {
 		mockSessionService.On("commitTransaction", mock.Anything, mock.Anything, mock.Anything).
 			Return(&mockCommitTransactionResult, test500)
 		mockSessionService.On("abortTransaction", mock.Anything).Return(&mockAbortTransactionResult, errMock)
-		session := session{mockSessionService, mockLogger}
+		session := session{communicator: mockSessionService, logger: mockLogger}
 
 		result, err := session.execute(context.Background(), func(txn Transaction) (interface{}, error) {
 			_, err := txn.Execute("SELECT v FROM table")
@@ -459,6 +511,7 @@ func TestSessionExecute(t *testing.T) {
 		assert.False(t, err.isISE)
 		assert.True(t, err.canRetry)
 		assert.False(t, err.abortSuccess)
+		assert.True(t, err.ambiguousCommit)
 	})
 
 	t.Run("commitOCC", func(t *testing.T) {
@@ -468,7 +521,7 @@ func TestSessionExecute(t *testing.T) {
 			Return(&mockExecuteResult, nil)
 		mockSessionService.On("commitTransaction", mock.Anything, mock.Anything, mock.Anything).
 			Return(&mockCommitTransactionResult, testOCC)
-		session := session{mockSessionService, mockLogger}
+		session := session{communicator: mockSessionService, logger: mockLogger}
 
 		result, err := session.execute(context.Background(), func(txn Transaction) (interface{}, error) {
 			_, err := txn.Execute("SELECT v FROM table")
@@ -483,13 +536,14 @@ func TestSessionExecute(t *testing.T) {
 		assert.False(t, err.isISE)
 		assert.True(t, err.canRetry)
 		assert.True(t, err.abortSuccess)
+		assert.False(t, err.ambiguousCommit)
 	})
 
 	t.Run("wrappedAWSErrorHandling", func(t *testing.T) {
 		mockSessionService := new(mockSessionService)
 		mockSessionService.On("abortTransaction", mock.Anything).Return(&mockAbortTransactionResult, errMock)
 
-		session := session{mockSessionService, mockLogger}
+		session := session{communicator: mockSessionService, logger: mockLogger}
 
 		err := session.wrapError(context.Background(), fmt.Errorf("ordinary error"), mockTransactionID)
 		assert.Equal(t, "", err.message)
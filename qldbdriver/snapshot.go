@@ -0,0 +1,225 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/amzn/ion-go/ion"
+)
+
+// ColumnSchema describes a top-level field of the documents of a table, as inferred by QLDBDriver.ExportSnapshot.
+type ColumnSchema struct {
+	// The name of the field.
+	Name string `json:"name"`
+	// The Ion types of the non-null values of the field, in alphabetical order, for example ["decimal", "int"].
+	Types []string `json:"types"`
+	// True if the field is missing or null in some documents.
+	Nullable bool `json:"nullable"`
+}
+
+// TableSchema is the schema of the documents of a table, as inferred by QLDBDriver.ExportSnapshot.
+type TableSchema struct {
+	// The top-level fields of the documents, in the order they first appear.
+	Columns []ColumnSchema `json:"columns"`
+}
+
+// TableSnapshotExport describes the export of a table by QLDBDriver.ExportSnapshot.
+type TableSnapshotExport struct {
+	// The name of the table.
+	TableName string
+	// The number of documents written.
+	Rows int
+	// The schema inferred from the documents.
+	Schema TableSchema
+}
+
+// SnapshotOptions can be used to configure a single call to QLDBDriver.ExportSnapshot.
+type SnapshotOptions struct {
+	// The format in which the documents are written. Default: qldbdriver.ExportIonText.
+	Format ExportFormat
+	// The options used to execute the transaction of each table. Default: nil.
+	ExecuteOptions []func(*ExecuteOptions)
+}
+
+// ExportSnapshot writes the current documents of each table to the writer returned by open for the table, for example
+// to feed an analytics pipeline with periodic full exports. Each table is read in its own transaction, so the
+// documents of a table are a consistent snapshot, but the tables are exported at different times. The schema of the
+// documents of each table is inferred from their Ion values and returned with the number of documents written.
+//
+// open is called again for each attempt of the transaction of a table, and must return a writer that replaces what a
+// previous attempt wrote, such as a file created with os.Create. The writer is closed at the end of each attempt.
+//
+// The documents are written as Ion, JSON Lines or CSV. Parquet is not supported: writing it requires a Parquet encoder,
+// which this module does not depend on, and a column type per field, which the inferred schema cannot always provide
+// since a field may hold values of several Ion types. For Parquet files, convert the JSON Lines or CSV output with the
+// returned schema, for example with Apache Spark or DuckDB.
+//
+// With ExportCSV, the documents are read twice within the transaction, first to infer the columns of the header
+// record, which doubles the read IOs consumed. Every field of the schema is then a column, and nested values are
+// written as JSON.
+//
+// Because the documents of a table are read in a single transaction, a table that cannot be read within
+// MaxTransactionDuration fails with a TransactionExpiredError, and the export stops with the tables exported before
+// it. Such tables are better exported from the journal, with the ExportJournalToS3 operation of the QLDB API.
+func (driver *QLDBDriver) ExportSnapshot(ctx context.Context, tableNames []string, open func(tableName string) (io.WriteCloser, error), fns ...func(*SnapshotOptions)) ([]TableSnapshotExport, error) {
+	options := &SnapshotOptions{}
+	for _, fn := range fns {
+		fn(options)
+	}
+	if options.Format < ExportIonText || options.Format > ExportCSV {
+		return nil, &qldbDriverError{"Invalid export format: " + strconv.Itoa(int(options.Format)) + "."}
+	}
+	for _, tableName := range tableNames {
+		if !tableNameRegex.MatchString(tableName) {
+			return nil, &qldbDriverError{"Invalid table name: '" + tableName + "'."}
+		}
+	}
+
+	exports := make([]TableSnapshotExport, 0, len(tableNames))
+	for _, tableName := range tableNames {
+		var export TableSnapshotExport
+		_, err := driver.Execute(ctx, func(txn Transaction) (interface{}, error) {
+			w, err := open(tableName)
+			if err != nil {
+				return nil, err
+			}
+			export, err = exportTableSnapshot(txn, w, tableName, options.Format)
+			closeErr := w.Close()
+			if err != nil {
+				return nil, err
+			}
+			return nil, closeErr
+		}, options.ExecuteOptions...)
+		if err != nil {
+			return exports, err
+		}
+		driver.logger.logf(LogDebug, "Exported %d documents of table %s.", export.Rows, tableName)
+		exports = append(exports, export)
+	}
+	return exports, nil
+}
+
+// exportTableSnapshot writes the documents of a table to w within txn and infers their schema.
+func exportTableSnapshot(txn Transaction, w io.Writer, tableName string, format ExportFormat) (TableSnapshotExport, error) {
+	statement := "SELECT * FROM " + tableName
+	export := TableSnapshotExport{TableName: tableName}
+	inferrer := newSchemaInferrer()
+	var exporter rowExporter
+	if format == ExportCSV {
+		err := ExecuteStream(txn, statement, inferrer.addRow)
+		if err != nil {
+			return export, err
+		}
+		columns := make([]string, len(inferrer.columns))
+		for i, column := range inferrer.columns {
+			columns[i] = column.Name
+		}
+		exporter = &csvExporter{writer: csv.NewWriter(w), columns: columns}
+	} else {
+		exporter = newRowExporter(w, format)
+	}
+
+	err := ExecuteStream(txn, statement, func(ionBinary []byte) error {
+		if format != ExportCSV {
+			if err := inferrer.addRow(ionBinary); err != nil {
+				return err
+			}
+		}
+		if err := exporter.writeRow(ionBinary); err != nil {
+			return err
+		}
+		export.Rows++
+		return nil
+	})
+	if err != nil {
+		return export, err
+	}
+	export.Schema = inferrer.schema()
+	return export, exporter.flush()
+}
+
+// schemaInferrer accumulates the top-level fields of Ion structs and the types of their values.
+type schemaInferrer struct {
+	rows    int
+	columns []*inferredColumn
+	byName  map[string]*inferredColumn
+}
+
+type inferredColumn struct {
+	ColumnSchema
+	types map[string]bool
+	rows  int
+}
+
+func newSchemaInferrer() *schemaInferrer {
+	return &schemaInferrer{byName: make(map[string]*inferredColumn)}
+}
+
+// addRow adds the fields of a row to the schema. Rows that are not structs are ignored.
+func (inferrer *schemaInferrer) addRow(ionBinary []byte) error {
+	reader := ion.NewReaderBytes(ionBinary)
+	if !reader.Next() || reader.Type() != ion.StructType || reader.IsNull() {
+		return reader.Err()
+	}
+	inferrer.rows++
+	err := reader.StepIn()
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool)
+	for reader.Next() {
+		fieldName, err := reader.FieldName()
+		if err != nil {
+			return err
+		}
+		if fieldName == nil || fieldName.Text == nil || seen[*fieldName.Text] {
+			continue
+		}
+		seen[*fieldName.Text] = true
+		column, ok := inferrer.byName[*fieldName.Text]
+		if !ok {
+			column = &inferredColumn{ColumnSchema: ColumnSchema{Name: *fieldName.Text}, types: make(map[string]bool)}
+			inferrer.byName[column.Name] = column
+			inferrer.columns = append(inferrer.columns, column)
+		}
+		if reader.IsNull() {
+			column.Nullable = true
+		} else {
+			column.types[reader.Type().String()] = true
+		}
+		column.rows++
+	}
+	return reader.Err()
+}
+
+// schema returns the schema of the rows added so far.
+func (inferrer *schemaInferrer) schema() TableSchema {
+	schema := TableSchema{Columns: make([]ColumnSchema, len(inferrer.columns))}
+	for i, column := range inferrer.columns {
+		columnSchema := column.ColumnSchema
+		columnSchema.Types = make([]string, 0, len(column.types))
+		for name := range column.types {
+			columnSchema.Types = append(columnSchema.Types, name)
+		}
+		sort.Strings(columnSchema.Types)
+		columnSchema.Nullable = columnSchema.Nullable || column.rows < inferrer.rows
+		schema.Columns[i] = columnSchema
+	}
+	return schema
+}
@@ -0,0 +1,125 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bufferCloser is a bytes.Buffer that records whether it was closed.
+type bufferCloser struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (buffer *bufferCloser) Close() error {
+	buffer.closed = true
+	return nil
+}
+
+func TestExportSnapshot(t *testing.T) {
+	rows := map[string][]string{
+		"SELECT * FROM Person":  {`{Name: "Jane", Age: 30}`, `{Name: "John", Age: 30.5, Address: {City: "Seattle"}}`, `{Name: null}`},
+		"SELECT * FROM Vehicle": {`{VIN: "1N4AL11D75C109151"}`},
+	}
+	newTestDriver := func() (*QLDBDriver, *qldbsessioniface.MockClientAPI) {
+		mockClient := &qldbsessioniface.MockClientAPI{
+			SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+				output := qldbsessioniface.DefaultSendCommandOutput(params)
+				if params.ExecuteStatement != nil {
+					for _, row := range rows[*params.ExecuteStatement.Statement] {
+						output.ExecuteStatement.FirstPage.Values = append(output.ExecuteStatement.FirstPage.Values, types.ValueHolder{IonBinary: ionTextToBinary(t, row)})
+					}
+				}
+				return output, nil
+			},
+		}
+		return newMockDriver(t, mockClient), mockClient
+	}
+	export := func(t *testing.T, format ExportFormat) ([]TableSnapshotExport, map[string]*bufferCloser, int) {
+		testDriver, mockClient := newTestDriver()
+		buffers := make(map[string]*bufferCloser)
+		exports, err := testDriver.ExportSnapshot(context.Background(), []string{"Person", "Vehicle"}, func(tableName string) (io.WriteCloser, error) {
+			buffers[tableName] = &bufferCloser{}
+			return buffers[tableName], nil
+		}, func(options *SnapshotOptions) {
+			options.Format = format
+		})
+		require.NoError(t, err)
+		statements := 0
+		for _, input := range mockClient.Inputs() {
+			if input.ExecuteStatement != nil {
+				statements++
+			}
+		}
+		return exports, buffers, statements
+	}
+
+	t.Run("JSON lines", func(t *testing.T) {
+		exports, buffers, statements := export(t, ExportJSONLines)
+		assert.Equal(t, 2, statements)
+		require.Len(t, exports, 2)
+		assert.Equal(t, "Person", exports[0].TableName)
+		assert.Equal(t, 3, exports[0].Rows)
+		assert.Equal(t, TableSchema{Columns: []ColumnSchema{
+			{Name: "Name", Types: []string{"string"}, Nullable: true},
+			{Name: "Age", Types: []string{"decimal", "int"}, Nullable: true},
+			{Name: "Address", Types: []string{"struct"}, Nullable: true},
+		}}, exports[0].Schema)
+		assert.Equal(t, TableSchema{Columns: []ColumnSchema{{Name: "VIN", Types: []string{"string"}}}}, exports[1].Schema)
+		assert.Equal(t, 3, strings.Count(buffers["Person"].String(), "\n"))
+		assert.True(t, buffers["Person"].closed)
+		assert.True(t, buffers["Vehicle"].closed)
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		exports, buffers, statements := export(t, ExportCSV)
+		assert.Equal(t, 4, statements)
+		assert.Equal(t, 3, exports[0].Rows)
+		assert.Equal(t, "Name,Age,Address\nJane,30,\nJohn,30.5,\"{\"\"City\"\":\"\"Seattle\"\"}\"\n,,\n", buffers["Person"].String())
+		assert.Equal(t, "VIN\n1N4AL11D75C109151\n", buffers["Vehicle"].String())
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		testDriver, _ := newTestDriver()
+		open := func(tableName string) (io.WriteCloser, error) {
+			return &bufferCloser{}, nil
+		}
+		_, err := testDriver.ExportSnapshot(context.Background(), []string{"Person; DROP"}, open)
+		assert.Error(t, err)
+		_, err = testDriver.ExportSnapshot(context.Background(), []string{"Person"}, open, func(options *SnapshotOptions) {
+			options.Format = ExportFormat(42)
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("open error", func(t *testing.T) {
+		testDriver, _ := newTestDriver()
+		exports, err := testDriver.ExportSnapshot(context.Background(), []string{"Person"}, func(tableName string) (io.WriteCloser, error) {
+			return nil, errMock
+		})
+		assert.Equal(t, errMock, err)
+		assert.Empty(t, exports)
+	})
+}
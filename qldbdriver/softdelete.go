@@ -0,0 +1,90 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"time"
+)
+
+// SoftDelete marks the documents of a table matching whereClause as deleted, instead of deleting them, by setting their
+// DriverOptions.SoftDeleteField to the current time. A DELETE statement removes the document from the current state
+// of the table, leaving it only in its history, whereas a soft-deleted document can be brought back with Restore.
+// whereClause is optional and, when not empty, is added to the condition of the statement, for example `VIN = ?`. Use
+// parameters for any values referenced by whereClause. It returns the number of documents soft deleted, which excludes
+// the documents that were already soft deleted.
+//
+// The statement runs in its own transaction, with the same retries as Execute.
+func (driver *QLDBDriver) SoftDelete(ctx context.Context, tableName string, whereClause string, parameters ...interface{}) (int, error) {
+	if driver.softDeleteField == "" {
+		return 0, &qldbDriverError{"SoftDelete requires DriverOptions.SoftDeleteField."}
+	}
+	statement := "UPDATE " + tableName + " SET " + driver.softDeleteField + " = ?"
+	return driver.updateSoftDeleted(ctx, tableName, statement, whereClause, driver.softDeleteField+" IS NULL",
+		append([]interface{}{time.Now().UTC()}, parameters...))
+}
+
+// Restore brings back the documents of a table matching whereClause that were soft deleted by SoftDelete, by removing
+// their DriverOptions.SoftDeleteField. whereClause is optional and, when not empty, is added to the condition of the
+// statement, for example `VIN = ?`. Use parameters for any values referenced by whereClause. It returns the number of
+// documents restored.
+//
+// The statement runs in its own transaction, with the same retries as Execute.
+func (driver *QLDBDriver) Restore(ctx context.Context, tableName string, whereClause string, parameters ...interface{}) (int, error) {
+	if driver.softDeleteField == "" {
+		return 0, &qldbDriverError{"Restore requires DriverOptions.SoftDeleteField."}
+	}
+	statement := "UPDATE " + tableName + " REMOVE " + driver.softDeleteField
+	return driver.updateSoftDeleted(ctx, tableName, statement, whereClause, driver.softDeleteField+" IS NOT NULL", parameters)
+}
+
+// updateSoftDeleted executes an UPDATE statement on the documents matching whereClause and condition, and returns the
+// number of documents updated.
+func (driver *QLDBDriver) updateSoftDeleted(ctx context.Context, tableName string, statement string, whereClause string, condition string, parameters []interface{}) (int, error) {
+	if !tableNameRegex.MatchString(tableName) {
+		return 0, &qldbDriverError{"Invalid table name: '" + tableName + "'."}
+	}
+	if whereClause != "" {
+		condition = "(" + whereClause + ") AND " + condition
+	}
+	statement += " WHERE " + condition
+
+	updated, err := driver.Execute(ctx, func(txn Transaction) (interface{}, error) {
+		result, err := txn.Execute(statement, parameters...)
+		if err != nil {
+			return nil, err
+		}
+		count := 0
+		for result.Next(txn) {
+			count++
+		}
+		return count, result.Err()
+	})
+	if err != nil {
+		return 0, err
+	}
+	return updated.(int), nil
+}
+
+// excludeSoftDeleted returns whereClause restricted to the documents that are not soft deleted, when
+// DriverOptions.SoftDeleteField is set.
+func (driver *QLDBDriver) excludeSoftDeleted(whereClause string) string {
+	if driver.softDeleteField == "" {
+		return whereClause
+	}
+	if whereClause == "" {
+		return driver.softDeleteField + " IS NULL"
+	}
+	return "(" + whereClause + ") AND " + driver.softDeleteField + " IS NULL"
+}
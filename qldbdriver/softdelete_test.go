@@ -0,0 +1,106 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSoftDelete(t *testing.T) {
+	newTestDriver := func(softDeleteField string, rows ...[]byte) (*QLDBDriver, *qldbsessioniface.MockClientAPI) {
+		mockClient := &qldbsessioniface.MockClientAPI{
+			SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+				output := qldbsessioniface.DefaultSendCommandOutput(params)
+				if params.ExecuteStatement != nil {
+					for _, row := range rows {
+						output.ExecuteStatement.FirstPage.Values = append(output.ExecuteStatement.FirstPage.Values, types.ValueHolder{IonBinary: row})
+					}
+				}
+				return output, nil
+			},
+		}
+		return newMockDriver(t, mockClient, func(options *DriverOptions) {
+			options.SoftDeleteField = softDeleteField
+		}), mockClient
+	}
+	executedStatements := func(mockClient *qldbsessioniface.MockClientAPI) []string {
+		var statements []string
+		for _, input := range mockClient.Inputs() {
+			if input.ExecuteStatement != nil {
+				statements = append(statements, *input.ExecuteStatement.Statement)
+			}
+		}
+		return statements
+	}
+
+	t.Run("soft delete", func(t *testing.T) {
+		testDriver, mockClient := newTestDriver("deletedAt", ionTextToBinary(t, `{documentId: "A"}`), ionTextToBinary(t, `{documentId: "B"}`))
+		count, err := testDriver.SoftDelete(context.Background(), "Vehicle", "Color = ?", "Red")
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, []string{"UPDATE Vehicle SET deletedAt = ? WHERE (Color = ?) AND deletedAt IS NULL"}, executedStatements(mockClient))
+		for _, input := range mockClient.Inputs() {
+			if input.ExecuteStatement != nil {
+				assert.Len(t, input.ExecuteStatement.Parameters, 2)
+			}
+		}
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		testDriver, mockClient := newTestDriver("deletedAt", ionTextToBinary(t, `{documentId: "A"}`))
+		count, err := testDriver.Restore(context.Background(), "Vehicle", "")
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.Equal(t, []string{"UPDATE Vehicle REMOVE deletedAt WHERE deletedAt IS NOT NULL"}, executedStatements(mockClient))
+	})
+
+	t.Run("query helpers exclude soft-deleted documents", func(t *testing.T) {
+		testDriver, mockClient := newTestDriver("deletedAt", ionTextToBinary(t, `{_1: 1}`))
+		require.NoError(t, testDriver.RegisterTable("Vehicle", struct{}{}))
+		_, err := testDriver.Count(context.Background(), "Vehicle", "")
+		require.NoError(t, err)
+		_, err = testDriver.Exists(context.Background(), "Vehicle", "VIN = ? OR VIN = ?", "A", "B")
+		require.NoError(t, err)
+		_, err = testDriver.QueryTyped(context.Background(), "Vehicle", "Color = ?", "Red")
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"SELECT COUNT(*) FROM Vehicle WHERE deletedAt IS NULL",
+			"SELECT 1 FROM Vehicle WHERE (VIN = ? OR VIN = ?) AND deletedAt IS NULL",
+			"SELECT * FROM Vehicle WHERE (Color = ?) AND deletedAt IS NULL",
+		}, executedStatements(mockClient))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		testDriver, mockClient := newTestDriver("")
+		_, err := testDriver.SoftDelete(context.Background(), "Vehicle", "VIN = ?", "A")
+		assert.Error(t, err)
+		_, err = testDriver.Restore(context.Background(), "Vehicle", "VIN = ?", "A")
+		assert.Error(t, err)
+		assert.Empty(t, executedStatements(mockClient))
+	})
+
+	t.Run("invalid table name", func(t *testing.T) {
+		testDriver, mockClient := newTestDriver("deletedAt")
+		_, err := testDriver.SoftDelete(context.Background(), "Vehicle; DROP", "")
+		assert.Error(t, err)
+		assert.Empty(t, executedStatements(mockClient))
+	})
+}
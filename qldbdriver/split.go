@@ -0,0 +1,39 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import "context"
+
+// SplitTransaction runs a flow that needs slow work outside of QLDB, such as a call to another service, between reading
+// and writing the ledger, without keeping a transaction open during the slow work, which could make QLDB expire it.
+// read is executed in a first transaction, external is then called outside of any transaction with the result of read,
+// and write is executed in a second transaction with the results of read and external. read and write are retried like
+// the function passed to Execute, with the same options, but external is not, and write is not executed if external
+// returns an error.
+//
+// Other transactions can change the documents read by read before write is executed, so write should verify that
+// they did not change, for example by reading them again and comparing their versions, and return an error otherwise.
+func (driver *QLDBDriver) SplitTransaction(ctx context.Context, read func(txn Transaction) (interface{}, error), external func(ctx context.Context, read interface{}) (interface{}, error), write func(txn Transaction, read interface{}, external interface{}) (interface{}, error), optFns ...func(*ExecuteOptions)) (interface{}, error) {
+	readResult, err := driver.Execute(ctx, read, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	externalResult, err := external(ctx, readResult)
+	if err != nil {
+		return nil, err
+	}
+	return driver.Execute(ctx, func(txn Transaction) (interface{}, error) {
+		return write(txn, readResult, externalResult)
+	}, optFns...)
+}
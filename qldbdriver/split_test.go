@@ -0,0 +1,89 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitTransaction(t *testing.T) {
+	mockClient := &qldbsessioniface.MockClientAPI{
+		SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+			output := qldbsessioniface.DefaultSendCommandOutput(params)
+			if params.ExecuteStatement != nil && *params.ExecuteStatement.Statement == "SELECT Balance FROM Account BY id WHERE id = ?" {
+				output.ExecuteStatement.FirstPage.Values = []types.ValueHolder{{IonBinary: ionTextToBinary(t, `{Balance: 10}`)}}
+			}
+			return output, nil
+		},
+	}
+	testDriver := newMockDriver(t, mockClient)
+	read := func(txn Transaction) (interface{}, error) {
+		result, err := txn.Execute("SELECT Balance FROM Account BY id WHERE id = ?", "A")
+		if err != nil {
+			return nil, err
+		}
+		return txn.BufferResult(result)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		var readInTransaction interface{}
+		result, err := testDriver.SplitTransaction(context.Background(), read, func(ctx context.Context, read interface{}) (interface{}, error) {
+			readInTransaction = read
+			return "approved", nil
+		}, func(txn Transaction, read interface{}, external interface{}) (interface{}, error) {
+			assert.Equal(t, readInTransaction, read)
+			assert.Equal(t, "approved", external)
+			_, err := txn.Execute("UPDATE Account BY id SET Balance = ? WHERE id = ?", 5, "A")
+			return "done", err
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "done", result)
+		require.NotNil(t, readInTransaction)
+		assert.True(t, readInTransaction.(BufferedResult).Next())
+	})
+
+	t.Run("external error", func(t *testing.T) {
+		written := false
+		_, err := testDriver.SplitTransaction(context.Background(), read, func(ctx context.Context, read interface{}) (interface{}, error) {
+			return nil, errMock
+		}, func(txn Transaction, read interface{}, external interface{}) (interface{}, error) {
+			written = true
+			return nil, nil
+		})
+		assert.Equal(t, errMock, err)
+		assert.False(t, written)
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		called := false
+		_, err := testDriver.SplitTransaction(context.Background(), func(txn Transaction) (interface{}, error) {
+			return nil, errMock
+		}, func(ctx context.Context, read interface{}) (interface{}, error) {
+			called = true
+			return nil, nil
+		}, func(txn Transaction, read interface{}, external interface{}) (interface{}, error) {
+			called = true
+			return nil, nil
+		})
+		assert.Equal(t, errMock, err)
+		assert.False(t, called)
+	})
+}
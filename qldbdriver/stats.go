@@ -0,0 +1,94 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"sync"
+	"time"
+)
+
+// SessionAcquisitionStats counts how the sessions used by a QLDBDriver were acquired, to diagnose a driver that runs out
+// of sessions or spends much time starting them. Every Execute call acquires a permit, bounded by
+// DriverOptions.MaxConcurrentTransactions, and then reuses an idle session from the pool or starts a new one.
+type SessionAcquisitionStats struct {
+	// The number of sessions reused from the pool.
+	Reused int64
+	// The number of StartSession commands sent to QLDB, including the ones replacing invalid sessions and the failed
+	// ones.
+	Created int64
+	// The number of StartSession commands that failed.
+	CreateFailures int64
+	// The number of times no permit was available, which fails with a "MaxConcurrentTransactions limit exceeded" error.
+	Rejected int64
+	// The number of StartSession commands and transaction attempts that QLDB throttled. A growing count means the
+	// driver sends more requests than the limits of the account allow, see RetryPolicy.ThrottleBackoff.
+	Throttled int64
+	// The total time spent acquiring permits.
+	PermitWait time.Duration
+	// The total time spent waiting for QLDB to start sessions.
+	StartSessionLatency time.Duration
+}
+
+// acquisitionStats accumulates the SessionAcquisitionStats of a driver. The zero value is ready to use.
+type acquisitionStats struct {
+	lock  sync.Mutex
+	stats SessionAcquisitionStats
+}
+
+// recordPermit records the time spent acquiring a permit, and whether it was acquired.
+func (s *acquisitionStats) recordPermit(wait time.Duration, acquired bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.stats.PermitWait += wait
+	if !acquired {
+		s.stats.Rejected++
+	}
+}
+
+// recordReuse records a session reused from the pool.
+func (s *acquisitionStats) recordReuse() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.stats.Reused++
+}
+
+// recordStartSession records the time spent starting a session, and whether it was started.
+func (s *acquisitionStats) recordStartSession(latency time.Duration, err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.stats.Created++
+	s.stats.StartSessionLatency += latency
+	if err != nil {
+		s.stats.CreateFailures++
+	}
+}
+
+// recordThrottle records a StartSession command or a transaction attempt throttled by QLDB.
+func (s *acquisitionStats) recordThrottle() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.stats.Throttled++
+}
+
+// snapshot returns a copy of the accumulated stats.
+func (s *acquisitionStats) snapshot() SessionAcquisitionStats {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.stats
+}
+
+// SessionAcquisitionStats returns how the sessions used by the driver were acquired since it was created.
+func (driver *QLDBDriver) SessionAcquisitionStats() SessionAcquisitionStats {
+	return driver.acquisitionStats.snapshot()
+}
@@ -0,0 +1,106 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/aws/smithy-go"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionAcquisitionStats(t *testing.T) {
+	newTestDriver := func(mockClient *qldbsessioniface.MockClientAPI, maxConcurrentTransactions int) *QLDBDriver {
+		return newMockDriver(t, mockClient, func(options *DriverOptions) {
+			options.MaxConcurrentTransactions = maxConcurrentTransactions
+		})
+	}
+	noop := func(txn Transaction) (interface{}, error) { return nil, nil }
+
+	t.Run("created and reused sessions", func(t *testing.T) {
+		testDriver := newTestDriver(&qldbsessioniface.MockClientAPI{}, 10)
+		for i := 0; i < 3; i++ {
+			_, err := testDriver.Execute(context.Background(), noop)
+			require.NoError(t, err)
+		}
+
+		stats := testDriver.SessionAcquisitionStats()
+		assert.Equal(t, int64(1), stats.Created)
+		assert.Equal(t, int64(2), stats.Reused)
+		assert.Equal(t, int64(0), stats.CreateFailures)
+		assert.Equal(t, int64(0), stats.Rejected)
+	})
+
+	t.Run("failed session start", func(t *testing.T) {
+		errStart := errors.New("start session failed")
+		testDriver := newTestDriver(&qldbsessioniface.MockClientAPI{
+			SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+				return nil, errStart
+			},
+		}, 10)
+
+		_, err := testDriver.Execute(context.Background(), noop)
+		assert.Error(t, err)
+
+		stats := testDriver.SessionAcquisitionStats()
+		assert.Equal(t, int64(1), stats.Created)
+		assert.Equal(t, int64(1), stats.CreateFailures)
+		assert.Equal(t, int64(0), stats.Reused)
+	})
+
+	t.Run("throttled", func(t *testing.T) {
+		throttled := 0
+		testDriver := newTestDriver(&qldbsessioniface.MockClientAPI{
+			SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+				if params.StartTransaction != nil && throttled < 2 {
+					throttled++
+					return nil, &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded"}
+				}
+				return qldbsessioniface.DefaultSendCommandOutput(params), nil
+			},
+		}, 10)
+		testDriver.retryPolicy = RetryPolicy{MaxRetryLimit: 4, Backoff: constantBackoff(time.Millisecond), ThrottleBackoff: constantBackoff(2 * time.Millisecond)}
+
+		_, err := testDriver.Execute(context.Background(), noop)
+		require.NoError(t, err)
+
+		stats := testDriver.SessionAcquisitionStats()
+		assert.Equal(t, int64(2), stats.Throttled)
+		assert.Equal(t, int64(1), stats.Created)
+		retries := testDriver.RecentRetries()
+		require.Len(t, retries, 2)
+		for _, retry := range retries {
+			assert.Equal(t, RetryThrottled, retry.ErrorClass)
+			assert.Equal(t, 2*time.Millisecond, retry.Delay)
+		}
+	})
+
+	t.Run("no permit available", func(t *testing.T) {
+		testDriver := newTestDriver(&qldbsessioniface.MockClientAPI{}, 1)
+		_, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+			return testDriver.Execute(context.Background(), noop)
+		})
+		assert.Error(t, err)
+
+		stats := testDriver.SessionAcquisitionStats()
+		assert.Equal(t, int64(1), stats.Rejected)
+		assert.Equal(t, int64(1), stats.Created)
+	})
+}
@@ -0,0 +1,66 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"regexp"
+)
+
+var (
+	// interpolationRegex matches the markers of Go format verbs and common template syntaxes, which are left in a
+	// literal when a statement is built from a template instead of with parameters.
+	interpolationRegex = regexp.MustCompile(`%[-+# 0-9.]*[vTtbcdoqxXeEfFgGsp]|\$\{|\{\{|#\{`)
+	// nonDeterministicRegex matches the functions whose result differs between the attempts of a transaction.
+	nonDeterministicRegex = regexp.MustCompile(`(?i)\butcnow\s*\(`)
+)
+
+// UnsafeStatementError is returned when DriverOptions.StrictStatements is set and a statement looks like it was built
+// by interpolating values instead of passing them as parameters, or calls a non-deterministic function. The statement
+// is not sent to QLDB and the transaction is not retried.
+type UnsafeStatementError struct {
+	// The statement, with its literals redacted.
+	Statement string
+	// The reason the statement was rejected.
+	Reason string
+}
+
+// Error returns the message denoting the cause of the error.
+func (e *UnsafeStatementError) Error() string {
+	return "Statement rejected by strict mode: " + e.Reason + ": " + e.Statement
+}
+
+// checkStrictStatement returns an UnsafeStatementError if the statement contains a literal with an interpolation marker
+// or calls a non-deterministic function. This is a heuristic, which cannot detect values that were interpolated
+// correctly, and is meant to catch statements built from templates in code that should use parameters.
+func checkStrictStatement(statement string) error {
+	for _, literal := range literalRegex.FindAllString(statement, -1) {
+		if literal[0] != '\'' && literal[0] != '`' {
+			continue
+		}
+		if interpolationRegex.MatchString(literal) {
+			return &UnsafeStatementError{
+				Statement: redactStatement(statement),
+				Reason:    "a literal contains the interpolation marker '" + interpolationRegex.FindString(literal) + "', use a parameter instead",
+			}
+		}
+	}
+	stripped := literalRegex.ReplaceAllString(statement, "?")
+	if match := nonDeterministicRegex.FindString(stripped); match != "" {
+		return &UnsafeStatementError{
+			Statement: redactStatement(statement),
+			Reason:    "the non-deterministic function " + match + ") returns different values when the transaction is retried, pass the value as a parameter instead",
+		}
+	}
+	return nil
+}
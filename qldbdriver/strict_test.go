@@ -0,0 +1,76 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckStrictStatement(t *testing.T) {
+	testCases := []struct {
+		statement string
+		rejected  bool
+	}{
+		{"SELECT * FROM Person WHERE name = ?", false},
+		{"SELECT name FROM information_schema.user_tables WHERE status = 'ACTIVE'", false},
+		{"SELECT * FROM Person WHERE discount = '50%'", false},
+		{"SELECT * FROM Person WHERE name = '%s'", true},
+		{"SELECT * FROM Person WHERE age = '%d'", true},
+		{"SELECT * FROM Person WHERE name = '${name}'", true},
+		{"SELECT * FROM Person WHERE name = '{{.Name}}'", true},
+		{"INSERT INTO Person `{name: \"%v\"}`", true},
+		{"UPDATE Person SET updated = UTCNOW() WHERE name = ?", true},
+		{"UPDATE Person SET updated = utcnow () WHERE name = ?", true},
+		{"UPDATE Person SET note = 'utcnow()' WHERE name = ?", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.statement, func(t *testing.T) {
+			err := checkStrictStatement(tc.statement)
+			if !tc.rejected {
+				assert.NoError(t, err)
+				return
+			}
+			var unsafeErr *UnsafeStatementError
+			require.True(t, errors.As(err, &unsafeErr))
+			assert.Equal(t, redactStatement(tc.statement), unsafeErr.Statement)
+			assert.NotEmpty(t, unsafeErr.Reason)
+		})
+	}
+}
+
+func TestStrictTransaction(t *testing.T) {
+	mockHash, _ := toQLDBHash(mockTxnID)
+	mockService := new(mockTransactionService)
+	testTransaction := &transaction{
+		communicator:     mockService,
+		id:               &mockTxnID,
+		logger:           mockLogger,
+		commitHash:       mockHash,
+		strictStatements: true,
+	}
+
+	_, err := testTransaction.execute(context.Background(), "SELECT * FROM Person WHERE name = '%s'")
+	var unsafeErr *UnsafeStatementError
+	require.True(t, errors.As(err, &unsafeErr))
+	assert.Equal(t, "SELECT * FROM Person WHERE name = '?'", unsafeErr.Statement)
+	assert.Equal(t, 0, testTransaction.statementCount)
+	mockService.AssertNotCalled(t, "executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
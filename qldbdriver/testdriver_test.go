@@ -0,0 +1,37 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"testing"
+
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/require"
+)
+
+// newMockDriver creates a QLDBDriver like New, sending its commands to qldbSession. Unless fns change them, the driver
+// has up to 10 transactions in progress, does not retry them, and logs like mockLogger.
+func newMockDriver(t *testing.T, qldbSession qldbsessioniface.ClientAPI, fns ...func(*DriverOptions)) *QLDBDriver {
+	options := defaultDriverOptions()
+	options.MaxConcurrentTransactions = 10
+	options.RetryPolicy = RetryPolicy{}
+	options.Logger = mockLogger.logger
+	options.LoggerVerbosity = mockLogger.verbosity
+	for _, fn := range fns {
+		fn(options)
+	}
+	driver, err := newDriver(mockLedgerName, qldbSession, options)
+	require.NoError(t, err)
+	return driver
+}
@@ -14,9 +14,11 @@ and limitations under the License.
 package qldbdriver
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"reflect"
+	"time"
 
 	"github.com/amzn/ion-go/ion"
 	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
@@ -35,18 +37,79 @@ type Transaction interface {
 }
 
 type transaction struct {
-	communicator qldbService
-	id           *string
-	logger       *qldbLogger
-	commitHash   *qldbHash
+	communicator        qldbService
+	id                  *string
+	logger              *qldbLogger
+	commitHash          *qldbHash
+	results             []*result
+	marshalOptions      IonMarshalOptions
+	statementCount      int
+	statementLimit      int
+	fetchPageRetry      *fetchPageRetry
+	resultSizeLimit     *resultSizeLimit
+	documentCache       *documentCache
+	maxCursorSkip       int64
+	strictStatements    bool
+	idleGapThreshold    time.Duration
+	failOnIdleGap       bool
+	lastActivity        time.Time
+	linter              *statementLinter
+	occConflicts        *occConflictTracker
+	releaseConsumedRows bool
+	writes              *writeSet
+	hooks               *statementHooks
+	capabilities        *serverCapabilities
 }
 
 func (txn *transaction) execute(ctx context.Context, statement string, parameters ...interface{}) (*result, error) {
+	if txn.strictStatements {
+		if err := checkStrictStatement(statement); err != nil {
+			return nil, err
+		}
+	}
+	if txn.linter != nil {
+		txn.linter.lint(txn.logger, statement)
+	}
+	if err := txn.checkIdleGap("statement"); err != nil {
+		return nil, err
+	}
+	defer func() {
+		txn.lastActivity = time.Now()
+	}()
+	return txn.hooks.run(ctx, txn, statement, len(parameters), func() (*result, error) {
+		if txn.documentCache != nil {
+			return txn.executeCached(ctx, statement, parameters...)
+		}
+		return txn.executeStatement(ctx, statement, parameters...)
+	})
+}
+
+// checkIdleGap logs, or fails with a TransactionIdleError, when the transaction was idle for longer than the idle gap
+// threshold before the next statement or the commit.
+func (txn *transaction) checkIdleGap(next string) error {
+	if txn.idleGapThreshold <= 0 {
+		return nil
+	}
+	gap := time.Since(txn.lastActivity)
+	if gap <= txn.idleGapThreshold {
+		return nil
+	}
+	if txn.failOnIdleGap {
+		return &TransactionIdleError{TransactionID: *txn.id, Gap: gap, Threshold: txn.idleGapThreshold}
+	}
+	txn.logger.logf(LogInfo, "Transaction %s was idle for %v before its %s, exceeding threshold of %v. Consider moving work that does not involve QLDB outside of the transaction.",
+		*txn.id, gap, next, txn.idleGapThreshold)
+	return nil
+}
+
+func (txn *transaction) executeStatement(ctx context.Context, statement string, parameters ...interface{}) (*result, error) {
 	executeHash, err := toQLDBHash(statement)
 	if err != nil {
 		return nil, err
 	}
+	parameters = txn.wrapParameters(parameters)
 	valueHolders := make([]types.ValueHolder, len(parameters))
+	totalSize := 0
 	for i, parameter := range parameters {
 		parameterHash, err := toQLDBHash(parameter)
 		if err != nil {
@@ -59,36 +122,153 @@ func (txn *transaction) execute(ctx context.Context, statement string, parameter
 
 		// Can ignore error here since toQLDBHash calls MarshalBinary already
 		ionBinary, _ := ion.MarshalBinary(parameter)
+		totalSize += len(ionBinary)
+		if err := checkParameterSize(i, ionBinary, totalSize); err != nil {
+			return nil, err
+		}
 		valueHolder := types.ValueHolder{IonBinary: ionBinary}
 		valueHolders[i] = valueHolder
 	}
+	err = txn.countStatement()
+	if err != nil {
+		return nil, err
+	}
 	commitHash, err := txn.commitHash.dot(executeHash)
 	if err != nil {
 		return nil, err
 	}
 	txn.commitHash = commitHash
 
+	start := time.Now()
 	executeResult, err := txn.communicator.executeStatement(ctx, &statement, valueHolders, txn.id)
+	latency := time.Since(start)
 	if err != nil {
 		return nil, err
 	}
+	txn.capabilities.observe(executeResult.ConsumedIOs != nil, executeResult.TimingInformation != nil)
 
 	// create IOUsage and copy the values returned in executeResult.ConsumedIOs
-	var ioUsage = &IOUsage{new(int64), new(int64)}
+	var ioUsage = &IOUsage{readIOs: new(int64), writeIOs: new(int64)}
 	if executeResult.ConsumedIOs != nil {
 		*ioUsage.readIOs += executeResult.ConsumedIOs.ReadIOs
 		*ioUsage.writeIOs += executeResult.ConsumedIOs.WriteIOs
 	}
 	// create TimingInformation and copy the values returned in executeResult.TimingInformation
-	var timingInfo = &TimingInformation{new(int64)}
+	var timingInfo = &TimingInformation{processingTimeMilliseconds: new(int64)}
 	if executeResult.TimingInformation != nil {
 		*timingInfo.processingTimeMilliseconds = executeResult.TimingInformation.ProcessingTimeMilliseconds
 	}
 
-	return &result{ctx, txn.communicator, txn.id, executeResult.FirstPage.Values, executeResult.FirstPage.NextPageToken, 0, txn.logger, nil, ioUsage, timingInfo, nil}, nil
+	res := &result{
+		ctx:           ctx,
+		communicator:  txn.communicator,
+		txnID:         txn.id,
+		pageValues:    executeResult.FirstPage.Values,
+		pageToken:     executeResult.FirstPage.NextPageToken,
+		logger:        txn.logger,
+		ioUsage:       ioUsage,
+		timingInfo:    timingInfo,
+		statementHash: executeHash.hash,
+		statement:     statement,
+		paramCount:    len(parameters),
+		pagesFetched:  1,
+		latency:       latency,
+		releaseRows:   txn.releaseConsumedRows,
+		fetchRetry:    txn.fetchPageRetry,
+		sizeLimit:     txn.resultSizeLimit,
+		capabilities:  txn.capabilities,
+	}
+	if txn.writes != nil {
+		txn.writes.record(statement, executeResult.FirstPage)
+	}
+	if txn.occConflicts != nil && txn.occConflicts.captureParameters {
+		res.parameters = parameters
+	}
+	txn.results = append(txn.results, res)
+	return res, nil
+}
+
+// countStatement counts a statement towards the statement limit, logging a warning when the transaction approaches it.
+func (txn *transaction) countStatement() error {
+	if txn.statementLimit <= 0 {
+		txn.statementCount++
+		return nil
+	}
+	if txn.statementCount >= txn.statementLimit {
+		return &StatementLimitError{TransactionID: *txn.id, Limit: txn.statementLimit}
+	}
+	txn.statementCount++
+	if txn.statementCount == txn.statementLimit-txn.statementLimit/5 {
+		txn.logger.logf(LogInfo, "Transaction %s has executed %d of the %d statements allowed per transaction. Consider splitting the work across transactions.",
+			*txn.id, txn.statementCount, txn.statementLimit)
+	}
+	return nil
+}
+
+func (txn *transaction) resume(ctx context.Context, cursor *Cursor, statement string, paramCount int) *result {
+	pageToken := *cursor.pageToken
+	res := &result{
+		ctx:           ctx,
+		communicator:  txn.communicator,
+		txnID:         txn.id,
+		pageToken:     &pageToken,
+		logger:        txn.logger,
+		ioUsage:       &IOUsage{readIOs: new(int64), writeIOs: new(int64)},
+		timingInfo:    &TimingInformation{processingTimeMilliseconds: new(int64)},
+		statementHash: cursor.statementHash,
+		position:      cursor.position,
+		statement:     statement,
+		paramCount:    paramCount,
+		releaseRows:   txn.releaseConsumedRows,
+		fetchRetry:    txn.fetchPageRetry,
+		sizeLimit:     txn.resultSizeLimit,
+		capabilities:  txn.capabilities,
+	}
+	txn.results = append(txn.results, res)
+	return res
+}
+
+// logDiagnostics logs the diagnostics of the statements whose results were not consumed to the end.
+func (txn *transaction) logDiagnostics() {
+	for _, res := range txn.results {
+		res.logDiagnostics()
+	}
+}
+
+// consumedIOs returns the total IO usage of the statements executed so far within this transaction.
+func (txn *transaction) consumedIOs() *IOUsage {
+	var readIOs, writeIOs int64
+	for _, res := range txn.results {
+		readIOs += *res.ioUsage.readIOs
+		writeIOs += *res.ioUsage.writeIOs
+	}
+	return newIOUsage(readIOs, writeIOs)
+}
+
+// wrapParameters applies the transaction's marshal options to parameters that do not specify their own, and converts
+// JSON parameters and parameters of math/big types to Ion.
+func (txn *transaction) wrapParameters(parameters []interface{}) []interface{} {
+	return wrapParameters(parameters, txn.marshalOptions)
+}
+
+// wrapParameters applies marshalOptions to parameters that do not specify their own, and converts JSON parameters and
+// parameters of math/big types to Ion.
+func wrapParameters(parameters []interface{}, marshalOptions IonMarshalOptions) []interface{} {
+	wrapped := make([]interface{}, len(parameters))
+	for i, parameter := range parameters {
+		if _, ok := parameter.(*ionParameter); ok || marshalOptions.isDefault() {
+			wrapped[i] = toIonParameter(parameter)
+		} else {
+			wrapped[i] = &ionParameter{parameter, marshalOptions}
+		}
+	}
+	return wrapped
 }
 
 func (txn *transaction) commit(ctx context.Context) error {
+	if err := txn.checkIdleGap("commit"); err != nil {
+		return err
+	}
 	commitResult, err := txn.communicator.commitTransaction(ctx, txn.id, txn.commitHash.hash)
 	if err != nil {
 		return err
@@ -100,6 +280,9 @@ func (txn *transaction) commit(ctx context.Context) error {
 		}
 	}
 
+	for _, res := range txn.results {
+		res.committed = true
+	}
 	return nil
 }
 
@@ -113,6 +296,97 @@ func (executor *transactionExecutor) Execute(statement string, parameters ...int
 	return executor.txn.execute(executor.ctx, statement, parameters...)
 }
 
+// ExecuteStream executes a statement with any parameters within txn, invoking onRow for each row of data in Ion format.
+// Pages are fetched as needed while iterating. Returning an error from onRow stops the iteration and that error is
+// returned.
+func ExecuteStream(txn Transaction, statement string, onRow func(ionBinary []byte) error, parameters ...interface{}) error {
+	result, err := txn.Execute(statement, parameters...)
+	if err != nil {
+		return err
+	}
+	for result.Next(txn) {
+		err = onRow(result.GetCurrentData())
+		if err != nil {
+			return err
+		}
+	}
+	return result.Err()
+}
+
+// ExecuteFromCursor executes a statement with any parameters within txn, resuming iteration at the position saved in
+// cursor. The statement and parameters must be the same as the ones used to produce the cursor. If cursor was returned
+// by GetCursor in this transaction at a page boundary, the next page is fetched directly instead of re-executing the
+// statement. txn must be a Transaction passed by the driver to the function of QLDBDriver.Execute.
+//
+// Otherwise the rows before the cursor are read again and skipped, which consumes their read IOs: resuming at each
+// page of a result in its own transaction costs read IOs quadratic in the number of pages. Since the position of a
+// Cursor parsed from a client can be chosen by the client, set ExecuteOptions.MaxCursorSkip to bound the rows read.
+func ExecuteFromCursor(txn Transaction, cursor *Cursor, statement string, parameters ...interface{}) (Result, error) {
+	if _, ok := txn.(*savepointTransaction); ok {
+		return nil, &qldbDriverError{"ExecuteFromCursor is not supported within ExecuteWithSavepoints."}
+	}
+	executor, ok := txn.(*transactionExecutor)
+	if !ok {
+		return nil, &qldbDriverError{"ExecuteFromCursor requires a Transaction of the driver."}
+	}
+	return executor.executeFromCursor(cursor, statement, parameters...)
+}
+
+func (executor *transactionExecutor) executeFromCursor(cursor *Cursor, statement string, parameters ...interface{}) (Result, error) {
+	if cursor == nil {
+		return nil, &qldbDriverError{"Provided cursor is nil."}
+	}
+	if cursor.pageToken != nil && cursor.transactionID == *executor.txn.id {
+		statementHash, err := toStatementHash(statement, executor.txn.wrapParameters(parameters))
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(statementHash.hash, cursor.statementHash) {
+			return nil, &qldbDriverError{"Cursor does not match the provided statement and parameters."}
+		}
+		return executor.txn.resume(executor.ctx, cursor, statement, len(parameters)), nil
+	}
+
+	if executor.txn.maxCursorSkip > 0 && cursor.position > executor.txn.maxCursorSkip {
+		return nil, &CursorSkipError{Position: cursor.position, Limit: executor.txn.maxCursorSkip}
+	}
+	result, err := executor.txn.execute(executor.ctx, statement, parameters...)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(result.statementHash, cursor.statementHash) {
+		return nil, &qldbDriverError{"Cursor does not match the provided statement and parameters."}
+	}
+	for result.position < cursor.position && result.Next(executor) {
+		// Skip over rows consumed before the cursor was created
+	}
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+	result.ionBinary = nil
+	return result, nil
+}
+
+// StatementCount returns the number of statements executed within txn, and false if txn is not a Transaction passed by
+// the driver to the function of QLDBDriver.Execute or QLDBDriver.ExecuteWithSavepoints.
+func StatementCount(txn Transaction) (int, bool) {
+	executor, ok := executorOf(txn)
+	if !ok {
+		return 0, false
+	}
+	return executor.txn.statementCount, true
+}
+
+// executorOf returns the transactionExecutor of txn, unwrapping the SavepointTransaction of
+// QLDBDriver.ExecuteWithSavepoints.
+func executorOf(txn Transaction) (*transactionExecutor, bool) {
+	if savepointTxn, ok := txn.(*savepointTransaction); ok {
+		txn = savepointTxn.Transaction
+	}
+	executor, ok := txn.(*transactionExecutor)
+	return executor, ok
+}
+
 // Buffer a Result into a BufferedResult to use outside the context of this transaction.
 func (executor *transactionExecutor) BufferResult(result Result) (BufferedResult, error) {
 	bufferedResults := make([][]byte, 0)
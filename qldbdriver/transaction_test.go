@@ -15,7 +15,9 @@ package qldbdriver
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
 	"github.com/stretchr/testify/assert"
@@ -145,6 +147,155 @@ func TestTransaction(t *testing.T) {
 			assert.Error(t, testTransaction.commit(context.Background()))
 		})
 	})
+
+	t.Run("statement limit", func(t *testing.T) {
+		mockHash, _ := toQLDBHash(mockTxnID)
+		executeResult := types.ExecuteStatementResult{FirstPage: &types.Page{}}
+		mockService := new(mockTransactionService)
+		mockService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&executeResult, nil)
+		testLogger := &recordingLogger{}
+		testTransaction := &transaction{
+			communicator:   mockService,
+			id:             &mockTxnID,
+			logger:         &qldbLogger{logger: testLogger, verbosity: LogInfo},
+			commitHash:     mockHash,
+			statementLimit: 5,
+		}
+
+		for i := 0; i < 5; i++ {
+			_, err := testTransaction.execute(context.Background(), "mockStatement")
+			require.NoError(t, err)
+		}
+		require.Len(t, testLogger.messages, 1)
+		assert.Contains(t, testLogger.messages[0], "has executed 4 of the 5 statements allowed per transaction")
+
+		_, err := testTransaction.execute(context.Background(), "mockStatement")
+		var limitErr *StatementLimitError
+		require.True(t, errors.As(err, &limitErr))
+		assert.Equal(t, mockTxnID, limitErr.TransactionID)
+		assert.Equal(t, 5, limitErr.Limit)
+		assert.Equal(t, 5, testTransaction.statementCount)
+		mockService.AssertNumberOfCalls(t, "executeStatement", 5)
+	})
+
+	t.Run("idle gap", func(t *testing.T) {
+		newTestTransaction := func(failOnIdleGap bool) (*transaction, *recordingLogger, *mockTransactionService) {
+			mockHash, _ := toQLDBHash(mockTxnID)
+			executeResult := types.ExecuteStatementResult{FirstPage: &types.Page{}}
+			mockService := new(mockTransactionService)
+			mockService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&executeResult, nil)
+			testLogger := &recordingLogger{}
+			return &transaction{
+				communicator:     mockService,
+				id:               &mockTxnID,
+				logger:           &qldbLogger{logger: testLogger, verbosity: LogInfo},
+				commitHash:       mockHash,
+				idleGapThreshold: time.Second,
+				failOnIdleGap:    failOnIdleGap,
+				lastActivity:     time.Now(),
+			}, testLogger, mockService
+		}
+
+		t.Run("within threshold", func(t *testing.T) {
+			testTransaction, testLogger, _ := newTestTransaction(true)
+			_, err := testTransaction.execute(context.Background(), "mockStatement")
+			require.NoError(t, err)
+			assert.Empty(t, testLogger.messages)
+		})
+
+		t.Run("log", func(t *testing.T) {
+			testTransaction, testLogger, _ := newTestTransaction(false)
+			testTransaction.lastActivity = time.Now().Add(-2 * time.Second)
+			_, err := testTransaction.execute(context.Background(), "mockStatement")
+			require.NoError(t, err)
+			require.Len(t, testLogger.messages, 1)
+			assert.Contains(t, testLogger.messages[0], "before its statement, exceeding threshold of 1s")
+
+			_, err = testTransaction.execute(context.Background(), "mockStatement")
+			require.NoError(t, err)
+			assert.Len(t, testLogger.messages, 1)
+		})
+
+		t.Run("fail", func(t *testing.T) {
+			testTransaction, _, mockService := newTestTransaction(true)
+			testTransaction.lastActivity = time.Now().Add(-2 * time.Second)
+			_, err := testTransaction.execute(context.Background(), "mockStatement")
+			var idleErr *TransactionIdleError
+			require.True(t, errors.As(err, &idleErr))
+			assert.Equal(t, mockTxnID, idleErr.TransactionID)
+			assert.Equal(t, time.Second, idleErr.Threshold)
+			assert.True(t, idleErr.Gap >= 2*time.Second)
+			mockService.AssertNotCalled(t, "executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+			err = testTransaction.commit(context.Background())
+			assert.True(t, errors.As(err, &idleErr))
+			mockService.AssertNotCalled(t, "commitTransaction", mock.Anything, mock.Anything, mock.Anything)
+		})
+	})
+
+	t.Run("parameter size", func(t *testing.T) {
+		newTestTransaction := func() (*transaction, *mockTransactionService) {
+			mockHash, _ := toQLDBHash(mockTxnID)
+			executeResult := types.ExecuteStatementResult{FirstPage: &types.Page{}}
+			mockService := new(mockTransactionService)
+			mockService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&executeResult, nil)
+			return &transaction{
+				communicator: mockService,
+				id:           &mockTxnID,
+				logger:       mockLogger,
+				commitHash:   mockHash,
+			}, mockService
+		}
+
+		t.Run("document too large", func(t *testing.T) {
+			testTransaction, mockService := newTestTransaction()
+			commitHash := testTransaction.commitHash
+
+			_, err := testTransaction.execute(context.Background(), "INSERT INTO Person << ?, ? >>", "small", map[string]interface{}{"data": make([]byte, MaxDocumentSize)})
+			var sizeErr *ParameterSizeError
+			require.True(t, errors.As(err, &sizeErr))
+			assert.Equal(t, 1, sizeErr.Index)
+			assert.True(t, sizeErr.Size > MaxDocumentSize)
+			assert.Equal(t, MaxDocumentSize, sizeErr.Limit)
+			assert.Equal(t, 0, testTransaction.statementCount)
+			assert.Equal(t, commitHash, testTransaction.commitHash)
+			mockService.AssertNotCalled(t, "executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+
+		t.Run("parameters too large", func(t *testing.T) {
+			testTransaction, mockService := newTestTransaction()
+			document := make([]byte, MaxDocumentSize-16)
+			parameters := make([]interface{}, MaxTransactionSize/len(document)+1)
+			for i := range parameters {
+				parameters[i] = document
+			}
+
+			_, err := testTransaction.execute(context.Background(), "INSERT INTO Person ?", parameters...)
+			var sizeErr *ParameterSizeError
+			require.True(t, errors.As(err, &sizeErr))
+			assert.Equal(t, len(parameters)-1, sizeErr.Index)
+			assert.True(t, sizeErr.Size > MaxTransactionSize)
+			assert.Equal(t, MaxTransactionSize, sizeErr.Limit)
+			mockService.AssertNotCalled(t, "executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+
+		t.Run("large list parameter", func(t *testing.T) {
+			testTransaction, mockService := newTestTransaction()
+			document := map[string]interface{}{"data": make([]byte, MaxDocumentSize/2)}
+
+			_, err := testTransaction.execute(context.Background(), "INSERT INTO Person ?", []interface{}{document, document, document})
+			require.NoError(t, err)
+			mockService.AssertNumberOfCalls(t, "executeStatement", 1)
+		})
+
+		t.Run("within limits", func(t *testing.T) {
+			testTransaction, mockService := newTestTransaction()
+
+			_, err := testTransaction.execute(context.Background(), "INSERT INTO Person ?", make([]byte, MaxDocumentSize-16))
+			require.NoError(t, err)
+			mockService.AssertNumberOfCalls(t, "executeStatement", 1)
+		})
+	})
 }
 
 func TestTransactionExecutor(t *testing.T) {
@@ -174,6 +325,24 @@ func TestTransactionExecutor(t *testing.T) {
 			FirstPage: &mockFirstPage,
 		}
 
+		t.Run("statement count", func(t *testing.T) {
+			mockService := new(mockTransactionService)
+			mockService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&mockExecuteResult, nil)
+			mockTransaction.communicator = mockService
+
+			statementCount, ok := StatementCount(&testExecutor)
+			require.True(t, ok)
+			_, err := testExecutor.Execute("mockStatement")
+			assert.NoError(t, err)
+			count, _ := StatementCount(&testExecutor)
+			assert.Equal(t, statementCount+1, count)
+
+			// The SavepointTransaction of ExecuteWithSavepoints wraps a transaction of the driver
+			count, ok = StatementCount(&savepointTransaction{Transaction: &testExecutor})
+			assert.True(t, ok)
+			assert.Equal(t, statementCount+1, count)
+		})
+
 		t.Run("success", func(t *testing.T) {
 			mockService := new(mockTransactionService)
 			mockService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&mockExecuteResult, nil)
@@ -250,6 +419,143 @@ func TestTransactionExecutor(t *testing.T) {
 		})
 	})
 
+	t.Run("ExecuteStream", func(t *testing.T) {
+		mockFirstValues := []types.ValueHolder{{IonBinary: []byte{1}}}
+		mockNextValues := []types.ValueHolder{{IonBinary: []byte{2}}}
+		mockNextPageToken := "mockToken"
+		mockExecuteResult := types.ExecuteStatementResult{
+			FirstPage: &types.Page{NextPageToken: &mockNextPageToken, Values: mockFirstValues},
+		}
+		mockFetchPageResult := types.FetchPageResult{Page: &types.Page{Values: mockNextValues}}
+
+		t.Run("success", func(t *testing.T) {
+			mockService := new(mockTransactionService)
+			mockService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&mockExecuteResult, nil)
+			mockService.On("fetchPage", mock.Anything, mock.Anything, mock.Anything).Return(&mockFetchPageResult, nil)
+			mockTransaction.communicator = mockService
+
+			rows := make([][]byte, 0)
+			err := ExecuteStream(&testExecutor, "mockStatement", func(ionBinary []byte) error {
+				rows = append(rows, ionBinary)
+				return nil
+			}, "mockParam")
+			assert.NoError(t, err)
+			assert.Equal(t, [][]byte{{1}, {2}}, rows)
+		})
+
+		t.Run("callback error stops iteration", func(t *testing.T) {
+			mockService := new(mockTransactionService)
+			mockService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&mockExecuteResult, nil)
+			mockTransaction.communicator = mockService
+
+			calls := 0
+			err := ExecuteStream(&testExecutor, "mockStatement", func(ionBinary []byte) error {
+				calls++
+				return errMock
+			})
+			assert.Equal(t, errMock, err)
+			assert.Equal(t, 1, calls)
+			mockService.AssertNotCalled(t, "fetchPage", mock.Anything, mock.Anything, mock.Anything)
+		})
+
+		t.Run("fetch page error", func(t *testing.T) {
+			mockService := new(mockTransactionService)
+			mockService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&mockExecuteResult, nil)
+			mockService.On("fetchPage", mock.Anything, mock.Anything, mock.Anything).Return(&mockFetchPageResult, errMock)
+			mockTransaction.communicator = mockService
+
+			err := ExecuteStream(&testExecutor, "mockStatement", func(ionBinary []byte) error {
+				return nil
+			})
+			assert.Equal(t, errMock, err)
+		})
+
+		t.Run("execute error", func(t *testing.T) {
+			mockService := new(mockTransactionService)
+			mockService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&mockExecuteResult, errMock)
+			mockTransaction.communicator = mockService
+
+			err := ExecuteStream(&testExecutor, "mockStatement", func(ionBinary []byte) error {
+				return nil
+			})
+			assert.Equal(t, errMock, err)
+		})
+	})
+
+	t.Run("ExecuteFromCursor", func(t *testing.T) {
+		mockValues := []types.ValueHolder{{IonBinary: []byte{1}}, {IonBinary: []byte{2}}, {IonBinary: []byte{3}}}
+		mockExecuteResult := types.ExecuteStatementResult{FirstPage: &types.Page{Values: mockValues}}
+		mockStatementHash, _ := toStatementHash("mockStatement", []interface{}{"mockParam"})
+
+		t.Run("resume in new transaction", func(t *testing.T) {
+			mockService := new(mockTransactionService)
+			mockService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&mockExecuteResult, nil)
+			mockTransaction.communicator = mockService
+
+			cursor := &Cursor{statementHash: mockStatementHash.hash, position: 2, transactionID: "otherTxnID"}
+			res, err := ExecuteFromCursor(&testExecutor, cursor, "mockStatement", "mockParam")
+			require.NoError(t, err)
+			assert.Nil(t, res.GetCurrentData())
+			assert.True(t, res.Next(&testExecutor))
+			assert.Equal(t, []byte{3}, res.GetCurrentData())
+			assert.False(t, res.Next(&testExecutor))
+			assert.Equal(t, int64(3), GetCursor(res).GetPosition())
+		})
+
+		t.Run("resume in same transaction uses page token", func(t *testing.T) {
+			mockToken := "mockToken"
+			mockFetchPageResult := types.FetchPageResult{Page: &types.Page{Values: mockValues[2:]}}
+			mockService := new(mockTransactionService)
+			mockService.On("fetchPage", mock.Anything, &mockToken, mock.Anything).Return(&mockFetchPageResult, nil)
+			mockTransaction.communicator = mockService
+
+			cursor := &Cursor{statementHash: mockStatementHash.hash, position: 2, transactionID: mockID, pageToken: &mockToken}
+			res, err := ExecuteFromCursor(&testExecutor, cursor, "mockStatement", "mockParam")
+			require.NoError(t, err)
+			assert.True(t, res.Next(&testExecutor))
+			assert.Equal(t, []byte{3}, res.GetCurrentData())
+			mockService.AssertNotCalled(t, "executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+
+		t.Run("statement mismatch", func(t *testing.T) {
+			mockService := new(mockTransactionService)
+			mockService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&mockExecuteResult, nil)
+			mockTransaction.communicator = mockService
+
+			cursor := &Cursor{statementHash: mockStatementHash.hash, position: 1, transactionID: "otherTxnID"}
+			res, err := ExecuteFromCursor(&testExecutor, cursor, "mockStatement", "otherParam")
+			assert.Error(t, err)
+			assert.Nil(t, res)
+		})
+
+		t.Run("skip limit", func(t *testing.T) {
+			mockService := new(mockTransactionService)
+			mockService.On("executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&mockExecuteResult, nil)
+			mockTransaction.communicator = mockService
+			mockTransaction.maxCursorSkip = 1
+			defer func() { mockTransaction.maxCursorSkip = 0 }()
+
+			cursor := &Cursor{statementHash: mockStatementHash.hash, position: 2, transactionID: "otherTxnID"}
+			res, err := ExecuteFromCursor(&testExecutor, cursor, "mockStatement", "mockParam")
+			var skipErr *CursorSkipError
+			require.True(t, errors.As(err, &skipErr))
+			assert.Equal(t, int64(2), skipErr.Position)
+			assert.Equal(t, int64(1), skipErr.Limit)
+			assert.Nil(t, res)
+			mockService.AssertNotCalled(t, "executeStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+			cursor.position = 1
+			_, err = ExecuteFromCursor(&testExecutor, cursor, "mockStatement", "mockParam")
+			assert.NoError(t, err)
+		})
+
+		t.Run("nil cursor", func(t *testing.T) {
+			res, err := ExecuteFromCursor(&testExecutor, nil, "mockStatement")
+			assert.Error(t, err)
+			assert.Nil(t, res)
+		})
+	})
+
 	t.Run("BufferResult", func(t *testing.T) {
 		mockIonBinary := make([]byte, 1)
 		mockIonBinary[0] = 1
@@ -345,7 +651,8 @@ func (m *mockTransactionService) executeStatement(ctx context.Context, statement
 }
 
 func (m *mockTransactionService) endSession(ctx context.Context) (*types.EndSessionResult, error) {
-	panic("not used")
+	args := m.Called(ctx)
+	return args.Get(0).(*types.EndSessionResult), args.Error(1)
 }
 
 func (m *mockTransactionService) fetchPage(ctx context.Context, pageToken *string, txnID *string) (*types.FetchPageResult, error) {
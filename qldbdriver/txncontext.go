@@ -0,0 +1,40 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+)
+
+// txnContextKey is the key of the Transaction stored in a context by NewContextWithTxn.
+type txnContextKey struct{}
+
+// NewContextWithTxn returns a copy of ctx carrying txn, so that functions called within the function passed to
+// QLDBDriver.Execute can retrieve the transaction with TxnFromContext instead of taking it as a parameter.
+//
+//	driver.Execute(ctx, func(txn qldbdriver.Transaction) (interface{}, error) {
+//	    return repository.FindPerson(qldbdriver.NewContextWithTxn(ctx, txn), "Alice")
+//	})
+//
+// The transaction must not be used after the function passed to Execute returns, so the returned context should not
+// outlive it.
+func NewContextWithTxn(ctx context.Context, txn Transaction) context.Context {
+	return context.WithValue(ctx, txnContextKey{}, txn)
+}
+
+// TxnFromContext returns the Transaction carried by ctx, and whether ctx carries one. See NewContextWithTxn.
+func TxnFromContext(ctx context.Context) (Transaction, bool) {
+	txn, ok := ctx.Value(txnContextKey{}).(Transaction)
+	return txn, ok
+}
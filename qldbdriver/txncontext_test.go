@@ -0,0 +1,46 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxnContext(t *testing.T) {
+	t.Run("no transaction", func(t *testing.T) {
+		txn, ok := TxnFromContext(context.Background())
+		assert.False(t, ok)
+		assert.Nil(t, txn)
+	})
+
+	t.Run("transaction of Execute", func(t *testing.T) {
+		testDriver := newMockDriver(t, &qldbsessioniface.MockClientAPI{})
+		findID := func(ctx context.Context) (interface{}, error) {
+			txn, ok := TxnFromContext(ctx)
+			require.True(t, ok)
+			return txn.ID(), nil
+		}
+
+		id, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+			return findID(NewContextWithTxn(context.Background(), txn))
+		})
+		require.NoError(t, err)
+		assert.Equal(t, qldbsessioniface.MockTransactionID, id)
+	})
+}
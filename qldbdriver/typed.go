@@ -0,0 +1,88 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/amzn/ion-go/ion"
+)
+
+// RegisterTable registers the type of model as the type of the documents in a table, so that QueryTyped can decode
+// them. model must be a struct or a pointer to a struct, for example Person{} or &Person{}. Registering a table again
+// replaces its type.
+func (driver *QLDBDriver) RegisterTable(tableName string, model interface{}) error {
+	if !tableNameRegex.MatchString(tableName) {
+		return &qldbDriverError{"Invalid table name: '" + tableName + "'."}
+	}
+	modelType := reflect.TypeOf(model)
+	if modelType != nil && modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+	if modelType == nil || modelType.Kind() != reflect.Struct {
+		return &qldbDriverError{"Model of table '" + tableName + "' must be a struct or a pointer to a struct."}
+	}
+
+	driver.lock.Lock()
+	defer driver.lock.Unlock()
+	if driver.models == nil {
+		driver.models = make(map[string]reflect.Type)
+	}
+	driver.models[tableName] = modelType
+	return nil
+}
+
+// QueryTyped executes a SELECT * query on a table registered with RegisterTable and returns the matching documents
+// decoded into the registered type. Each element of the returned slice is a pointer to a struct of that type.
+// whereClause is optional and, when not empty, is appended to the query after a WHERE keyword, for example
+// `Name = ?`. Use parameters for any values referenced by whereClause. Documents soft deleted by SoftDelete are
+// excluded when DriverOptions.SoftDeleteField is set. With DriverOptions.DeduplicateReads, concurrent identical calls
+// share the same documents, which must not be modified.
+//
+// The query runs in its own transaction, with the same retries as Execute. Use Execute to access the raw Ion values or
+// to query tables within a larger transaction.
+func (driver *QLDBDriver) QueryTyped(ctx context.Context, tableName string, whereClause string, parameters ...interface{}) ([]interface{}, error) {
+	driver.lock.Lock()
+	modelType, ok := driver.models[tableName]
+	driver.lock.Unlock()
+	if !ok {
+		return nil, &qldbDriverError{"Table '" + tableName + "' is not registered. Call RegisterTable before QueryTyped."}
+	}
+
+	statement := selectStatement("SELECT * FROM ", tableName, driver.excludeSoftDeleted(whereClause))
+
+	result, err := driver.readFlights.do(ctx, "QueryTyped", statement, parameters, func(ctx context.Context) (interface{}, error) {
+		return driver.Execute(ctx, func(txn Transaction) (interface{}, error) {
+			documents := make([]interface{}, 0)
+			err := ExecuteStream(txn, statement, func(ionBinary []byte) error {
+				document := reflect.New(modelType)
+				err := ion.Unmarshal(ionBinary, document.Interface())
+				if err != nil {
+					return err
+				}
+				documents = append(documents, document.Interface())
+				return nil
+			}, parameters...)
+			if err != nil {
+				return nil, err
+			}
+			return documents, nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]interface{}), nil
+}
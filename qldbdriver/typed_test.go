@@ -0,0 +1,131 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/amzn/ion-go/ion"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type typedPerson struct {
+	Name string `ion:"Name"`
+	Age  int    `ion:"Age"`
+}
+
+func TestRegisterTable(t *testing.T) {
+	testDriver := &QLDBDriver{}
+
+	t.Run("struct", func(t *testing.T) {
+		require.NoError(t, testDriver.RegisterTable("Person", typedPerson{}))
+		assert.Equal(t, "typedPerson", testDriver.models["Person"].Name())
+	})
+
+	t.Run("pointer to struct", func(t *testing.T) {
+		require.NoError(t, testDriver.RegisterTable("Person", &typedPerson{}))
+		assert.Equal(t, "typedPerson", testDriver.models["Person"].Name())
+	})
+
+	t.Run("invalid model", func(t *testing.T) {
+		assert.Error(t, testDriver.RegisterTable("Person", "not a struct"))
+		assert.Error(t, testDriver.RegisterTable("Person", nil))
+	})
+
+	t.Run("invalid table name", func(t *testing.T) {
+		assert.Error(t, testDriver.RegisterTable("Person; DROP TABLE Person", typedPerson{}))
+	})
+}
+
+func TestQueryTyped(t *testing.T) {
+	newTestDriver := func(mockClient *qldbsessioniface.MockClientAPI) *QLDBDriver {
+		return newMockDriver(t, mockClient)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		alice, _ := ion.MarshalBinary(typedPerson{Name: "Alice", Age: 30})
+		bob, _ := ion.MarshalBinary(typedPerson{Name: "Bob", Age: 40})
+		mockClient := &qldbsessioniface.MockClientAPI{
+			SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+				if params.ExecuteStatement != nil {
+					return &qldbsession.SendCommandOutput{ExecuteStatement: &types.ExecuteStatementResult{
+						FirstPage: &types.Page{Values: []types.ValueHolder{{IonBinary: alice}, {IonBinary: bob}}},
+					}}, nil
+				}
+				return qldbsessioniface.DefaultSendCommandOutput(params), nil
+			},
+		}
+		testDriver := newTestDriver(mockClient)
+		require.NoError(t, testDriver.RegisterTable("Person", typedPerson{}))
+
+		documents, err := testDriver.QueryTyped(context.Background(), "Person", "Age > ?", 18)
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{&typedPerson{"Alice", 30}, &typedPerson{"Bob", 40}}, documents)
+
+		var statement string
+		for _, input := range mockClient.Inputs() {
+			if input.ExecuteStatement != nil {
+				statement = *input.ExecuteStatement.Statement
+			}
+		}
+		assert.Equal(t, "SELECT * FROM Person WHERE Age > ?", statement)
+	})
+
+	t.Run("without where clause", func(t *testing.T) {
+		mockClient := &qldbsessioniface.MockClientAPI{}
+		testDriver := newTestDriver(mockClient)
+		require.NoError(t, testDriver.RegisterTable("Person", typedPerson{}))
+
+		documents, err := testDriver.QueryTyped(context.Background(), "Person", "")
+		require.NoError(t, err)
+		assert.Empty(t, documents)
+		for _, input := range mockClient.Inputs() {
+			if input.ExecuteStatement != nil {
+				assert.Equal(t, "SELECT * FROM Person", *input.ExecuteStatement.Statement)
+			}
+		}
+	})
+
+	t.Run("unregistered table", func(t *testing.T) {
+		mockClient := &qldbsessioniface.MockClientAPI{}
+		testDriver := newTestDriver(mockClient)
+
+		_, err := testDriver.QueryTyped(context.Background(), "Person", "")
+		assert.Error(t, err)
+		assert.Empty(t, mockClient.Inputs())
+	})
+
+	t.Run("decode error", func(t *testing.T) {
+		mockClient := &qldbsessioniface.MockClientAPI{
+			SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+				if params.ExecuteStatement != nil {
+					return &qldbsession.SendCommandOutput{ExecuteStatement: &types.ExecuteStatementResult{
+						FirstPage: &types.Page{Values: []types.ValueHolder{{IonBinary: []byte{0xff}}}},
+					}}, nil
+				}
+				return qldbsessioniface.DefaultSendCommandOutput(params), nil
+			},
+		}
+		testDriver := newTestDriver(mockClient)
+		require.NoError(t, testDriver.RegisterTable("Person", typedPerson{}))
+
+		_, err := testDriver.QueryTyped(context.Background(), "Person", "")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,275 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+// Package unitofwork provides a UnitOfWork that collects the insertions, updates and deletions of entities, structs
+// registered with a table and a key field, and writes them to QLDB in a single transaction with generated PartiQL
+// statements. It is an optional layer over qldbdriver for services that mostly read and write whole entities.
+package unitofwork
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver"
+)
+
+// unitOfWorkError is returned when an entity or its registration is invalid.
+type unitOfWorkError struct {
+	errorMessage string
+}
+
+// Return the message denoting the cause of the error.
+func (e *unitOfWorkError) Error() string {
+	return e.errorMessage
+}
+
+// NotFoundError is returned by UnitOfWork.Flush when an updated or deleted entity does not exist in its table. The
+// transaction is aborted, so none of the changes are written.
+type NotFoundError struct {
+	// The table of the entity.
+	Table string
+	// The value of the key field of the entity.
+	Key interface{}
+}
+
+// Error returns the message denoting the cause of the error.
+func (e *NotFoundError) Error() string {
+	return "No document of table " + e.Table + " matches the key of the entity."
+}
+
+// entityMapping describes how the entities of a struct type are stored.
+type entityMapping struct {
+	table    string
+	keyField string
+	fields   []entityField
+}
+
+// entityField is a field of an entity mapped to a top-level field of its document.
+type entityField struct {
+	name  string
+	index []int
+}
+
+// Registry maps struct types to the tables storing them. It must be populated before it is used by units of work, and
+// is safe for concurrent use once populated.
+type Registry struct {
+	mappings map[reflect.Type]*entityMapping
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{mappings: make(map[reflect.Type]*entityMapping)}
+}
+
+// Register registers the type of model, a struct or a pointer to a struct, as stored in a table. keyField is the Ion
+// name of the field identifying the entities, for example "VIN", which updates and deletions use to find their
+// document. Fields are named after their ion tag, or after the Go field name when they have none.
+func (registry *Registry) Register(tableName string, model interface{}, keyField string) error {
+	if !qldbdriver.IsValidIdentifier(tableName) {
+		return &unitOfWorkError{"Invalid table name: '" + tableName + "'."}
+	}
+	modelType := reflect.TypeOf(model)
+	if modelType != nil && modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+	if modelType == nil || modelType.Kind() != reflect.Struct {
+		return &unitOfWorkError{"Model of table '" + tableName + "' must be a struct or a pointer to a struct."}
+	}
+
+	mapping := &entityMapping{table: tableName, keyField: keyField, fields: structFields(modelType, nil)}
+	hasKey := false
+	for _, field := range mapping.fields {
+		if !qldbdriver.IsValidIdentifier(field.name) {
+			return &unitOfWorkError{"Field '" + field.name + "' of " + modelType.String() + " is not a valid PartiQL identifier."}
+		}
+		hasKey = hasKey || field.name == keyField
+	}
+	if !hasKey {
+		return &unitOfWorkError{"Key field '" + keyField + "' is not a field of " + modelType.String() + "."}
+	}
+	registry.mappings[modelType] = mapping
+	return nil
+}
+
+// structFields returns the exported fields of a struct type, including the fields promoted from embedded structs.
+func structFields(structType reflect.Type, index []int) []entityField {
+	var fields []entityField
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		tag := field.Tag.Get("ion")
+		if tag == "-" {
+			continue
+		}
+		name := tag
+		if idx := strings.Index(tag, ","); idx != -1 {
+			name = tag[:idx]
+		}
+		fieldIndex := append(append([]int(nil), index...), i)
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			fields = append(fields, structFields(field.Type, fieldIndex)...)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, entityField{name: name, index: fieldIndex})
+	}
+	return fields
+}
+
+// mapping returns the mapping of the type of entity.
+func (registry *Registry) mapping(entity interface{}) (*entityMapping, reflect.Value, error) {
+	if entity == nil {
+		return nil, reflect.Value{}, &unitOfWorkError{"Entity is nil."}
+	}
+	value := reflect.ValueOf(entity)
+	if value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.IsValid() {
+		if mapping, ok := registry.mappings[value.Type()]; ok {
+			return mapping, value, nil
+		}
+	}
+	return nil, reflect.Value{}, &unitOfWorkError{"Type " + reflect.TypeOf(entity).String() + " is not registered."}
+}
+
+// statement is a generated PartiQL statement and its parameters.
+type statement struct {
+	text       string
+	parameters []interface{}
+	// Whether the statement must change a document, and the table and key reported when it does not.
+	mustMatch bool
+	table     string
+	key       interface{}
+}
+
+// UnitOfWork collects changes to entities and writes them in a single transaction when flushed. Changes are written in
+// the order they were made. A UnitOfWork is not safe for concurrent use.
+type UnitOfWork struct {
+	registry   *Registry
+	statements []statement
+}
+
+// New creates a UnitOfWork for the entities registered in registry.
+func New(registry *Registry) *UnitOfWork {
+	return &UnitOfWork{registry: registry}
+}
+
+// Insert records the insertion of entity as a new document of its table.
+func (uow *UnitOfWork) Insert(entity interface{}) error {
+	mapping, value, err := uow.registry.mapping(entity)
+	if err != nil {
+		return err
+	}
+	uow.statements = append(uow.statements, statement{
+		text:       "INSERT INTO " + mapping.table + " ?",
+		parameters: []interface{}{value.Interface()},
+	})
+	return nil
+}
+
+// Update records the update of the document of entity, found by its key field, with the values of the other fields.
+func (uow *UnitOfWork) Update(entity interface{}) error {
+	mapping, value, err := uow.registry.mapping(entity)
+	if err != nil {
+		return err
+	}
+	var assignments []string
+	var parameters []interface{}
+	var key interface{}
+	for _, field := range mapping.fields {
+		fieldValue := value.FieldByIndex(field.index).Interface()
+		if field.name == mapping.keyField {
+			key = fieldValue
+			continue
+		}
+		assignments = append(assignments, "e."+field.name+" = ?")
+		parameters = append(parameters, fieldValue)
+	}
+	if len(assignments) == 0 {
+		return &unitOfWorkError{"Type " + value.Type().String() + " has no field to update besides its key."}
+	}
+	uow.statements = append(uow.statements, statement{
+		text:       "UPDATE " + mapping.table + " AS e SET " + strings.Join(assignments, ", ") + " WHERE e." + mapping.keyField + " = ?",
+		parameters: append(parameters, key),
+		mustMatch:  true,
+		table:      mapping.table,
+		key:        key,
+	})
+	return nil
+}
+
+// Delete records the deletion of the document of entity, found by its key field.
+func (uow *UnitOfWork) Delete(entity interface{}) error {
+	mapping, value, err := uow.registry.mapping(entity)
+	if err != nil {
+		return err
+	}
+	var key interface{}
+	for _, field := range mapping.fields {
+		if field.name == mapping.keyField {
+			key = value.FieldByIndex(field.index).Interface()
+		}
+	}
+	uow.statements = append(uow.statements, statement{
+		text:       "DELETE FROM " + mapping.table + " AS e WHERE e." + mapping.keyField + " = ?",
+		parameters: []interface{}{key},
+		mustMatch:  true,
+		table:      mapping.table,
+		key:        key,
+	})
+	return nil
+}
+
+// Pending returns the number of changes recorded since the UnitOfWork was created or last flushed.
+func (uow *UnitOfWork) Pending() int {
+	return len(uow.statements)
+}
+
+// Flush writes the recorded changes in a single transaction executed by executor with the provided options, and
+// forgets them once the transaction is committed. An update or deletion that matches no document aborts the
+// transaction with a NotFoundError. The recorded changes are kept when an error is returned.
+func (uow *UnitOfWork) Flush(ctx context.Context, executor qldbdriver.Executor, optFns ...func(*qldbdriver.ExecuteOptions)) error {
+	if len(uow.statements) == 0 {
+		return nil
+	}
+	_, err := executor.Execute(ctx, func(txn qldbdriver.Transaction) (interface{}, error) {
+		for _, stmt := range uow.statements {
+			result, err := txn.Execute(stmt.text, stmt.parameters...)
+			if err != nil {
+				return nil, err
+			}
+			matched := false
+			for result.Next(txn) {
+				matched = true
+			}
+			if result.Err() != nil {
+				return nil, result.Err()
+			}
+			if stmt.mustMatch && !matched {
+				return nil, &NotFoundError{Table: stmt.table, Key: stmt.key}
+			}
+		}
+		return nil, nil
+	}, optFns...)
+	if err != nil {
+		return err
+	}
+	uow.statements = nil
+	return nil
+}
@@ -0,0 +1,152 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package unitofwork
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type audited struct {
+	UpdatedBy string `ion:"updatedBy"`
+}
+
+type vehicle struct {
+	audited
+	VIN   string `ion:"VIN"`
+	Make  string `ion:"make"`
+	Year  int    `ion:"year,omitempty"`
+	notes string
+	Cache string `ion:"-"`
+}
+
+type executedStatement struct {
+	text       string
+	parameters []interface{}
+}
+
+// fakeResult is a Result with a number of rows.
+type fakeResult struct {
+	qldbdriver.Result
+	rows int
+}
+
+func (r *fakeResult) Next(txn qldbdriver.Transaction) bool {
+	if r.rows == 0 {
+		return false
+	}
+	r.rows--
+	return true
+}
+
+func (r *fakeResult) Err() error {
+	return nil
+}
+
+// fakeTransaction records the statements it executes, which match rows documents.
+type fakeTransaction struct {
+	qldbdriver.Transaction
+	rows       int
+	statements []executedStatement
+}
+
+func (txn *fakeTransaction) Execute(statement string, parameters ...interface{}) (qldbdriver.Result, error) {
+	txn.statements = append(txn.statements, executedStatement{statement, parameters})
+	return &fakeResult{rows: txn.rows}, nil
+}
+
+// fakeExecutor executes functions with a fakeTransaction.
+type fakeExecutor struct {
+	txn *fakeTransaction
+}
+
+func (executor *fakeExecutor) Execute(ctx context.Context, fn func(txn qldbdriver.Transaction) (interface{}, error), optFns ...func(*qldbdriver.ExecuteOptions)) (interface{}, error) {
+	return fn(executor.txn)
+}
+
+func newTestRegistry(t *testing.T) *Registry {
+	registry := NewRegistry()
+	require.NoError(t, registry.Register("Vehicle", &vehicle{}, "VIN"))
+	return registry
+}
+
+func TestRegister(t *testing.T) {
+	registry := NewRegistry()
+	assert.Error(t, registry.Register("Vehicle; DROP", vehicle{}, "VIN"))
+	assert.Error(t, registry.Register("Vehicle", "not a struct", "VIN"))
+	assert.Error(t, registry.Register("Vehicle", vehicle{}, "notes"))
+	assert.Error(t, registry.Register("Vehicle", vehicle{}, "Cache"))
+	require.NoError(t, registry.Register("Vehicle", vehicle{}, "VIN"))
+
+	mapping := registry.mappings[reflect.TypeOf(vehicle{})]
+	var names []string
+	for _, field := range mapping.fields {
+		names = append(names, field.name)
+	}
+	assert.Equal(t, []string{"updatedBy", "VIN", "make", "year"}, names)
+}
+
+func TestFlush(t *testing.T) {
+	car := vehicle{audited: audited{UpdatedBy: "Alice"}, VIN: "1N4AL11D75C109151", Make: "Nissan", Year: 2005}
+
+	t.Run("generated statements", func(t *testing.T) {
+		uow := New(newTestRegistry(t))
+		require.NoError(t, uow.Insert(car))
+		require.NoError(t, uow.Update(&car))
+		require.NoError(t, uow.Delete(car))
+		assert.Equal(t, 3, uow.Pending())
+
+		txn := &fakeTransaction{rows: 1}
+		require.NoError(t, uow.Flush(context.Background(), &fakeExecutor{txn}))
+		assert.Equal(t, []executedStatement{
+			{"INSERT INTO Vehicle ?", []interface{}{car}},
+			{"UPDATE Vehicle AS e SET e.updatedBy = ?, e.make = ?, e.year = ? WHERE e.VIN = ?", []interface{}{"Alice", "Nissan", 2005, car.VIN}},
+			{"DELETE FROM Vehicle AS e WHERE e.VIN = ?", []interface{}{car.VIN}},
+		}, txn.statements)
+		assert.Equal(t, 0, uow.Pending())
+	})
+
+	t.Run("missing document", func(t *testing.T) {
+		uow := New(newTestRegistry(t))
+		require.NoError(t, uow.Update(car))
+
+		err := uow.Flush(context.Background(), &fakeExecutor{&fakeTransaction{rows: 0}})
+		var notFound *NotFoundError
+		require.True(t, errors.As(err, &notFound))
+		assert.Equal(t, "Vehicle", notFound.Table)
+		assert.Equal(t, car.VIN, notFound.Key)
+		assert.Equal(t, 1, uow.Pending())
+	})
+
+	t.Run("unregistered entity", func(t *testing.T) {
+		uow := New(newTestRegistry(t))
+		assert.Error(t, uow.Insert(struct{ Name string }{"Alice"}))
+		assert.Error(t, uow.Update(nil))
+		assert.Error(t, uow.Delete((*vehicle)(nil)))
+		assert.Equal(t, 0, uow.Pending())
+	})
+
+	t.Run("nothing to flush", func(t *testing.T) {
+		uow := New(newTestRegistry(t))
+		txn := &fakeTransaction{}
+		require.NoError(t, uow.Flush(context.Background(), &fakeExecutor{txn}))
+		assert.Empty(t, txn.statements)
+	})
+}
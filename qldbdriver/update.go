@@ -0,0 +1,211 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// BuildUpdate builds a statement updating the documents of a table that match whereClause, for example `VIN = ?`,
+// with one assignment per changed field, such as UPDATE Vehicle SET Color = ?, Owner.City = ? WHERE VIN = ?. It returns
+// the statement and its parameters: the values of the assignments followed by whereParameters.
+//
+// changes is either a map from field paths to their new values, where a path such as "Owner.City" names a nested
+// field, or a struct or pointer to a struct mapped to Ion fields like ion.Marshal does. Struct fields are skipped when:
+//   - they are nil pointers, so that a struct of pointers can describe which fields changed,
+//   - they have the omitempty ion tag option and a zero value,
+//   - they are null types, such as NullString, that are neither Present nor Valid.
+//
+// Nested structs are not assigned as a whole, their fields are assigned one by one. Use a map to replace a nested
+// struct. whereClause is required, to prevent a forgotten condition from updating all the documents of the table.
+func BuildUpdate(tableName string, changes interface{}, whereClause string, whereParameters ...interface{}) (string, []interface{}, error) {
+	if !tableNameRegex.MatchString(tableName) {
+		return "", nil, &qldbDriverError{"Invalid table name: '" + tableName + "'."}
+	}
+	if strings.TrimSpace(whereClause) == "" {
+		return "", nil, &qldbDriverError{"An UPDATE statement requires a WHERE clause."}
+	}
+
+	builder, err := buildAssignments(changes, "")
+	if err != nil {
+		return "", nil, err
+	}
+	statement := "UPDATE " + tableName + " SET " + strings.Join(builder.assignments, ", ") + " WHERE " + whereClause
+	return statement, append(builder.parameters, whereParameters...), nil
+}
+
+// UpdateFields executes within txn the statement built by BuildUpdate, and returns its result, which has a row with
+// the ID of each updated document.
+func UpdateFields(txn Transaction, tableName string, changes interface{}, whereClause string, whereParameters ...interface{}) (Result, error) {
+	statement, parameters, err := BuildUpdate(tableName, changes, whereClause, whereParameters...)
+	if err != nil {
+		return nil, err
+	}
+	return txn.Execute(statement, parameters...)
+}
+
+type updateBuilder struct {
+	alias       string
+	assignments []string
+	parameters  []interface{}
+}
+
+// buildAssignments returns the assignments of the changed fields described by changes, as documented by BuildUpdate.
+// The field paths are qualified by alias when it is not empty.
+func buildAssignments(changes interface{}, alias string) (*updateBuilder, error) {
+	builder := &updateBuilder{alias: alias}
+	value := reflect.ValueOf(changes)
+	if value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	switch {
+	case value.Kind() == reflect.Map && value.Type().Key().Kind() == reflect.String:
+		err := builder.addMap(value)
+		if err != nil {
+			return nil, err
+		}
+	case value.Kind() == reflect.Struct:
+		err := builder.addStruct("", value)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, &qldbDriverError{"Changes must be a map with string keys, a struct or a pointer to a struct."}
+	}
+	if len(builder.assignments) == 0 {
+		return nil, &qldbDriverError{"No changed field to update."}
+	}
+	return builder, nil
+}
+
+func (builder *updateBuilder) assign(path string, value interface{}) {
+	if builder.alias != "" {
+		path = builder.alias + "." + path
+	}
+	builder.assignments = append(builder.assignments, path+" = ?")
+	builder.parameters = append(builder.parameters, value)
+}
+
+// addMap assigns the values of a map from field paths, in the order of the paths.
+func (builder *updateBuilder) addMap(value reflect.Value) error {
+	paths := make([]string, 0, value.Len())
+	for _, key := range value.MapKeys() {
+		paths = append(paths, key.String())
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		for _, name := range strings.Split(path, ".") {
+			if !tableNameRegex.MatchString(name) {
+				return &qldbDriverError{"Invalid field path: '" + path + "'."}
+			}
+		}
+		builder.assign(path, value.MapIndex(reflect.ValueOf(path).Convert(value.Type().Key())).Interface())
+	}
+	return nil
+}
+
+// addStruct assigns the changed fields of a struct reached through path.
+func (builder *updateBuilder) addStruct(path string, value reflect.Value) error {
+	for _, field := range structFields(value.Type(), nil) {
+		if !tableNameRegex.MatchString(field.name) {
+			return &qldbDriverError{"Field '" + field.name + "' of " + value.Type().String() + " is not a valid PartiQL identifier."}
+		}
+		fieldValue, ok := embeddedFieldByPath(value, field.path)
+		if !ok {
+			continue
+		}
+		fieldPath := field.name
+		if path != "" {
+			fieldPath = path + "." + field.name
+		}
+		omitEmpty := false
+		for _, opt := range strings.Split(field.opts, ",") {
+			omitEmpty = omitEmpty || opt == "omitempty"
+		}
+		if omitEmpty && fieldValue.IsZero() {
+			continue
+		}
+		err := builder.addField(fieldPath, fieldValue)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addField assigns a field of a struct unless it is unchanged, and assigns the fields of nested structs one by one.
+func (builder *updateBuilder) addField(path string, value reflect.Value) error {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		if isNestedStruct(value.Type().Elem()) {
+			return builder.addStruct(path, value.Elem())
+		}
+		builder.assign(path, value.Interface())
+		return nil
+	}
+	if unset, ok := isUnsetNullType(value.Interface()); ok {
+		if !unset {
+			builder.assign(path, value.Interface())
+		}
+		return nil
+	}
+	if isNestedStruct(value.Type()) {
+		return builder.addStruct(path, value)
+	}
+	builder.assign(path, value.Interface())
+	return nil
+}
+
+// isNestedStruct returns whether values of valueType are written as Ion structs with a field per struct field.
+func isNestedStruct(valueType reflect.Type) bool {
+	return valueType.Kind() == reflect.Struct && !ionStructTypes[valueType] &&
+		!valueType.Implements(ionMarshalerType) && !reflect.PtrTo(valueType).Implements(ionMarshalerType)
+}
+
+// embeddedFieldByPath returns the field of value at path, and false if an embedded struct reached through a nil pointer
+// does not hold it.
+func embeddedFieldByPath(value reflect.Value, path []int) (reflect.Value, bool) {
+	for _, i := range path {
+		if value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return reflect.Value{}, false
+			}
+			value = value.Elem()
+		}
+		value = value.Field(i)
+	}
+	return value, true
+}
+
+// isUnsetNullType returns whether value is a null type holding neither a value nor a null, and whether it is a null
+// type.
+func isUnsetNullType(value interface{}) (bool, bool) {
+	switch n := value.(type) {
+	case NullString:
+		return !n.Present && !n.Valid, true
+	case NullInt64:
+		return !n.Present && !n.Valid, true
+	case NullFloat64:
+		return !n.Present && !n.Valid, true
+	case NullBool:
+		return !n.Present && !n.Valid, true
+	case NullTime:
+		return !n.Present && !n.Valid, true
+	}
+	return false, false
+}
@@ -0,0 +1,128 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type updateAddress struct {
+	City   string `ion:"City"`
+	Street string `ion:"Street,omitempty"`
+}
+
+type updateOwner struct {
+	Name    *string        `ion:"Name"`
+	Address *updateAddress `ion:"Address"`
+}
+
+type updateVehicle struct {
+	Color    *string    `ion:"Color"`
+	Mileage  *int       `ion:"Mileage"`
+	Note     NullString `ion:"Note"`
+	Owner    updateOwner
+	Sold     *time.Time `ion:"Sold"`
+	internal string
+}
+
+func TestBuildUpdate(t *testing.T) {
+	red := "red"
+	alice := "Alice"
+	sold := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name               string
+		changes            interface{}
+		expectedStatement  string
+		expectedParameters []interface{}
+	}{
+		{
+			name:               "pointer fields",
+			changes:            &updateVehicle{Color: &red},
+			expectedStatement:  "UPDATE Vehicle SET Color = ? WHERE VIN = ?",
+			expectedParameters: []interface{}{&red, "VIN-1"},
+		},
+		{
+			name:               "nested struct paths",
+			changes:            updateVehicle{Owner: updateOwner{Name: &alice, Address: &updateAddress{City: "Seattle"}}, Sold: &sold},
+			expectedStatement:  "UPDATE Vehicle SET Owner.Name = ?, Owner.Address.City = ?, Sold = ? WHERE VIN = ?",
+			expectedParameters: []interface{}{&alice, "Seattle", &sold, "VIN-1"},
+		},
+		{
+			name:               "null type set to null",
+			changes:            updateVehicle{Note: NullString{Present: true}},
+			expectedStatement:  "UPDATE Vehicle SET Note = ? WHERE VIN = ?",
+			expectedParameters: []interface{}{NullString{Present: true}, "VIN-1"},
+		},
+		{
+			name:               "map of paths",
+			changes:            map[string]interface{}{"Owner.Address": updateAddress{City: "Seattle"}, "Color": "red"},
+			expectedStatement:  "UPDATE Vehicle SET Color = ?, Owner.Address = ? WHERE VIN = ?",
+			expectedParameters: []interface{}{"red", updateAddress{City: "Seattle"}, "VIN-1"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			statement, parameters, err := BuildUpdate("Vehicle", tc.changes, "VIN = ?", "VIN-1")
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStatement, statement)
+			assert.Equal(t, tc.expectedParameters, parameters)
+		})
+	}
+
+	errorCases := []struct {
+		name        string
+		tableName   string
+		changes     interface{}
+		whereClause string
+	}{
+		{"invalid table name", "Vehicle; DROP", map[string]interface{}{"Color": "red"}, "VIN = ?"},
+		{"missing WHERE clause", "Vehicle", map[string]interface{}{"Color": "red"}, " "},
+		{"invalid field path", "Vehicle", map[string]interface{}{"Color = 1, Mileage": 2}, "VIN = ?"},
+		{"no changes", "Vehicle", updateVehicle{}, "VIN = ?"},
+		{"unsupported changes", "Vehicle", "Color", "VIN = ?"},
+		{"nil changes", "Vehicle", nil, "VIN = ?"},
+	}
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := BuildUpdate(tc.tableName, tc.changes, tc.whereClause, "VIN-1")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestUpdateFields(t *testing.T) {
+	mockClient := &qldbsessioniface.MockClientAPI{}
+	testDriver := newMockDriver(t, mockClient)
+
+	_, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+		return UpdateFields(txn, "Vehicle", map[string]interface{}{"Color": "red"}, "VIN = ?", "VIN-1")
+	})
+	require.NoError(t, err)
+
+	var statements []string
+	for _, input := range mockClient.Inputs() {
+		if input.ExecuteStatement != nil {
+			statements = append(statements, *input.ExecuteStatement.Statement)
+			assert.Len(t, input.ExecuteStatement.Parameters, 2)
+		}
+	}
+	assert.Equal(t, []string{"UPDATE Vehicle SET Color = ? WHERE VIN = ?"}, statements)
+}
@@ -0,0 +1,64 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import "context"
+
+// WorkflowStep is a named part of a workflow run in a single transaction by RunWorkflow or QLDBDriver.ExecuteWorkflow.
+type WorkflowStep struct {
+	// The name of the step, reported by StepError when the step fails.
+	Name string
+	// The function of the step, which is passed the transaction and the result of the previous step, or nil for the
+	// first step. Like the function passed to QLDBDriver.Execute, it is run again from the first step when the
+	// transaction is retried.
+	Run func(txn Transaction, previous interface{}) (interface{}, error)
+}
+
+// RunWorkflow runs steps in order within txn, passing the result of each step to the next one, and returns the result
+// of the last step. The workflow stops at the first step that fails, with a StepError identifying the step. Between
+// steps, the workflow also stops if the context of the transaction is done.
+func RunWorkflow(txn Transaction, steps []WorkflowStep) (interface{}, error) {
+	var ctx context.Context
+	var logger *qldbLogger
+	if executor, ok := executorOf(txn); ok {
+		ctx = executor.ctx
+		logger = executor.txn.logger
+	}
+
+	var result interface{}
+	for i, step := range steps {
+		if ctx != nil && ctx.Err() != nil {
+			return nil, &StepError{Step: step.Name, Index: i, err: ctx.Err()}
+		}
+		var err error
+		result, err = step.Run(txn, result)
+		if err != nil {
+			return nil, &StepError{Step: step.Name, Index: i, err: err}
+		}
+		if logger != nil {
+			logger.logf(LogDebug, "Completed step %d '%s' of transaction %s.", i, step.Name, txn.ID())
+		}
+	}
+	return result, nil
+}
+
+// ExecuteWorkflow runs steps in a single transaction as RunWorkflow does, and commits the transaction once all the
+// steps have succeeded. The transaction is retried like the function passed to Execute, from the first step. When the
+// transaction fails, the returned error wraps a StepError identifying the step that failed, unless the transaction
+// failed to start or commit.
+func (driver *QLDBDriver) ExecuteWorkflow(ctx context.Context, steps []WorkflowStep, optFns ...func(*ExecuteOptions)) (interface{}, error) {
+	return driver.Execute(ctx, func(txn Transaction) (interface{}, error) {
+		return RunWorkflow(txn, steps)
+	}, optFns...)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteWorkflow(t *testing.T) {
+	newTestDriver := func(mockClient *qldbsessioniface.MockClientAPI) *QLDBDriver {
+		return newMockDriver(t, mockClient, func(options *DriverOptions) {
+			options.RetryPolicy = RetryPolicy{MaxRetryLimit: 2, Backoff: ExponentialBackoffStrategy{}}
+		})
+	}
+	appendStep := func(name string) WorkflowStep {
+		return WorkflowStep{Name: name, Run: func(txn Transaction, previous interface{}) (interface{}, error) {
+			names, _ := previous.([]string)
+			return append(names, name), nil
+		}}
+	}
+
+	t.Run("results are passed to the next step", func(t *testing.T) {
+		testDriver := newTestDriver(&qldbsessioniface.MockClientAPI{})
+
+		result, err := testDriver.ExecuteWorkflow(context.Background(), []WorkflowStep{appendStep("a"), appendStep("b"), appendStep("c")})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b", "c"}, result)
+	})
+
+	t.Run("failed step", func(t *testing.T) {
+		mockClient := &qldbsessioniface.MockClientAPI{}
+		testDriver := newTestDriver(mockClient)
+		errStep := errors.New("insufficient funds")
+		ran := 0
+		failing := WorkflowStep{Name: "debit", Run: func(txn Transaction, previous interface{}) (interface{}, error) {
+			return nil, errStep
+		}}
+		last := WorkflowStep{Name: "credit", Run: func(txn Transaction, previous interface{}) (interface{}, error) {
+			ran++
+			return nil, nil
+		}}
+
+		_, err := testDriver.ExecuteWorkflow(context.Background(), []WorkflowStep{appendStep("lookup"), failing, last})
+		var stepErr *StepError
+		require.True(t, errors.As(err, &stepErr))
+		assert.Equal(t, "debit", stepErr.Step)
+		assert.Equal(t, 1, stepErr.Index)
+		assert.True(t, errors.Is(err, errStep))
+		assert.Equal(t, 0, ran)
+		for _, input := range mockClient.Inputs() {
+			assert.Nil(t, input.CommitTransaction)
+		}
+	})
+
+	t.Run("retried from the first step", func(t *testing.T) {
+		executed := 0
+		testDriver := newTestDriver(&qldbsessioniface.MockClientAPI{
+			SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+				if params.ExecuteStatement != nil {
+					executed++
+					if executed == 2 {
+						return nil, &types.OccConflictException{Message: &ErrMessageOccConflictException}
+					}
+				}
+				return qldbsessioniface.DefaultSendCommandOutput(params), nil
+			},
+		})
+		runs := 0
+		step := func(name string) WorkflowStep {
+			return WorkflowStep{Name: name, Run: func(txn Transaction, previous interface{}) (interface{}, error) {
+				if name == "first" {
+					runs++
+				}
+				_, err := txn.Execute("UPDATE Accounts SET balance = 0")
+				return name, err
+			}}
+		}
+
+		result, err := testDriver.ExecuteWorkflow(context.Background(), []WorkflowStep{step("first"), step("second")})
+		require.NoError(t, err)
+		assert.Equal(t, "second", result)
+		assert.Equal(t, 2, runs)
+	})
+
+	t.Run("cancelled between steps", func(t *testing.T) {
+		testDriver := newTestDriver(&qldbsessioniface.MockClientAPI{})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancelling := WorkflowStep{Name: "cancel", Run: func(txn Transaction, previous interface{}) (interface{}, error) {
+			cancel()
+			return nil, nil
+		}}
+
+		_, err := testDriver.ExecuteWorkflow(ctx, []WorkflowStep{cancelling, appendStep("never")})
+		var stepErr *StepError
+		require.True(t, errors.As(err, &stepErr))
+		assert.Equal(t, "never", stepErr.Step)
+		assert.True(t, errors.Is(err, context.Canceled))
+	})
+}
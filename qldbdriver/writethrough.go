@@ -0,0 +1,79 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"github.com/amzn/ion-go/ion"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+)
+
+// WrittenDocument is a document written by a committed transaction, as passed to DriverOptions.AfterCommit.
+//
+// The documents are derived from the document IDs returned by the INSERT, UPDATE and DELETE statements executed. They
+// are passed to AfterCommit before QLDBDriver.Execute returns, including through the methods built on it, and
+// including when ExecuteOptions.VerifyCommit finds that a transaction whose commit outcome was unknown was committed.
+// They are not passed when Execute returns an AmbiguousCommitError, after which the entries of the tables written by
+// the transaction should be considered stale.
+type WrittenDocument struct {
+	// The table written to, or "" if the table of the statement could not be determined, in which case any table may
+	// have been written to.
+	TableName string
+	// The ID of the document, or "" if the documents written by the statement could not be determined, in which case
+	// any document of the table may have been written to.
+	DocumentID string
+}
+
+// writeSet holds the documents written within a transaction, in the order they were first written.
+type writeSet struct {
+	documents []WrittenDocument
+	seen      map[WrittenDocument]bool
+}
+
+func newWriteSet() *writeSet {
+	return &writeSet{seen: make(map[WrittenDocument]bool)}
+}
+
+func (writes *writeSet) add(written WrittenDocument) {
+	if !writes.seen[written] {
+		writes.seen[written] = true
+		writes.documents = append(writes.documents, written)
+	}
+}
+
+// record records the documents written by a statement, from the document IDs QLDB returns in the first page of the
+// result of an INSERT, UPDATE or DELETE statement. When the result has more pages, the whole table is recorded, since
+// its next pages may never be fetched.
+func (writes *writeSet) record(statement string, page *types.Page) {
+	if selectRegex.MatchString(statement) {
+		return
+	}
+	match := writeTargetRegex.FindStringSubmatch(statement)
+	if match == nil {
+		writes.add(WrittenDocument{})
+		return
+	}
+	tableName := match[1]
+	if page == nil || page.NextPageToken != nil {
+		writes.add(WrittenDocument{TableName: tableName})
+		return
+	}
+	for _, value := range page.Values {
+		row := insertedDocumentRow{}
+		if err := ion.Unmarshal(value.IonBinary, &row); err != nil || row.DocumentID == "" {
+			writes.add(WrittenDocument{TableName: tableName})
+			continue
+		}
+		writes.add(WrittenDocument{TableName: tableName, DocumentID: row.DocumentID})
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use this file except in compliance with
+the License. A copy of the License is located at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+or in the "license" file accompanying this file. This file is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions
+and limitations under the License.
+*/
+
+package qldbdriver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession"
+	"github.com/aws/aws-sdk-go-v2/service/qldbsession/types"
+	"github.com/awslabs/amazon-qldb-driver-go/v3/qldbdriver/qldbsessioniface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSetRecord(t *testing.T) {
+	page := func(rows ...string) *types.Page {
+		page := &types.Page{}
+		for _, row := range rows {
+			page.Values = append(page.Values, types.ValueHolder{IonBinary: ionTextToBinary(t, row)})
+		}
+		return page
+	}
+
+	writes := newWriteSet()
+	writes.record("SELECT * FROM Vehicle", page(`{VIN: "1"}`))
+	writes.record("INSERT INTO Vehicle ?", page(`{documentId: "A"}`, `{documentId: "B"}`))
+	writes.record("UPDATE Vehicle SET Color = ? WHERE VIN = ?", page(`{documentId: "A"}`))
+	writes.record("DELETE FROM Person WHERE Age > ?", page())
+	assert.Equal(t, []WrittenDocument{{"Vehicle", "A"}, {"Vehicle", "B"}}, writes.documents)
+
+	token := "token"
+	writes.record("UPDATE Person SET Age = 1", &types.Page{NextPageToken: &token})
+	writes.record("DELETE FROM Person WHERE Age > ?", page(`{other: 1}`))
+	writes.record("DROP TABLE Person", page())
+	assert.Equal(t, []WrittenDocument{{"Vehicle", "A"}, {"Vehicle", "B"}, {"Person", ""}, {"", ""}}, writes.documents)
+}
+
+func TestAfterCommit(t *testing.T) {
+	rows := map[string][]string{
+		"INSERT INTO Vehicle ?":                    {`{documentId: "A"}`},
+		"UPDATE Person SET Age = ? WHERE Name = ?": {`{documentId: "B"}`},
+	}
+	var written [][]WrittenDocument
+	testDriver := newMockDriver(t, &qldbsessioniface.MockClientAPI{
+		SendCommandFunc: func(ctx context.Context, params *qldbsession.SendCommandInput, optFns ...func(*qldbsession.Options)) (*qldbsession.SendCommandOutput, error) {
+			output := qldbsessioniface.DefaultSendCommandOutput(params)
+			if params.ExecuteStatement != nil {
+				for _, row := range rows[*params.ExecuteStatement.Statement] {
+					output.ExecuteStatement.FirstPage.Values = append(output.ExecuteStatement.FirstPage.Values, types.ValueHolder{IonBinary: ionTextToBinary(t, row)})
+				}
+			}
+			return output, nil
+		},
+	}, func(options *DriverOptions) {
+		options.AfterCommit = func(ctx context.Context, documents []WrittenDocument) {
+			written = append(written, documents)
+		}
+	})
+
+	t.Run("written documents", func(t *testing.T) {
+		written = nil
+		_, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+			if _, err := txn.Execute("INSERT INTO Vehicle ?", map[string]string{"VIN": "1"}); err != nil {
+				return nil, err
+			}
+			return txn.Execute("UPDATE Person SET Age = ? WHERE Name = ?", 30, "Ana")
+		})
+		require.NoError(t, err)
+		assert.Equal(t, [][]WrittenDocument{{{"Vehicle", "A"}, {"Person", "B"}}}, written)
+	})
+
+	t.Run("read only", func(t *testing.T) {
+		written = nil
+		_, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+			return txn.Execute("SELECT * FROM Vehicle")
+		})
+		require.NoError(t, err)
+		assert.Empty(t, written)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		written = nil
+		_, err := testDriver.Execute(context.Background(), func(txn Transaction) (interface{}, error) {
+			if _, err := txn.Execute("INSERT INTO Vehicle ?", map[string]string{"VIN": "1"}); err != nil {
+				return nil, err
+			}
+			return nil, errMock
+		})
+		assert.Equal(t, errMock, err)
+		assert.Empty(t, written)
+	})
+}